@@ -0,0 +1,206 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+)
+
+var errNotLoggedIn = errors.New("not logged in, run beerbux login first")
+
+// refreshBefore is how long before the access token expires that it is refreshed.
+const refreshBefore = 30 * time.Second
+
+// apiError is an error response from the API, which is either a single message or the
+// validation errors of each field of the request.
+type apiError struct {
+	Status  int
+	Message string            `json:"error"`
+	Fields  map[string]string `json:"errors"`
+}
+
+func (e *apiError) Error() string {
+	if len(e.Fields) > 0 {
+		errs := make([]string, 0, len(e.Fields))
+		for _, field := range slices.Sorted(maps.Keys(e.Fields)) {
+			errs = append(errs, field+": "+e.Fields[field])
+		}
+		return strings.Join(errs, ", ")
+	}
+	if e.Message != "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%d %s", e.Status, http.StatusText(e.Status))
+}
+
+// tokenResponse is returned by the token endpoints. When the user has two-factor authentication
+// enabled, logging in returns MFARequired and an MFAToken to send with the code instead.
+type tokenResponse struct {
+	AccessToken  string    `json:"accessToken"`
+	RefreshToken string    `json:"refreshToken"`
+	ExpiresIn    int64     `json:"expiresIn"`
+	User         *userInfo `json:"user"`
+	MFARequired  bool      `json:"mfaRequired"`
+	MFAToken     string    `json:"mfaToken"`
+}
+
+// client calls the API with the stored access token, refreshing it when it has expired.
+type client struct {
+	creds *credentials
+	http  *http.Client
+}
+
+func newClient(creds *credentials) *client {
+	return &client{
+		creds: creds,
+		http:  &http.Client{},
+	}
+}
+
+func (c *client) get(ctx context.Context, path string, v any) error {
+	return c.do(ctx, http.MethodGet, path, nil, v)
+}
+
+func (c *client) post(ctx context.Context, path string, body, v any) error {
+	return c.do(ctx, http.MethodPost, path, body, v)
+}
+
+// do sends an authenticated request to the API and decodes the JSON response into v, if given.
+func (c *client) do(ctx context.Context, method, path string, body, v any) error {
+	res, err := c.send(ctx, method, path, body)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if v == nil || res.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	if err := json.NewDecoder(res.Body).Decode(v); err != nil {
+		return fmt.Errorf("failed to decode response from %s: %w", path, err)
+	}
+	return nil
+}
+
+// send sends an authenticated request, retrying once with a refreshed access token if the
+// access token is rejected. Error responses are returned as an *apiError.
+func (c *client) send(ctx context.Context, method, path string, body any) (*http.Response, error) {
+	if c.creds.AccessToken == "" {
+		return nil, errNotLoggedIn
+	}
+	if c.creds.RefreshToken != "" && time.Until(c.creds.ExpiresAt) < refreshBefore {
+		if err := c.refresh(ctx); err != nil {
+			return nil, err
+		}
+	}
+
+	res, err := c.sendWithToken(ctx, method, path, body)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode == http.StatusUnauthorized && c.creds.RefreshToken != "" {
+		res.Body.Close()
+		if err := c.refresh(ctx); err != nil {
+			return nil, err
+		}
+		if res, err = c.sendWithToken(ctx, method, path, body); err != nil {
+			return nil, err
+		}
+	}
+
+	if res.StatusCode >= http.StatusBadRequest {
+		defer res.Body.Close()
+		return nil, readAPIError(res)
+	}
+	return res, nil
+}
+
+func (c *client) sendWithToken(ctx context.Context, method, path string, body any) (*http.Response, error) {
+	req, err := c.newRequest(ctx, method, path, body)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.creds.AccessToken)
+	return c.http.Do(req)
+}
+
+// postUnauthenticated sends a request to one of the token endpoints, which take no access token.
+func (c *client) postUnauthenticated(ctx context.Context, path string, body, v any) error {
+	req, err := c.newRequest(ctx, http.MethodPost, path, body)
+	if err != nil {
+		return err
+	}
+	res, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= http.StatusBadRequest {
+		return readAPIError(res)
+	}
+	if v == nil || res.StatusCode == http.StatusNoContent {
+		return nil
+	}
+	return json.NewDecoder(res.Body).Decode(v)
+}
+
+func (c *client) newRequest(ctx context.Context, method, path string, body any) (*http.Request, error) {
+	var r io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		r = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimRight(c.creds.Server, "/")+"/api"+path, r)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	return req, nil
+}
+
+// refresh exchanges the refresh token for new tokens and stores them.
+func (c *client) refresh(ctx context.Context) error {
+	var tokens tokenResponse
+	err := c.postUnauthenticated(ctx, "/auth/token/refresh", map[string]string{"refreshToken": c.creds.RefreshToken}, &tokens)
+	if err != nil {
+		var apiErr *apiError
+		if errors.As(err, &apiErr) && apiErr.Status == http.StatusUnauthorized {
+			return errors.New("your login has expired, run beerbux login again")
+		}
+		return fmt.Errorf("failed to refresh access token: %w", err)
+	}
+	return c.setTokens(tokens)
+}
+
+// setTokens stores the tokens, keeping the user from the previous tokens if none is given.
+func (c *client) setTokens(tokens tokenResponse) error {
+	c.creds.AccessToken = tokens.AccessToken
+	c.creds.RefreshToken = tokens.RefreshToken
+	c.creds.ExpiresAt = time.Now().Add(time.Duration(tokens.ExpiresIn) * time.Second)
+	if tokens.User != nil {
+		c.creds.User = tokens.User
+	}
+	return c.creds.save()
+}
+
+func readAPIError(res *http.Response) error {
+	apiErr := &apiError{Status: res.StatusCode}
+	body, _ := io.ReadAll(io.LimitReader(res.Body, 1<<20))
+	_ = json.Unmarshal(body, apiErr)
+	return apiErr
+}
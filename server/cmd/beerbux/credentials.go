@@ -0,0 +1,97 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// credentials are the tokens for the server the user logged in to, stored in the user's
+// config directory and readable only by them.
+type credentials struct {
+	Server       string    `json:"server"`
+	AccessToken  string    `json:"accessToken,omitempty"`
+	RefreshToken string    `json:"refreshToken,omitempty"`
+	ExpiresAt    time.Time `json:"expiresAt,omitzero"`
+	User         *userInfo `json:"user,omitempty"`
+
+	// fromEnv is set when the access token is a personal access token from BEERBUX_TOKEN,
+	// which is never written to the credentials file.
+	fromEnv bool
+}
+
+type userInfo struct {
+	ID       uuid.UUID `json:"id"`
+	Name     string    `json:"name"`
+	Username string    `json:"username"`
+}
+
+// credentialsPath returns the path of the credentials file, which BEERBUX_CREDENTIALS overrides.
+func credentialsPath() (string, error) {
+	if path := os.Getenv("BEERBUX_CREDENTIALS"); path != "" {
+		return path, nil
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to find config directory: %w", err)
+	}
+	return filepath.Join(dir, "beerbux", "credentials.json"), nil
+}
+
+// loadCredentials reads the stored credentials, returning empty credentials if the user has not logged in.
+func loadCredentials() (*credentials, error) {
+	path, err := credentialsPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return &credentials{}, nil
+		}
+		return nil, fmt.Errorf("failed to read credentials: %w", err)
+	}
+
+	var creds credentials
+	if err := json.Unmarshal(data, &creds); err != nil {
+		return nil, fmt.Errorf("failed to parse credentials %s: %w", path, err)
+	}
+	return &creds, nil
+}
+
+func (c *credentials) save() error {
+	if c.fromEnv {
+		return nil
+	}
+	path, err := credentialsPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("failed to write credentials: %w", err)
+	}
+	return nil
+}
+
+func removeCredentials() error {
+	path, err := credentialsPath()
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return fmt.Errorf("failed to remove credentials: %w", err)
+	}
+	return nil
+}
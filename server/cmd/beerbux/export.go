@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/google/uuid"
+	"io"
+	"net/url"
+	"os"
+	"time"
+)
+
+type sessionHistory struct {
+	SessionID uuid.UUID      `json:"sessionId"`
+	Events    []historyEvent `json:"events"`
+}
+
+type historyEvent struct {
+	ID        int32     `json:"id"`
+	MemberID  uuid.UUID `json:"memberId"`
+	EventType string    `json:"eventType"`
+	EventData struct {
+		// TransactionID and Lines are set for transaction_created events and MemberID for
+		// events about another member, such as member_added.
+		TransactionID *uuid.UUID `json:"transactionId,omitempty"`
+		MemberID      *uuid.UUID `json:"memberId,omitempty"`
+		Lines         []struct {
+			MemberID uuid.UUID `json:"memberId"`
+			Amount   float64   `json:"amount"`
+		} `json:"lines,omitempty"`
+	} `json:"eventData"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// export writes the history of the session as CSV, with a row for each member in each
+// transaction, or as the JSON returned by the API.
+func export(ctx context.Context, c *client, args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	format := fs.String("format", "csv", "export format: csv or json")
+	outputPath := fs.String("o", "", "file to write the export to, defaults to stdout")
+	_ = fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		return errors.New("export requires a session ID or name")
+	}
+	if *format != "csv" && *format != "json" {
+		return fmt.Errorf("invalid format %q, must be csv or json", *format)
+	}
+
+	session, err := getSession(ctx, c, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	var history sessionHistory
+	if err := c.get(ctx, "/session/"+url.PathEscape(session.ID.String())+"/history", &history); err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if *outputPath != "" {
+		f, err := os.Create(*outputPath)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	if *format == "json" {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(history)
+	} else {
+		err = writeHistoryCSV(w, session, history)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write export: %w", err)
+	}
+
+	if *outputPath != "" {
+		fmt.Fprintf(os.Stderr, "Exported %d events from %s to %s\n", len(history.Events), session.Name, *outputPath)
+	}
+	return nil
+}
+
+func writeHistoryCSV(w io.Writer, session *sessionDetail, history sessionHistory) error {
+	cw := csv.NewWriter(w)
+	_ = cw.Write([]string{"time", "event", "member", "member_username", "subject", "subject_username", "amount", "transaction_id"})
+
+	member := func(id uuid.UUID) (string, string) {
+		if m, ok := session.member(id); ok {
+			return m.Name, m.Username
+		}
+		return id.String(), ""
+	}
+
+	for _, e := range history.Events {
+		createdAt := e.CreatedAt.Format(time.RFC3339)
+		memberName, memberUsername := member(e.MemberID)
+
+		if len(e.EventData.Lines) > 0 {
+			transactionID := ""
+			if e.EventData.TransactionID != nil {
+				transactionID = e.EventData.TransactionID.String()
+			}
+			for _, l := range e.EventData.Lines {
+				subjectName, subjectUsername := member(l.MemberID)
+				_ = cw.Write([]string{createdAt, e.EventType, memberName, memberUsername, subjectName, subjectUsername, formatBeers(l.Amount), transactionID})
+			}
+			continue
+		}
+
+		var subjectName, subjectUsername string
+		if e.EventData.MemberID != nil {
+			subjectName, subjectUsername = member(*e.EventData.MemberID)
+		}
+		_ = cw.Write([]string{createdAt, e.EventType, memberName, memberUsername, subjectName, subjectUsername, "", ""})
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/google/uuid"
+	"io"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+var stdin = bufio.NewReader(os.Stdin)
+
+type currentUser struct {
+	ID            uuid.UUID `json:"id"`
+	Name          string    `json:"name"`
+	Username      string    `json:"username"`
+	EmailVerified bool      `json:"emailVerified"`
+	Locale        string    `json:"locale"`
+}
+
+// login logs in with a username and password, asking for a two-factor code if the user has
+// two-factor authentication enabled, or with a personal access token.
+func login(ctx context.Context, c *client, args []string) error {
+	fs := flag.NewFlagSet("login", flag.ExitOnError)
+	username := fs.String("username", "", "username to log in with, asked for if not given")
+	passwordStdin := fs.Bool("password-stdin", false, "read the password from stdin rather than asking for it")
+	token := fs.String("token", "", "personal access token to log in with instead of a password")
+	_ = fs.Parse(args)
+
+	c.creds = &credentials{Server: c.creds.Server}
+
+	if *token != "" {
+		c.creds.AccessToken = *token
+		var user currentUser
+		if err := c.get(ctx, "/user", &user); err != nil {
+			return fmt.Errorf("failed to log in with the access token: %w", err)
+		}
+		c.creds.User = &userInfo{ID: user.ID, Name: user.Name, Username: user.Username}
+		if err := c.creds.save(); err != nil {
+			return err
+		}
+		fmt.Printf("Logged in to %s as %s\n", c.creds.Server, user.Username)
+		return nil
+	}
+
+	var err error
+	if *username == "" {
+		if *username, err = prompt("Username: "); err != nil {
+			return err
+		}
+	}
+	var password string
+	if *passwordStdin {
+		password, err = readLine()
+	} else {
+		password, err = readPassword("Password: ")
+	}
+	if err != nil {
+		return err
+	}
+
+	var tokens tokenResponse
+	err = c.postUnauthenticated(ctx, "/auth/token", map[string]string{
+		"username": *username,
+		"password": password,
+	}, &tokens)
+	if err != nil {
+		return err
+	}
+
+	if tokens.MFARequired {
+		code, err := prompt("Two-factor code: ")
+		if err != nil {
+			return err
+		}
+		err = c.postUnauthenticated(ctx, "/auth/token/2fa", map[string]string{
+			"mfaToken": tokens.MFAToken,
+			"code":     code,
+		}, &tokens)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := c.setTokens(tokens); err != nil {
+		return err
+	}
+	fmt.Printf("Logged in to %s as %s\n", c.creds.Server, c.creds.User.Username)
+	return nil
+}
+
+// logout revokes the refresh token and removes the stored credentials.
+func logout(ctx context.Context, c *client) error {
+	if c.creds.RefreshToken != "" {
+		err := c.postUnauthenticated(ctx, "/auth/token/revoke", map[string]string{"refreshToken": c.creds.RefreshToken}, nil)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, "beerbux: failed to revoke refresh token:", err)
+		}
+	}
+	if err := removeCredentials(); err != nil {
+		return err
+	}
+	fmt.Println("Logged out")
+	return nil
+}
+
+func whoami(ctx context.Context, c *client, out *output) error {
+	var user currentUser
+	if err := c.get(ctx, "/user", &user); err != nil {
+		return err
+	}
+	if out.json {
+		return out.printJSON(user)
+	}
+
+	fmt.Printf("ID:       %s\n", user.ID)
+	fmt.Printf("Name:     %s\n", user.Name)
+	fmt.Printf("Username: %s\n", user.Username)
+	fmt.Printf("Server:   %s\n", c.creds.Server)
+	return nil
+}
+
+func prompt(label string) (string, error) {
+	fmt.Fprint(os.Stderr, label)
+	return readLine()
+}
+
+func readLine() (string, error) {
+	line, err := stdin.ReadString('\n')
+	if err != nil && (line == "" || !errors.Is(err, io.EOF)) {
+		return "", fmt.Errorf("failed to read input: %w", err)
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// readPassword asks for a password without echoing it when stdin is a terminal that stty can
+// control. Elsewhere, such as on Windows, the password is echoed.
+func readPassword(label string) (string, error) {
+	if info, err := os.Stdin.Stat(); err == nil && info.Mode()&os.ModeCharDevice != 0 {
+		if stty("-echo") == nil {
+			defer func() {
+				_ = stty("echo")
+				fmt.Fprintln(os.Stderr)
+			}()
+		}
+	}
+	return prompt(label)
+}
+
+func stty(args ...string) error {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = os.Stdin
+	return cmd.Run()
+}
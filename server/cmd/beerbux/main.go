@@ -0,0 +1,110 @@
+// Command beerbux is a command-line client for the Beerbux API. It logs in with a username
+// and password, or a personal access token, and stores the tokens in the user's config
+// directory so that later commands are authenticated.
+//
+// Sessions can be given by ID or by name. Members are given by username. BEERBUX_SERVER sets
+// the server to use and BEERBUX_TOKEN a personal access token to use instead of logging in.
+//
+// Usage:
+//
+//	beerbux [-server url] [-json] login [-username name] [-password-stdin] [-token pat]
+//	beerbux logout
+//	beerbux whoami
+//	beerbux sessions [-limit n]
+//	beerbux session <session>
+//	beerbux round [-amount n] <session> <username>...
+//	beerbux add-member <session> <username>
+//	beerbux tail [session]
+//	beerbux export [-format csv|json] [-o file] <session>
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+)
+
+// defaultServer is used until the user logs in to another server.
+const defaultServer = "http://localhost:8080"
+
+func main() {
+	server := flag.String("server", os.Getenv("BEERBUX_SERVER"), "Beerbux server URL, defaults to the server logged in to")
+	jsonOutput := flag.Bool("json", false, "print the API response as JSON rather than a table")
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+
+	creds, err := loadCredentials()
+	if err != nil {
+		fatal(err)
+	}
+	if *server != "" && *server != creds.Server {
+		// The stored tokens were issued by another server.
+		creds = &credentials{Server: *server}
+	}
+	if token := os.Getenv("BEERBUX_TOKEN"); token != "" {
+		creds = &credentials{Server: creds.Server, AccessToken: token, fromEnv: true}
+	}
+	if creds.Server == "" {
+		creds.Server = defaultServer
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	c := newClient(creds)
+	out := newOutput(*jsonOutput)
+	args := flag.Args()[1:]
+
+	switch flag.Arg(0) {
+	case "login":
+		err = login(ctx, c, args)
+	case "logout":
+		err = logout(ctx, c)
+	case "whoami":
+		err = whoami(ctx, c, out)
+	case "sessions":
+		err = listSessions(ctx, c, out, args)
+	case "session":
+		err = showSession(ctx, c, out, args)
+	case "round":
+		err = round(ctx, c, args)
+	case "add-member":
+		err = addMember(ctx, c, args)
+	case "tail":
+		err = tail(ctx, c, out, args)
+	case "export":
+		err = export(ctx, c, args)
+	default:
+		usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		fatal(err)
+	}
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: beerbux [-server url] [-json] login [-username name] [-password-stdin] [-token pat]")
+	fmt.Fprintln(os.Stderr, "       beerbux logout")
+	fmt.Fprintln(os.Stderr, "       beerbux whoami")
+	fmt.Fprintln(os.Stderr, "       beerbux sessions [-limit n]")
+	fmt.Fprintln(os.Stderr, "       beerbux session <session>")
+	fmt.Fprintln(os.Stderr, "       beerbux round [-amount n] <session> <username>...")
+	fmt.Fprintln(os.Stderr, "       beerbux add-member <session> <username>")
+	fmt.Fprintln(os.Stderr, "       beerbux tail [session]")
+	fmt.Fprintln(os.Stderr, "       beerbux export [-format csv|json] [-o file] <session>")
+	fmt.Fprintln(os.Stderr)
+	fmt.Fprintln(os.Stderr, "Sessions can be given by ID or name. BEERBUX_SERVER sets the server and BEERBUX_TOKEN a personal access token to use instead of logging in.")
+}
+
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, "beerbux:", err)
+	os.Exit(1)
+}
@@ -0,0 +1,39 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"strconv"
+	"text/tabwriter"
+)
+
+// output prints results as tables, or as indented JSON when -json is given.
+type output struct {
+	json bool
+}
+
+func newOutput(json bool) *output {
+	return &output{json: json}
+}
+
+func (o *output) printJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
+
+func (o *output) table() *tabwriter.Writer {
+	return tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+}
+
+func formatBeers(beers float64) string {
+	return strconv.FormatFloat(beers, 'f', -1, 64)
+}
+
+// formatBalance signs positive balances, which are owed to the member.
+func formatBalance(balance float64) string {
+	if balance > 0 {
+		return "+" + formatBeers(balance)
+	}
+	return formatBeers(balance)
+}
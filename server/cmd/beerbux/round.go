@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/google/uuid"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// round buys each of the members, given by username, a number of beers in the session.
+func round(ctx context.Context, c *client, args []string) error {
+	fs := flag.NewFlagSet("round", flag.ExitOnError)
+	amount := fs.Float64("amount", 1, "number of beers bought for each member")
+	_ = fs.Parse(args)
+
+	if fs.NArg() < 2 {
+		return errors.New("round requires a session and the usernames of the members in the round")
+	}
+	if *amount <= 0 {
+		return errors.New("amount must be greater than zero")
+	}
+
+	session, err := getSession(ctx, c, fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	userID, err := currentUserID(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	lines := make(map[uuid.UUID]float64)
+	names := make([]string, 0, fs.NArg()-1)
+	for _, username := range fs.Args()[1:] {
+		username = strings.TrimPrefix(username, "@")
+		m, ok := session.memberByUsername(username)
+		if !ok {
+			return fmt.Errorf("%s is not a member of %s", username, session.Name)
+		}
+		if m.ID == userID {
+			return errors.New("you cannot buy a round for yourself")
+		}
+		if _, ok := lines[m.ID]; ok {
+			continue
+		}
+		lines[m.ID] = *amount
+		names = append(names, m.Name)
+	}
+
+	if err := c.post(ctx, "/session/"+url.PathEscape(session.ID.String())+"/transaction", lines, nil); err != nil {
+		return err
+	}
+	fmt.Printf("Bought %s for %s in %s\n", beersEach(*amount), strings.Join(names, ", "), session.Name)
+	return nil
+}
+
+// addMember adds a user to the session. Only admins of the session can add members.
+func addMember(ctx context.Context, c *client, args []string) error {
+	if len(args) != 2 {
+		return errors.New("add-member requires a session and the username of the user to add")
+	}
+	sessionID, err := resolveSession(ctx, c, args[0])
+	if err != nil {
+		return err
+	}
+	username := strings.TrimPrefix(args[1], "@")
+
+	res, err := c.send(ctx, http.MethodPost, "/session/"+url.PathEscape(sessionID.String())+"/member", map[string]string{"username": username})
+	if err != nil {
+		return err
+	}
+	res.Body.Close()
+
+	if res.StatusCode == http.StatusCreated {
+		fmt.Printf("Added %s to the session\n", username)
+	} else {
+		fmt.Printf("%s is already a member of the session\n", username)
+	}
+	return nil
+}
+
+// currentUserID returns the ID of the logged in user, asking the API when it was not stored at login.
+func currentUserID(ctx context.Context, c *client) (uuid.UUID, error) {
+	if c.creds.User != nil {
+		return c.creds.User.ID, nil
+	}
+	var user currentUser
+	if err := c.get(ctx, "/user", &user); err != nil {
+		return uuid.Nil, err
+	}
+	c.creds.User = &userInfo{ID: user.ID, Name: user.Name, Username: user.Username}
+	return user.ID, nil
+}
+
+func beersEach(beers float64) string {
+	if beers == 1 {
+		return "a beer each"
+	}
+	return formatBeers(beers) + " beers each"
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/google/uuid"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type sessionSummary struct {
+	ID       uuid.UUID `json:"id"`
+	Name     string    `json:"name"`
+	Total    float64   `json:"total"`
+	IsActive bool      `json:"isActive"`
+	Members  []struct {
+		ID       uuid.UUID `json:"id"`
+		Name     string    `json:"name"`
+		Username string    `json:"username"`
+	} `json:"members"`
+}
+
+type sessionDetail struct {
+	ID           uuid.UUID            `json:"id"`
+	Name         string               `json:"name"`
+	Total        float64              `json:"total"`
+	IsActive     bool                 `json:"isActive"`
+	Members      []sessionMember      `json:"members"`
+	Transactions []sessionTransaction `json:"transactions"`
+}
+
+type sessionMember struct {
+	ID                 uuid.UUID `json:"id"`
+	Name               string    `json:"name"`
+	Username           string    `json:"username"`
+	IsAdmin            bool      `json:"isAdmin"`
+	IsDeleted          bool      `json:"isDeleted"`
+	TransactionSummary struct {
+		// Debit is the number of beers the member has bought and Credit the number they have received.
+		Credit float64 `json:"credit"`
+		Debit  float64 `json:"debit"`
+	} `json:"transactionSummary"`
+}
+
+// Balance is positive when the member has bought more beers than they have received.
+func (m sessionMember) Balance() float64 {
+	return m.TransactionSummary.Debit - m.TransactionSummary.Credit
+}
+
+type sessionTransaction struct {
+	ID     uuid.UUID `json:"id"`
+	UserID uuid.UUID `json:"userId"`
+	Total  float64   `json:"total"`
+	Lines  []struct {
+		UserID uuid.UUID `json:"userId"`
+		Amount float64   `json:"amount"`
+	} `json:"lines"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// recentTransactions is the number of transactions shown with a session.
+const recentTransactions = 10
+
+func listSessions(ctx context.Context, c *client, out *output, args []string) error {
+	fs := flag.NewFlagSet("sessions", flag.ExitOnError)
+	limit := fs.Int("limit", 0, "maximum number of sessions to list")
+	_ = fs.Parse(args)
+
+	path := "/user/sessions"
+	if *limit > 0 {
+		path += "?page_size=" + strconv.Itoa(*limit)
+	}
+	var sessions []sessionSummary
+	if err := c.get(ctx, path, &sessions); err != nil {
+		return err
+	}
+	if out.json {
+		return out.printJSON(sessions)
+	}
+
+	tw := out.table()
+	fmt.Fprintln(tw, "ID\tNAME\tSTATUS\tMEMBERS\tTOTAL")
+	for _, s := range sessions {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%s\n", s.ID, s.Name, sessionStatus(s.IsActive), len(s.Members), formatBeers(s.Total))
+	}
+	return tw.Flush()
+}
+
+// showSession prints the session with the balance of each member and the most recent transactions.
+func showSession(ctx context.Context, c *client, out *output, args []string) error {
+	if len(args) != 1 {
+		return errors.New("session requires a session ID or name")
+	}
+	session, err := getSession(ctx, c, args[0])
+	if err != nil {
+		return err
+	}
+	if out.json {
+		return out.printJSON(session)
+	}
+
+	fmt.Printf("ID:     %s\n", session.ID)
+	fmt.Printf("Name:   %s\n", session.Name)
+	fmt.Printf("Status: %s\n", sessionStatus(session.IsActive))
+	fmt.Printf("Total:  %s\n", formatBeers(session.Total))
+	fmt.Println()
+
+	members := slices.Clone(session.Members)
+	slices.SortFunc(members, func(a, b sessionMember) int {
+		return cmp.Or(cmp.Compare(a.Balance(), b.Balance()), cmp.Compare(a.Name, b.Name))
+	})
+
+	tw := out.table()
+	fmt.Fprintln(tw, "NAME\tUSERNAME\tROLE\tBOUGHT\tRECEIVED\tBALANCE")
+	for _, m := range members {
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			m.Name, m.Username, memberRole(m), formatBeers(m.TransactionSummary.Debit), formatBeers(m.TransactionSummary.Credit), formatBalance(m.Balance()))
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if len(session.Transactions) == 0 {
+		return nil
+	}
+	transactions := slices.SortedFunc(slices.Values(session.Transactions), func(a, b sessionTransaction) int {
+		return b.CreatedAt.Compare(a.CreatedAt)
+	})
+	transactions = transactions[:min(len(transactions), recentTransactions)]
+
+	fmt.Println()
+	tw = out.table()
+	fmt.Fprintln(tw, "TIME\tBOUGHT BY\tFOR\tTOTAL")
+	for _, t := range transactions {
+		names := make([]string, 0, len(t.Lines))
+		for _, l := range t.Lines {
+			names = append(names, session.memberName(l.UserID))
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n",
+			t.CreatedAt.Local().Format(time.DateTime), session.memberName(t.UserID), strings.Join(names, ", "), formatBeers(t.Total))
+	}
+	return tw.Flush()
+}
+
+// getSession fetches the session given by ID or by name.
+func getSession(ctx context.Context, c *client, ref string) (*sessionDetail, error) {
+	sessionID, err := resolveSession(ctx, c, ref)
+	if err != nil {
+		return nil, err
+	}
+	var session sessionDetail
+	if err := c.get(ctx, "/session/"+url.PathEscape(sessionID.String()), &session); err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+// resolveSession returns the ID of the session given by ID, or by the name of one of the user's
+// sessions. Names are matched case-insensitively and must name a single session.
+func resolveSession(ctx context.Context, c *client, ref string) (uuid.UUID, error) {
+	if id, err := uuid.Parse(ref); err == nil {
+		return id, nil
+	}
+
+	var sessions []sessionSummary
+	if err := c.get(ctx, "/user/sessions", &sessions); err != nil {
+		return uuid.Nil, err
+	}
+	var matches []sessionSummary
+	for _, s := range sessions {
+		if strings.EqualFold(s.Name, ref) {
+			matches = append(matches, s)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return uuid.Nil, fmt.Errorf("you are not a member of a session called %q", ref)
+	case 1:
+		return matches[0].ID, nil
+	default:
+		return uuid.Nil, fmt.Errorf("%d sessions are called %q, use the session ID instead", len(matches), ref)
+	}
+}
+
+func (s *sessionDetail) member(id uuid.UUID) (sessionMember, bool) {
+	for _, m := range s.Members {
+		if m.ID == id {
+			return m, true
+		}
+	}
+	return sessionMember{}, false
+}
+
+func (s *sessionDetail) memberByUsername(username string) (sessionMember, bool) {
+	for _, m := range s.Members {
+		if !m.IsDeleted && strings.EqualFold(m.Username, username) {
+			return m, true
+		}
+	}
+	return sessionMember{}, false
+}
+
+func (s *sessionDetail) memberName(id uuid.UUID) string {
+	if m, ok := s.member(id); ok {
+		return m.Name
+	}
+	return id.String()
+}
+
+func sessionStatus(isActive bool) string {
+	if isActive {
+		return "active"
+	}
+	return "closed"
+}
+
+func memberRole(m sessionMember) string {
+	switch {
+	case m.IsDeleted:
+		return "left"
+	case m.IsAdmin:
+		return "admin"
+	default:
+		return "member"
+	}
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// event is a server-sent event from one of the event streams.
+type event struct {
+	Event string          `json:"event"`
+	Data  json.RawMessage `json:"data"`
+}
+
+type transactionCreatedMessage struct {
+	TransactionID string  `json:"transactionId"`
+	CreatorID     string  `json:"creatorId"`
+	Total         float64 `json:"total"`
+}
+
+type notificationMessage struct {
+	Type    string `json:"type"`
+	Session struct {
+		Name string `json:"name"`
+	} `json:"session"`
+	Actor struct {
+		Name string `json:"name"`
+	} `json:"actor"`
+	Amount *float64 `json:"amount"`
+}
+
+// tail prints the live events of the session, or the user's notifications when no session is
+// given, until interrupted. With -json each event is printed as a line of JSON.
+func tail(ctx context.Context, c *client, out *output, args []string) error {
+	if len(args) > 1 {
+		return errors.New("tail takes at most one session")
+	}
+
+	path := "/events/notifications"
+	var session *sessionDetail
+	if len(args) == 1 {
+		var err error
+		if session, err = getSession(ctx, c, args[0]); err != nil {
+			return err
+		}
+		userID, err := currentUserID(ctx, c)
+		if err != nil {
+			return err
+		}
+		path = "/events/session?" + url.Values{
+			"session_id": {session.ID.String()},
+			"user_id":    {userID.String()},
+		}.Encode()
+	}
+
+	res, err := c.send(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if session != nil {
+		fmt.Fprintf(os.Stderr, "Watching %s, press Ctrl+C to stop\n", session.Name)
+	} else {
+		fmt.Fprintln(os.Stderr, "Watching your notifications, press Ctrl+C to stop")
+	}
+
+	err = readEvents(res, func(e event) error {
+		if e.Event == "heartbeat" {
+			return nil
+		}
+		if out.json {
+			return json.NewEncoder(os.Stdout).Encode(e)
+		}
+		fmt.Printf("%s  %s  %s\n", time.Now().Format(time.TimeOnly), e.Event, describeEvent(e, session))
+		return nil
+	})
+	if ctx.Err() != nil {
+		return nil
+	}
+	return err
+}
+
+// readEvents calls fn with each event read from the stream until it ends.
+func readEvents(res *http.Response, fn func(event) error) error {
+	scanner := bufio.NewScanner(res.Body)
+	scanner.Buffer(make([]byte, 0, 64<<10), 1<<20)
+
+	var e event
+	var data []string
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" {
+			if e.Event != "" || len(data) > 0 {
+				e.Data = json.RawMessage(strings.Join(data, "\n"))
+				if err := fn(e); err != nil {
+					return err
+				}
+			}
+			e, data = event{}, nil
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "event":
+			e.Event = value
+		case "data":
+			data = append(data, value)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("event stream closed: %w", err)
+	}
+	return errors.New("event stream closed by the server")
+}
+
+func describeEvent(e event, session *sessionDetail) string {
+	switch e.Event {
+	case "session.transaction.created":
+		var m transactionCreatedMessage
+		if json.Unmarshal(e.Data, &m) == nil && session != nil {
+			creator := m.CreatorID
+			if id, err := uuid.Parse(m.CreatorID); err == nil {
+				creator = session.memberName(id)
+			}
+			return fmt.Sprintf("%s bought %s beers", creator, formatBeers(m.Total))
+		}
+	case "notification.created":
+		var m notificationMessage
+		if json.Unmarshal(e.Data, &m) == nil {
+			s := fmt.Sprintf("%s: %s in %s", m.Type, m.Actor.Name, m.Session.Name)
+			if m.Amount != nil {
+				s += fmt.Sprintf(" (%s)", formatBeers(*m.Amount))
+			}
+			return s
+		}
+	}
+	return string(e.Data)
+}
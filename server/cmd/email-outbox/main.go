@@ -0,0 +1,171 @@
+// Command email-outbox inspects the email outbox and queues failed emails to be sent again.
+// It connects to the database named by the -db flag, which defaults to DB_URI.
+//
+// Usage:
+//
+//	email-outbox list [-status failed] [-limit 50]
+//	email-outbox show <id>
+//	email-outbox resend <id>
+//	email-outbox resend -failed
+package main
+
+import (
+	"beerbux/internal/emailoutbox"
+	"beerbux/internal/emailoutbox/db"
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"github.com/google/uuid"
+	_ "github.com/lib/pq"
+	"os"
+	"text/tabwriter"
+	"time"
+)
+
+func main() {
+	dbURI := flag.String("db", os.Getenv("DB_URI"), "database connection string")
+	flag.Usage = usage
+	flag.Parse()
+
+	if flag.NArg() == 0 || *dbURI == "" {
+		usage()
+		os.Exit(2)
+	}
+
+	database, err := sql.Open("postgres", *dbURI)
+	if err != nil {
+		fatal(err)
+	}
+	defer database.Close()
+
+	queries := db.New(database)
+	ctx := context.Background()
+	args := flag.Args()[1:]
+
+	switch flag.Arg(0) {
+	case "list":
+		err = list(ctx, queries, args)
+	case "show":
+		err = show(ctx, queries, args)
+	case "resend":
+		err = resend(ctx, emailoutbox.New(queries), args)
+	default:
+		usage()
+		os.Exit(2)
+	}
+	if err != nil {
+		fatal(err)
+	}
+}
+
+func list(ctx context.Context, queries *db.Queries, args []string) error {
+	fs := flag.NewFlagSet("list", flag.ExitOnError)
+	status := fs.String("status", "", "only list emails with this status: pending, sending, sent or failed")
+	limit := fs.Int("limit", 50, "maximum number of emails to list")
+	_ = fs.Parse(args)
+
+	emails, err := queries.ListEmails(ctx, db.ListEmailsParams{
+		Status:     sql.NullString{String: *status, Valid: *status != ""},
+		MaxResults: int32(*limit),
+	})
+	if err != nil {
+		return err
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ID\tSTATUS\tATTEMPTS\tRECIPIENT\tSUBJECT\tCREATED\tLAST ERROR")
+	for _, e := range emails {
+		fmt.Fprintf(tw, "%s\t%s\t%d\t%s\t%s\t%s\t%s\n",
+			e.ID, e.Status, e.Attempts, e.Recipient, e.Subject, e.CreatedAt.Format(time.DateTime), e.LastError.String)
+	}
+	return tw.Flush()
+}
+
+func show(ctx context.Context, queries *db.Queries, args []string) error {
+	if len(args) != 1 {
+		return errors.New("show requires an email ID")
+	}
+	id, err := uuid.Parse(args[0])
+	if err != nil {
+		return fmt.Errorf("invalid email ID: %w", err)
+	}
+
+	e, err := queries.GetEmail(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return errors.New("email not found")
+		}
+		return err
+	}
+	attempts, err := queries.ListEmailDeliveryAttempts(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("ID:          %s\n", e.ID)
+	fmt.Printf("Recipient:   %s\n", e.Recipient)
+	fmt.Printf("Subject:     %s\n", e.Subject)
+	fmt.Printf("Status:      %s\n", e.Status)
+	fmt.Printf("Attempts:    %d\n", e.Attempts)
+	fmt.Printf("Created:     %s\n", e.CreatedAt.Format(time.DateTime))
+	if e.SentAt.Valid {
+		fmt.Printf("Sent:        %s\n", e.SentAt.Time.Format(time.DateTime))
+	}
+	if e.ProviderMessageID.Valid {
+		fmt.Printf("Provider ID: %s\n", e.ProviderMessageID.String)
+	}
+	fmt.Println()
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ATTEMPT\tTIME\tRESULT\tPROVIDER ID\tERROR")
+	for _, a := range attempts {
+		result := "failed"
+		if a.Succeeded {
+			result = "sent"
+		}
+		fmt.Fprintf(tw, "%d\t%s\t%s\t%s\t%s\n",
+			a.Attempt, a.CreatedAt.Format(time.DateTime), result, a.ProviderMessageID.String, a.Error.String)
+	}
+	return tw.Flush()
+}
+
+func resend(ctx context.Context, outbox *emailoutbox.Outbox, args []string) error {
+	fs := flag.NewFlagSet("resend", flag.ExitOnError)
+	allFailed := fs.Bool("failed", false, "resend every failed email")
+	_ = fs.Parse(args)
+
+	if *allFailed {
+		n, err := outbox.ResendFailed(ctx)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Queued %d failed emails to be sent again\n", n)
+		return nil
+	}
+
+	if fs.NArg() != 1 {
+		return errors.New("resend requires an email ID or -failed")
+	}
+	id, err := uuid.Parse(fs.Arg(0))
+	if err != nil {
+		return fmt.Errorf("invalid email ID: %w", err)
+	}
+	if err := outbox.Resend(ctx, id); err != nil {
+		return err
+	}
+	fmt.Printf("Queued %s to be sent again\n", id)
+	return nil
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: email-outbox [-db uri] list [-status status] [-limit n]")
+	fmt.Fprintln(os.Stderr, "       email-outbox [-db uri] show <id>")
+	fmt.Fprintln(os.Stderr, "       email-outbox [-db uri] resend <id> | -failed")
+}
+
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, "email-outbox:", err)
+	os.Exit(1)
+}
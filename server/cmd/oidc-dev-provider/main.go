@@ -0,0 +1,301 @@
+// Command oidc-dev-provider runs a minimal OpenID Connect provider for developing and
+// testing the OIDC login flow locally. It supports discovery, the authorization code flow
+// with S256 PKCE and a JWKS endpoint. The user to log in as is entered in a form, so it
+// must never be exposed publicly.
+//
+// Configure the API with:
+//
+//	OIDC_PROVIDERS=local
+//	OIDC_LOCAL_ISSUER=http://localhost:9096
+//	OIDC_LOCAL_CLIENT_ID=beerbux
+//	OIDC_LOCAL_DISPLAY_NAME=Local
+package main
+
+import (
+	"beerbux/pkg/jwtkeys"
+	"beerbux/pkg/oidc"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"flag"
+	"github.com/golang-jwt/jwt/v4"
+	"html/template"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	codeTimeToLive    = time.Minute
+	idTokenTimeToLive = 5 * time.Minute
+)
+
+type authorizationCode struct {
+	clientID      string
+	redirectURI   string
+	codeChallenge string
+	nonce         string
+	subject       string
+	email         string
+	emailVerified bool
+	name          string
+	expiresAt     time.Time
+}
+
+type provider struct {
+	issuer   string
+	clientID string
+	keys     *jwtkeys.KeySet
+	logger   *slog.Logger
+
+	mu    sync.Mutex
+	codes map[string]authorizationCode
+}
+
+func main() {
+	addr := flag.String("addr", "localhost:9096", "address to listen on")
+	issuer := flag.String("issuer", "http://localhost:9096", "issuer URL, which must match the address the API reaches the provider at")
+	clientID := flag.String("client-id", "beerbux", "client ID accepted by the provider")
+	flag.Parse()
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	keys, err := generateKeySet()
+	if err != nil {
+		logger.Error("Failed to generate signing key", "error", err)
+		os.Exit(1)
+	}
+
+	p := &provider{
+		issuer:   *issuer,
+		clientID: *clientID,
+		keys:     keys,
+		logger:   logger,
+		codes:    make(map[string]authorizationCode),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /.well-known/openid-configuration", p.discovery)
+	mux.HandleFunc("GET /authorize", p.authorizeForm)
+	mux.HandleFunc("POST /authorize", p.authorize)
+	mux.HandleFunc("POST /token", p.token)
+	mux.HandleFunc("GET /jwks", p.jwks)
+
+	logger.Info("Starting OIDC development provider", "address", *addr, "issuer", *issuer, "clientId", *clientID)
+	if err := http.ListenAndServe(*addr, mux); err != nil {
+		logger.Error("Failed to start provider", "error", err)
+		os.Exit(1)
+	}
+}
+
+// generateKeySet creates a new Ed25519 signing key each time the provider starts.
+func generateKeySet() (*jwtkeys.KeySet, error) {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return nil, err
+	}
+	key, err := jwtkeys.ParseKey(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}))
+	if err != nil {
+		return nil, err
+	}
+	return jwtkeys.NewKeySet(jwtkeys.Options{SigningKey: key})
+}
+
+func (p *provider) discovery(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, map[string]any{
+		"issuer":                                p.issuer,
+		"authorization_endpoint":                p.issuer + "/authorize",
+		"token_endpoint":                        p.issuer + "/token",
+		"jwks_uri":                              p.issuer + "/jwks",
+		"response_types_supported":              []string{"code"},
+		"subject_types_supported":               []string{"public"},
+		"id_token_signing_alg_values_supported": p.keys.Algorithms(),
+		"code_challenge_methods_supported":      []string{"S256"},
+		"scopes_supported":                      []string{"openid", "email", "profile"},
+	}, http.StatusOK)
+}
+
+func (p *provider) jwks(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, p.keys.JWKS(), http.StatusOK)
+}
+
+var authorizeTemplate = template.Must(template.New("authorize").Parse(`<!doctype html>
+<html>
+<head><title>OIDC development provider</title></head>
+<body>
+<h1>Log in to the development provider</h1>
+<form method="post" action="/authorize">
+	{{range $name, $value := .Params}}<input type="hidden" name="{{$name}}" value="{{$value}}">
+	{{end}}
+	<p><label>Subject <input name="sub" value="dev-user-1" required></label></p>
+	<p><label>Email <input name="email" type="email" value="dev@example.com"></label></p>
+	<p><label>Name <input name="name" value="Dev User"></label></p>
+	<p><label><input name="email_verified" type="checkbox" value="true" checked> Email verified</label></p>
+	<p><button name="action" value="allow">Log in</button> <button name="action" value="deny">Deny</button></p>
+</form>
+</body>
+</html>
+`))
+
+// authorizeParams are the authorization request parameters carried through the login form.
+var authorizeParams = []string{"client_id", "redirect_uri", "state", "nonce", "code_challenge", "code_challenge_method"}
+
+func (p *provider) authorizeForm(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	if q.Get("response_type") != "code" {
+		http.Error(w, "unsupported response_type", http.StatusBadRequest)
+		return
+	}
+	if msg := p.validateAuthorizeRequest(q); msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+
+	params := make(map[string]string, len(authorizeParams))
+	for _, name := range authorizeParams {
+		params[name] = q.Get(name)
+	}
+
+	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	if err := authorizeTemplate.Execute(w, map[string]any{"Params": params}); err != nil {
+		p.logger.Error("Failed to render login form", "error", err)
+	}
+}
+
+func (p *provider) authorize(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, "invalid form", http.StatusBadRequest)
+		return
+	}
+	if msg := p.validateAuthorizeRequest(r.PostForm); msg != "" {
+		http.Error(w, msg, http.StatusBadRequest)
+		return
+	}
+
+	redirectURI, err := url.Parse(r.PostForm.Get("redirect_uri"))
+	if err != nil {
+		http.Error(w, "invalid redirect_uri", http.StatusBadRequest)
+		return
+	}
+	q := redirectURI.Query()
+	q.Set("state", r.PostForm.Get("state"))
+
+	if r.PostForm.Get("action") != "allow" {
+		q.Set("error", "access_denied")
+	} else {
+		code := oidc.RandomString()
+		p.mu.Lock()
+		p.codes[code] = authorizationCode{
+			clientID:      r.PostForm.Get("client_id"),
+			redirectURI:   r.PostForm.Get("redirect_uri"),
+			codeChallenge: r.PostForm.Get("code_challenge"),
+			nonce:         r.PostForm.Get("nonce"),
+			subject:       r.PostForm.Get("sub"),
+			email:         r.PostForm.Get("email"),
+			emailVerified: r.PostForm.Get("email_verified") == "true",
+			name:          r.PostForm.Get("name"),
+			expiresAt:     time.Now().Add(codeTimeToLive),
+		}
+		p.mu.Unlock()
+		q.Set("code", code)
+	}
+
+	redirectURI.RawQuery = q.Encode()
+	http.Redirect(w, r, redirectURI.String(), http.StatusFound)
+}
+
+func (p *provider) validateAuthorizeRequest(params url.Values) string {
+	switch {
+	case params.Get("client_id") != p.clientID:
+		return "unknown client_id"
+	case params.Get("redirect_uri") == "":
+		return "redirect_uri is required"
+	case params.Get("code_challenge") == "" || params.Get("code_challenge_method") != "S256":
+		return "S256 PKCE is required"
+	default:
+		return ""
+	}
+}
+
+func (p *provider) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		tokenError(w, "invalid_request")
+		return
+	}
+	if r.PostForm.Get("grant_type") != "authorization_code" {
+		tokenError(w, "unsupported_grant_type")
+		return
+	}
+
+	clientID := r.PostForm.Get("client_id")
+	if basicClientID, _, ok := r.BasicAuth(); ok {
+		clientID, _ = url.QueryUnescape(basicClientID)
+	}
+
+	// Codes are single use, so the code is removed before it is validated.
+	p.mu.Lock()
+	code, ok := p.codes[r.PostForm.Get("code")]
+	delete(p.codes, r.PostForm.Get("code"))
+	p.mu.Unlock()
+
+	challenge := oidc.CodeChallenge(r.PostForm.Get("code_verifier"))
+	if !ok ||
+		time.Now().After(code.expiresAt) ||
+		code.clientID != clientID ||
+		code.redirectURI != r.PostForm.Get("redirect_uri") ||
+		subtle.ConstantTimeCompare([]byte(challenge), []byte(code.codeChallenge)) != 1 {
+		tokenError(w, "invalid_grant")
+		return
+	}
+
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":                p.issuer,
+		"sub":                code.subject,
+		"aud":                code.clientID,
+		"iat":                now.Unix(),
+		"exp":                now.Add(idTokenTimeToLive).Unix(),
+		"nonce":              code.nonce,
+		"name":               code.name,
+		"email_verified":     code.emailVerified,
+		"preferred_username": code.subject,
+	}
+	if code.email != "" {
+		claims["email"] = code.email
+	}
+
+	idToken, err := p.keys.Sign(claims)
+	if err != nil {
+		p.logger.Error("Failed to sign ID token", "error", err)
+		tokenError(w, "server_error")
+		return
+	}
+
+	writeJSON(w, map[string]any{
+		"access_token": oidc.RandomString(),
+		"token_type":   "Bearer",
+		"expires_in":   int(idTokenTimeToLive.Seconds()),
+		"id_token":     idToken,
+	}, http.StatusOK)
+}
+
+func tokenError(w http.ResponseWriter, code string) {
+	writeJSON(w, map[string]string{"error": code}, http.StatusBadRequest)
+}
+
+func writeJSON(w http.ResponseWriter, v any, status int) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -3,7 +3,18 @@ package api
 import (
 	"beerbux/internal/api/config"
 	"beerbux/internal/api/database"
+	"beerbux/internal/auth/command"
+	authQueries "beerbux/internal/auth/db"
+	"beerbux/internal/emailoutbox"
+	emailOutboxQueries "beerbux/internal/emailoutbox/db"
+	"beerbux/internal/notifications"
+	notificationsQueries "beerbux/internal/notifications/db"
 	"beerbux/internal/sse"
+	"beerbux/internal/webhooks"
+	webhooksQueries "beerbux/internal/webhooks/db"
+	"beerbux/pkg/email"
+	"beerbux/pkg/webhook"
+	"beerbux/pkg/webpush"
 	"context"
 	"database/sql"
 	"log/slog"
@@ -13,11 +24,20 @@ import (
 	"time"
 )
 
+// refreshTokenPurgeInterval is how often expired and revoked refresh tokens are deleted.
+const refreshTokenPurgeInterval = time.Hour
+
 type App struct {
 	Config      *config.Config
 	Logger      *slog.Logger
 	DB          *sql.DB
 	messageChan chan *sse.Message
+	// emailProvider delivers emails; everything else sends through emailOutbox.
+	emailProvider email.Sender
+	emailOutbox   *emailoutbox.Outbox
+	// pushDispatcher sends notifications with Web Push; it does nothing when Web Push is disabled.
+	pushDispatcher *notifications.PushDispatcher
+	webhookOutbox  *webhooks.Outbox
 }
 
 func NewApp(cfg *config.Config, logger *slog.Logger) (*App, error) {
@@ -28,11 +48,28 @@ func NewApp(cfg *config.Config, logger *slog.Logger) (*App, error) {
 		return nil, err
 	}
 
+	email.SetTemplateDir(cfg.Email.TemplateDir)
+	emailProvider, err := email.New(cfg.Email, logger)
+	if err != nil {
+		return nil, err
+	}
+
+	var pushClient *webpush.Client
+	if cfg.WebPush.Enabled() {
+		pushClient = webpush.NewClient(cfg.WebPush.Keys, cfg.WebPush.Subject, cfg.Environment.IsDevelopment())
+	} else {
+		logger.Info("Web Push is disabled as VAPID_PRIVATE_KEY is not set")
+	}
+
 	return &App{
-		Config:      cfg,
-		Logger:      logger,
-		DB:          db,
-		messageChan: make(chan *sse.Message, 10),
+		Config:         cfg,
+		Logger:         logger,
+		DB:             db,
+		messageChan:    make(chan *sse.Message, 10),
+		emailProvider:  emailProvider,
+		emailOutbox:    emailoutbox.New(emailOutboxQueries.New(db)),
+		pushDispatcher: notifications.NewPushDispatcher(notificationsQueries.New(db), pushClient, cfg.CORSClientBaseURL, logger),
+		webhookOutbox:  webhooks.NewOutbox(webhooksQueries.New(db)),
 	}, nil
 }
 
@@ -56,6 +93,18 @@ func (app *App) Start() error {
 		errChan <- server.ListenAndServe()
 	}()
 
+	go app.purgeRefreshTokens(ctx)
+	go emailoutbox.NewWorker(emailOutboxQueries.New(app.DB), app.emailProvider, app.emailOutbox.Queued(), app.Logger).Run(ctx)
+	// Webhooks may only be sent to private networks in development, where receivers run locally.
+	webhookClient := webhook.NewClient(app.Config.Environment.IsDevelopment())
+	go webhooks.NewWorker(webhooksQueries.New(app.DB), webhookClient, app.webhookOutbox.Queued(), app.Logger).Run(ctx)
+	if app.Config.Notifications.Enabled {
+		go notifications.NewScheduler(notificationsQueries.New(app.DB), app.emailOutbox, app.Config.CORSClientBaseURL, app.Config.Notifications, app.Logger).Run(ctx)
+	}
+	if app.Config.WebPush.Enabled() {
+		go app.pushDispatcher.Run(ctx)
+	}
+
 	hb := time.NewTicker(time.Duration(app.Config.StreamService.HeartbeatTickerSeconds) * time.Second)
 	app.Logger.Debug("Starting API server", "addr", app.Config.Address)
 
@@ -65,7 +114,7 @@ func (app *App) Start() error {
 			streamServer.Heartbeat()
 		case msg := <-app.messageChan:
 			switch msg.Topic {
-			case "session.transaction.created":
+			case "session.transaction.created", "notification.created":
 				streamServer.BroadcastMessageToRoom(msg.Key, msg)
 			default:
 				app.Logger.Error("Unknown message topic", "topic", msg.Topic)
@@ -80,6 +129,27 @@ func (app *App) Start() error {
 	}
 }
 
+// purgeRefreshTokens periodically deletes stale refresh tokens until ctx is cancelled.
+func (app *App) purgeRefreshTokens(ctx context.Context) {
+	purgeCommand := command.NewPurgeRefreshTokensCommand(authQueries.New(app.DB))
+	ticker := time.NewTicker(refreshTokenPurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			deleted, err := purgeCommand.Execute(ctx)
+			if err != nil {
+				app.Logger.Error("Failed to purge refresh tokens", "error", err)
+				continue
+			}
+			app.Logger.Debug("Purged refresh tokens", "deleted", deleted)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
 func createNotifyContext() (context.Context, context.CancelFunc) {
 	ctx, cancel := context.WithCancel(context.Background())
 	sigChan := make(chan os.Signal, 1)
@@ -1,10 +1,16 @@
 package config
 
 import (
+	"beerbux/internal/ratelimit"
+	"beerbux/pkg/jwtkeys"
+	"beerbux/pkg/password"
+	"beerbux/pkg/webpush"
 	"fmt"
 	"github.com/joho/godotenv"
+	"golang.org/x/crypto/bcrypt"
 	"log/slog"
 	"os"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
@@ -15,10 +21,19 @@ type Config struct {
 	LogLevel          slog.Level
 	CORSClientBaseURL string
 	Address           string
+	TrustProxy        bool
 	Database          DBConfig
-	Resend            ResendConfig
+	Email             EmailConfig
 	Secrets           SecretConfig
+	JWTKeys           *jwtkeys.KeySet
 	StreamService     StreamServiceConfig
+	RateLimit         RateLimitConfig
+	Notifications     NotificationsConfig
+	WebPush           WebPushConfig
+	Chat              ChatConfig
+	OIDC              []OIDCProviderConfig
+	PasswordHasher    *password.Hasher
+	PasswordPolicy    *password.Policy
 	AccessTokenTTL    time.Duration
 	RefreshTokenTTL   time.Duration
 }
@@ -33,15 +48,103 @@ type DBConfig struct {
 	MigrationDir string
 }
 
+const (
+	EmailProviderResend   = "resend"
+	EmailProviderSMTP     = "smtp"
+	EmailProviderTerminal = "terminal"
+	EmailProviderMailbox  = "mailbox"
+)
+
+type EmailConfig struct {
+	// Provider is resend or smtp, or in development terminal, which prints emails instead of
+	// sending them, or mailbox, which captures them to be read back from /dev/mail.
+	Provider string
+	From     string
+	// TemplateDir optionally holds templates overriding the embedded email templates.
+	TemplateDir string
+	Resend      ResendConfig
+	SMTP        SMTPConfig
+}
+
 type ResendConfig struct {
 	Key                    string
 	DevelopmentSendToEmail string
 }
 
+const (
+	SMTPTLSModeStartTLS = "starttls"
+	SMTPTLSModeImplicit = "tls"
+	SMTPTLSModeNone     = "none"
+)
+
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	// TLSMode is starttls, tls for implicit TLS (usually port 465) or none for local mail catchers.
+	TLSMode string
+}
+
 type StreamServiceConfig struct {
 	HeartbeatTickerSeconds int64
 }
 
+const (
+	RateLimitBackendMemory   = "memory"
+	RateLimitBackendPostgres = "postgres"
+)
+
+// OIDCProviderConfig configures an OpenID Connect provider users can log in with.
+type OIDCProviderConfig struct {
+	// Name identifies the provider in URLs and linked identities, e.g. "google".
+	Name         string
+	DisplayName  string
+	Issuer       string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+	RedirectURL  string
+}
+
+// NotificationsConfig configures the weekly digest and settle-up reminder emails.
+type NotificationsConfig struct {
+	Enabled bool
+	// SettleUpThreshold is the number of beers a user must owe overall before they are reminded to settle up.
+	SettleUpThreshold float64
+	// SettleUpReminderInterval is the minimum time between two settle-up reminders to the same user.
+	SettleUpReminderInterval time.Duration
+}
+
+// WebPushConfig configures Web Push notifications, which are disabled when Keys is nil.
+type WebPushConfig struct {
+	Keys *webpush.VAPIDKeys
+	// Subject is the mailto: or https: URL push services can use to contact us.
+	Subject string
+}
+
+func (c WebPushConfig) Enabled() bool {
+	return c.Keys != nil
+}
+
+// ChatConfig configures the Slack-compatible slash command endpoint, which is disabled when
+// SigningSecret is empty.
+type ChatConfig struct {
+	// SigningSecret verifies that slash commands were sent by the chat workspace.
+	SigningSecret string
+}
+
+func (c ChatConfig) Enabled() bool {
+	return c.SigningSecret != ""
+}
+
+type RateLimitConfig struct {
+	Enabled bool
+	// Backend is either memory or postgres; postgres should be used when running multiple instances.
+	Backend  string
+	Policies ratelimit.Policies
+}
+
 func Load() (*Config, error) {
 	if err := loadFirstEnvFile(".env", "/etc/secrets/.env"); err != nil {
 		return nil, err
@@ -71,26 +174,84 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("invalid HEARTBEAT_INTERVAL_SECONDS: %s", hbIntervalSeconds)
 	}
 
+	// TRUST_PROXY determines if the client IP is taken from the X-Forwarded-For header.
+	// This should only be enabled when the API is exclusively reachable through a reverse proxy.
+	trustProxyValue := getenvDefault("TRUST_PROXY", "false")
+	trustProxy, err := strconv.ParseBool(trustProxyValue)
+	if err != nil {
+		return nil, fmt.Errorf("invalid TRUST_PROXY: %s", trustProxyValue)
+	}
+
+	rateLimit, err := loadRateLimitConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	notifications, err := loadNotificationsConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	clientBaseURL := mustGetenv("CLIENT_BASE_URL")
+	webPush, err := loadWebPushConfig(environment, clientBaseURL)
+	if err != nil {
+		return nil, err
+	}
+
+	oidcProviders, err := loadOIDCProviders(getenvDefault("OIDC_REDIRECT_BASE_URL", clientBaseURL))
+	if err != nil {
+		return nil, err
+	}
+
+	passwordHasher, err := loadPasswordHasher()
+	if err != nil {
+		return nil, err
+	}
+
+	passwordPolicy, err := loadPasswordPolicy()
+	if err != nil {
+		return nil, err
+	}
+
+	emailConfig, err := loadEmailConfig(environment)
+	if err != nil {
+		return nil, err
+	}
+
+	jwtSecret := mustGetenv("JWT_SECRET")
+	jwtKeys, err := loadJWTKeySet(jwtSecret)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Config{
 		Environment:       environment,
 		LogLevel:          getSlogLevel(),
 		Address:           mustGetenv("API_ADDRESS"),
-		CORSClientBaseURL: mustGetenv("CLIENT_BASE_URL"),
+		TrustProxy:        trustProxy,
+		CORSClientBaseURL: clientBaseURL,
 		Database: DBConfig{
 			Driver:       mustGetenv("DB_DRIVER"),
 			URI:          mustGetenv("DB_URI"),
 			MigrationDir: mustGetenv("GOOSE_MIGRATION_DIR"),
 		},
 		Secrets: SecretConfig{
-			JWTSecret: mustGetenv("JWT_SECRET"),
+			JWTSecret: jwtSecret,
 		},
+		JWTKeys: jwtKeys,
 		StreamService: StreamServiceConfig{
 			HeartbeatTickerSeconds: heartbeatIntervalSeconds,
 		},
-		Resend: ResendConfig{
-			Key:                    mustGetenv("RESEND_KEY"),
-			DevelopmentSendToEmail: os.Getenv("RESEND_DEVELOPMENT_SEND_TO_EMAIL"),
+		Chat: ChatConfig{
+			SigningSecret: os.Getenv("SLACK_SIGNING_SECRET"),
 		},
+		RateLimit:       rateLimit,
+		Notifications:   notifications,
+		WebPush:         webPush,
+		OIDC:            oidcProviders,
+		PasswordHasher:  passwordHasher,
+		PasswordPolicy:  passwordPolicy,
+		Email:           emailConfig,
 		AccessTokenTTL:  time.Duration(accessTokenExpirationMinutes) * time.Minute,
 		RefreshTokenTTL: time.Duration(refreshTokenExpirationMinutes) * time.Minute,
 	}, nil
@@ -106,11 +267,314 @@ func (c *Config) SlogLevel() slog.Level {
 func (c *Config) GetAuthOptions() AuthOptions {
 	return AuthOptions{
 		JWTSecret:       c.Secrets.JWTSecret,
+		JWTKeys:         c.JWTKeys,
 		AccessTokenTTL:  c.AccessTokenTTL,
 		RefreshTokenTTL: c.RefreshTokenTTL,
+		PasswordHasher:  c.PasswordHasher,
+		PasswordPolicy:  c.PasswordPolicy,
 	}
 }
 
+// loadRateLimitConfig configures rate limiting. RATE_LIMIT_POLICIES overrides the limit and window
+// of the named default policies as a comma separated list such as "login=20/1m,send-email=3/1h".
+// RATE_LIMIT_ROUTES replaces the policies applied to routes as a semicolon separated list such as
+// "POST /auth/login=login,otp;POST /auth/signup=", where a route without policies is not limited.
+func loadRateLimitConfig() (RateLimitConfig, error) {
+	enabledValue := getenvDefault("RATE_LIMIT_ENABLED", "true")
+	enabled, err := strconv.ParseBool(enabledValue)
+	if err != nil {
+		return RateLimitConfig{}, fmt.Errorf("invalid RATE_LIMIT_ENABLED: %s", enabledValue)
+	}
+
+	backend := strings.ToLower(getenvDefault("RATE_LIMIT_BACKEND", RateLimitBackendMemory))
+	if backend != RateLimitBackendMemory && backend != RateLimitBackendPostgres {
+		return RateLimitConfig{}, fmt.Errorf("invalid RATE_LIMIT_BACKEND: %s", backend)
+	}
+
+	var policyOverrides []ratelimit.PolicyOverride
+	for _, value := range splitList(os.Getenv("RATE_LIMIT_POLICIES")) {
+		name, limitWindow, _ := strings.Cut(value, "=")
+		limitValue, windowValue, _ := strings.Cut(limitWindow, "/")
+		limit, err := strconv.Atoi(limitValue)
+		if err != nil {
+			return RateLimitConfig{}, fmt.Errorf("invalid RATE_LIMIT_POLICIES: %s", value)
+		}
+		window, err := time.ParseDuration(windowValue)
+		if err != nil {
+			return RateLimitConfig{}, fmt.Errorf("invalid RATE_LIMIT_POLICIES: %s", value)
+		}
+		policyOverrides = append(policyOverrides, ratelimit.PolicyOverride{
+			Name:   strings.TrimSpace(name),
+			Limit:  limit,
+			Window: window,
+		})
+	}
+
+	var routeOverrides []ratelimit.RouteOverride
+	for _, value := range strings.Split(os.Getenv("RATE_LIMIT_ROUTES"), ";") {
+		if value = strings.TrimSpace(value); value == "" {
+			continue
+		}
+		pattern, names, ok := strings.Cut(value, "=")
+		if !ok {
+			return RateLimitConfig{}, fmt.Errorf("invalid RATE_LIMIT_ROUTES: %s", value)
+		}
+		routeOverrides = append(routeOverrides, ratelimit.RouteOverride{
+			Pattern:  strings.TrimSpace(pattern),
+			Policies: splitList(names),
+		})
+	}
+
+	policies, err := ratelimit.NewPolicies(policyOverrides, routeOverrides)
+	if err != nil {
+		return RateLimitConfig{}, fmt.Errorf("invalid rate limit configuration: %w", err)
+	}
+
+	return RateLimitConfig{
+		Enabled:  enabled,
+		Backend:  backend,
+		Policies: policies,
+	}, nil
+}
+
+// loadNotificationsConfig configures the scheduled notification emails. NOTIFICATIONS_ENABLED
+// defaults to true, SETTLE_UP_THRESHOLD to 5 beers and SETTLE_UP_REMINDER_INTERVAL_DAYS to 7.
+func loadNotificationsConfig() (NotificationsConfig, error) {
+	enabledValue := getenvDefault("NOTIFICATIONS_ENABLED", "true")
+	enabled, err := strconv.ParseBool(enabledValue)
+	if err != nil {
+		return NotificationsConfig{}, fmt.Errorf("invalid NOTIFICATIONS_ENABLED: %s", enabledValue)
+	}
+
+	thresholdValue := getenvDefault("SETTLE_UP_THRESHOLD", "5")
+	threshold, err := strconv.ParseFloat(thresholdValue, 64)
+	if err != nil || threshold < 0 {
+		return NotificationsConfig{}, fmt.Errorf("invalid SETTLE_UP_THRESHOLD: %s", thresholdValue)
+	}
+
+	intervalDays, err := getenvUint("SETTLE_UP_REMINDER_INTERVAL_DAYS", 7, 16)
+	if err != nil {
+		return NotificationsConfig{}, err
+	}
+	if intervalDays == 0 {
+		return NotificationsConfig{}, fmt.Errorf("invalid SETTLE_UP_REMINDER_INTERVAL_DAYS: %d", intervalDays)
+	}
+
+	return NotificationsConfig{
+		Enabled:                  enabled,
+		SettleUpThreshold:        threshold,
+		SettleUpReminderInterval: time.Duration(intervalDays) * 24 * time.Hour,
+	}, nil
+}
+
+// loadWebPushConfig configures Web Push. VAPID_PRIVATE_KEY is the base64url encoded P-256
+// private key identifying the API to push services; Web Push is disabled when it is not set,
+// except in development where a key pair is generated at startup. VAPID_SUBJECT defaults to
+// the client base URL.
+func loadWebPushConfig(environment Environment, clientBaseURL string) (WebPushConfig, error) {
+	conf := WebPushConfig{
+		Subject: getenvDefault("VAPID_SUBJECT", clientBaseURL),
+	}
+	if !strings.HasPrefix(conf.Subject, "mailto:") && !strings.HasPrefix(conf.Subject, "https://") && !environment.IsDevelopment() {
+		return WebPushConfig{}, fmt.Errorf("invalid VAPID_SUBJECT: %s", conf.Subject)
+	}
+
+	var err error
+	if privateKey := os.Getenv("VAPID_PRIVATE_KEY"); privateKey != "" {
+		conf.Keys, err = webpush.ParseVAPIDPrivateKey(privateKey)
+	} else if environment.IsDevelopment() {
+		conf.Keys, err = webpush.GenerateVAPIDKeys()
+	}
+	if err != nil {
+		return WebPushConfig{}, fmt.Errorf("invalid VAPID_PRIVATE_KEY: %w", err)
+	}
+	return conf, nil
+}
+
+// loadEmailConfig configures how emails are sent. EMAIL_PROVIDER defaults to resend, or to
+// mailbox in development unless RESEND_DEVELOPMENT_SEND_TO_EMAIL is set. The smtp provider
+// is configured with SMTP_HOST, SMTP_PORT, SMTP_USERNAME, SMTP_PASSWORD and SMTP_TLS_MODE.
+func loadEmailConfig(environment Environment) (EmailConfig, error) {
+	resend := ResendConfig{
+		DevelopmentSendToEmail: os.Getenv("RESEND_DEVELOPMENT_SEND_TO_EMAIL"),
+	}
+
+	defaultProvider := EmailProviderResend
+	if environment.IsDevelopment() && resend.DevelopmentSendToEmail == "" {
+		defaultProvider = EmailProviderMailbox
+	}
+
+	conf := EmailConfig{
+		Provider:    strings.ToLower(getenvDefault("EMAIL_PROVIDER", defaultProvider)),
+		From:        getenvDefault("EMAIL_FROM", "Beerbux <onboarding@resend.dev>"),
+		TemplateDir: os.Getenv("EMAIL_TEMPLATE_DIR"),
+	}
+
+	switch conf.Provider {
+	case EmailProviderResend:
+		resend.Key = mustGetenv("RESEND_KEY")
+		conf.Resend = resend
+	case EmailProviderSMTP:
+		port, err := getenvUint("SMTP_PORT", 587, 16)
+		if err != nil {
+			return EmailConfig{}, err
+		}
+		tlsMode := strings.ToLower(getenvDefault("SMTP_TLS_MODE", SMTPTLSModeStartTLS))
+		if tlsMode != SMTPTLSModeStartTLS && tlsMode != SMTPTLSModeImplicit && tlsMode != SMTPTLSModeNone {
+			return EmailConfig{}, fmt.Errorf("invalid SMTP_TLS_MODE: %s", tlsMode)
+		}
+		conf.SMTP = SMTPConfig{
+			Host:     mustGetenv("SMTP_HOST"),
+			Port:     int(port),
+			Username: os.Getenv("SMTP_USERNAME"),
+			Password: os.Getenv("SMTP_PASSWORD"),
+			TLSMode:  tlsMode,
+		}
+	case EmailProviderTerminal, EmailProviderMailbox:
+		if !environment.IsDevelopment() {
+			return EmailConfig{}, fmt.Errorf("EMAIL_PROVIDER %s is only available in development", conf.Provider)
+		}
+	default:
+		return EmailConfig{}, fmt.Errorf("invalid EMAIL_PROVIDER: %s", conf.Provider)
+	}
+
+	return conf, nil
+}
+
+// loadPasswordHasher configures how passwords are hashed. PASSWORD_HASH_ALGORITHM is argon2id
+// (the default) or bcrypt; ARGON2_MEMORY_KIB, ARGON2_ITERATIONS and ARGON2_PARALLELISM tune
+// argon2id and BCRYPT_COST tunes bcrypt. Hashes created with another algorithm or different
+// parameters are rehashed when the user next logs in.
+func loadPasswordHasher() (*password.Hasher, error) {
+	params := password.DefaultArgon2idParams
+	memory, err := getenvUint("ARGON2_MEMORY_KIB", uint64(params.Memory), 32)
+	if err != nil {
+		return nil, err
+	}
+	iterations, err := getenvUint("ARGON2_ITERATIONS", uint64(params.Iterations), 32)
+	if err != nil {
+		return nil, err
+	}
+	parallelism, err := getenvUint("ARGON2_PARALLELISM", uint64(params.Parallelism), 8)
+	if err != nil {
+		return nil, err
+	}
+	bcryptCost, err := getenvUint("BCRYPT_COST", uint64(bcrypt.DefaultCost), 8)
+	if err != nil {
+		return nil, err
+	}
+
+	params.Memory = uint32(memory)
+	params.Iterations = uint32(iterations)
+	params.Parallelism = uint8(parallelism)
+
+	hasher, err := password.NewHasher(password.Options{
+		Algorithm:  strings.ToLower(getenvDefault("PASSWORD_HASH_ALGORITHM", password.AlgorithmArgon2id)),
+		Argon2id:   params,
+		BcryptCost: int(bcryptCost),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid password hashing configuration: %w", err)
+	}
+	return hasher, nil
+}
+
+// loadPasswordPolicy configures the password policy. PASSWORD_MIN_LENGTH defaults to 8 and
+// PASSWORD_BREACHED_LIST_FILE optionally names the Have I Been Pwned SHA-1 list, ordered by hash,
+// of breached passwords to reject.
+func loadPasswordPolicy() (*password.Policy, error) {
+	minLength, err := getenvUint("PASSWORD_MIN_LENGTH", 8, 8)
+	if err != nil {
+		return nil, err
+	}
+
+	policy, err := password.NewPolicy(int(minLength), os.Getenv("PASSWORD_BREACHED_LIST_FILE"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid password policy configuration: %w", err)
+	}
+	return policy, nil
+}
+
+var oidcProviderNameRegexp = regexp.MustCompile("^[a-z0-9-]+$")
+
+// loadOIDCProviders loads the OpenID Connect providers named in OIDC_PROVIDERS, a comma separated
+// list such as "google,local". Each provider is configured with OIDC_<NAME>_ISSUER, _CLIENT_ID,
+// _CLIENT_SECRET and optionally _DISPLAY_NAME and _SCOPES. The provider must allow the redirect URI
+// {OIDC_REDIRECT_BASE_URL}/api/auth/oidc/{name}/callback, which defaults to the client base URL.
+func loadOIDCProviders(redirectBaseURL string) ([]OIDCProviderConfig, error) {
+	var providers []OIDCProviderConfig
+	for _, name := range splitList(os.Getenv("OIDC_PROVIDERS")) {
+		name = strings.ToLower(name)
+		if !oidcProviderNameRegexp.MatchString(name) {
+			return nil, fmt.Errorf("invalid OIDC_PROVIDERS: %s", name)
+		}
+
+		prefix := "OIDC_" + strings.ToUpper(strings.ReplaceAll(name, "-", "_")) + "_"
+		issuer := os.Getenv(prefix + "ISSUER")
+		clientID := os.Getenv(prefix + "CLIENT_ID")
+		if issuer == "" || clientID == "" {
+			return nil, fmt.Errorf("%sISSUER and %sCLIENT_ID are required", prefix, prefix)
+		}
+
+		providers = append(providers, OIDCProviderConfig{
+			Name:         name,
+			DisplayName:  getenvDefault(prefix+"DISPLAY_NAME", name),
+			Issuer:       issuer,
+			ClientID:     clientID,
+			ClientSecret: os.Getenv(prefix + "CLIENT_SECRET"),
+			Scopes:       strings.Fields(getenvDefault(prefix+"SCOPES", "openid email profile")),
+			RedirectURL:  strings.TrimSuffix(redirectBaseURL, "/") + "/api/auth/oidc/" + name + "/callback",
+		})
+	}
+	return providers, nil
+}
+
+// loadJWTKeySet loads the keys used to sign access tokens.
+//
+// JWT_SIGNING_KEY_FILE is a PEM encoded Ed25519 or RSA private key used to sign tokens; when it is
+// not set, tokens are signed with HS256 using JWT_SECRET. JWT_VERIFICATION_KEY_FILES is a comma
+// separated list of retired keys that are still accepted, and JWT_ALLOWED_ALGORITHMS optionally
+// restricts or extends the accepted algorithms, e.g. "EdDSA,HS256" while migrating from HS256.
+func loadJWTKeySet(secret string) (*jwtkeys.KeySet, error) {
+	opts := jwtkeys.Options{
+		Secret:     secret,
+		Algorithms: splitList(os.Getenv("JWT_ALLOWED_ALGORITHMS")),
+	}
+
+	if path := os.Getenv("JWT_SIGNING_KEY_FILE"); path != "" {
+		key, err := jwtkeys.LoadKeyFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("invalid JWT_SIGNING_KEY_FILE: %w", err)
+		}
+		opts.SigningKey = key
+	}
+
+	for _, path := range splitList(os.Getenv("JWT_VERIFICATION_KEY_FILES")) {
+		key, err := jwtkeys.LoadKeyFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("invalid JWT_VERIFICATION_KEY_FILES: %w", err)
+		}
+		opts.VerificationKeys = append(opts.VerificationKeys, key)
+	}
+
+	keys, err := jwtkeys.NewKeySet(opts)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JWT key configuration: %w", err)
+	}
+	return keys, nil
+}
+
+// splitList splits a comma separated environment variable, ignoring empty values.
+func splitList(value string) []string {
+	var values []string
+	for _, v := range strings.Split(value, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
 func getSlogLevel() slog.Level {
 	switch strings.ToLower(getenvDefault("LOG_LEVEL", "debug")) {
 	case "debug":
@@ -144,6 +608,18 @@ func getenvDefault(key, defaultValue string) string {
 	return value
 }
 
+func getenvUint(key string, defaultValue uint64, bitSize int) (uint64, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	n, err := strconv.ParseUint(value, 10, bitSize)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %s", key, value)
+	}
+	return n, nil
+}
+
 func mustGetenv(key string) string {
 	value := os.Getenv(key)
 	if value == "" {
@@ -1,9 +1,16 @@
 package config
 
-import "time"
+import (
+	"beerbux/pkg/jwtkeys"
+	"beerbux/pkg/password"
+	"time"
+)
 
 type AuthOptions struct {
 	JWTSecret       string
+	JWTKeys         *jwtkeys.KeySet
 	AccessTokenTTL  time.Duration
 	RefreshTokenTTL time.Duration
+	PasswordHasher  *password.Hasher
+	PasswordPolicy  *password.Policy
 }
@@ -0,0 +1,136 @@
+package middleware
+
+import (
+	"beerbux/internal/auth/cookie"
+	"beerbux/pkg/send"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+type CSRFMiddleware struct {
+	trustedOrigins map[string]bool
+	exempt         *http.ServeMux
+	logger         *slog.Logger
+}
+
+// NewCSRFMiddleware creates a middleware that only accepts state-changing requests
+// from the trusted origins, e.g. the CLIENT_BASE_URL, or from the API's own origin.
+//
+// Requests matching the exempt route patterns are not checked. These must be endpoints
+// called by other services, which authenticate the request themselves, such as slash commands
+// signed by the chat workspace.
+func NewCSRFMiddleware(trustedOrigins []string, exemptRoutes []string, logger *slog.Logger) *CSRFMiddleware {
+	origins := make(map[string]bool, len(trustedOrigins))
+	for _, o := range trustedOrigins {
+		if origin, ok := normalizeOrigin(o); ok {
+			origins[origin] = true
+		}
+	}
+
+	// The exempt mux is only used to match requests to the exempt patterns.
+	exempt := http.NewServeMux()
+	for _, pattern := range exemptRoutes {
+		exempt.Handle(pattern, http.NotFoundHandler())
+	}
+
+	return &CSRFMiddleware{
+		trustedOrigins: origins,
+		exempt:         exempt,
+		logger:         logger,
+	}
+}
+
+// Protect rejects cross-site state-changing requests authenticated by cookies.
+//
+// Browsers send the Origin header, or at least the Referer header, with every POST, PUT,
+// PATCH and DELETE request, so the request is rejected when the origin they name is not
+// trusted. Safe methods, which includes the SSE stream, are not checked as they must not
+// change state. Requests with a bearer token are not checked either, as the token is not
+// sent automatically by the browser and the cookies are ignored when one is present.
+//
+// Requests with neither header are rejected when they carry the authentication cookies,
+// as the headers may have been stripped, e.g. by a Referrer-Policy. Without the cookies
+// the request cannot act as the user and is allowed, so that non-browser clients keep working.
+func (mw *CSRFMiddleware) Protect(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if isSafeMethod(r.Method) {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if _, ok := bearerToken(r); ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if _, pattern := mw.exempt.Handler(r); pattern != "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		source := r.Header.Get("Origin")
+		if source == "" {
+			source = r.Header.Get("Referer")
+		}
+		if source == "" {
+			if hasAuthCookie(r) {
+				mw.logger.Warn("rejected cookie-authenticated request without an origin", "method", r.Method, "path", r.URL.Path)
+				send.Forbidden(w, "Cross-site request rejected")
+				return
+			}
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		if !mw.trusted(r, source) {
+			mw.logger.Warn("rejected cross-site request", "method", r.Method, "path", r.URL.Path, "origin", source)
+			send.Forbidden(w, "Cross-site request rejected")
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (mw *CSRFMiddleware) trusted(r *http.Request, source string) bool {
+	origin, ok := normalizeOrigin(source)
+	if !ok {
+		// Includes the opaque "null" origin sent from sandboxed frames and privacy-sensitive redirects.
+		return false
+	}
+	if mw.trustedOrigins[origin] {
+		return true
+	}
+
+	// Same-origin requests are trusted, such as when the API serves the webapp itself.
+	_, host, _ := strings.Cut(origin, "://")
+	return strings.EqualFold(host, r.Host)
+}
+
+// hasAuthCookie reports whether the request carries a cookie that authenticates the user.
+func hasAuthCookie(r *http.Request) bool {
+	for _, name := range []string{cookie.AccessTokenKey, cookie.RefreshTokenKey} {
+		if _, err := r.Cookie(name); err == nil {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeOrigin returns the scheme://host[:port] of the URL in lower case.
+func normalizeOrigin(rawURL string) (string, bool) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return "", false
+	}
+	return strings.ToLower(u.Scheme + "://" + u.Host), true
+}
+
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,83 @@
+package middleware
+
+import (
+	"beerbux/internal/auth/cookie"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestCSRFProtect(t *testing.T) {
+	mw := NewCSRFMiddleware([]string{"https://app.example.com/"}, []string{"POST /chat/slack/commands"}, slog.New(slog.DiscardHandler))
+	handler := mw.Protect(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	for _, tt := range []struct {
+		name    string
+		method  string
+		target  string
+		origin  string
+		referer string
+		bearer  bool
+		cookie  bool
+		allowed bool
+	}{
+		{name: "trusted origin", method: http.MethodPost, target: "/session", origin: "https://app.example.com", cookie: true, allowed: true},
+		{name: "trusted origin in another case", method: http.MethodPost, target: "/session", origin: "HTTPS://APP.EXAMPLE.COM", cookie: true, allowed: true},
+		{name: "trusted referer", method: http.MethodPut, target: "/user", referer: "https://app.example.com/settings", cookie: true, allowed: true},
+		{name: "same origin", method: http.MethodDelete, target: "/session", origin: "https://api.example.com", cookie: true, allowed: true},
+		{name: "cross-site origin", method: http.MethodPost, target: "/session", origin: "https://evil.example.com", cookie: true, allowed: false},
+		{name: "cross-site referer", method: http.MethodPost, target: "/session", referer: "https://evil.example.com/page", cookie: true, allowed: false},
+		{name: "cross-site origin without cookies", method: http.MethodPost, target: "/auth/login", origin: "https://evil.example.com", allowed: false},
+		{name: "null origin", method: http.MethodPost, target: "/session", origin: "null", cookie: true, allowed: false},
+		{name: "other port", method: http.MethodPost, target: "/session", origin: "https://app.example.com:8443", cookie: true, allowed: false},
+		{name: "cross-site safe method", method: http.MethodGet, target: "/session", origin: "https://evil.example.com", cookie: true, allowed: true},
+		{name: "cross-site bearer", method: http.MethodPost, target: "/session", origin: "https://evil.example.com", bearer: true, cookie: true, allowed: true},
+		{name: "missing headers with cookies", method: http.MethodPost, target: "/session", cookie: true, allowed: false},
+		{name: "missing headers without cookies", method: http.MethodPost, target: "/auth/login", allowed: true},
+		{name: "missing headers with bearer", method: http.MethodPost, target: "/session", bearer: true, allowed: true},
+		{name: "exempt route", method: http.MethodPost, target: "/chat/slack/commands", cookie: true, allowed: true},
+		{name: "exempt route cross-site", method: http.MethodPost, target: "/chat/slack/commands", origin: "https://evil.example.com", allowed: true},
+		{name: "exempt pattern only matches its method", method: http.MethodDelete, target: "/chat/slack/commands", cookie: true, allowed: false},
+	} {
+		r := httptest.NewRequest(tt.method, "https://api.example.com"+tt.target, nil)
+		if tt.origin != "" {
+			r.Header.Set("Origin", tt.origin)
+		}
+		if tt.referer != "" {
+			r.Header.Set("Referer", tt.referer)
+		}
+		if tt.bearer {
+			r.Header.Set("Authorization", "Bearer token")
+		}
+		if tt.cookie {
+			r.AddCookie(&http.Cookie{Name: cookie.AccessTokenKey, Value: "token"})
+		}
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		if allowed := w.Code == http.StatusNoContent; allowed != tt.allowed {
+			t.Errorf("%s: allowed = %t (status %d), want %t", tt.name, allowed, w.Code, tt.allowed)
+		}
+	}
+}
+
+func TestCSRFProtectRefreshCookie(t *testing.T) {
+	mw := NewCSRFMiddleware(nil, nil, slog.New(slog.DiscardHandler))
+	handler := mw.Protect(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	// The refresh token cookie alone is enough to act as the user, as it is exchanged for an access token.
+	r := httptest.NewRequest(http.MethodPost, "/session", nil)
+	r.AddCookie(&http.Cookie{Name: cookie.RefreshTokenKey, Value: "token"})
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+
+	if w.Code != http.StatusForbidden {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusForbidden)
+	}
+}
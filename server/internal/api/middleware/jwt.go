@@ -3,28 +3,42 @@ package middleware
 import (
 	"beerbux/internal/auth/command"
 	"beerbux/internal/auth/cookie"
+	"beerbux/internal/auth/shared"
 	"beerbux/internal/common/claims"
+	"beerbux/pkg/jwtkeys"
 	"context"
 	"errors"
 	"fmt"
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/google/uuid"
 	"net/http"
+	"strings"
 )
 
 type AuthMiddleware struct {
-	refreshTokenCommand *command.RefreshTokenCommand
-	secret              string
+	refreshTokenCommand                    *command.RefreshTokenCommand
+	authenticatePersonalAccessTokenCommand *command.AuthenticatePersonalAccessTokenCommand
+	keys                                   *jwtkeys.KeySet
+	trustProxy                             bool
 }
 
-func NewAuthMiddleware(refreshTokenCommand *command.RefreshTokenCommand, secret string) *AuthMiddleware {
+func NewAuthMiddleware(
+	refreshTokenCommand *command.RefreshTokenCommand,
+	authenticatePersonalAccessTokenCommand *command.AuthenticatePersonalAccessTokenCommand,
+	keys *jwtkeys.KeySet,
+	trustProxy bool,
+) *AuthMiddleware {
 	return &AuthMiddleware{
-		refreshTokenCommand: refreshTokenCommand,
-		secret:              secret,
+		refreshTokenCommand:                    refreshTokenCommand,
+		authenticatePersonalAccessTokenCommand: authenticatePersonalAccessTokenCommand,
+		keys:                                   keys,
+		trustProxy:                             trustProxy,
 	}
 }
 
 // WithJWT is a middleware that extracts the JWT claims from the request and adds them to the context.
+// The access token is read from the Authorization header when present, otherwise from the cookies.
+// A personal access token may also be given in the Authorization header.
 // If the JWT is invalid or does not exist, the middleware will continue to the next handler.
 func (mw *AuthMiddleware) WithJWT(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
@@ -33,6 +47,18 @@ func (mw *AuthMiddleware) WithJWT(next http.Handler) http.Handler {
 			return
 		}
 
+		if bearer, ok := bearerToken(r); ok {
+			jwtClaims, err := mw.bearerClaims(r.Context(), bearer)
+			if err != nil {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), claims.JWTClaimsKey, jwtClaims)
+			next.ServeHTTP(w, r.WithContext(ctx))
+			return
+		}
+
 		accessCookie, err := r.Cookie(cookie.AccessTokenKey)
 		if err != nil {
 			next.ServeHTTP(w, r)
@@ -52,7 +78,7 @@ func (mw *AuthMiddleware) WithJWT(next http.Handler) http.Handler {
 		jwtClaims, err := mw.parseJWTClaims(accessToken)
 		if err != nil && errors.As(err, &ve) && ve.Errors == jwt.ValidationErrorExpired {
 			// Attempt to refresh the access token if it has expired.
-			user, err := mw.refreshAccessToken(r.Context(), accessToken, refreshToken)
+			user, err := mw.refreshAccessToken(r.Context(), refreshToken, shared.NewDeviceInfo(r, mw.trustProxy))
 			if err != nil {
 				next.ServeHTTP(w, r)
 				return
@@ -82,23 +108,51 @@ func (mw *AuthMiddleware) WithJWT(next http.Handler) http.Handler {
 	})
 }
 
-func (mw *AuthMiddleware) refreshAccessToken(ctx context.Context, accessToken, refreshToken string) (*command.TokenResponse, error) {
-	if accessToken == "" || refreshToken == "" {
-		return nil, errors.New("missing access or refresh token")
+// bearerToken returns the token from an "Authorization: Bearer" header.
+func bearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return "", false
 	}
+	return strings.TrimSpace(token), true
+}
 
-	subject, err := mw.unsafeGetSubjectFromJWT(accessToken)
+// bearerClaims returns the claims for a bearer token, which is either a personal access token or
+// an access token. Bearer clients refresh their own tokens through the token endpoint, so an
+// expired access token is not refreshed here.
+func (mw *AuthMiddleware) bearerClaims(ctx context.Context, token string) (claims.JWTClaims, error) {
+	if shared.IsPersonalAccessToken(token) {
+		return mw.personalAccessTokenClaims(ctx, token)
+	}
+	return mw.parseJWTClaims(token)
+}
+
+// personalAccessTokenClaims builds the claims for a request authenticated with a personal access token.
+func (mw *AuthMiddleware) personalAccessTokenClaims(ctx context.Context, token string) (claims.JWTClaims, error) {
+	user, err := mw.authenticatePersonalAccessTokenCommand.Execute(ctx, token)
 	if err != nil {
-		return nil, err
+		return claims.JWTClaims{}, err
 	}
 
-	return mw.refreshTokenCommand.Execute(ctx, subject, refreshToken)
+	return claims.JWTClaims{
+		Subject:    user.UserID,
+		Username:   user.Username,
+		Email:      user.Email,
+		Expiration: user.ExpiresAt.Unix(),
+		Scopes:     user.Scopes,
+	}, nil
+}
+
+func (mw *AuthMiddleware) refreshAccessToken(ctx context.Context, refreshToken string, device shared.DeviceInfo) (*command.TokenResponse, error) {
+	if refreshToken == "" {
+		return nil, errors.New("missing refresh token")
+	}
+
+	return mw.refreshTokenCommand.Execute(ctx, refreshToken, device)
 }
 
 func (mw *AuthMiddleware) parseJWTClaims(jwtValue string) (claims.JWTClaims, error) {
-	token, err := jwt.Parse(jwtValue, func(token *jwt.Token) (interface{}, error) {
-		return []byte(mw.secret), nil
-	})
+	token, err := mw.keys.Parse(jwtValue, jwt.MapClaims{})
 	if err != nil {
 		return claims.JWTClaims{}, fmt.Errorf("failed parsing access token: %w", err)
 	}
@@ -115,27 +169,6 @@ func (mw *AuthMiddleware) parseJWTClaims(jwtValue string) (claims.JWTClaims, err
 	return mw.parseClaimValues(mapClaims)
 }
 
-// unsafeGetSubjectFromJWT returns the subject from the JWT without verifying the signature.
-// This is intended for determining the subject when refreshing the JWT.
-func (mw *AuthMiddleware) unsafeGetSubjectFromJWT(jwtValue string) (uuid.UUID, error) {
-	token, _, err := new(jwt.Parser).ParseUnverified(jwtValue, jwt.MapClaims{})
-	if err != nil {
-		return uuid.Nil, fmt.Errorf("failed parsing access token: %w", err)
-	}
-
-	mapClaims, ok := token.Claims.(jwt.MapClaims)
-	if !ok {
-		return uuid.Nil, fmt.Errorf("invalid claims")
-	}
-
-	subValue, ok := mapClaims["sub"].(string)
-	if !ok {
-		return uuid.Nil, fmt.Errorf("sub claim not found")
-	}
-
-	return uuid.Parse(subValue)
-}
-
 func (mw *AuthMiddleware) parseClaimValues(mapClaims jwt.MapClaims) (claims.JWTClaims, error) {
 	subValue, ok := mapClaims["sub"].(string)
 	if !ok {
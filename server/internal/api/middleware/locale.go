@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"beerbux/internal/common/claims"
+	"beerbux/internal/common/useraccess"
+	"beerbux/pkg/i18n"
+	"log/slog"
+	"net/http"
+)
+
+type LocaleMiddleware struct {
+	userReader useraccess.UserReader
+	logger     *slog.Logger
+}
+
+func NewLocaleMiddleware(userReader useraccess.UserReader, logger *slog.Logger) *LocaleMiddleware {
+	return &LocaleMiddleware{
+		userReader: userReader,
+		logger:     logger,
+	}
+}
+
+// Localize determines the locale of the request from its Accept-Language header and
+// makes it available to handlers and to the send package, which translates error
+// messages into it. It should wrap the whole API so that errors from other middleware
+// are translated too.
+func (mw *LocaleMiddleware) Localize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		locale := i18n.NewRequestLocale(r.Header.Get("Accept-Language"))
+		w.Header().Add("Vary", "Accept-Language")
+
+		next.ServeHTTP(
+			&localeResponseWriter{ResponseWriter: w, locale: locale},
+			r.WithContext(i18n.NewContext(r.Context(), locale)),
+		)
+	})
+}
+
+// WithUserPreference makes the locale saved by the authenticated user take precedence
+// over the Accept-Language header. It must run after the JWT middleware and inside Localize.
+func (mw *LocaleMiddleware) WithUserPreference(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c := claims.GetClaims(r)
+		locale, ok := i18n.RequestLocaleFromContext(r.Context())
+		if ok && c.Authenticated() {
+			ctx := r.Context()
+			locale.SetPreference(func() string {
+				preference, err := mw.userReader.GetUserLocale(ctx, c.Subject)
+				if err != nil {
+					mw.logger.Error("failed to get user locale", "user", c.Subject, "error", err)
+					return ""
+				}
+				return preference
+			})
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// localeResponseWriter exposes the locale of the request to the send package.
+type localeResponseWriter struct {
+	http.ResponseWriter
+	locale *i18n.RequestLocale
+}
+
+func (w *localeResponseWriter) Locale() string {
+	return w.locale.Locale()
+}
+
+// Flush allows streaming handlers to keep flushing through the wrapped writer.
+func (w *localeResponseWriter) Flush() {
+	if flusher, ok := w.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+func (w *localeResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
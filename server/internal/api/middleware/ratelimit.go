@@ -0,0 +1,110 @@
+package middleware
+
+import (
+	"beerbux/internal/common/claims"
+	"beerbux/internal/ratelimit"
+	"beerbux/pkg/clientip"
+	"beerbux/pkg/send"
+	"log/slog"
+	"math"
+	"net/http"
+	"strconv"
+)
+
+type RateLimitMiddleware struct {
+	store      ratelimit.Store
+	policies   ratelimit.Policies
+	routes     *http.ServeMux
+	trustProxy bool
+	logger     *slog.Logger
+}
+
+func NewRateLimitMiddleware(store ratelimit.Store, policies ratelimit.Policies, trustProxy bool, logger *slog.Logger) *RateLimitMiddleware {
+	// The routes mux is only used to match requests to the pattern of the route policies,
+	// so that patterns are matched exactly as the API mux would match them.
+	routes := http.NewServeMux()
+	for pattern := range policies.Routes {
+		routes.Handle(pattern, http.NotFoundHandler())
+	}
+
+	return &RateLimitMiddleware{
+		store:      store,
+		policies:   policies,
+		routes:     routes,
+		trustProxy: trustProxy,
+		logger:     logger,
+	}
+}
+
+// Limit rejects requests exceeding the policy for the matched route with a 429 status.
+// Requests are allowed through if the store cannot be reached.
+func (mw *RateLimitMiddleware) Limit(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		policies := mw.policiesFor(r)
+		if len(policies) == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		var mostRestrictive *ratelimit.Result
+		for _, policy := range policies {
+			result, err := mw.store.Hit(r.Context(), mw.key(r, policy), policy.Limit, policy.Window)
+			if err != nil {
+				mw.logger.Error("failed to apply rate limit", "policy", policy.Name, "error", err)
+				continue
+			}
+
+			if !result.Allowed {
+				setRateLimitHeaders(w, result)
+				w.Header().Set("Retry-After", strconv.Itoa(ceilSeconds(result)))
+				send.TooManyRequests(w, "Too many requests, please try again later")
+				return
+			}
+			if mostRestrictive == nil || result.Remaining < mostRestrictive.Remaining {
+				mostRestrictive = &result
+			}
+		}
+
+		if mostRestrictive != nil {
+			setRateLimitHeaders(w, *mostRestrictive)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (mw *RateLimitMiddleware) policiesFor(r *http.Request) []ratelimit.Policy {
+	if r.Method == http.MethodOptions {
+		return nil
+	}
+
+	if _, pattern := mw.routes.Handler(r); pattern != "" {
+		return mw.policies.Routes[pattern]
+	}
+
+	switch r.Method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return mw.policies.Write
+	default:
+		return nil
+	}
+}
+
+func (mw *RateLimitMiddleware) key(r *http.Request, policy ratelimit.Policy) string {
+	if policy.KeyBy == ratelimit.KeyByUser {
+		if c := claims.GetClaims(r); c.Authenticated() {
+			return policy.Name + ":user:" + c.Subject.String()
+		}
+	}
+	return policy.Name + ":ip:" + clientip.FromRequest(r, mw.trustProxy)
+}
+
+func setRateLimitHeaders(w http.ResponseWriter, result ratelimit.Result) {
+	w.Header().Set("X-RateLimit-Limit", strconv.Itoa(result.Limit))
+	w.Header().Set("X-RateLimit-Remaining", strconv.Itoa(result.Remaining))
+	w.Header().Set("X-RateLimit-Reset", strconv.Itoa(ceilSeconds(result)))
+}
+
+// ceilSeconds returns the number of whole seconds until the rate limit window resets.
+func ceilSeconds(result ratelimit.Result) int {
+	return int(math.Ceil(result.RetryAfter().Seconds()))
+}
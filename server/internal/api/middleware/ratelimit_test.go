@@ -0,0 +1,143 @@
+package middleware
+
+import (
+	"beerbux/internal/common/claims"
+	"beerbux/internal/ratelimit"
+	"context"
+	"github.com/google/uuid"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func newTestRateLimitMiddleware(keyBy ratelimit.KeyBy) http.Handler {
+	policies := ratelimit.Policies{
+		Routes: map[string][]ratelimit.Policy{
+			"POST /limited": {{Name: "test", Limit: 1, Window: time.Minute, KeyBy: keyBy}},
+		},
+		Write: []ratelimit.Policy{{Name: "write", Limit: 1, Window: time.Minute, KeyBy: keyBy}},
+	}
+	mw := NewRateLimitMiddleware(ratelimit.NewMemoryStore(), policies, false, slog.New(slog.DiscardHandler))
+	return mw.Limit(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+}
+
+func newRateLimitRequest(method, target, remoteAddr string, userID uuid.UUID) *http.Request {
+	r := httptest.NewRequest(method, target, nil)
+	r.RemoteAddr = remoteAddr
+	if userID != uuid.Nil {
+		ctx := context.WithValue(r.Context(), claims.JWTClaimsKey, claims.JWTClaims{
+			Expiration: time.Now().Add(time.Minute).Unix(),
+			Subject:    userID,
+			Username:   "user",
+		})
+		r = r.WithContext(ctx)
+	}
+	return r
+}
+
+func serve(handler http.Handler, r *http.Request) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	return w
+}
+
+func TestRateLimitRejectsWithRetryAfter(t *testing.T) {
+	handler := newTestRateLimitMiddleware(ratelimit.KeyByIP)
+
+	w := serve(handler, newRateLimitRequest(http.MethodPost, "/limited", "192.0.2.1:1234", uuid.Nil))
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("first request status = %d, want %d", w.Code, http.StatusNoContent)
+	}
+	if got := w.Header().Get("X-RateLimit-Remaining"); got != "0" {
+		t.Errorf("X-RateLimit-Remaining = %q, want 0", got)
+	}
+	if got := w.Header().Get("Retry-After"); got != "" {
+		t.Errorf("allowed request has Retry-After %q", got)
+	}
+
+	w = serve(handler, newRateLimitRequest(http.MethodPost, "/limited", "192.0.2.1:1234", uuid.Nil))
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("second request status = %d, want %d", w.Code, http.StatusTooManyRequests)
+	}
+	retryAfter, err := strconv.Atoi(w.Header().Get("Retry-After"))
+	if err != nil {
+		t.Fatalf("invalid Retry-After %q: %v", w.Header().Get("Retry-After"), err)
+	}
+	if retryAfter < 1 || retryAfter > 60 {
+		t.Errorf("Retry-After = %d, want between 1 and 60 seconds", retryAfter)
+	}
+}
+
+func TestRateLimitKeyByIP(t *testing.T) {
+	handler := newTestRateLimitMiddleware(ratelimit.KeyByIP)
+	userID := uuid.New()
+
+	serve(handler, newRateLimitRequest(http.MethodPost, "/limited", "192.0.2.1:1234", userID))
+
+	// Requests from the same IP address share a counter, even when made by another user.
+	w := serve(handler, newRateLimitRequest(http.MethodPost, "/limited", "192.0.2.1:5678", uuid.New()))
+	if w.Code != http.StatusTooManyRequests {
+		t.Errorf("same IP status = %d, want %d", w.Code, http.StatusTooManyRequests)
+	}
+
+	w = serve(handler, newRateLimitRequest(http.MethodPost, "/limited", "192.0.2.2:1234", userID))
+	if w.Code != http.StatusNoContent {
+		t.Errorf("other IP status = %d, want %d", w.Code, http.StatusNoContent)
+	}
+}
+
+func TestRateLimitKeyByUser(t *testing.T) {
+	handler := newTestRateLimitMiddleware(ratelimit.KeyByUser)
+	userID := uuid.New()
+
+	serve(handler, newRateLimitRequest(http.MethodPost, "/limited", "192.0.2.1:1234", userID))
+
+	// Requests by the same user share a counter, even from another IP address.
+	w := serve(handler, newRateLimitRequest(http.MethodPost, "/limited", "192.0.2.2:1234", userID))
+	if w.Code != http.StatusTooManyRequests {
+		t.Errorf("same user status = %d, want %d", w.Code, http.StatusTooManyRequests)
+	}
+
+	w = serve(handler, newRateLimitRequest(http.MethodPost, "/limited", "192.0.2.1:1234", uuid.New()))
+	if w.Code != http.StatusNoContent {
+		t.Errorf("other user status = %d, want %d", w.Code, http.StatusNoContent)
+	}
+
+	// Unauthenticated requests fall back to the IP address.
+	w = serve(handler, newRateLimitRequest(http.MethodPost, "/limited", "192.0.2.3:1234", uuid.Nil))
+	if w.Code != http.StatusNoContent {
+		t.Errorf("first unauthenticated status = %d, want %d", w.Code, http.StatusNoContent)
+	}
+	w = serve(handler, newRateLimitRequest(http.MethodPost, "/limited", "192.0.2.3:1234", uuid.Nil))
+	if w.Code != http.StatusTooManyRequests {
+		t.Errorf("second unauthenticated status = %d, want %d", w.Code, http.StatusTooManyRequests)
+	}
+}
+
+func TestRateLimitPolicySelection(t *testing.T) {
+	handler := newTestRateLimitMiddleware(ratelimit.KeyByIP)
+
+	for _, tt := range []struct {
+		name   string
+		method string
+		target string
+		// limited reports whether the second request is rejected.
+		limited bool
+	}{
+		{name: "unmatched read", method: http.MethodGet, target: "/other", limited: false},
+		{name: "preflight", method: http.MethodOptions, target: "/limited", limited: false},
+		{name: "unmatched write", method: http.MethodPut, target: "/other", limited: true},
+	} {
+		remoteAddr := "198.51.100.1:1234"
+		serve(handler, newRateLimitRequest(tt.method, tt.target, remoteAddr, uuid.Nil))
+		w := serve(handler, newRateLimitRequest(tt.method, tt.target, remoteAddr, uuid.Nil))
+		if got := w.Code == http.StatusTooManyRequests; got != tt.limited {
+			t.Errorf("%s: limited = %t, want %t", tt.name, got, tt.limited)
+		}
+	}
+}
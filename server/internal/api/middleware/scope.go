@@ -0,0 +1,53 @@
+package middleware
+
+import (
+	"beerbux/internal/common/claims"
+	"beerbux/pkg/i18n"
+	"beerbux/pkg/send"
+	"net/http"
+)
+
+type ScopeMiddleware struct {
+	scopes map[string]string
+	routes *http.ServeMux
+}
+
+// NewScopeMiddleware creates a middleware enforcing the scopes of personal access tokens,
+// where scopes maps route patterns to the scope required to call them.
+func NewScopeMiddleware(scopes map[string]string) *ScopeMiddleware {
+	routes := http.NewServeMux()
+	for pattern := range scopes {
+		routes.Handle(pattern, http.NotFoundHandler())
+	}
+
+	return &ScopeMiddleware{
+		scopes: scopes,
+		routes: routes,
+	}
+}
+
+// Enforce rejects requests made with a personal access token that has not been granted
+// the scope of the matched route. Routes without a scope cannot be called with a
+// personal access token at all. Requests from logged-in users are not affected.
+func (mw *ScopeMiddleware) Enforce(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		c := claims.GetClaims(r)
+		if !c.PersonalAccessToken() || r.Method == http.MethodOptions {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		_, pattern := mw.routes.Handler(r)
+		required, ok := mw.scopes[pattern]
+		if !ok {
+			send.Forbidden(w, "This endpoint cannot be used with an access token")
+			return
+		}
+		if !c.HasScope(required) {
+			send.Forbidden(w, i18n.Sprintf(i18n.FromContext(r.Context()), "The access token is missing the %s scope", required))
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,52 @@
+package middleware
+
+import (
+	"beerbux/internal/auth/scope"
+	"beerbux/internal/common/claims"
+	"context"
+	"github.com/google/uuid"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestScopeEnforce(t *testing.T) {
+	mw := NewScopeMiddleware(scope.DefaultRoutes())
+	handler := mw.Enforce(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+
+	for _, tt := range []struct {
+		name    string
+		method  string
+		target  string
+		scopes  []string
+		allowed bool
+	}{
+		{name: "logged in user", method: http.MethodPost, target: "/auth/personal-access-tokens", scopes: nil, allowed: true},
+		{name: "granted scope", method: http.MethodGet, target: "/user", scopes: []string{scope.UserRead}, allowed: true},
+		{name: "granted scope with path values", method: http.MethodPost, target: "/session/abc/transaction", scopes: []string{scope.TransactionsWrite}, allowed: true},
+		{name: "missing scope", method: http.MethodPost, target: "/session/abc/transaction", scopes: []string{scope.SessionsWrite}, allowed: false},
+		{name: "read scope does not grant write", method: http.MethodPost, target: "/session", scopes: []string{scope.SessionsRead}, allowed: false},
+		{name: "no scopes", method: http.MethodGet, target: "/user", scopes: []string{}, allowed: false},
+		{name: "route without a scope", method: http.MethodPost, target: "/auth/personal-access-tokens", scopes: scope.All(), allowed: false},
+		{name: "unknown route", method: http.MethodGet, target: "/unknown", scopes: scope.All(), allowed: false},
+		{name: "preflight", method: http.MethodOptions, target: "/auth/personal-access-tokens", scopes: []string{}, allowed: true},
+	} {
+		r := httptest.NewRequest(tt.method, tt.target, nil)
+		r = r.WithContext(context.WithValue(r.Context(), claims.JWTClaimsKey, claims.JWTClaims{
+			Expiration: time.Now().Add(time.Minute).Unix(),
+			Subject:    uuid.New(),
+			Username:   "user",
+			Scopes:     tt.scopes,
+		}))
+
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+
+		if allowed := w.Code == http.StatusNoContent; allowed != tt.allowed {
+			t.Errorf("%s: allowed = %t (status %d), want %t", tt.name, allowed, w.Code, tt.allowed)
+		}
+	}
+}
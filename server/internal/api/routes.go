@@ -1,17 +1,29 @@
 package api
 
 import (
+	"beerbux/internal/api/config"
 	"beerbux/internal/api/middleware"
 	"beerbux/internal/api/webapp"
 	"beerbux/internal/auth/command"
 	authQueries "beerbux/internal/auth/db"
 	authHandler "beerbux/internal/auth/handler"
+	"beerbux/internal/auth/scope"
+	chatHandler "beerbux/internal/chat/handler"
+	"beerbux/internal/common/useraccess"
+	useraccessQueries "beerbux/internal/common/useraccess/db"
+	devMailHandler "beerbux/internal/devmail/handler"
+	devPushHandler "beerbux/internal/devpush/handler"
 	friendsHandler "beerbux/internal/friends/handler"
+	notificationsHandler "beerbux/internal/notifications/handler"
+	"beerbux/internal/ratelimit"
+	rateLimitQueries "beerbux/internal/ratelimit/db"
 	sessionHandler "beerbux/internal/session/handler"
 	"beerbux/internal/sse"
 	streamHandler "beerbux/internal/streamer/handler"
 	userHandler "beerbux/internal/user/handler"
+	webhooksHandler "beerbux/internal/webhooks/handler"
 	"beerbux/pkg/email"
+	"beerbux/pkg/webpush"
 	"net/http"
 )
 
@@ -26,41 +38,59 @@ func (app *App) NewServer(streamServer *sse.Server) (*Server, error) {
 	apiMux := http.NewServeMux()
 	webMux := http.NewServeMux()
 
-	emailSender := email.New(app.Config.Resend)
-	if app.Config.Environment.IsDevelopment() && app.Config.Resend.DevelopmentSendToEmail == "" {
-		emailSender = email.NewTerminalEmailLogger(app.Logger)
-	}
-
 	// Build and handle API routes
 	apiMux.HandleFunc("/ping", func(w http.ResponseWriter, r *http.Request) {
 		_, _ = w.Write([]byte("pong"))
 	})
-	authHandler.BuildRoutes(app.Config, app.Logger, app.DB, emailSender, apiMux)
-	sessionHandler.BuildRoutes(app.Logger, app.DB, apiMux, app.MessageReceiver())
+	authHandler.BuildRoutes(app.Config, app.Logger, app.DB, app.emailOutbox, apiMux)
+	sessionHandler.BuildRoutes(app.Logger, app.DB, apiMux, app.MessageReceiver(), app.pushDispatcher, app.webhookOutbox)
 	userHandler.BuildRoutes(app.Logger, app.DB, apiMux)
 	friendsHandler.BuildRoutes(app.Logger, app.DB, apiMux)
+	notificationsHandler.BuildRoutes(app.Config, app.Logger, app.DB, apiMux)
+	webhooksHandler.BuildRoutes(app.Config, app.Logger, app.DB, app.webhookOutbox, apiMux)
+	chatHandler.BuildRoutes(app.Config, app.Logger, app.DB, apiMux, app.MessageReceiver(), app.pushDispatcher, app.webhookOutbox)
 	apiMux.Handle("/events/session", streamHandler.NewSessionTransactionCreatedHandler(app.Logger, streamServer))
+	apiMux.Handle("GET /events/notifications", streamHandler.NewNotificationsHandler(app.Logger, streamServer))
 
 	// Construct middleware for API routes
 	authenticationQueries := authQueries.New(app.DB)
 	refreshTokenCommand := command.NewRefreshTokenCommand(authenticationQueries, app.Config.GetAuthOptions())
-	authMiddleware := middleware.NewAuthMiddleware(refreshTokenCommand, app.Config.Secrets.JWTSecret)
+	authenticatePersonalAccessTokenCommand := command.NewAuthenticatePersonalAccessTokenCommand(authenticationQueries)
+	authMiddleware := middleware.NewAuthMiddleware(refreshTokenCommand, authenticatePersonalAccessTokenCommand, app.Config.JWTKeys, app.Config.TrustProxy)
+	scopeMiddleware := middleware.NewScopeMiddleware(scope.DefaultRoutes())
 	recoverMiddleware := middleware.NewRecoverMiddleware(app.Logger)
+	csrfMiddleware := middleware.NewCSRFMiddleware([]string{app.Config.CORSClientBaseURL}, []string{"POST /chat/slack/commands"}, app.Logger)
+	localeMiddleware := middleware.NewLocaleMiddleware(useraccess.NewUserReaderService(useraccessQueries.New(app.DB)), app.Logger)
+
+	var apiHandler http.Handler = scopeMiddleware.Enforce(apiMux)
+	if app.Config.RateLimit.Enabled {
+		rateLimitMiddleware := middleware.NewRateLimitMiddleware(app.newRateLimitStore(), app.Config.RateLimit.Policies, app.Config.TrustProxy, app.Logger)
+		apiHandler = rateLimitMiddleware.Limit(apiHandler)
+	}
+	apiHandler = localeMiddleware.WithUserPreference(apiHandler)
 
-	var apiHandler http.Handler
 	if app.Config.Environment.IsDevelopment() {
 		// We only want to run the CORS middleware when we run React separate
 		// from the API in development mode.
 		app.Logger.Info("Setting up API with CORS middleware")
 		apiHandler = recoverMiddleware.Recover(
-			authMiddleware.WithJWT(
-				middleware.CORS(apiMux, app.Config.CORSClientBaseURL),
+			middleware.CORS(
+				localeMiddleware.Localize(
+					csrfMiddleware.Protect(
+						authMiddleware.WithJWT(apiHandler),
+					),
+				),
+				app.Config.CORSClientBaseURL,
 			),
 		)
 	} else {
 		app.Logger.Info("Setting up API without CORS middleware")
 		apiHandler = recoverMiddleware.Recover(
-			authMiddleware.WithJWT(apiMux),
+			localeMiddleware.Localize(
+				csrfMiddleware.Protect(
+					authMiddleware.WithJWT(apiHandler),
+				),
+			),
 		)
 	}
 
@@ -74,6 +104,18 @@ func (app *App) NewServer(streamServer *sse.Server) (*Server, error) {
 	}
 
 	rootMux.Handle("/api/", http.StripPrefix("/api", apiHandler))
+	rootMux.Handle("GET /.well-known/jwks.json", authHandler.NewJWKSHandler(app.Config.JWTKeys))
+
+	if app.Config.Environment.IsDevelopment() {
+		devMux := http.NewServeMux()
+		if mailbox, ok := app.emailProvider.(*email.Mailbox); ok {
+			app.Logger.Info("Capturing emails in the development mailbox at /dev/mail")
+			devMailHandler.BuildRoutes(mailbox, devMux)
+		}
+		app.Logger.Info("Running a stand-in push service at /dev/push")
+		devPushHandler.BuildRoutes(webpush.NewDevService(app.Logger), devMux)
+		rootMux.Handle("/dev/", middleware.CORS(devMux, app.Config.CORSClientBaseURL))
+	}
 
 	return &Server{
 		Server: &http.Server{
@@ -82,3 +124,12 @@ func (app *App) NewServer(streamServer *sse.Server) (*Server, error) {
 		},
 	}, nil
 }
+
+func (app *App) newRateLimitStore() ratelimit.Store {
+	if app.Config.RateLimit.Backend == config.RateLimitBackendPostgres {
+		app.Logger.Info("Using Postgres rate limit store")
+		return ratelimit.NewPostgresStore(rateLimitQueries.New(app.DB), app.Logger)
+	}
+	app.Logger.Info("Using in-memory rate limit store")
+	return ratelimit.NewMemoryStore()
+}
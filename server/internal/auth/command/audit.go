@@ -0,0 +1,39 @@
+package command
+
+import (
+	"beerbux/internal/auth/db"
+	"beerbux/internal/auth/shared"
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/google/uuid"
+)
+
+const (
+	AuditEventSignup             = "signup"
+	AuditEventLogin              = "login"
+	AuditEventTokenRefreshed     = "token_refreshed"
+	AuditEventRefreshTokenReused = "refresh_token_reused"
+	AuditEventPasswordChanged    = "password_changed"
+	AuditEventPasswordReset      = "password_reset"
+	AuditEventEmailChanged       = "email_changed"
+	AuditEventEmailReverted      = "email_reverted"
+	AuditEventTwoFactorEnabled   = "two_factor_enabled"
+	AuditEventTwoFactorDisabled  = "two_factor_disabled"
+)
+
+// recordAuditEvent adds an entry to the user's audit log. Pass a transaction scoped
+// queries value to record the event atomically with the change it describes.
+func recordAuditEvent(ctx context.Context, queries *db.Queries, userID uuid.UUID, event string, detail string, device shared.DeviceInfo) error {
+	err := queries.CreateUserAuditEvent(ctx, db.CreateUserAuditEventParams{
+		UserID:    userID,
+		Event:     event,
+		Detail:    sql.NullString{String: detail, Valid: detail != ""},
+		UserAgent: device.NullUserAgent(),
+		IpAddress: device.NullIPAddress(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record %s audit event: %w", event, err)
+	}
+	return nil
+}
@@ -0,0 +1,74 @@
+package command
+
+import (
+	"beerbux/internal/auth/db"
+	"beerbux/internal/auth/shared"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"time"
+)
+
+var ErrPersonalAccessTokenInvalid = errors.New("personal access token invalid")
+
+type AuthenticatePersonalAccessTokenCommand struct {
+	queries *db.Queries
+}
+
+func NewAuthenticatePersonalAccessTokenCommand(queries *db.Queries) *AuthenticatePersonalAccessTokenCommand {
+	return &AuthenticatePersonalAccessTokenCommand{
+		queries: queries,
+	}
+}
+
+type PersonalAccessTokenUser struct {
+	UserID    uuid.UUID
+	Username  string
+	Email     string
+	Scopes    []string
+	ExpiresAt time.Time
+}
+
+// Execute checks the personal access token and returns the user it acts on behalf of,
+// recording when the token was last used.
+func (c *AuthenticatePersonalAccessTokenCommand) Execute(ctx context.Context, token string) (*PersonalAccessTokenUser, error) {
+	selector, verifier, ok := shared.ParseSelectorVerifierToken(token, shared.PersonalAccessTokenPrefix)
+	if !ok {
+		return nil, ErrPersonalAccessTokenInvalid
+	}
+
+	pat, err := c.queries.GetPersonalAccessTokenBySelector(ctx, selector)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrPersonalAccessTokenInvalid
+		}
+		return nil, fmt.Errorf("failed to get personal access token: %w", err)
+	}
+
+	if !shared.VerifySelectorVerifierToken(verifier, pat.HashedVerifier) {
+		return nil, ErrPersonalAccessTokenInvalid
+	}
+	if pat.RevokedAt.Valid || !pat.ExpiresAt.After(time.Now()) {
+		return nil, ErrPersonalAccessTokenInvalid
+	}
+
+	if err := c.queries.TouchPersonalAccessToken(ctx, pat.ID); err != nil {
+		return nil, fmt.Errorf("failed to update personal access token last used: %w", err)
+	}
+
+	scopes := pat.Scopes
+	if scopes == nil {
+		// A nil slice would give the token full access.
+		scopes = []string{}
+	}
+
+	return &PersonalAccessTokenUser{
+		UserID:    pat.UserID,
+		Username:  pat.Username,
+		Email:     pat.Email,
+		Scopes:    scopes,
+		ExpiresAt: pat.ExpiresAt,
+	}, nil
+}
@@ -2,23 +2,28 @@ package command
 
 import (
 	"beerbux/internal/auth/db"
+	"beerbux/pkg/password"
 	"context"
 	"database/sql"
 	"errors"
-	"golang.org/x/crypto/bcrypt"
+	"fmt"
 )
 
 type ComparePasswordCommand struct {
 	queries *db.Queries
+	hasher  *password.Hasher
 }
 
-func NewComparePasswordCommand(queries *db.Queries) *ComparePasswordCommand {
+func NewComparePasswordCommand(queries *db.Queries, hasher *password.Hasher) *ComparePasswordCommand {
 	return &ComparePasswordCommand{
 		queries: queries,
+		hasher:  hasher,
 	}
 }
 
-func (c *ComparePasswordCommand) Execute(ctx context.Context, username, password string) error {
+// Execute compares the password with the user's password hash. When the hash was created
+// with an outdated algorithm or parameters, the password is rehashed with the current ones.
+func (c *ComparePasswordCommand) Execute(ctx context.Context, username, pw string) error {
 	user, err := c.queries.GetUserByUsername(ctx, username)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -26,8 +31,36 @@ func (c *ComparePasswordCommand) Execute(ctx context.Context, username, password
 		}
 		return err
 	}
-	if err := bcrypt.CompareHashAndPassword([]byte(user.HashedPassword), []byte(password)); err != nil {
+
+	match, needsRehash, err := c.hasher.Verify(user.HashedPassword, pw)
+	if err != nil {
+		return fmt.Errorf("failed to verify password: %w", err)
+	}
+	if !match {
 		return ErrPasswordMismatch
 	}
+
+	if needsRehash {
+		if err := c.rehash(ctx, user, pw); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *ComparePasswordCommand) rehash(ctx context.Context, user db.User, pw string) error {
+	hashedPassword, err := c.hasher.Hash(pw)
+	if err != nil {
+		return err
+	}
+
+	// The old hash is matched so that a password changed in the meantime is not overwritten.
+	if err := c.queries.RehashUserPassword(ctx, db.RehashUserPasswordParams{
+		NewHashedPassword: hashedPassword,
+		ID:                user.ID,
+		OldHashedPassword: user.HashedPassword,
+	}); err != nil {
+		return fmt.Errorf("failed to rehash password: %w", err)
+	}
 	return nil
 }
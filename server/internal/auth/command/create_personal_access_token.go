@@ -0,0 +1,69 @@
+package command
+
+import (
+	"beerbux/internal/auth/db"
+	"beerbux/internal/auth/shared"
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"time"
+)
+
+type CreatePersonalAccessTokenCommand struct {
+	queries *db.Queries
+}
+
+func NewCreatePersonalAccessTokenCommand(queries *db.Queries) *CreatePersonalAccessTokenCommand {
+	return &CreatePersonalAccessTokenCommand{
+		queries: queries,
+	}
+}
+
+type PersonalAccessTokenResponse struct {
+	ID         uuid.UUID  `json:"id"`
+	Name       string     `json:"name"`
+	Scopes     []string   `json:"scopes"`
+	ExpiresAt  time.Time  `json:"expiresAt"`
+	LastUsedAt *time.Time `json:"lastUsedAt"`
+	CreatedAt  time.Time  `json:"createdAt"`
+}
+
+type CreatedPersonalAccessTokenResponse struct {
+	PersonalAccessTokenResponse
+	// Token is only returned when the token is created, as it is not stored.
+	Token string `json:"token"`
+}
+
+func (c *CreatePersonalAccessTokenCommand) Execute(ctx context.Context, userID uuid.UUID, name string, scopes []string, ttl time.Duration) (*CreatedPersonalAccessTokenResponse, error) {
+	token := shared.GenerateSelectorVerifierToken(shared.PersonalAccessTokenPrefix)
+	pat, err := c.queries.CreatePersonalAccessToken(ctx, db.CreatePersonalAccessTokenParams{
+		UserID:         userID,
+		Name:           name,
+		Selector:       token.Selector,
+		HashedVerifier: token.HashedVerifier,
+		Scopes:         scopes,
+		ExpiresAt:      time.Now().Add(ttl),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create personal access token: %w", err)
+	}
+
+	return &CreatedPersonalAccessTokenResponse{
+		PersonalAccessTokenResponse: newPersonalAccessTokenResponse(pat),
+		Token:                       token.Token,
+	}, nil
+}
+
+func newPersonalAccessTokenResponse(pat db.PersonalAccessToken) PersonalAccessTokenResponse {
+	resp := PersonalAccessTokenResponse{
+		ID:        pat.ID,
+		Name:      pat.Name,
+		Scopes:    pat.Scopes,
+		ExpiresAt: pat.ExpiresAt,
+		CreatedAt: pat.CreatedAt,
+	}
+	if pat.LastUsedAt.Valid {
+		resp.LastUsedAt = &pat.LastUsedAt.Time
+	}
+	return resp
+}
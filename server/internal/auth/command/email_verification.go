@@ -0,0 +1,75 @@
+package command
+
+import (
+	"beerbux/internal/auth/db"
+	"beerbux/pkg/dbtx"
+	"beerbux/pkg/otp"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// EmailVerificationOTPTimeToLiveHours is longer than the other OTPs as the code is
+// sent as a link that the user may not get around to opening straight away.
+const EmailVerificationOTPTimeToLiveHours int = 24
+
+type VerifyEmailCommand struct {
+	dbtx.TX
+	queries *db.Queries
+}
+
+func NewVerifyEmailCommand(tx dbtx.TX, queries *db.Queries) *VerifyEmailCommand {
+	return &VerifyEmailCommand{
+		TX:      tx,
+		queries: queries,
+	}
+}
+
+func (c *VerifyEmailCommand) Execute(ctx context.Context, email, OTP string) error {
+	tx, err := c.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback()
+	qtx := c.queries.WithTx(tx)
+
+	user, err := qtx.GetUserByEmailForUpdate(ctx, email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrUserNotFound
+		}
+		return fmt.Errorf("failed to get user for email verification: %w", err)
+	}
+
+	if user.EmailVerified {
+		return ErrEmailAlreadyVerified
+	}
+
+	if !user.EmailVerificationRequestedAt.Valid || !user.EmailVerificationOtp.Valid {
+		return ErrProcessNotInitialized
+	}
+
+	ttl := time.Duration(EmailVerificationOTPTimeToLiveHours) * time.Hour
+	expirationTime := user.EmailVerificationRequestedAt.Time.Add(ttl)
+	if expirationTime.Before(time.Now()) {
+		return ErrOTPExpired
+	}
+
+	if !otp.Compare(user.EmailVerificationOtp.String, OTP) {
+		return commitFailedAttempt(tx, emailVerificationAttemptRecorder(qtx).recordFailure(ctx, user.ID))
+	}
+
+	rowsAffected, err := qtx.VerifyEmail(ctx, db.VerifyEmailParams{
+		ID:             user.ID,
+		RequestedAfter: otpRequestedAfter(ttl),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to verify email: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrOTPExpired
+	}
+	return tx.Commit()
+}
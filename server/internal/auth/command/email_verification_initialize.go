@@ -0,0 +1,66 @@
+package command
+
+import (
+	"beerbux/internal/auth/db"
+	"beerbux/pkg/otp"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+)
+
+const EmailVerificationOTPLength = 6
+
+var ErrEmailAlreadyVerified = errors.New("email already verified")
+
+type InitializeEmailVerificationCommand struct {
+	queries *db.Queries
+}
+
+func NewInitializeEmailVerificationCommand(queries *db.Queries) *InitializeEmailVerificationCommand {
+	return &InitializeEmailVerificationCommand{
+		queries: queries,
+	}
+}
+
+type InitializeEmailVerificationResponse struct {
+	OTP string
+}
+
+func (c *InitializeEmailVerificationCommand) Execute(ctx context.Context, userID uuid.UUID) (*InitializeEmailVerificationResponse, error) {
+	user, err := c.queries.GetUser(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to get user for email verification: %w", err)
+	}
+
+	if user.EmailVerified {
+		return nil, ErrEmailAlreadyVerified
+	}
+
+	OTP, err := otp.Generate(EmailVerificationOTPLength)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate one-time password: %w", err)
+	}
+
+	rowsAffected, err := c.queries.InitializeEmailVerification(ctx, db.InitializeEmailVerificationParams{
+		ID: userID,
+		EmailVerificationOtp: sql.NullString{
+			String: otp.Hash(OTP),
+			Valid:  true,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize email verification: %w", err)
+	}
+	if rowsAffected == 0 {
+		return nil, ErrOTPLocked
+	}
+
+	return &InitializeEmailVerificationResponse{
+		OTP: OTP,
+	}, nil
+}
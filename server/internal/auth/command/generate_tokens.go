@@ -11,7 +11,6 @@ import (
 	"github.com/google/uuid"
 	"regexp"
 	"strings"
-	"time"
 )
 
 var ErrUserNotFound = errors.New("user not found")
@@ -29,19 +28,37 @@ func NewGenerateTokensCommand(queries *db.Queries, options config.AuthOptions) *
 }
 
 type AuthenticatedUserDetails struct {
-	ID       uuid.UUID `json:"id"`
-	Username string    `json:"username"`
-	Email    string    `json:"email"`
-	Name     string    `json:"name"`
+	ID            uuid.UUID `json:"id"`
+	Username      string    `json:"username"`
+	Email         string    `json:"email"`
+	EmailVerified bool      `json:"emailVerified"`
+	Name          string    `json:"name"`
+	Locale        string    `json:"locale"`
 }
 
 type TokensResponse struct {
 	AccessToken  string                   `json:"accessToken"`
 	RefreshToken string                   `json:"refreshToken"`
 	User         AuthenticatedUserDetails `json:"user"`
+	// NewDevice is set when the login came from a user agent or IP address that
+	// has not been seen for the user before.
+	NewDevice bool `json:"-"`
 }
 
-func (c *GenerateTokensCommand) Execute(ctx context.Context, usernameOrEmail string) (*TokensResponse, error) {
+// LoginMethod records how the user proved their identity before tokens were issued.
+// Logins completed with a second factor are recorded with the method used before it.
+type LoginMethod string
+
+const (
+	LoginMethodPassword  LoginMethod = "password"
+	LoginMethodMagicLink LoginMethod = "magic_link"
+	LoginMethodOIDC      LoginMethod = "oidc"
+	// LoginMethodReissue replaces the tokens of an already authenticated user, e.g. after
+	// their email address changes. It is not recorded as a login.
+	LoginMethodReissue LoginMethod = "reissue"
+)
+
+func (c *GenerateTokensCommand) Execute(ctx context.Context, usernameOrEmail string, method LoginMethod, device shared.DeviceInfo) (*TokensResponse, error) {
 	user, err := c.getUserByUsernameOrEmail(ctx, usernameOrEmail)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -50,38 +67,58 @@ func (c *GenerateTokensCommand) Execute(ctx context.Context, usernameOrEmail str
 		return nil, ErrUserNotFound
 	}
 
-	accessToken, err := shared.GenerateJWT(user.ID, user.Username, user.Email, c.options.JWTSecret, c.options.AccessTokenTTL)
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate JWT: %w", err)
+	var newDevice bool
+	if method != LoginMethodReissue {
+		newDevice, err = c.recordLogin(ctx, user.ID, method, device)
+		if err != nil {
+			return nil, err
+		}
 	}
 
-	refreshToken := shared.GenerateRefreshToken()
-	hashedRefreshToken, err := shared.HashRefreshToken(refreshToken)
+	accessToken, err := shared.GenerateJWT(user.ID, user.Username, user.Email, c.options.JWTKeys, c.options.AccessTokenTTL)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
+		return nil, fmt.Errorf("failed to generate JWT: %w", err)
 	}
 
-	err = c.queries.RegisterRefreshToken(ctx, db.RegisterRefreshTokenParams{
-		UserID:      user.ID,
-		HashedToken: hashedRefreshToken,
-		ExpiresAt:   time.Now().Add(c.options.RefreshTokenTTL),
-	})
+	refreshToken, err := registerRefreshToken(ctx, c.queries, user.ID, c.options.RefreshTokenTTL, device)
 	if err != nil {
-		return nil, fmt.Errorf("failed to store refresh token: %w", err)
+		return nil, err
 	}
 
 	return &TokensResponse{
 		AccessToken:  accessToken,
 		RefreshToken: refreshToken,
 		User: AuthenticatedUserDetails{
-			ID:       user.ID,
-			Username: user.Username,
-			Email:    user.Email,
-			Name:     user.Name,
+			ID:            user.ID,
+			Username:      user.Username,
+			Email:         user.Email,
+			EmailVerified: user.EmailVerified,
+			Name:          user.Name,
+			Locale:        user.Locale.String,
 		},
+		NewDevice: newDevice,
 	}, nil
 }
 
+// recordLogin adds the login to the audit log, reporting whether the device is new to the user.
+// A user's first login is never reported as a new device.
+func (c *GenerateTokensCommand) recordLogin(ctx context.Context, userID uuid.UUID, method LoginMethod, device shared.DeviceInfo) (bool, error) {
+	history, err := c.queries.GetLoginDeviceHistory(ctx, db.GetLoginDeviceHistoryParams{
+		UserAgent: device.NullUserAgent(),
+		IpAddress: device.NullIPAddress(),
+		UserID:    userID,
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to get login device history: %w", err)
+	}
+
+	if err := recordAuditEvent(ctx, c.queries, userID, AuditEventLogin, string(method), device); err != nil {
+		return false, err
+	}
+
+	return history.HasLogins && (!history.SeenUserAgent || !history.SeenIpAddress), nil
+}
+
 func (c *GenerateTokensCommand) getUserByUsernameOrEmail(ctx context.Context, usernameOrEmail string) (db.User, error) {
 	if isEmail(usernameOrEmail) {
 		return c.queries.GetUserByEmail(ctx, usernameOrEmail)
@@ -3,9 +3,8 @@ package command
 import (
 	"beerbux/internal/auth/db"
 	"context"
+	"errors"
 	"fmt"
-	"github.com/google/uuid"
-	"golang.org/x/crypto/bcrypt"
 )
 
 type InvalidateRefreshTokenCommand struct {
@@ -18,19 +17,19 @@ func NewInvalidateRefreshTokenCommand(authRepository *db.Queries) *InvalidateRef
 	}
 }
 
-func (c *InvalidateRefreshTokenCommand) Execute(ctx context.Context, userID uuid.UUID, token string) error {
-	userRefreshTokens, err := c.queries.GetRefreshTokensByUserID(ctx, userID)
+// Execute revokes the token family of the given refresh token, ending that login.
+// Holding the refresh token is enough to revoke it, so no user is required.
+func (c *InvalidateRefreshTokenCommand) Execute(ctx context.Context, token string) error {
+	storedToken, err := getRefreshToken(ctx, c.queries, token)
 	if err != nil {
-		return fmt.Errorf("failed to get refresh tokens: %w", err)
-	}
-
-	for _, t := range userRefreshTokens {
-		if err := bcrypt.CompareHashAndPassword([]byte(t.HashedToken), []byte(token)); err == nil {
-			if err := c.queries.InvalidateRefreshToken(ctx, t.ID); err != nil {
-				return fmt.Errorf("failed to invalidate refresh token: %w", err)
-			}
+		if errors.Is(err, ErrRefreshTokenNotFound) {
 			return nil
 		}
+		return err
+	}
+
+	if err := c.queries.RevokeRefreshTokenFamily(ctx, storedToken.FamilyID); err != nil {
+		return fmt.Errorf("failed to invalidate refresh token: %w", err)
 	}
 
 	return nil
@@ -0,0 +1,64 @@
+package command
+
+import (
+	"beerbux/internal/auth/db"
+	"beerbux/pkg/oidc"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrIdentityLinkedToOtherUser = errors.New("identity is linked to another user")
+	ErrProviderAlreadyLinked     = errors.New("provider already linked")
+)
+
+type LinkIdentityCommand struct {
+	queries *db.Queries
+}
+
+func NewLinkIdentityCommand(queries *db.Queries) *LinkIdentityCommand {
+	return &LinkIdentityCommand{
+		queries: queries,
+	}
+}
+
+// Execute links the provider identity to the user so that they can log in with the provider.
+// A user can link a single identity per provider.
+func (c *LinkIdentityCommand) Execute(ctx context.Context, userID uuid.UUID, provider string, identity *oidc.IDToken) error {
+	existing, err := c.queries.GetUserIdentityByProviderSubject(ctx, db.GetUserIdentityByProviderSubjectParams{
+		Provider: provider,
+		Subject:  identity.Subject,
+	})
+	if err == nil {
+		if existing.UserID != userID {
+			return ErrIdentityLinkedToOtherUser
+		}
+		return nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed to get user identity: %w", err)
+	}
+
+	identities, err := c.queries.ListUserIdentitiesByUserID(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to list user identities: %w", err)
+	}
+	for _, i := range identities {
+		if i.Provider == provider {
+			return ErrProviderAlreadyLinked
+		}
+	}
+
+	if _, err := c.queries.CreateUserIdentity(ctx, db.CreateUserIdentityParams{
+		UserID:   userID,
+		Provider: provider,
+		Subject:  identity.Subject,
+		Email:    nullString(identity.Email),
+	}); err != nil {
+		return fmt.Errorf("failed to create user identity: %w", err)
+	}
+	return nil
+}
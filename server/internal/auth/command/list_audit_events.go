@@ -0,0 +1,67 @@
+package command
+
+import (
+	"beerbux/internal/auth/db"
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"time"
+)
+
+const (
+	DefaultAuditEventLimit = 50
+	MaxAuditEventLimit     = 100
+)
+
+type ListAuditEventsCommand struct {
+	queries *db.Queries
+}
+
+func NewListAuditEventsCommand(queries *db.Queries) *ListAuditEventsCommand {
+	return &ListAuditEventsCommand{
+		queries: queries,
+	}
+}
+
+type AuditEventResponse struct {
+	ID        uuid.UUID `json:"id"`
+	Event     string    `json:"event"`
+	Detail    *string   `json:"detail"`
+	UserAgent string    `json:"userAgent"`
+	IPAddress string    `json:"ipAddress"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Execute lists the most recent audit events for the user, newest first.
+func (c *ListAuditEventsCommand) Execute(ctx context.Context, userID uuid.UUID, limit int) ([]AuditEventResponse, error) {
+	if limit <= 0 {
+		limit = DefaultAuditEventLimit
+	}
+	limit = min(limit, MaxAuditEventLimit)
+
+	events, err := c.queries.ListUserAuditEvents(ctx, db.ListUserAuditEventsParams{
+		UserID: userID,
+		Limit:  int32(limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list audit events: %w", err)
+	}
+
+	resp := make([]AuditEventResponse, 0, len(events))
+	for _, e := range events {
+		var detail *string
+		if e.Detail.Valid {
+			detail = &e.Detail.String
+		}
+		resp = append(resp, AuditEventResponse{
+			ID:        e.ID,
+			Event:     e.Event,
+			Detail:    detail,
+			UserAgent: e.UserAgent.String,
+			IPAddress: e.IpAddress.String,
+			CreatedAt: e.CreatedAt,
+		})
+	}
+
+	return resp, nil
+}
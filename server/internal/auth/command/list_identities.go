@@ -0,0 +1,52 @@
+package command
+
+import (
+	"beerbux/internal/auth/db"
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"time"
+)
+
+type ListIdentitiesCommand struct {
+	queries *db.Queries
+}
+
+func NewListIdentitiesCommand(queries *db.Queries) *ListIdentitiesCommand {
+	return &ListIdentitiesCommand{
+		queries: queries,
+	}
+}
+
+type IdentityResponse struct {
+	ID          uuid.UUID  `json:"id"`
+	Provider    string     `json:"provider"`
+	Email       *string    `json:"email"`
+	LastLoginAt *time.Time `json:"lastLoginAt"`
+	CreatedAt   time.Time  `json:"createdAt"`
+}
+
+// Execute lists the provider identities linked to the user.
+func (c *ListIdentitiesCommand) Execute(ctx context.Context, userID uuid.UUID) ([]IdentityResponse, error) {
+	userIdentities, err := c.queries.ListUserIdentitiesByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list user identities: %w", err)
+	}
+
+	identities := make([]IdentityResponse, 0, len(userIdentities))
+	for _, i := range userIdentities {
+		resp := IdentityResponse{
+			ID:        i.ID,
+			Provider:  i.Provider,
+			CreatedAt: i.CreatedAt,
+		}
+		if i.Email.Valid {
+			resp.Email = &i.Email.String
+		}
+		if i.LastLoginAt.Valid {
+			resp.LastLoginAt = &i.LastLoginAt.Time
+		}
+		identities = append(identities, resp)
+	}
+	return identities, nil
+}
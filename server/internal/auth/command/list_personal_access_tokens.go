@@ -0,0 +1,32 @@
+package command
+
+import (
+	"beerbux/internal/auth/db"
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+)
+
+type ListPersonalAccessTokensCommand struct {
+	queries *db.Queries
+}
+
+func NewListPersonalAccessTokensCommand(queries *db.Queries) *ListPersonalAccessTokensCommand {
+	return &ListPersonalAccessTokensCommand{
+		queries: queries,
+	}
+}
+
+// Execute lists the user's personal access tokens that have not been revoked, including expired tokens.
+func (c *ListPersonalAccessTokensCommand) Execute(ctx context.Context, userID uuid.UUID) ([]PersonalAccessTokenResponse, error) {
+	pats, err := c.queries.ListPersonalAccessTokensByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list personal access tokens: %w", err)
+	}
+
+	tokens := make([]PersonalAccessTokenResponse, 0, len(pats))
+	for _, pat := range pats {
+		tokens = append(tokens, newPersonalAccessTokenResponse(pat))
+	}
+	return tokens, nil
+}
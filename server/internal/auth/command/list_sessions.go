@@ -0,0 +1,59 @@
+package command
+
+import (
+	"beerbux/internal/auth/db"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"time"
+)
+
+type ListSessionsCommand struct {
+	queries *db.Queries
+}
+
+func NewListSessionsCommand(queries *db.Queries) *ListSessionsCommand {
+	return &ListSessionsCommand{
+		queries: queries,
+	}
+}
+
+type SessionResponse struct {
+	ID         uuid.UUID `json:"id"`
+	UserAgent  string    `json:"userAgent"`
+	IPAddress  string    `json:"ipAddress"`
+	CreatedAt  time.Time `json:"createdAt"`
+	LastUsedAt time.Time `json:"lastUsedAt"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+	Current    bool      `json:"current"`
+}
+
+// Execute lists the active refresh token families for the user, flagging the one matching the current refresh token.
+// The family ID is used as the session ID as it stays the same when the refresh token is rotated.
+func (c *ListSessionsCommand) Execute(ctx context.Context, userID uuid.UUID, currentRefreshToken string) ([]SessionResponse, error) {
+	refreshTokens, err := c.queries.GetRefreshTokensByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get refresh tokens: %w", err)
+	}
+
+	current, err := getActiveRefreshToken(ctx, c.queries, currentRefreshToken)
+	if err != nil && !errors.Is(err, ErrRefreshTokenNotFound) {
+		return nil, err
+	}
+
+	sessions := make([]SessionResponse, 0, len(refreshTokens))
+	for _, t := range refreshTokens {
+		sessions = append(sessions, SessionResponse{
+			ID:         t.FamilyID,
+			UserAgent:  t.UserAgent.String,
+			IPAddress:  t.IpAddress.String,
+			CreatedAt:  t.CreatedAt,
+			LastUsedAt: t.LastUsedAt,
+			ExpiresAt:  t.ExpiresAt,
+			Current:    t.FamilyID == current.FamilyID,
+		})
+	}
+
+	return sessions, nil
+}
@@ -0,0 +1,79 @@
+package command
+
+import (
+	"beerbux/internal/auth/db"
+	"beerbux/pkg/dbtx"
+	"beerbux/pkg/otp"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// MagicLinkOTPTimeToLiveMinutes is kept short as the OTP grants a login on its own.
+const MagicLinkOTPTimeToLiveMinutes int = 15
+
+type MagicLinkLoginCommand struct {
+	dbtx.TX
+	queries *db.Queries
+}
+
+func NewMagicLinkLoginCommand(tx dbtx.TX, queries *db.Queries) *MagicLinkLoginCommand {
+	return &MagicLinkLoginCommand{
+		TX:      tx,
+		queries: queries,
+	}
+}
+
+// Execute consumes the magic link OTP sent to the email address and returns the username
+// of the user it logs in. The OTP can only be used once.
+func (c *MagicLinkLoginCommand) Execute(ctx context.Context, email, OTP string) (string, error) {
+	tx, err := c.BeginTx(ctx, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback()
+	qtx := c.queries.WithTx(tx)
+
+	user, err := qtx.GetUserByEmailForUpdate(ctx, email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", ErrUserNotFound
+		}
+		return "", fmt.Errorf("failed to get user for magic link: %w", err)
+	}
+
+	if !user.LoginOtpRequestedAt.Valid || !user.LoginOtp.Valid {
+		return "", ErrProcessNotInitialized
+	}
+
+	ttl := time.Duration(MagicLinkOTPTimeToLiveMinutes) * time.Minute
+	expirationTime := user.LoginOtpRequestedAt.Time.Add(ttl)
+	if expirationTime.Before(time.Now()) {
+		return "", ErrOTPExpired
+	}
+
+	if !otp.Compare(user.LoginOtp.String, OTP) {
+		return "", commitFailedAttempt(tx, loginAttemptRecorder(qtx).recordFailure(ctx, user.ID))
+	}
+
+	// The OTP is matched again when consuming it so that concurrent requests cannot both log in.
+	rowsAffected, err := qtx.ConsumeLoginOTP(ctx, db.ConsumeLoginOTPParams{
+		ID:             user.ID,
+		LoginOtp:       user.LoginOtp,
+		RequestedAfter: otpRequestedAfter(ttl),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to consume magic link: %w", err)
+	}
+	if rowsAffected == 0 {
+		return "", ErrOTPExpired
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", fmt.Errorf("failed to commit tx: %w", err)
+	}
+
+	return user.Username, nil
+}
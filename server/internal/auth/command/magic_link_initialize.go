@@ -0,0 +1,53 @@
+package command
+
+import (
+	"beerbux/internal/auth/db"
+	"beerbux/pkg/otp"
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/google/uuid"
+)
+
+const MagicLinkOTPLength = 6
+
+type InitializeMagicLinkCommand struct {
+	queries *db.Queries
+}
+
+func NewInitializeMagicLinkCommand(queries *db.Queries) *InitializeMagicLinkCommand {
+	return &InitializeMagicLinkCommand{
+		queries: queries,
+	}
+}
+
+type InitializeMagicLinkResponse struct {
+	OTP string
+}
+
+// Execute generates a one-time password that logs the user in, replacing any outstanding one.
+// Only users with a verified email address can log in with a magic link.
+func (c *InitializeMagicLinkCommand) Execute(ctx context.Context, userID uuid.UUID) (*InitializeMagicLinkResponse, error) {
+	OTP, err := otp.Generate(MagicLinkOTPLength)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate one-time password: %w", err)
+	}
+
+	rowsAffected, err := c.queries.InitializeLoginOTP(ctx, db.InitializeLoginOTPParams{
+		ID: userID,
+		LoginOtp: sql.NullString{
+			String: otp.Hash(OTP),
+			Valid:  true,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize magic link: %w", err)
+	}
+	if rowsAffected == 0 {
+		return nil, ErrOTPLocked
+	}
+
+	return &InitializeMagicLinkResponse{
+		OTP: OTP,
+	}, nil
+}
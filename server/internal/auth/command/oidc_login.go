@@ -0,0 +1,171 @@
+package command
+
+import (
+	"beerbux/internal/api/config"
+	"beerbux/internal/auth/db"
+	"beerbux/pkg/dbtx"
+	"beerbux/pkg/oidc"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"regexp"
+	"strings"
+	"time"
+)
+
+const (
+	oidcUsernameMaxLength = 20
+	oidcUsernameAttempts  = 5
+)
+
+var (
+	ErrOIDCEmailRequired = errors.New("identity provider did not supply an email address")
+	ErrOIDCEmailInUse    = errors.New("email address already belongs to an account")
+)
+
+var usernameInvalidCharsRegexp = regexp.MustCompile("[^a-zA-Z0-9_.-]+")
+
+type OIDCLoginCommand struct {
+	dbtx.TX
+	queries *db.Queries
+	options config.AuthOptions
+}
+
+func NewOIDCLoginCommand(tx dbtx.TX, queries *db.Queries, options config.AuthOptions) *OIDCLoginCommand {
+	return &OIDCLoginCommand{
+		TX:      tx,
+		queries: queries,
+		options: options,
+	}
+}
+
+// Execute finds the user linked to the provider identity, creating a new user when the
+// identity has not been seen before, and returns their username.
+//
+// An identity is never linked to an existing user automatically by matching email
+// addresses, as that would let anyone controlling an account with the same email address
+// at the provider take over the user. Users link providers from their settings instead.
+func (c *OIDCLoginCommand) Execute(ctx context.Context, provider string, identity *oidc.IDToken) (string, error) {
+	userIdentity, err := c.queries.GetUserIdentityByProviderSubject(ctx, db.GetUserIdentityByProviderSubjectParams{
+		Provider: provider,
+		Subject:  identity.Subject,
+	})
+	if err == nil {
+		return c.login(ctx, userIdentity, identity)
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("failed to get user identity: %w", err)
+	}
+
+	if identity.Email == "" {
+		return "", ErrOIDCEmailRequired
+	}
+	if _, err := c.queries.GetUserByEmail(ctx, identity.Email); err == nil {
+		return "", ErrOIDCEmailInUse
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return "", fmt.Errorf("failed to get user by email: %w", err)
+	}
+
+	return c.createUser(ctx, provider, identity)
+}
+
+func (c *OIDCLoginCommand) login(ctx context.Context, userIdentity db.UserIdentity, identity *oidc.IDToken) (string, error) {
+	if err := c.queries.UpdateUserIdentityLogin(ctx, db.UpdateUserIdentityLoginParams{
+		ID:    userIdentity.ID,
+		Email: nullString(identity.Email),
+	}); err != nil {
+		return "", fmt.Errorf("failed to update user identity: %w", err)
+	}
+
+	user, err := c.queries.GetUser(ctx, userIdentity.UserID)
+	if err != nil {
+		return "", fmt.Errorf("failed to get user: %w", err)
+	}
+	return user.Username, nil
+}
+
+func (c *OIDCLoginCommand) createUser(ctx context.Context, provider string, identity *oidc.IDToken) (string, error) {
+	username, err := c.availableUsername(ctx, identity)
+	if err != nil {
+		return "", err
+	}
+
+	// The user can only log in with the provider until they reset their password.
+	hashedPassword, err := c.options.PasswordHasher.Hash(oidc.RandomString())
+	if err != nil {
+		return "", fmt.Errorf("failed to generate password: %w", err)
+	}
+
+	name := strings.TrimSpace(identity.Name)
+	if name == "" {
+		name = username
+	}
+
+	tx, err := c.BeginTx(ctx, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback()
+	qtx := c.queries.WithTx(tx)
+
+	user, err := qtx.CreateOIDCUser(ctx, db.CreateOIDCUserParams{
+		Name:           name,
+		Username:       username,
+		Email:          identity.Email,
+		HashedPassword: hashedPassword,
+		EmailVerified:  identity.EmailVerified,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create user: %w", err)
+	}
+
+	if _, err := qtx.CreateUserIdentity(ctx, db.CreateUserIdentityParams{
+		UserID:      user.ID,
+		Provider:    provider,
+		Subject:     identity.Subject,
+		Email:       nullString(identity.Email),
+		LastLoginAt: sql.NullTime{Time: time.Now(), Valid: true},
+	}); err != nil {
+		return "", fmt.Errorf("failed to create user identity: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return "", fmt.Errorf("failed to commit tx: %w", err)
+	}
+
+	return user.Username, nil
+}
+
+// availableUsername derives a username from the identity, adding a random suffix when it is already taken.
+func (c *OIDCLoginCommand) availableUsername(ctx context.Context, identity *oidc.IDToken) (string, error) {
+	base := identity.PreferredUsername
+	if base == "" {
+		base, _, _ = strings.Cut(identity.Email, "@")
+	}
+	base = usernameInvalidCharsRegexp.ReplaceAllString(base, "")
+	if len(base) > oidcUsernameMaxLength {
+		base = base[:oidcUsernameMaxLength]
+	}
+	if len(base) < 3 {
+		base = "user"
+	}
+
+	username := base
+	for range oidcUsernameAttempts {
+		taken, err := c.queries.UserWithUsernameExists(ctx, username)
+		if err != nil {
+			return "", fmt.Errorf("failed to determine if username exists: %w", err)
+		}
+		if !taken {
+			return username, nil
+		}
+		username = fmt.Sprintf("%s%04d", base, rand.IntN(10000))
+	}
+	return "", ErrUsernameTaken
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
@@ -0,0 +1,131 @@
+package command
+
+import (
+	"beerbux/internal/auth/db"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"time"
+)
+
+const (
+	// MaxOTPAttempts is the number of incorrect attempts allowed before the OTP is invalidated.
+	MaxOTPAttempts int32 = 5
+	// OTPLockoutDuration is how long a user must wait to request a new OTP after too many incorrect attempts.
+	OTPLockoutDuration = 15 * time.Minute
+)
+
+var (
+	ErrTooManyOTPAttempts = errors.New("too many incorrect OTP attempts")
+	ErrOTPLocked          = errors.New("OTP requests are temporarily locked")
+)
+
+type otpAttemptRecorder struct {
+	increment func(ctx context.Context, userID uuid.UUID) (int32, error)
+	lock      func(ctx context.Context, userID uuid.UUID, lockedUntil sql.NullTime) error
+}
+
+// recordFailure increments the attempt counter for the flow. Once the maximum number
+// of attempts has been reached the OTP is invalidated and the user is locked out of
+// requesting another for OTPLockoutDuration. Each flow is locked separately, so failed
+// attempts in one flow do not lock the user out of the others.
+//
+// ErrIncorrectOTP is returned unless the maximum has been reached, in which case ErrTooManyOTPAttempts is returned.
+func (r otpAttemptRecorder) recordFailure(ctx context.Context, userID uuid.UUID) error {
+	attempts, err := r.increment(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to record OTP attempt: %w", err)
+	}
+	if attempts < MaxOTPAttempts {
+		return ErrIncorrectOTP
+	}
+
+	lockedUntil := sql.NullTime{
+		Time:  time.Now().Add(OTPLockoutDuration),
+		Valid: true,
+	}
+	if err := r.lock(ctx, userID, lockedUntil); err != nil {
+		return fmt.Errorf("failed to invalidate OTP: %w", err)
+	}
+	return ErrTooManyOTPAttempts
+}
+
+// commitFailedAttempt commits the failed attempt recorded by recordFailure within tx and
+// returns the error describing it. Attempts are checked with the user row locked so that
+// concurrent requests are counted one at a time and cannot exceed MaxOTPAttempts.
+func commitFailedAttempt(tx *sql.Tx, err error) error {
+	if !errors.Is(err, ErrIncorrectOTP) && !errors.Is(err, ErrTooManyOTPAttempts) {
+		return err
+	}
+	if commitErr := tx.Commit(); commitErr != nil {
+		return fmt.Errorf("failed to commit OTP attempt: %w", commitErr)
+	}
+	return err
+}
+
+func passwordUpdateAttemptRecorder(queries *db.Queries) otpAttemptRecorder {
+	return otpAttemptRecorder{
+		increment: queries.IncrementPasswordUpdateOTPAttempts,
+		lock: func(ctx context.Context, userID uuid.UUID, lockedUntil sql.NullTime) error {
+			return queries.LockPasswordUpdateOTP(ctx, db.LockPasswordUpdateOTPParams{
+				ID:                     userID,
+				PasswordOtpLockedUntil: lockedUntil,
+			})
+		},
+	}
+}
+
+func emailUpdateAttemptRecorder(queries *db.Queries) otpAttemptRecorder {
+	return otpAttemptRecorder{
+		increment: queries.IncrementEmailUpdateOTPAttempts,
+		lock: func(ctx context.Context, userID uuid.UUID, lockedUntil sql.NullTime) error {
+			return queries.LockEmailUpdateOTP(ctx, db.LockEmailUpdateOTPParams{
+				ID:                        userID,
+				EmailUpdateOtpLockedUntil: lockedUntil,
+			})
+		},
+	}
+}
+
+func emailVerificationAttemptRecorder(queries *db.Queries) otpAttemptRecorder {
+	return otpAttemptRecorder{
+		increment: queries.IncrementEmailVerificationOTPAttempts,
+		lock: func(ctx context.Context, userID uuid.UUID, lockedUntil sql.NullTime) error {
+			return queries.LockEmailVerificationOTP(ctx, db.LockEmailVerificationOTPParams{
+				ID:                              userID,
+				EmailVerificationOtpLockedUntil: lockedUntil,
+			})
+		},
+	}
+}
+
+func loginAttemptRecorder(queries *db.Queries) otpAttemptRecorder {
+	return otpAttemptRecorder{
+		increment: queries.IncrementLoginOTPAttempts,
+		lock: func(ctx context.Context, userID uuid.UUID, lockedUntil sql.NullTime) error {
+			return queries.LockLoginOTP(ctx, db.LockLoginOTPParams{
+				ID:                  userID,
+				LoginOtpLockedUntil: lockedUntil,
+			})
+		},
+	}
+}
+
+func twoFactorAttemptRecorder(queries *db.Queries) otpAttemptRecorder {
+	return otpAttemptRecorder{
+		increment: queries.IncrementTwoFactorAttempts,
+		lock: func(ctx context.Context, userID uuid.UUID, lockedUntil sql.NullTime) error {
+			return queries.LockTwoFactor(ctx, db.LockTwoFactorParams{
+				UserID:      userID,
+				LockedUntil: lockedUntil,
+			})
+		},
+	}
+}
+
+// otpRequestedAfter returns the earliest time an OTP can have been requested for it to still be valid.
+func otpRequestedAfter(ttl time.Duration) time.Time {
+	return time.Now().Add(-ttl)
+}
@@ -0,0 +1,90 @@
+package command
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/google/uuid"
+	"testing"
+	"time"
+)
+
+// fakeAttempts records the calls made by an otpAttemptRecorder in place of the database.
+type fakeAttempts struct {
+	attempts    int32
+	lockedUntil sql.NullTime
+}
+
+func (f *fakeAttempts) recorder() otpAttemptRecorder {
+	return otpAttemptRecorder{
+		increment: func(ctx context.Context, userID uuid.UUID) (int32, error) {
+			f.attempts++
+			return f.attempts, nil
+		},
+		lock: func(ctx context.Context, userID uuid.UUID, lockedUntil sql.NullTime) error {
+			f.attempts = 0
+			f.lockedUntil = lockedUntil
+			return nil
+		},
+	}
+}
+
+func TestOTPAttemptRecorderLocksAfterMaxAttempts(t *testing.T) {
+	attempts := &fakeAttempts{}
+	recorder := attempts.recorder()
+	ctx := context.Background()
+	userID := uuid.New()
+
+	for i := int32(1); i < MaxOTPAttempts; i++ {
+		if err := recorder.recordFailure(ctx, userID); !errors.Is(err, ErrIncorrectOTP) {
+			t.Fatalf("attempt %d: err = %v, want %v", i, err, ErrIncorrectOTP)
+		}
+		if attempts.lockedUntil.Valid {
+			t.Fatalf("attempt %d: locked before reaching the maximum", i)
+		}
+	}
+
+	before := time.Now()
+	if err := recorder.recordFailure(ctx, userID); !errors.Is(err, ErrTooManyOTPAttempts) {
+		t.Fatalf("attempt %d: err = %v, want %v", MaxOTPAttempts, err, ErrTooManyOTPAttempts)
+	}
+	if !attempts.lockedUntil.Valid {
+		t.Fatal("not locked after the maximum number of attempts")
+	}
+	if lockout := attempts.lockedUntil.Time.Sub(before); lockout < OTPLockoutDuration || lockout > OTPLockoutDuration+time.Minute {
+		t.Errorf("locked for %s, want %s", lockout, OTPLockoutDuration)
+	}
+	if attempts.attempts != 0 {
+		t.Errorf("attempts = %d after locking, want 0", attempts.attempts)
+	}
+}
+
+func TestOTPAttemptRecorderIncrementError(t *testing.T) {
+	errDatabase := errors.New("database unavailable")
+	recorder := otpAttemptRecorder{
+		increment: func(ctx context.Context, userID uuid.UUID) (int32, error) {
+			return 0, errDatabase
+		},
+		lock: func(ctx context.Context, userID uuid.UUID, lockedUntil sql.NullTime) error {
+			t.Error("locked after failing to record the attempt")
+			return nil
+		},
+	}
+
+	err := recorder.recordFailure(context.Background(), uuid.New())
+	if !errors.Is(err, errDatabase) {
+		t.Errorf("err = %v, want %v", err, errDatabase)
+	}
+	if errors.Is(err, ErrIncorrectOTP) || errors.Is(err, ErrTooManyOTPAttempts) {
+		t.Error("a failure to record the attempt is reported as an incorrect OTP")
+	}
+}
+
+func TestCommitFailedAttemptPassesOtherErrorsThrough(t *testing.T) {
+	// Errors other than a recorded attempt are returned without committing, so a nil
+	// transaction is never used.
+	errDatabase := errors.New("database unavailable")
+	if err := commitFailedAttempt(nil, errDatabase); !errors.Is(err, errDatabase) {
+		t.Errorf("err = %v, want %v", err, errDatabase)
+	}
+}
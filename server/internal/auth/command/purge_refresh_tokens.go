@@ -0,0 +1,27 @@
+package command
+
+import (
+	"beerbux/internal/auth/db"
+	"context"
+	"fmt"
+)
+
+type PurgeRefreshTokensCommand struct {
+	queries *db.Queries
+}
+
+func NewPurgeRefreshTokensCommand(queries *db.Queries) *PurgeRefreshTokensCommand {
+	return &PurgeRefreshTokensCommand{
+		queries: queries,
+	}
+}
+
+// Execute deletes expired and revoked refresh tokens, returning how many were removed.
+// Rotated tokens are kept until they expire so that reuse can still be detected.
+func (c *PurgeRefreshTokensCommand) Execute(ctx context.Context) (int64, error) {
+	rowsAffected, err := c.queries.DeleteStaleRefreshTokens(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete stale refresh tokens: %w", err)
+	}
+	return rowsAffected, nil
+}
@@ -5,14 +5,22 @@ import (
 	"beerbux/internal/auth/db"
 	"beerbux/internal/auth/shared"
 	"context"
+	"database/sql"
 	"errors"
 	"fmt"
 	"github.com/google/uuid"
-	"golang.org/x/crypto/bcrypt"
 	"time"
 )
 
-var ErrRefreshTokenNotFound = errors.New("refresh token not found")
+var (
+	ErrRefreshTokenNotFound = errors.New("refresh token not found")
+	ErrRefreshTokenReused   = errors.New("refresh token reused")
+)
+
+// RefreshTokenReuseGracePeriod is how long an already rotated refresh token is
+// rejected without revoking its family. This stops concurrent requests from the
+// same client, which race to refresh with the same token, from logging the user out.
+const RefreshTokenReuseGracePeriod = 30 * time.Second
 
 type RefreshTokenCommand struct {
 	queries *db.Queries
@@ -26,27 +34,29 @@ func NewRefreshTokenCommand(queries *db.Queries, options config.AuthOptions) *Re
 	}
 }
 
-func (c *RefreshTokenCommand) Execute(ctx context.Context, userID uuid.UUID, refreshToken string) (*TokenResponse, error) {
-	userRefreshTokens, err := c.queries.GetRefreshTokensByUserID(ctx, userID)
+// Execute rotates the refresh token, issuing a new token in the same family, and records the
+// rotation in the audit log. Presenting a token that has already been rotated is treated as
+// theft, and every token in the family is revoked.
+func (c *RefreshTokenCommand) Execute(ctx context.Context, refreshToken string, device shared.DeviceInfo) (*TokenResponse, error) {
+	storedToken, err := getRefreshToken(ctx, c.queries, refreshToken)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get refresh tokens: %w", err)
+		return nil, err
 	}
 
-	var matchedToken db.RefreshToken
-	var tokenFound bool
-	for _, t := range userRefreshTokens {
-		if err := bcrypt.CompareHashAndPassword([]byte(t.HashedToken), []byte(refreshToken)); err == nil {
-			matchedToken = t
-			tokenFound = true
-			break
+	if err := checkRefreshTokenRotation(storedToken, time.Now()); err != nil {
+		if !errors.Is(err, ErrRefreshTokenReused) {
+			return nil, err
 		}
+		if err := c.queries.RevokeRefreshTokenFamily(ctx, storedToken.FamilyID); err != nil {
+			return nil, fmt.Errorf("failed to revoke refresh token family: %w", err)
+		}
+		if err := recordAuditEvent(ctx, c.queries, storedToken.UserID, AuditEventRefreshTokenReused, "", device); err != nil {
+			return nil, err
+		}
+		return nil, ErrRefreshTokenReused
 	}
 
-	if !tokenFound {
-		return nil, ErrRefreshTokenNotFound
-	}
-
-	usr, err := c.queries.GetUser(ctx, userID)
+	usr, err := c.queries.GetUser(ctx, storedToken.UserID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get user: %w", err)
 	}
@@ -55,32 +65,105 @@ func (c *RefreshTokenCommand) Execute(ctx context.Context, userID uuid.UUID, ref
 		usr.ID,
 		usr.Username,
 		usr.Email,
-		c.options.JWTSecret,
+		c.options.JWTKeys,
 		c.options.AccessTokenTTL)
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate JWT: %w", err)
 	}
 
-	newRefreshToken := shared.GenerateRefreshToken()
-	newHashedRefreshToken, err := shared.HashRefreshToken(newRefreshToken)
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
-	}
-
-	err = c.queries.RegisterRefreshToken(ctx, db.RegisterRefreshTokenParams{
-		UserID:      usr.ID,
-		HashedToken: newHashedRefreshToken,
-		ExpiresAt:   time.Now().Add(c.options.RefreshTokenTTL),
+	newRefreshToken := shared.GenerateSelectorVerifierToken("")
+	rowsAffected, err := c.queries.RotateRefreshToken(ctx, db.RotateRefreshTokenParams{
+		ID:             storedToken.ID,
+		Selector:       newRefreshToken.Selector,
+		HashedVerifier: newRefreshToken.HashedVerifier,
+		ExpiresAt:      time.Now().Add(c.options.RefreshTokenTTL),
+		UserAgent:      device.NullUserAgent(),
+		IpAddress:      device.NullIPAddress(),
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to store refresh token: %w", err)
 	}
+	if rowsAffected == 0 {
+		// Another request rotated the token first.
+		return nil, ErrRefreshTokenNotFound
+	}
 
-	_ = c.queries.DeleteRefreshToken(ctx, matchedToken.ID)
+	if err := recordAuditEvent(ctx, c.queries, usr.ID, AuditEventTokenRefreshed, "", device); err != nil {
+		return nil, err
+	}
 
 	return &TokenResponse{
 		AccessToken:  newAccessToken,
-		RefreshToken: newRefreshToken,
+		RefreshToken: newRefreshToken.Token,
 	}, nil
 }
+
+// checkRefreshTokenRotation reports whether the stored token can be rotated at now.
+// ErrRefreshTokenReused is returned when the token was already rotated before the
+// RefreshTokenReuseGracePeriod, in which case its family must be revoked.
+func checkRefreshTokenRotation(storedToken db.RefreshToken, now time.Time) error {
+	if storedToken.Revoked || !storedToken.ExpiresAt.After(now) {
+		return ErrRefreshTokenNotFound
+	}
+	if storedToken.ReplacedAt.Valid {
+		if now.Sub(storedToken.ReplacedAt.Time) < RefreshTokenReuseGracePeriod {
+			return ErrRefreshTokenNotFound
+		}
+		return ErrRefreshTokenReused
+	}
+	return nil
+}
+
+// getRefreshToken looks up the stored refresh token by its selector and checks the verifier.
+// The returned token may be revoked, rotated or expired.
+func getRefreshToken(ctx context.Context, queries *db.Queries, refreshToken string) (db.RefreshToken, error) {
+	selector, verifier, ok := shared.ParseSelectorVerifierToken(refreshToken, "")
+	if !ok {
+		return db.RefreshToken{}, ErrRefreshTokenNotFound
+	}
+
+	storedToken, err := queries.GetRefreshTokenBySelector(ctx, selector)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return db.RefreshToken{}, ErrRefreshTokenNotFound
+		}
+		return db.RefreshToken{}, fmt.Errorf("failed to get refresh token: %w", err)
+	}
+
+	if !shared.VerifySelectorVerifierToken(verifier, storedToken.HashedVerifier) {
+		return db.RefreshToken{}, ErrRefreshTokenNotFound
+	}
+
+	return storedToken, nil
+}
+
+// getActiveRefreshToken is like getRefreshToken but only returns tokens that can still be used.
+func getActiveRefreshToken(ctx context.Context, queries *db.Queries, refreshToken string) (db.RefreshToken, error) {
+	storedToken, err := getRefreshToken(ctx, queries, refreshToken)
+	if err != nil {
+		return db.RefreshToken{}, err
+	}
+	if storedToken.Revoked || storedToken.ReplacedAt.Valid || !storedToken.ExpiresAt.After(time.Now()) {
+		return db.RefreshToken{}, ErrRefreshTokenNotFound
+	}
+	return storedToken, nil
+}
+
+// registerRefreshToken issues a refresh token starting a new token family, which represents a single login.
+func registerRefreshToken(ctx context.Context, queries *db.Queries, userID uuid.UUID, ttl time.Duration, device shared.DeviceInfo) (string, error) {
+	refreshToken := shared.GenerateSelectorVerifierToken("")
+	err := queries.RegisterRefreshToken(ctx, db.RegisterRefreshTokenParams{
+		UserID:         userID,
+		FamilyID:       uuid.New(),
+		Selector:       refreshToken.Selector,
+		HashedVerifier: refreshToken.HashedVerifier,
+		ExpiresAt:      time.Now().Add(ttl),
+		UserAgent:      device.NullUserAgent(),
+		IpAddress:      device.NullIPAddress(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to store refresh token: %w", err)
+	}
+	return refreshToken.Token, nil
+}
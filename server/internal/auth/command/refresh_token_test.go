@@ -0,0 +1,63 @@
+package command
+
+import (
+	"beerbux/internal/auth/db"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCheckRefreshTokenRotation(t *testing.T) {
+	now := time.Now()
+	replacedAt := func(ago time.Duration) sql.NullTime {
+		return sql.NullTime{Time: now.Add(-ago), Valid: true}
+	}
+
+	for _, tt := range []struct {
+		name  string
+		token db.RefreshToken
+		want  error
+	}{
+		{
+			name:  "active",
+			token: db.RefreshToken{ExpiresAt: now.Add(time.Hour)},
+			want:  nil,
+		},
+		{
+			name:  "expired",
+			token: db.RefreshToken{ExpiresAt: now},
+			want:  ErrRefreshTokenNotFound,
+		},
+		{
+			name:  "revoked",
+			token: db.RefreshToken{ExpiresAt: now.Add(time.Hour), Revoked: true},
+			want:  ErrRefreshTokenNotFound,
+		},
+		{
+			name:  "rotated within the grace period",
+			token: db.RefreshToken{ExpiresAt: now.Add(time.Hour), ReplacedAt: replacedAt(RefreshTokenReuseGracePeriod / 2)},
+			want:  ErrRefreshTokenNotFound,
+		},
+		{
+			name:  "rotated at the end of the grace period",
+			token: db.RefreshToken{ExpiresAt: now.Add(time.Hour), ReplacedAt: replacedAt(RefreshTokenReuseGracePeriod)},
+			want:  ErrRefreshTokenReused,
+		},
+		{
+			name:  "rotated after the grace period",
+			token: db.RefreshToken{ExpiresAt: now.Add(time.Hour), ReplacedAt: replacedAt(time.Hour)},
+			want:  ErrRefreshTokenReused,
+		},
+		{
+			// A revoked family is not revoked again, so reuse is only reported once.
+			name:  "rotated and revoked",
+			token: db.RefreshToken{ExpiresAt: now.Add(time.Hour), Revoked: true, ReplacedAt: replacedAt(time.Hour)},
+			want:  ErrRefreshTokenNotFound,
+		},
+	} {
+		if err := checkRefreshTokenRotation(tt.token, now); !errors.Is(err, tt.want) {
+			t.Errorf("%s: err = %v, want %v", tt.name, err, tt.want)
+		}
+	}
+}
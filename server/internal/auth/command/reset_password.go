@@ -1,31 +1,64 @@
 package command
 
 import (
+	"beerbux/internal/api/config"
 	"beerbux/internal/auth/db"
+	"beerbux/internal/auth/shared"
+	"beerbux/pkg/dbtx"
+	"beerbux/pkg/otp"
 	"context"
 	"errors"
 	"fmt"
-	"golang.org/x/crypto/bcrypt"
+	"time"
 )
 
-var ErrPasswordResetNotInitialized = errors.New("password reset not initialized")
+var (
+	ErrPasswordResetNotInitialized = errors.New("password reset not initialized")
+	ErrEmailNotVerified            = errors.New("email not verified")
+)
 
 type ResetPasswordCommand struct {
+	dbtx.TX
 	queries *db.Queries
+	options config.AuthOptions
 }
 
-func NewResetPasswordCommand(queries *db.Queries) *ResetPasswordCommand {
+func NewResetPasswordCommand(tx dbtx.TX, queries *db.Queries, options config.AuthOptions) *ResetPasswordCommand {
 	return &ResetPasswordCommand{
+		TX:      tx,
 		queries: queries,
+		options: options,
 	}
 }
 
-func (c *ResetPasswordCommand) Execute(ctx context.Context, userEmail, otp, newPassword string) error {
-	user, err := c.queries.GetUserByEmail(ctx, userEmail)
+// Execute resets the password and revokes all refresh tokens and personal access tokens,
+// logging the user out of every device.
+func (c *ResetPasswordCommand) Execute(ctx context.Context, userEmail, OTP, newPassword string, device shared.DeviceInfo) error {
+	if err := c.options.PasswordPolicy.Validate(newPassword); err != nil {
+		return err
+	}
+
+	hashedPassword, err := c.options.PasswordHasher.Hash(newPassword)
+	if err != nil {
+		return fmt.Errorf("failed to generate password: %w", err)
+	}
+
+	tx, err := c.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback()
+	qtx := c.queries.WithTx(tx)
+
+	user, err := qtx.GetUserByEmailForUpdate(ctx, userEmail)
 	if err != nil {
 		return err
 	}
 
+	if !user.EmailVerified {
+		return ErrEmailNotVerified
+	}
+
 	if !user.PasswordUpdateOtp.Valid || !user.PasswordUpdateRequestedAt.Valid {
 		return ErrPasswordResetNotInitialized
 	}
@@ -34,21 +67,39 @@ func (c *ResetPasswordCommand) Execute(ctx context.Context, userEmail, otp, newP
 		return ErrPasswordResetNotInitialized
 	}
 
-	if user.PasswordUpdateOtp.String != otp {
-		return ErrIncorrectOTP
+	ttl := time.Duration(OTPTimeToLiveMinutes) * time.Minute
+	expirationTime := user.PasswordUpdateRequestedAt.Time.Add(ttl)
+	if expirationTime.Before(time.Now()) {
+		return ErrOTPExpired
 	}
 
-	hashedBytes, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
-	if err != nil {
-		return fmt.Errorf("failed to generate password: %w", err)
+	if !otp.Compare(user.PasswordUpdateOtp.String, OTP) {
+		return commitFailedAttempt(tx, passwordUpdateAttemptRecorder(qtx).recordFailure(ctx, user.ID))
 	}
 
-	if err := c.queries.ResetPassword(ctx, db.ResetPasswordParams{
+	rowsAffected, err := qtx.ResetPassword(ctx, db.ResetPasswordParams{
 		ID:             user.ID,
-		HashedPassword: string(hashedBytes),
-	}); err != nil {
+		RequestedAfter: otpRequestedAfter(ttl),
+		HashedPassword: hashedPassword,
+	})
+	if err != nil {
 		return fmt.Errorf("failed to reset password: %w", err)
 	}
+	if rowsAffected == 0 {
+		return ErrOTPExpired
+	}
+
+	if err := qtx.RevokeAllUserRefreshTokens(ctx, user.ID); err != nil {
+		return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+
+	if err := qtx.RevokeAllUserPersonalAccessTokens(ctx, user.ID); err != nil {
+		return fmt.Errorf("failed to revoke personal access tokens: %w", err)
+	}
+
+	if err := recordAuditEvent(ctx, qtx, user.ID, AuditEventPasswordReset, "", device); err != nil {
+		return err
+	}
 
-	return nil
+	return tx.Commit()
 }
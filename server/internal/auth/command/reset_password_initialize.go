@@ -31,16 +31,19 @@ func (c *InitializePasswordResetCommand) Execute(ctx context.Context, userID uui
 		return nil, fmt.Errorf("failed to generate OTP: %w", err)
 	}
 
-	err = c.queries.InitializePasswordReset(ctx, db.InitializePasswordResetParams{
+	rowsAffected, err := c.queries.InitializePasswordReset(ctx, db.InitializePasswordResetParams{
 		ID: userID,
 		PasswordUpdateOtp: sql.NullString{
-			String: OTP,
+			String: otp.Hash(OTP),
 			Valid:  true,
 		},
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize password reset: %w", err)
 	}
+	if rowsAffected == 0 {
+		return nil, ErrOTPLocked
+	}
 
 	return &InitializePasswordResetResponse{
 		OTP: OTP,
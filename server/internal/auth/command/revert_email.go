@@ -0,0 +1,102 @@
+package command
+
+import (
+	"beerbux/internal/auth/db"
+	"beerbux/internal/auth/shared"
+	"beerbux/pkg/dbtx"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrEmailRevertTokenInvalid = errors.New("email revert token invalid")
+	ErrPreviousEmailTaken      = errors.New("previous email address taken")
+)
+
+type RevertEmailCommand struct {
+	dbtx.TX
+	queries *db.Queries
+}
+
+func NewRevertEmailCommand(tx dbtx.TX, queries *db.Queries) *RevertEmailCommand {
+	return &RevertEmailCommand{
+		TX:      tx,
+		queries: queries,
+	}
+}
+
+// Execute restores the email address that was replaced by the email change the token was
+// issued for. Any later email changes can no longer be reverted, and all refresh tokens and
+// personal access tokens are revoked as the change may have been made by someone else.
+func (c *RevertEmailCommand) Execute(ctx context.Context, token string, device shared.DeviceInfo) error {
+	selector, verifier, ok := shared.ParseSelectorVerifierToken(token, "")
+	if !ok {
+		return ErrEmailRevertTokenInvalid
+	}
+
+	revert, err := c.queries.GetEmailChangeRevertBySelector(ctx, selector)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrEmailRevertTokenInvalid
+		}
+		return fmt.Errorf("failed to get email revert token: %w", err)
+	}
+
+	if !shared.VerifySelectorVerifierToken(verifier, revert.HashedVerifier) {
+		return ErrEmailRevertTokenInvalid
+	}
+	if revert.UsedAt.Valid || !revert.ExpiresAt.After(time.Now()) {
+		return ErrEmailRevertTokenInvalid
+	}
+
+	tx, err := c.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback()
+	qtx := c.queries.WithTx(tx)
+
+	rowsAffected, err := qtx.UseEmailChangeRevert(ctx, revert.ID)
+	if err != nil {
+		return fmt.Errorf("failed to use email revert token: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrEmailRevertTokenInvalid
+	}
+
+	err = qtx.CancelLaterEmailChangeReverts(ctx, db.CancelLaterEmailChangeRevertsParams{
+		UserID:       revert.UserID,
+		CreatedAfter: revert.CreatedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to cancel later email revert tokens: %w", err)
+	}
+
+	rowsAffected, err = qtx.RevertEmail(ctx, db.RevertEmailParams{
+		PreviousEmail: revert.PreviousEmail,
+		ID:            revert.UserID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to revert email: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrPreviousEmailTaken
+	}
+
+	if err := qtx.RevokeAllUserRefreshTokens(ctx, revert.UserID); err != nil {
+		return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+
+	if err := qtx.RevokeAllUserPersonalAccessTokens(ctx, revert.UserID); err != nil {
+		return fmt.Errorf("failed to revoke personal access tokens: %w", err)
+	}
+
+	if err := recordAuditEvent(ctx, qtx, revert.UserID, AuditEventEmailReverted, revert.PreviousEmail, device); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,37 @@
+package command
+
+import (
+	"beerbux/internal/auth/db"
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+)
+
+type RevokeOtherSessionsCommand struct {
+	queries *db.Queries
+}
+
+func NewRevokeOtherSessionsCommand(queries *db.Queries) *RevokeOtherSessionsCommand {
+	return &RevokeOtherSessionsCommand{
+		queries: queries,
+	}
+}
+
+// Execute revokes every refresh token for the user except the current one, logging out all other devices.
+func (c *RevokeOtherSessionsCommand) Execute(ctx context.Context, userID uuid.UUID, currentRefreshToken string) error {
+	current, err := getActiveRefreshToken(ctx, c.queries, currentRefreshToken)
+	if err != nil {
+		return err
+	}
+	if current.UserID != userID {
+		return ErrRefreshTokenNotFound
+	}
+
+	if err := c.queries.RevokeOtherUserRefreshTokenFamilies(ctx, db.RevokeOtherUserRefreshTokenFamiliesParams{
+		UserID:   userID,
+		FamilyID: current.FamilyID,
+	}); err != nil {
+		return fmt.Errorf("failed to revoke refresh tokens: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+package command
+
+import (
+	"beerbux/internal/auth/db"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+)
+
+var ErrPersonalAccessTokenNotFound = errors.New("personal access token not found")
+
+type RevokePersonalAccessTokenCommand struct {
+	queries *db.Queries
+}
+
+func NewRevokePersonalAccessTokenCommand(queries *db.Queries) *RevokePersonalAccessTokenCommand {
+	return &RevokePersonalAccessTokenCommand{
+		queries: queries,
+	}
+}
+
+func (c *RevokePersonalAccessTokenCommand) Execute(ctx context.Context, userID, tokenID uuid.UUID) error {
+	rowsAffected, err := c.queries.RevokePersonalAccessToken(ctx, db.RevokePersonalAccessTokenParams{
+		ID:     tokenID,
+		UserID: userID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to revoke personal access token: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrPersonalAccessTokenNotFound
+	}
+	return nil
+}
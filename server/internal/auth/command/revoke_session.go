@@ -0,0 +1,35 @@
+package command
+
+import (
+	"beerbux/internal/auth/db"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+)
+
+var ErrSessionNotFound = errors.New("session not found")
+
+type RevokeSessionCommand struct {
+	queries *db.Queries
+}
+
+func NewRevokeSessionCommand(queries *db.Queries) *RevokeSessionCommand {
+	return &RevokeSessionCommand{
+		queries: queries,
+	}
+}
+
+func (c *RevokeSessionCommand) Execute(ctx context.Context, userID, sessionID uuid.UUID) error {
+	rowsAffected, err := c.queries.RevokeUserRefreshTokenFamily(ctx, db.RevokeUserRefreshTokenFamilyParams{
+		FamilyID: sessionID,
+		UserID:   userID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to revoke refresh token: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrSessionNotFound
+	}
+	return nil
+}
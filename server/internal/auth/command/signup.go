@@ -7,8 +7,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"golang.org/x/crypto/bcrypt"
-	"time"
 )
 
 var (
@@ -31,9 +29,10 @@ func NewSignupCommand(queries *db.Queries, options config.AuthOptions) *SignupCo
 type TokenResponse struct {
 	AccessToken  string
 	RefreshToken string
+	User         AuthenticatedUserDetails
 }
 
-func (c *SignupCommand) Execute(ctx context.Context, name, username, email, password, verificationPassword string) (*TokenResponse, error) {
+func (c *SignupCommand) Execute(ctx context.Context, name, username, email, password, verificationPassword string, device shared.DeviceInfo) (*TokenResponse, error) {
 	usernameTaken, err := c.queries.UserWithUsernameExists(ctx, username)
 	if err != nil {
 		return nil, fmt.Errorf("failed to determine if username exists: %w", err)
@@ -45,7 +44,11 @@ func (c *SignupCommand) Execute(ctx context.Context, name, username, email, pass
 		return nil, ErrPasswordMismatch
 	}
 
-	hashedBytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err := c.options.PasswordPolicy.Validate(password); err != nil {
+		return nil, err
+	}
+
+	hashedPassword, err := c.options.PasswordHasher.Hash(password)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate password: %w", err)
 	}
@@ -54,17 +57,21 @@ func (c *SignupCommand) Execute(ctx context.Context, name, username, email, pass
 		Name:           name,
 		Username:       username,
 		Email:          email,
-		HashedPassword: string(hashedBytes),
+		HashedPassword: hashedPassword,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to create user: %w", err)
 	}
 
+	if err := recordAuditEvent(ctx, c.queries, usr.ID, AuditEventSignup, "", device); err != nil {
+		return nil, err
+	}
+
 	accessToken, err := shared.GenerateJWT(
 		usr.ID,
 		usr.Username,
 		usr.Email,
-		c.options.JWTSecret,
+		c.options.JWTKeys,
 		c.options.AccessTokenTTL,
 	)
 
@@ -72,23 +79,20 @@ func (c *SignupCommand) Execute(ctx context.Context, name, username, email, pass
 		return nil, fmt.Errorf("failed to generate JWT: %w", err)
 	}
 
-	refreshToken := shared.GenerateRefreshToken()
-	hashedRefreshToken, err := shared.HashRefreshToken(refreshToken)
-	if err != nil {
-		return nil, fmt.Errorf("failed to generate refresh token: %w", err)
-	}
-
-	err = c.queries.RegisterRefreshToken(ctx, db.RegisterRefreshTokenParams{
-		UserID:      usr.ID,
-		HashedToken: hashedRefreshToken,
-		ExpiresAt:   time.Now().Add(c.options.RefreshTokenTTL),
-	})
+	refreshToken, err := registerRefreshToken(ctx, c.queries, usr.ID, c.options.RefreshTokenTTL, device)
 	if err != nil {
-		return nil, fmt.Errorf("failed to store refresh token: %w", err)
+		return nil, err
 	}
 
 	return &TokenResponse{
 		AccessToken:  accessToken,
 		RefreshToken: refreshToken,
+		User: AuthenticatedUserDetails{
+			ID:            usr.ID,
+			Username:      usr.Username,
+			Email:         usr.Email,
+			EmailVerified: usr.EmailVerified,
+			Name:          usr.Name,
+		},
 	}, nil
 }
@@ -0,0 +1,69 @@
+package command
+
+import (
+	"beerbux/internal/api/config"
+	"beerbux/internal/auth/db"
+	"beerbux/internal/auth/shared"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// MFATokenTimeToLive is how long the user has to enter their two-factor code after entering their password.
+const MFATokenTimeToLive = 5 * time.Minute
+
+type TwoFactorChallengeCommand struct {
+	queries *db.Queries
+	options config.AuthOptions
+}
+
+func NewTwoFactorChallengeCommand(queries *db.Queries, options config.AuthOptions) *TwoFactorChallengeCommand {
+	return &TwoFactorChallengeCommand{
+		queries: queries,
+		options: options,
+	}
+}
+
+type TwoFactorChallengeResponse struct {
+	Required bool
+	MFAToken string
+}
+
+// Execute determines if the user, who has proved their identity with method, must provide a
+// second factor to log in. If so, an MFA token is returned which must be presented alongside the code.
+func (c *TwoFactorChallengeCommand) Execute(ctx context.Context, username string, method LoginMethod) (*TwoFactorChallengeResponse, error) {
+	user, err := c.queries.GetUserByUsername(ctx, username)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrUserNotFound
+		}
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	twoFactor, err := getUserTwoFactor(ctx, c.queries, user.ID)
+	if err != nil {
+		if errors.Is(err, ErrTwoFactorNotEnrolled) {
+			return &TwoFactorChallengeResponse{Required: false}, nil
+		}
+		return nil, err
+	}
+	if !twoFactor.Enabled {
+		return &TwoFactorChallengeResponse{Required: false}, nil
+	}
+
+	token, err := shared.GenerateMFAToken(shared.MFAToken{
+		UserID:   user.ID,
+		Username: user.Username,
+		Method:   string(method),
+	}, c.options.JWTSecret, MFATokenTimeToLive)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate MFA token: %w", err)
+	}
+
+	return &TwoFactorChallengeResponse{
+		Required: true,
+		MFAToken: token,
+	}, nil
+}
@@ -0,0 +1,44 @@
+package command
+
+import (
+	"beerbux/internal/auth/db"
+	"beerbux/internal/auth/shared"
+	"beerbux/pkg/dbtx"
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+)
+
+type DisableTwoFactorCommand struct {
+	dbtx.TX
+	queries *db.Queries
+}
+
+func NewDisableTwoFactorCommand(tx dbtx.TX, queries *db.Queries) *DisableTwoFactorCommand {
+	return &DisableTwoFactorCommand{
+		TX:      tx,
+		queries: queries,
+	}
+}
+
+func (c *DisableTwoFactorCommand) Execute(ctx context.Context, userID uuid.UUID, device shared.DeviceInfo) error {
+	tx, err := c.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback()
+	qtx := c.queries.WithTx(tx)
+
+	if err := qtx.DeleteUserRecoveryCodes(ctx, userID); err != nil {
+		return fmt.Errorf("failed to delete recovery codes: %w", err)
+	}
+	if err := qtx.DeleteUserTwoFactor(ctx, userID); err != nil {
+		return fmt.Errorf("failed to delete two-factor settings: %w", err)
+	}
+
+	if err := recordAuditEvent(ctx, qtx, userID, AuditEventTwoFactorDisabled, "", device); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
@@ -0,0 +1,119 @@
+package command
+
+import (
+	"beerbux/internal/auth/db"
+	"beerbux/internal/auth/shared"
+	"beerbux/pkg/dbtx"
+	"beerbux/pkg/otp"
+	"beerbux/pkg/totp"
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/google/uuid"
+	"strings"
+	"time"
+)
+
+const (
+	RecoveryCodeCount  = 10
+	RecoveryCodeLength = 10
+)
+
+type EnableTwoFactorCommand struct {
+	dbtx.TX
+	queries *db.Queries
+}
+
+func NewEnableTwoFactorCommand(tx dbtx.TX, queries *db.Queries) *EnableTwoFactorCommand {
+	return &EnableTwoFactorCommand{
+		TX:      tx,
+		queries: queries,
+	}
+}
+
+type EnableTwoFactorResponse struct {
+	RecoveryCodes []string `json:"recoveryCodes"`
+}
+
+// Execute enables two-factor authentication if the code matches the enrolled secret.
+// The returned recovery codes are only stored hashed and cannot be retrieved again.
+func (c *EnableTwoFactorCommand) Execute(ctx context.Context, userID uuid.UUID, code string, device shared.DeviceInfo) (*EnableTwoFactorResponse, error) {
+	twoFactor, err := getUserTwoFactor(ctx, c.queries, userID)
+	if err != nil {
+		return nil, err
+	}
+	if twoFactor.Enabled {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	step, ok := totp.Validate(twoFactor.Secret, code, time.Now())
+	if !ok {
+		return nil, ErrIncorrectTwoFactorCode
+	}
+
+	recoveryCodes, err := generateRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := c.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback()
+	qtx := c.queries.WithTx(tx)
+
+	rowsAffected, err := qtx.EnableUserTwoFactor(ctx, db.EnableUserTwoFactorParams{
+		UserID:       userID,
+		LastUsedStep: sql.NullInt64{Int64: step, Valid: true},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to enable two-factor authentication: %w", err)
+	}
+	if rowsAffected == 0 {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	if err := qtx.DeleteUserRecoveryCodes(ctx, userID); err != nil {
+		return nil, fmt.Errorf("failed to delete existing recovery codes: %w", err)
+	}
+	for _, recoveryCode := range recoveryCodes {
+		if err := qtx.CreateUserRecoveryCode(ctx, db.CreateUserRecoveryCodeParams{
+			UserID:     userID,
+			HashedCode: otp.Hash(normalizeRecoveryCode(recoveryCode)),
+		}); err != nil {
+			return nil, fmt.Errorf("failed to store recovery code: %w", err)
+		}
+	}
+
+	if err := recordAuditEvent(ctx, qtx, userID, AuditEventTwoFactorEnabled, "", device); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit tx: %w", err)
+	}
+
+	return &EnableTwoFactorResponse{
+		RecoveryCodes: recoveryCodes,
+	}, nil
+}
+
+// generateRecoveryCodes returns codes formatted as two hyphenated halves for readability, e.g. abcde-12345.
+func generateRecoveryCodes() ([]string, error) {
+	codes := make([]string, RecoveryCodeCount)
+	for i := range codes {
+		code, err := otp.Generate(RecoveryCodeLength)
+		if err != nil {
+			return nil, fmt.Errorf("failed to generate recovery code: %w", err)
+		}
+		half := RecoveryCodeLength / 2
+		codes[i] = code[:half] + "-" + code[half:]
+	}
+	return codes, nil
+}
+
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.TrimSpace(code))
+	return strings.ReplaceAll(code, "-", "")
+}
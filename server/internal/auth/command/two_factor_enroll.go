@@ -0,0 +1,73 @@
+package command
+
+import (
+	"beerbux/internal/auth/db"
+	"beerbux/pkg/totp"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+)
+
+const TwoFactorIssuer = "Beerbux"
+
+var (
+	ErrTwoFactorAlreadyEnabled = errors.New("two-factor authentication already enabled")
+	ErrTwoFactorNotEnrolled    = errors.New("two-factor authentication not enrolled")
+	ErrTwoFactorNotEnabled     = errors.New("two-factor authentication not enabled")
+	ErrIncorrectTwoFactorCode  = errors.New("incorrect two-factor code")
+	ErrTwoFactorLocked         = errors.New("two-factor authentication is temporarily locked")
+)
+
+type EnrollTwoFactorCommand struct {
+	queries *db.Queries
+}
+
+func NewEnrollTwoFactorCommand(queries *db.Queries) *EnrollTwoFactorCommand {
+	return &EnrollTwoFactorCommand{
+		queries: queries,
+	}
+}
+
+type EnrollTwoFactorResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioningUri"`
+}
+
+// Execute generates a new TOTP secret for the user. Two-factor authentication is not
+// enabled until a code generated from the secret has been verified by the EnableTwoFactorCommand.
+func (c *EnrollTwoFactorCommand) Execute(ctx context.Context, userID uuid.UUID, accountName string) (*EnrollTwoFactorResponse, error) {
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate TOTP secret: %w", err)
+	}
+
+	rowsAffected, err := c.queries.UpsertPendingUserTwoFactor(ctx, db.UpsertPendingUserTwoFactorParams{
+		UserID: userID,
+		Secret: secret,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to store TOTP secret: %w", err)
+	}
+	if rowsAffected == 0 {
+		return nil, ErrTwoFactorAlreadyEnabled
+	}
+
+	return &EnrollTwoFactorResponse{
+		Secret:          secret,
+		ProvisioningURI: totp.ProvisioningURI(TwoFactorIssuer, accountName, secret),
+	}, nil
+}
+
+// getUserTwoFactor returns the two-factor settings for the user or ErrTwoFactorNotEnrolled if there are none.
+func getUserTwoFactor(ctx context.Context, queries *db.Queries, userID uuid.UUID) (db.UserTwoFactor, error) {
+	twoFactor, err := queries.GetUserTwoFactor(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return db.UserTwoFactor{}, ErrTwoFactorNotEnrolled
+		}
+		return db.UserTwoFactor{}, fmt.Errorf("failed to get two-factor settings: %w", err)
+	}
+	return twoFactor, nil
+}
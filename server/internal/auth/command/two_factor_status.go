@@ -0,0 +1,47 @@
+package command
+
+import (
+	"beerbux/internal/auth/db"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+)
+
+type TwoFactorStatusCommand struct {
+	queries *db.Queries
+}
+
+func NewTwoFactorStatusCommand(queries *db.Queries) *TwoFactorStatusCommand {
+	return &TwoFactorStatusCommand{
+		queries: queries,
+	}
+}
+
+type TwoFactorStatusResponse struct {
+	Enabled                bool
+	RemainingRecoveryCodes int
+}
+
+func (c *TwoFactorStatusCommand) Execute(ctx context.Context, userID uuid.UUID) (*TwoFactorStatusResponse, error) {
+	twoFactor, err := getUserTwoFactor(ctx, c.queries, userID)
+	if err != nil {
+		if errors.Is(err, ErrTwoFactorNotEnrolled) {
+			return &TwoFactorStatusResponse{}, nil
+		}
+		return nil, err
+	}
+	if !twoFactor.Enabled {
+		return &TwoFactorStatusResponse{}, nil
+	}
+
+	remaining, err := c.queries.CountUnusedUserRecoveryCodes(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count recovery codes: %w", err)
+	}
+
+	return &TwoFactorStatusResponse{
+		Enabled:                true,
+		RemainingRecoveryCodes: int(remaining),
+	}, nil
+}
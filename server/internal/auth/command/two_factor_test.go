@@ -0,0 +1,85 @@
+package command
+
+import (
+	"beerbux/internal/auth/db"
+	"beerbux/pkg/otp"
+	"database/sql"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+)
+
+var recoveryCodeRegexp = regexp.MustCompile(`^[0-9a-z]{5}-[0-9a-z]{5}$`)
+
+func TestGenerateRecoveryCodes(t *testing.T) {
+	codes, err := generateRecoveryCodes()
+	if err != nil {
+		t.Fatalf("generateRecoveryCodes: %v", err)
+	}
+	if len(codes) != RecoveryCodeCount {
+		t.Fatalf("got %d codes, want %d", len(codes), RecoveryCodeCount)
+	}
+
+	seen := make(map[string]bool)
+	for _, code := range codes {
+		if !recoveryCodeRegexp.MatchString(code) {
+			t.Errorf("code %q is not formatted as two hyphenated halves", code)
+		}
+		if seen[code] {
+			t.Errorf("code %q was generated twice", code)
+		}
+		seen[code] = true
+	}
+}
+
+func TestRecoveryCodeMatchesHowItIsTyped(t *testing.T) {
+	codes, err := generateRecoveryCodes()
+	if err != nil {
+		t.Fatalf("generateRecoveryCodes: %v", err)
+	}
+	code := codes[0]
+
+	// The hash stored when two-factor authentication is enabled.
+	stored := otp.Hash(normalizeRecoveryCode(code))
+
+	for _, typed := range []string{
+		code,
+		code[:5] + code[6:],
+		" " + code + "\n",
+		strings.ToUpper(code),
+	} {
+		if got := otp.Hash(normalizeRecoveryCode(typed)); got != stored {
+			t.Errorf("recovery code typed as %q does not match the stored code %q", typed, code)
+		}
+	}
+
+	if otp.Hash(normalizeRecoveryCode(codes[1])) == stored {
+		t.Error("another recovery code matches the stored code")
+	}
+}
+
+func TestTwoFactorLocked(t *testing.T) {
+	now := time.Now()
+	for _, tt := range []struct {
+		name        string
+		lockedUntil sql.NullTime
+		locked      bool
+	}{
+		{name: "never locked", lockedUntil: sql.NullTime{}, locked: false},
+		{name: "locked", lockedUntil: sql.NullTime{Time: now.Add(time.Minute), Valid: true}, locked: true},
+		{name: "lock expired", lockedUntil: sql.NullTime{Time: now.Add(-time.Minute), Valid: true}, locked: false},
+	} {
+		if got := twoFactorLocked(db.UserTwoFactor{LockedUntil: tt.lockedUntil}, now); got != tt.locked {
+			t.Errorf("%s: locked = %t, want %t", tt.name, got, tt.locked)
+		}
+	}
+}
+
+func TestTwoFactorLockoutOutlastsMFAToken(t *testing.T) {
+	// Any MFA token issued before the lockout must have expired by the time it ends, so that
+	// the user has to enter their password again before guessing more codes.
+	if OTPLockoutDuration <= MFATokenTimeToLive {
+		t.Errorf("OTPLockoutDuration %s does not outlast MFATokenTimeToLive %s", OTPLockoutDuration, MFATokenTimeToLive)
+	}
+}
@@ -0,0 +1,92 @@
+package command
+
+import (
+	"beerbux/internal/auth/db"
+	"beerbux/pkg/otp"
+	"beerbux/pkg/totp"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"time"
+)
+
+type VerifyTwoFactorCodeCommand struct {
+	queries *db.Queries
+}
+
+func NewVerifyTwoFactorCodeCommand(queries *db.Queries) *VerifyTwoFactorCodeCommand {
+	return &VerifyTwoFactorCodeCommand{
+		queries: queries,
+	}
+}
+
+// Execute checks the code against the user's TOTP secret, falling back to the unused recovery codes.
+// TOTP codes can only be used once and a recovery code is consumed when it is used.
+//
+// After MaxOTPAttempts incorrect codes two-factor authentication is locked for OTPLockoutDuration.
+// The lockout outlasts MFATokenTimeToLive, so any MFA token issued before the lockout has expired
+// by the time it ends and the user has to enter their password again.
+func (c *VerifyTwoFactorCodeCommand) Execute(ctx context.Context, userID uuid.UUID, code string) error {
+	twoFactor, err := getUserTwoFactor(ctx, c.queries, userID)
+	if err != nil {
+		return err
+	}
+	if !twoFactor.Enabled {
+		return ErrTwoFactorNotEnabled
+	}
+	if twoFactorLocked(twoFactor, time.Now()) {
+		return ErrTwoFactorLocked
+	}
+
+	if step, ok := totp.Validate(twoFactor.Secret, code, time.Now()); ok {
+		rowsAffected, err := c.queries.UseUserTwoFactorStep(ctx, db.UseUserTwoFactorStepParams{
+			UserID:       userID,
+			LastUsedStep: sql.NullInt64{Int64: step, Valid: true},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to record two-factor code use: %w", err)
+		}
+		if rowsAffected == 0 {
+			// The code has already been used.
+			return c.recordFailure(ctx, userID)
+		}
+		return c.resetAttempts(ctx, twoFactor)
+	}
+
+	rowsAffected, err := c.queries.UseUserRecoveryCode(ctx, db.UseUserRecoveryCodeParams{
+		UserID:     userID,
+		HashedCode: otp.Hash(normalizeRecoveryCode(code)),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to use recovery code: %w", err)
+	}
+	if rowsAffected == 0 {
+		return c.recordFailure(ctx, userID)
+	}
+	return c.resetAttempts(ctx, twoFactor)
+}
+
+// twoFactorLocked reports whether codes are rejected at now after too many incorrect attempts.
+func twoFactorLocked(twoFactor db.UserTwoFactor, now time.Time) bool {
+	return twoFactor.LockedUntil.Valid && now.Before(twoFactor.LockedUntil.Time)
+}
+
+func (c *VerifyTwoFactorCodeCommand) recordFailure(ctx context.Context, userID uuid.UUID) error {
+	err := twoFactorAttemptRecorder(c.queries).recordFailure(ctx, userID)
+	if errors.Is(err, ErrIncorrectOTP) {
+		return ErrIncorrectTwoFactorCode
+	}
+	return err
+}
+
+func (c *VerifyTwoFactorCodeCommand) resetAttempts(ctx context.Context, twoFactor db.UserTwoFactor) error {
+	if twoFactor.FailedAttempts == 0 {
+		return nil
+	}
+	if err := c.queries.ResetTwoFactorAttempts(ctx, twoFactor.UserID); err != nil {
+		return fmt.Errorf("failed to reset two-factor attempts: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package command
+
+import (
+	"beerbux/internal/auth/db"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+)
+
+var (
+	ErrIdentityNotFound = errors.New("identity not found")
+	ErrLastIdentity     = errors.New("cannot unlink the last identity of a user without a verified email")
+)
+
+type UnlinkIdentityCommand struct {
+	queries *db.Queries
+}
+
+func NewUnlinkIdentityCommand(queries *db.Queries) *UnlinkIdentityCommand {
+	return &UnlinkIdentityCommand{
+		queries: queries,
+	}
+}
+
+// Execute unlinks the provider identity from the user. Users created through a provider
+// have no known password and can only set one through a password reset, which requires a
+// verified email, so the last identity cannot be unlinked until the email is verified.
+func (c *UnlinkIdentityCommand) Execute(ctx context.Context, userID, identityID uuid.UUID) error {
+	user, err := c.queries.GetUser(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("failed to get user: %w", err)
+	}
+
+	if !user.EmailVerified {
+		identities, err := c.queries.ListUserIdentitiesByUserID(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("failed to list user identities: %w", err)
+		}
+		if len(identities) == 1 && identities[0].ID == identityID {
+			return ErrLastIdentity
+		}
+	}
+
+	rowsAffected, err := c.queries.DeleteUserIdentity(ctx, db.DeleteUserIdentityParams{
+		ID:     identityID,
+		UserID: userID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete user identity: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrIdentityNotFound
+	}
+	return nil
+}
@@ -2,6 +2,9 @@ package command
 
 import (
 	"beerbux/internal/auth/db"
+	"beerbux/internal/auth/shared"
+	"beerbux/pkg/dbtx"
+	"beerbux/pkg/otp"
 	"context"
 	"database/sql"
 	"errors"
@@ -10,40 +13,95 @@ import (
 	"time"
 )
 
+// EmailRevertTimeToLiveHours is how long the previous email address can undo an email change.
+const EmailRevertTimeToLiveHours int = 72
+
 type UpdateEmailCommand struct {
+	dbtx.TX
 	queries *db.Queries
 }
 
-func NewUpdateEmailCommand(queries *db.Queries) *UpdateEmailCommand {
+func NewUpdateEmailCommand(tx dbtx.TX, queries *db.Queries) *UpdateEmailCommand {
 	return &UpdateEmailCommand{
+		TX:      tx,
 		queries: queries,
 	}
 }
 
-func (c *UpdateEmailCommand) Execute(ctx context.Context, userID uuid.UUID, OTP string) error {
-	user, err := c.queries.GetUser(ctx, userID)
+type UpdateEmailResponse struct {
+	PreviousEmail string
+	NewEmail      string
+	RevertToken   string
+}
+
+// Execute changes the email address once the OTP is confirmed. The returned revert token
+// must be sent to the previous email address so that its owner can undo the change if
+// they did not make it.
+func (c *UpdateEmailCommand) Execute(ctx context.Context, userID uuid.UUID, OTP string, device shared.DeviceInfo) (*UpdateEmailResponse, error) {
+	tx, err := c.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback()
+	qtx := c.queries.WithTx(tx)
+
+	user, err := qtx.GetUserForUpdate(ctx, userID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return ErrUserNotFound
+			return nil, ErrUserNotFound
 		}
-		return fmt.Errorf("failed to get user for email update: %w", err)
+		return nil, fmt.Errorf("failed to get user for email update: %w", err)
 	}
 
-	if !user.EmailUpdateRequestedAt.Valid || !user.EmailUpdateOtp.Valid {
-		return ErrProcessNotInitialized
+	if !user.EmailUpdateRequestedAt.Valid || !user.EmailUpdateOtp.Valid || !user.UpdateEmail.Valid {
+		return nil, ErrProcessNotInitialized
 	}
 
-	expirationTime := user.EmailUpdateRequestedAt.Time.Add(time.Duration(OTPTimeToLiveMinutes) * time.Minute)
+	ttl := time.Duration(OTPTimeToLiveMinutes) * time.Minute
+	expirationTime := user.EmailUpdateRequestedAt.Time.Add(ttl)
 	if expirationTime.Before(time.Now()) {
-		return ErrOTPExpired
+		return nil, ErrOTPExpired
+	}
+
+	if !otp.Compare(user.EmailUpdateOtp.String, OTP) {
+		return nil, commitFailedAttempt(tx, emailUpdateAttemptRecorder(qtx).recordFailure(ctx, userID))
 	}
 
-	if OTP != user.EmailUpdateOtp.String {
-		return ErrIncorrectOTP
+	rowsAffected, err := qtx.UpdateEmail(ctx, db.UpdateEmailParams{
+		ID:             userID,
+		RequestedAfter: otpRequestedAfter(ttl),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update email: %w", err)
+	}
+	if rowsAffected == 0 {
+		return nil, ErrOTPExpired
+	}
+
+	revertToken := shared.GenerateSelectorVerifierToken("")
+	err = qtx.CreateEmailChangeRevert(ctx, db.CreateEmailChangeRevertParams{
+		UserID:         userID,
+		PreviousEmail:  user.Email,
+		NewEmail:       user.UpdateEmail.String,
+		Selector:       revertToken.Selector,
+		HashedVerifier: revertToken.HashedVerifier,
+		ExpiresAt:      time.Now().Add(time.Duration(EmailRevertTimeToLiveHours) * time.Hour),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to store email revert token: %w", err)
+	}
+
+	if err := recordAuditEvent(ctx, qtx, userID, AuditEventEmailChanged, user.UpdateEmail.String, device); err != nil {
+		return nil, err
 	}
 
-	if err := c.queries.UpdateEmail(ctx, userID); err != nil {
-		return fmt.Errorf("failed to update email: %w", err)
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit tx: %w", err)
 	}
-	return nil
+
+	return &UpdateEmailResponse{
+		PreviousEmail: user.Email,
+		NewEmail:      user.UpdateEmail.String,
+		RevertToken:   revertToken.Token,
+	}, nil
 }
@@ -31,20 +31,23 @@ func (c *InitializeUpdateEmailCommand) Execute(ctx context.Context, userID uuid.
 		return nil, fmt.Errorf("failed to generate one-time password: %w", err)
 	}
 
-	err = c.queries.InitialiseUpdateEmail(ctx, db.InitialiseUpdateEmailParams{
+	rowsAffected, err := c.queries.InitialiseUpdateEmail(ctx, db.InitialiseUpdateEmailParams{
 		ID: userID,
 		UpdateEmail: sql.NullString{
 			String: email,
 			Valid:  true,
 		},
 		EmailUpdateOtp: sql.NullString{
-			String: OTP,
+			String: otp.Hash(OTP),
 			Valid:  true,
 		},
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialise update email: %w", err)
 	}
+	if rowsAffected == 0 {
+		return nil, ErrOTPLocked
+	}
 
 	return &InitializeUpdateEmailCommandResponse{
 		OTP: OTP,
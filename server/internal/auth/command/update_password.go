@@ -2,6 +2,9 @@ package command
 
 import (
 	"beerbux/internal/auth/db"
+	"beerbux/internal/auth/shared"
+	"beerbux/pkg/dbtx"
+	"beerbux/pkg/otp"
 	"context"
 	"database/sql"
 	"errors"
@@ -17,17 +20,26 @@ var ErrOTPExpired = errors.New("OTP has expired")
 var ErrIncorrectOTP = errors.New("incorrect OTP")
 
 type UpdatePasswordCommand struct {
+	dbtx.TX
 	queries *db.Queries
 }
 
-func NewUpdatePasswordCommand(queries *db.Queries) *UpdatePasswordCommand {
+func NewUpdatePasswordCommand(tx dbtx.TX, queries *db.Queries) *UpdatePasswordCommand {
 	return &UpdatePasswordCommand{
+		TX:      tx,
 		queries: queries,
 	}
 }
 
-func (c *UpdatePasswordCommand) Execute(ctx context.Context, userID uuid.UUID, OTP string) error {
-	user, err := c.queries.GetUser(ctx, userID)
+func (c *UpdatePasswordCommand) Execute(ctx context.Context, userID uuid.UUID, OTP string, device shared.DeviceInfo) error {
+	tx, err := c.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback()
+	qtx := c.queries.WithTx(tx)
+
+	user, err := qtx.GetUserForUpdate(ctx, userID)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return ErrUserNotFound
@@ -39,19 +51,30 @@ func (c *UpdatePasswordCommand) Execute(ctx context.Context, userID uuid.UUID, O
 		return ErrProcessNotInitialized
 	}
 
-	now := time.Now()
-	expirationTime := user.PasswordUpdateRequestedAt.Time.Add(time.Duration(OTPTimeToLiveMinutes) * time.Minute)
-	if expirationTime.Before(now) {
+	ttl := time.Duration(OTPTimeToLiveMinutes) * time.Minute
+	expirationTime := user.PasswordUpdateRequestedAt.Time.Add(ttl)
+	if expirationTime.Before(time.Now()) {
 		return ErrOTPExpired
 	}
 
-	if OTP != user.PasswordUpdateOtp.String {
-		return ErrIncorrectOTP
+	if !otp.Compare(user.PasswordUpdateOtp.String, OTP) {
+		return commitFailedAttempt(tx, passwordUpdateAttemptRecorder(qtx).recordFailure(ctx, userID))
 	}
 
-	if err := c.queries.UpdatePassword(ctx, userID); err != nil {
+	rowsAffected, err := qtx.UpdatePassword(ctx, db.UpdatePasswordParams{
+		ID:             userID,
+		RequestedAfter: otpRequestedAfter(ttl),
+	})
+	if err != nil {
 		return fmt.Errorf("failed to update password: %w", err)
 	}
+	if rowsAffected == 0 {
+		return ErrOTPExpired
+	}
+
+	if err := recordAuditEvent(ctx, qtx, userID, AuditEventPasswordChanged, "", device); err != nil {
+		return err
+	}
 
-	return nil
+	return tx.Commit()
 }
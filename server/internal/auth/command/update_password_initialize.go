@@ -1,24 +1,26 @@
 package command
 
 import (
+	"beerbux/internal/api/config"
 	"beerbux/internal/auth/db"
 	"beerbux/pkg/otp"
 	"context"
 	"database/sql"
 	"fmt"
 	"github.com/google/uuid"
-	"golang.org/x/crypto/bcrypt"
 )
 
 const PasswordUpdateOTPLength = 6
 
 type InitializeUpdatePasswordCommand struct {
 	queries *db.Queries
+	options config.AuthOptions
 }
 
-func NewInitializeUpdatePasswordCommand(queries *db.Queries) *InitializeUpdatePasswordCommand {
+func NewInitializeUpdatePasswordCommand(queries *db.Queries, options config.AuthOptions) *InitializeUpdatePasswordCommand {
 	return &InitializeUpdatePasswordCommand{
 		queries: queries,
+		options: options,
 	}
 }
 
@@ -27,7 +29,11 @@ type InitializeUpdatePasswordResponse struct {
 }
 
 func (c *InitializeUpdatePasswordCommand) Execute(ctx context.Context, userID uuid.UUID, password string) (*InitializeUpdatePasswordResponse, error) {
-	hashedBytes, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err := c.options.PasswordPolicy.Validate(password); err != nil {
+		return nil, err
+	}
+
+	hashedPassword, err := c.options.PasswordHasher.Hash(password)
 	if err != nil {
 		return nil, fmt.Errorf("failed to generate password: %w", err)
 	}
@@ -37,20 +43,23 @@ func (c *InitializeUpdatePasswordCommand) Execute(ctx context.Context, userID uu
 		return nil, fmt.Errorf("failed to generate one-time password: %w", err)
 	}
 
-	err = c.queries.InitializePasswordUpdate(ctx, db.InitializePasswordUpdateParams{
+	rowsAffected, err := c.queries.InitializePasswordUpdate(ctx, db.InitializePasswordUpdateParams{
 		ID: userID,
 		PasswordUpdateOtp: sql.NullString{
-			String: OTP,
+			String: otp.Hash(OTP),
 			Valid:  true,
 		},
 		UpdateHashedPassword: sql.NullString{
-			String: string(hashedBytes),
+			String: hashedPassword,
 			Valid:  true,
 		},
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize password update: %w", err)
 	}
+	if rowsAffected == 0 {
+		return nil, ErrOTPLocked
+	}
 
 	return &InitializeUpdatePasswordResponse{
 		OTP: OTP,
@@ -8,6 +8,8 @@ import (
 const (
 	AccessTokenKey  = "access_token"
 	RefreshTokenKey = "refresh_token"
+	OIDCStateKey    = "oidc_state"
+	OIDCStatePath   = "/api/auth/oidc"
 )
 
 func SetAccessTokenCookie(w http.ResponseWriter, token string) {
@@ -33,3 +35,30 @@ func SetRefreshTokenCookie(w http.ResponseWriter, token string) {
 		SameSite: http.SameSiteLaxMode,
 	})
 }
+
+// SetOIDCStateCookie stores the state of an OpenID Connect login until the provider redirects back.
+// SameSite Lax allows the cookie to be sent on the top-level redirect from the provider.
+func SetOIDCStateCookie(w http.ResponseWriter, state string, ttl time.Duration) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     OIDCStateKey,
+		Value:    state,
+		Path:     OIDCStatePath,
+		Expires:  time.Now().Add(ttl),
+		HttpOnly: true,
+		Secure:   false,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+func ClearOIDCStateCookie(w http.ResponseWriter) {
+	http.SetCookie(w, &http.Cookie{
+		Name:     OIDCStateKey,
+		Value:    "",
+		Path:     OIDCStatePath,
+		Expires:  time.Unix(0, 0),
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   false,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
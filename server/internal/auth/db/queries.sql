@@ -7,6 +7,12 @@ select * from users where username = $1 limit 1;
 -- name: GetUserByEmail :one
 select * from users where email = $1 limit 1;
 
+-- name: GetUserForUpdate :one
+select * from users where id = $1 limit 1 for update;
+
+-- name: GetUserByEmailForUpdate :one
+select * from users where email = $1 limit 1 for update;
+
 -- name: UserWithUsernameExists :one
 select exists(select 1 from users where username = $1);
 
@@ -16,36 +22,33 @@ values ($1, $2, $3, $4)
 returning *;
 
 -- name: RegisterRefreshToken :exec
-insert into refresh_tokens (user_id, hashed_token, expires_at)
-values ($1, $2, $3);
+insert into refresh_tokens (user_id, family_id, selector, hashed_verifier, expires_at, user_agent, ip_address)
+values ($1, $2, $3, $4, $5, $6, $7);
 
 -- name: GetRefreshTokensByUserID :many
 select *
 from refresh_tokens
 where user_id = $1
   and revoked = false
+  and replaced_at is null
   and expires_at > now();
 
--- name: DeleteRefreshToken :exec
-delete from refresh_tokens where id = $1;
-
--- name: InvalidateRefreshToken :exec
-update refresh_tokens
-set revoked = true
-where id = $1;
-
--- name: InitializePasswordUpdate :exec
+-- name: InitializePasswordUpdate :execrows
 update users
 set update_hashed_password = $2,
     password_update_otp = $3,
-    password_update_requested_at = now()
-where id = $1;
+    password_update_requested_at = now(),
+    password_update_otp_attempts = 0
+where id = $1
+  and (password_otp_locked_until is null or password_otp_locked_until <= now());
 
--- name: UpdatePassword :exec
+-- name: UpdatePassword :execrows
 with updated as (
     select id, update_hashed_password
     from users updated_users
-    where updated_users.id = $1 and updated_users.password_update_requested_at is not null
+    where updated_users.id = @id
+      and updated_users.update_hashed_password is not null
+      and updated_users.password_update_requested_at > @requested_after::timestamptz
 )
 update users u
 set
@@ -53,51 +56,387 @@ set
     update_hashed_password = null,
     password_update_otp = null,
     password_update_requested_at = null,
+    password_update_otp_attempts = 0,
     password_last_updated_at = now()
 from updated
 where u.id = updated.id;
 
--- name: InitializePasswordReset :exec
+-- name: InitializePasswordReset :execrows
 update users
 set update_hashed_password = null,
     password_update_otp = $2,
-    password_update_requested_at = now()
-where id = $1;
+    password_update_requested_at = now(),
+    password_update_otp_attempts = 0
+where id = $1
+  and (password_otp_locked_until is null or password_otp_locked_until <= now());
 
--- name: ResetPassword :exec
+-- name: ResetPassword :execrows
 with updated as (
     select id, update_hashed_password
     from users updated_users
-    where updated_users.id = $1 and updated_users.password_update_requested_at is not null
+    where updated_users.id = @id
+      and updated_users.update_hashed_password is null
+      and updated_users.password_update_requested_at > @requested_after::timestamptz
 )
 update users u
 set
-    hashed_password = $2,
+    hashed_password = @hashed_password,
     update_hashed_password = null,
     password_update_otp = null,
     password_update_requested_at = null,
+    password_update_otp_attempts = 0,
     password_last_updated_at = now()
 from updated
 where u.id = updated.id;
 
--- name: InitialiseUpdateEmail :exec
+-- name: InitialiseUpdateEmail :execrows
 update users
 set update_email = $2,
     email_update_otp = $3,
-    email_update_requested_at = now()
-where id = $1;
+    email_update_requested_at = now(),
+    email_update_otp_attempts = 0
+where id = $1
+  and (email_update_otp_locked_until is null or email_update_otp_locked_until <= now());
 
--- name: UpdateEmail :exec
+-- name: UpdateEmail :execrows
 with updated as (
     select id, update_email
     from users updated_users
-    where updated_users.id = $1 and updated_users.email_update_requested_at is not null
+    where updated_users.id = @id
+      and updated_users.email_update_requested_at > @requested_after::timestamptz
 )
 update users u
 set email = updated.update_email,
     update_email = null,
     email_update_otp = null,
     email_update_requested_at = null,
+    email_update_otp_attempts = 0,
     email_last_updated_at = now()
 from updated
-where u.id = updated.id;
\ No newline at end of file
+where u.id = updated.id;
+
+-- name: InitializeEmailVerification :execrows
+update users
+set email_verification_otp = $2,
+    email_verification_requested_at = now(),
+    email_verification_otp_attempts = 0
+where id = $1
+  and email_verified = false
+  and (email_verification_otp_locked_until is null or email_verification_otp_locked_until <= now());
+
+-- name: VerifyEmail :execrows
+update users
+set email_verified = true,
+    email_verification_otp = null,
+    email_verification_requested_at = null,
+    email_verification_otp_attempts = 0
+where id = @id
+  and email_verification_requested_at > @requested_after::timestamptz;
+
+-- name: IncrementPasswordUpdateOTPAttempts :one
+update users
+set password_update_otp_attempts = password_update_otp_attempts + 1
+where id = $1
+returning password_update_otp_attempts;
+
+-- name: LockPasswordUpdateOTP :exec
+update users
+set update_hashed_password = null,
+    password_update_otp = null,
+    password_update_requested_at = null,
+    password_update_otp_attempts = 0,
+    password_otp_locked_until = $2
+where id = $1;
+
+-- name: IncrementEmailUpdateOTPAttempts :one
+update users
+set email_update_otp_attempts = email_update_otp_attempts + 1
+where id = $1
+returning email_update_otp_attempts;
+
+-- name: LockEmailUpdateOTP :exec
+update users
+set update_email = null,
+    email_update_otp = null,
+    email_update_requested_at = null,
+    email_update_otp_attempts = 0,
+    email_update_otp_locked_until = $2
+where id = $1;
+
+-- name: IncrementEmailVerificationOTPAttempts :one
+update users
+set email_verification_otp_attempts = email_verification_otp_attempts + 1
+where id = $1
+returning email_verification_otp_attempts;
+
+-- name: LockEmailVerificationOTP :exec
+update users
+set email_verification_otp = null,
+    email_verification_requested_at = null,
+    email_verification_otp_attempts = 0,
+    email_verification_otp_locked_until = $2
+where id = $1;
+
+-- name: GetUserTwoFactor :one
+select * from user_two_factor where user_id = $1 limit 1;
+
+-- name: UpsertPendingUserTwoFactor :execrows
+insert into user_two_factor (user_id, secret)
+values ($1, $2)
+on conflict (user_id) do update
+set secret = excluded.secret,
+    last_used_step = null
+where user_two_factor.enabled = false;
+
+-- name: EnableUserTwoFactor :execrows
+update user_two_factor
+set enabled = true,
+    enabled_at = now(),
+    last_used_step = $2
+where user_id = $1 and enabled = false;
+
+-- name: UseUserTwoFactorStep :execrows
+update user_two_factor
+set last_used_step = $2
+where user_id = $1
+  and enabled = true
+  and (last_used_step is null or last_used_step < $2);
+
+-- name: DeleteUserTwoFactor :exec
+delete from user_two_factor where user_id = $1;
+
+-- name: CreateUserRecoveryCode :exec
+insert into user_recovery_codes (user_id, hashed_code)
+values ($1, $2);
+
+-- name: DeleteUserRecoveryCodes :exec
+delete from user_recovery_codes where user_id = $1;
+
+-- name: UseUserRecoveryCode :execrows
+update user_recovery_codes
+set used_at = now()
+where user_id = $1 and hashed_code = $2 and used_at is null;
+
+-- name: CountUnusedUserRecoveryCodes :one
+select count(*) from user_recovery_codes
+where user_id = $1 and used_at is null;
+
+-- name: RotateRefreshToken :execrows
+with replaced as (
+    update refresh_tokens
+    set replaced_at = now(),
+        last_used_at = now()
+    where id = @id
+      and revoked = false
+      and replaced_at is null
+    returning user_id, family_id, created_at
+)
+insert into refresh_tokens (user_id, family_id, selector, hashed_verifier, expires_at, user_agent, ip_address, created_at)
+select user_id, family_id, @selector::text, @hashed_verifier::text, @expires_at::timestamptz, sqlc.narg(user_agent)::text, sqlc.narg(ip_address)::text, created_at
+from replaced;
+
+-- name: RevokeAllUserRefreshTokens :exec
+update refresh_tokens
+set revoked = true
+where user_id = $1 and revoked = false;
+
+-- name: GetRefreshTokenBySelector :one
+select *
+from refresh_tokens
+where selector = $1;
+
+-- name: RevokeRefreshTokenFamily :exec
+update refresh_tokens
+set revoked = true
+where family_id = $1 and revoked = false;
+
+-- name: RevokeUserRefreshTokenFamily :execrows
+update refresh_tokens
+set revoked = true
+where family_id = $1 and user_id = $2 and revoked = false;
+
+-- name: RevokeOtherUserRefreshTokenFamilies :exec
+update refresh_tokens
+set revoked = true
+where user_id = $1 and family_id <> $2 and revoked = false;
+
+-- name: DeleteStaleRefreshTokens :execrows
+delete from refresh_tokens
+where revoked = true or expires_at <= now();
+
+-- name: CreatePersonalAccessToken :one
+insert into personal_access_tokens (user_id, name, selector, hashed_verifier, scopes, expires_at)
+values ($1, $2, $3, $4, $5, $6)
+returning *;
+
+-- name: GetPersonalAccessTokenBySelector :one
+select pat.id, pat.user_id, pat.hashed_verifier, pat.scopes, pat.expires_at, pat.revoked_at, u.username, u.email
+from personal_access_tokens pat
+join users u on u.id = pat.user_id
+where pat.selector = $1;
+
+-- name: ListPersonalAccessTokensByUserID :many
+select *
+from personal_access_tokens
+where user_id = $1
+  and revoked_at is null
+order by created_at desc;
+
+-- name: TouchPersonalAccessToken :exec
+update personal_access_tokens
+set last_used_at = now()
+where id = $1
+  and (last_used_at is null or last_used_at < now() - interval '1 minute');
+
+-- name: RevokePersonalAccessToken :execrows
+update personal_access_tokens
+set revoked_at = now()
+where id = $1 and user_id = $2 and revoked_at is null;
+
+-- name: RevokeAllUserPersonalAccessTokens :exec
+update personal_access_tokens
+set revoked_at = now()
+where user_id = $1 and revoked_at is null;
+
+-- name: CreateOIDCUser :one
+insert into users (name, username, email, hashed_password, email_verified)
+values ($1, $2, $3, $4, $5)
+returning *;
+
+-- name: CreateUserIdentity :one
+insert into user_identities (user_id, provider, subject, email, last_login_at)
+values ($1, $2, $3, $4, $5)
+returning *;
+
+-- name: GetUserIdentityByProviderSubject :one
+select *
+from user_identities
+where provider = $1 and subject = $2;
+
+-- name: ListUserIdentitiesByUserID :many
+select *
+from user_identities
+where user_id = $1
+order by created_at;
+
+-- name: UpdateUserIdentityLogin :exec
+update user_identities
+set last_login_at = now(), email = $2
+where id = $1;
+
+-- name: DeleteUserIdentity :execrows
+delete from user_identities
+where id = $1 and user_id = $2;
+
+-- name: InitializeLoginOTP :execrows
+update users
+set login_otp = $2,
+    login_otp_requested_at = now(),
+    login_otp_attempts = 0
+where id = $1
+  and email_verified = true
+  and (login_otp_locked_until is null or login_otp_locked_until <= now());
+
+-- name: ConsumeLoginOTP :execrows
+update users
+set login_otp = null,
+    login_otp_requested_at = null,
+    login_otp_attempts = 0
+where id = @id
+  and login_otp = @login_otp
+  and login_otp_requested_at > @requested_after::timestamptz;
+
+-- name: IncrementLoginOTPAttempts :one
+update users
+set login_otp_attempts = login_otp_attempts + 1
+where id = $1
+returning login_otp_attempts;
+
+-- name: LockLoginOTP :exec
+update users
+set login_otp = null,
+    login_otp_requested_at = null,
+    login_otp_attempts = 0,
+    login_otp_locked_until = $2
+where id = $1;
+
+-- name: RehashUserPassword :exec
+update users
+set hashed_password = @new_hashed_password
+where id = @id
+  and hashed_password = @old_hashed_password;
+
+-- name: CreateUserAuditEvent :exec
+insert into user_audit_events (user_id, event, detail, user_agent, ip_address)
+values ($1, $2, $3, $4, $5);
+
+-- name: ListUserAuditEvents :many
+select *
+from user_audit_events
+where user_id = $1
+order by created_at desc
+limit $2;
+
+-- name: GetLoginDeviceHistory :one
+select
+    count(*) > 0 as has_logins,
+    count(*) filter (where user_agent is not distinct from @user_agent) > 0 as seen_user_agent,
+    count(*) filter (where ip_address is not distinct from @ip_address) > 0 as seen_ip_address
+from user_audit_events
+where user_id = @user_id
+  and event in ('signup', 'login');
+
+-- name: CreateEmailChangeRevert :exec
+insert into email_change_reverts (user_id, previous_email, new_email, selector, hashed_verifier, expires_at)
+values ($1, $2, $3, $4, $5, $6);
+
+-- name: GetEmailChangeRevertBySelector :one
+select *
+from email_change_reverts
+where selector = $1;
+
+-- name: UseEmailChangeRevert :execrows
+update email_change_reverts
+set used_at = now()
+where id = $1
+  and used_at is null;
+
+-- name: CancelLaterEmailChangeReverts :exec
+update email_change_reverts
+set used_at = now()
+where user_id = @user_id
+  and created_at > @created_after
+  and used_at is null;
+
+-- name: RevertEmail :execrows
+update users
+set email = @previous_email,
+    update_email = null,
+    email_update_otp = null,
+    email_update_requested_at = null,
+    email_update_otp_attempts = 0,
+    email_last_updated_at = now()
+where id = @id
+  and not exists (
+    select 1
+    from users other_users
+    where other_users.email = @previous_email
+      and other_users.id <> @id
+  );
+
+-- name: IncrementTwoFactorAttempts :one
+update user_two_factor
+set failed_attempts = failed_attempts + 1
+where user_id = $1
+returning failed_attempts;
+
+-- name: LockTwoFactor :exec
+update user_two_factor
+set failed_attempts = 0,
+    locked_until = $2
+where user_id = $1;
+
+-- name: ResetTwoFactorAttempts :exec
+update user_two_factor
+set failed_attempts = 0
+where user_id = $1;
@@ -11,12 +11,171 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
+const cancelLaterEmailChangeReverts = `-- name: CancelLaterEmailChangeReverts :exec
+update email_change_reverts
+set used_at = now()
+where user_id = $1
+  and created_at > $2
+  and used_at is null
+`
+
+type CancelLaterEmailChangeRevertsParams struct {
+	UserID       uuid.UUID
+	CreatedAfter time.Time
+}
+
+func (q *Queries) CancelLaterEmailChangeReverts(ctx context.Context, arg CancelLaterEmailChangeRevertsParams) error {
+	_, err := q.db.ExecContext(ctx, cancelLaterEmailChangeReverts, arg.UserID, arg.CreatedAfter)
+	return err
+}
+
+const consumeLoginOTP = `-- name: ConsumeLoginOTP :execrows
+update users
+set login_otp = null,
+    login_otp_requested_at = null,
+    login_otp_attempts = 0
+where id = $1
+  and login_otp = $2
+  and login_otp_requested_at > $3::timestamptz
+`
+
+type ConsumeLoginOTPParams struct {
+	ID             uuid.UUID
+	LoginOtp       sql.NullString
+	RequestedAfter time.Time
+}
+
+func (q *Queries) ConsumeLoginOTP(ctx context.Context, arg ConsumeLoginOTPParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, consumeLoginOTP, arg.ID, arg.LoginOtp, arg.RequestedAfter)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const countUnusedUserRecoveryCodes = `-- name: CountUnusedUserRecoveryCodes :one
+select count(*) from user_recovery_codes
+where user_id = $1 and used_at is null
+`
+
+func (q *Queries) CountUnusedUserRecoveryCodes(ctx context.Context, userID uuid.UUID) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countUnusedUserRecoveryCodes, userID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
+const createEmailChangeRevert = `-- name: CreateEmailChangeRevert :exec
+insert into email_change_reverts (user_id, previous_email, new_email, selector, hashed_verifier, expires_at)
+values ($1, $2, $3, $4, $5, $6)
+`
+
+type CreateEmailChangeRevertParams struct {
+	UserID         uuid.UUID
+	PreviousEmail  string
+	NewEmail       string
+	Selector       string
+	HashedVerifier string
+	ExpiresAt      time.Time
+}
+
+func (q *Queries) CreateEmailChangeRevert(ctx context.Context, arg CreateEmailChangeRevertParams) error {
+	_, err := q.db.ExecContext(ctx, createEmailChangeRevert, arg.UserID, arg.PreviousEmail, arg.NewEmail, arg.Selector, arg.HashedVerifier, arg.ExpiresAt)
+	return err
+}
+
+const createOIDCUser = `-- name: CreateOIDCUser :one
+insert into users (name, username, email, hashed_password, email_verified)
+values ($1, $2, $3, $4, $5)
+returning id, username, email, update_email, email_update_requested_at, email_update_otp, email_last_updated_at, name, hashed_password, update_hashed_password, password_update_requested_at, password_update_otp, password_last_updated_at, created_at, updated_at, email_verified, email_verification_otp, email_verification_requested_at, password_update_otp_attempts, email_update_otp_attempts, email_verification_otp_attempts, password_otp_locked_until, email_update_otp_locked_until, email_verification_otp_locked_until, login_otp, login_otp_requested_at, login_otp_attempts, login_otp_locked_until, locale
+`
+
+type CreateOIDCUserParams struct {
+	Name           string
+	Username       string
+	Email          string
+	HashedPassword string
+	EmailVerified  bool
+}
+
+func (q *Queries) CreateOIDCUser(ctx context.Context, arg CreateOIDCUserParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, createOIDCUser, arg.Name, arg.Username, arg.Email, arg.HashedPassword, arg.EmailVerified)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Email,
+		&i.UpdateEmail,
+		&i.EmailUpdateRequestedAt,
+		&i.EmailUpdateOtp,
+		&i.EmailLastUpdatedAt,
+		&i.Name,
+		&i.HashedPassword,
+		&i.UpdateHashedPassword,
+		&i.PasswordUpdateRequestedAt,
+		&i.PasswordUpdateOtp,
+		&i.PasswordLastUpdatedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.EmailVerified,
+		&i.EmailVerificationOtp,
+		&i.EmailVerificationRequestedAt,
+		&i.PasswordUpdateOtpAttempts,
+		&i.EmailUpdateOtpAttempts,
+		&i.EmailVerificationOtpAttempts,
+		&i.PasswordOtpLockedUntil,
+		&i.EmailUpdateOtpLockedUntil,
+		&i.EmailVerificationOtpLockedUntil,
+		&i.LoginOtp,
+		&i.LoginOtpRequestedAt,
+		&i.LoginOtpAttempts,
+		&i.LoginOtpLockedUntil,
+		&i.Locale,
+	)
+	return i, err
+}
+
+const createPersonalAccessToken = `-- name: CreatePersonalAccessToken :one
+insert into personal_access_tokens (user_id, name, selector, hashed_verifier, scopes, expires_at)
+values ($1, $2, $3, $4, $5, $6)
+returning id, user_id, name, selector, hashed_verifier, scopes, expires_at, last_used_at, revoked_at, created_at, updated_at
+`
+
+type CreatePersonalAccessTokenParams struct {
+	UserID         uuid.UUID
+	Name           string
+	Selector       string
+	HashedVerifier string
+	Scopes         []string
+	ExpiresAt      time.Time
+}
+
+func (q *Queries) CreatePersonalAccessToken(ctx context.Context, arg CreatePersonalAccessTokenParams) (PersonalAccessToken, error) {
+	row := q.db.QueryRowContext(ctx, createPersonalAccessToken, arg.UserID, arg.Name, arg.Selector, arg.HashedVerifier, pq.Array(arg.Scopes), arg.ExpiresAt)
+	var i PersonalAccessToken
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Name,
+		&i.Selector,
+		&i.HashedVerifier,
+		pq.Array(&i.Scopes),
+		&i.ExpiresAt,
+		&i.LastUsedAt,
+		&i.RevokedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
 const createUser = `-- name: CreateUser :one
 insert into users (name, username, email, hashed_password)
 values ($1, $2, $3, $4)
-returning id, username, email, update_email, email_update_requested_at, email_update_otp, email_last_updated_at, name, hashed_password, update_hashed_password, password_update_requested_at, password_update_otp, password_last_updated_at, created_at, updated_at
+returning id, username, email, update_email, email_update_requested_at, email_update_otp, email_last_updated_at, name, hashed_password, update_hashed_password, password_update_requested_at, password_update_otp, password_last_updated_at, created_at, updated_at, email_verified, email_verification_otp, email_verification_requested_at, password_update_otp_attempts, email_update_otp_attempts, email_verification_otp_attempts, password_otp_locked_until, email_update_otp_locked_until, email_verification_otp_locked_until, login_otp, login_otp_requested_at, login_otp_attempts, login_otp_locked_until, locale
 `
 
 type CreateUserParams struct {
@@ -50,24 +209,280 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.PasswordLastUpdatedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.EmailVerified,
+		&i.EmailVerificationOtp,
+		&i.EmailVerificationRequestedAt,
+		&i.PasswordUpdateOtpAttempts,
+		&i.EmailUpdateOtpAttempts,
+		&i.EmailVerificationOtpAttempts,
+		&i.PasswordOtpLockedUntil,
+		&i.EmailUpdateOtpLockedUntil,
+		&i.EmailVerificationOtpLockedUntil,
+		&i.LoginOtp,
+		&i.LoginOtpRequestedAt,
+		&i.LoginOtpAttempts,
+		&i.LoginOtpLockedUntil,
+		&i.Locale,
+	)
+	return i, err
+}
+
+const createUserAuditEvent = `-- name: CreateUserAuditEvent :exec
+insert into user_audit_events (user_id, event, detail, user_agent, ip_address)
+values ($1, $2, $3, $4, $5)
+`
+
+type CreateUserAuditEventParams struct {
+	UserID    uuid.UUID
+	Event     string
+	Detail    sql.NullString
+	UserAgent sql.NullString
+	IpAddress sql.NullString
+}
+
+func (q *Queries) CreateUserAuditEvent(ctx context.Context, arg CreateUserAuditEventParams) error {
+	_, err := q.db.ExecContext(ctx, createUserAuditEvent, arg.UserID, arg.Event, arg.Detail, arg.UserAgent, arg.IpAddress)
+	return err
+}
+
+const createUserIdentity = `-- name: CreateUserIdentity :one
+insert into user_identities (user_id, provider, subject, email, last_login_at)
+values ($1, $2, $3, $4, $5)
+returning id, user_id, provider, subject, email, last_login_at, created_at, updated_at
+`
+
+type CreateUserIdentityParams struct {
+	UserID      uuid.UUID
+	Provider    string
+	Subject     string
+	Email       sql.NullString
+	LastLoginAt sql.NullTime
+}
+
+func (q *Queries) CreateUserIdentity(ctx context.Context, arg CreateUserIdentityParams) (UserIdentity, error) {
+	row := q.db.QueryRowContext(ctx, createUserIdentity, arg.UserID, arg.Provider, arg.Subject, arg.Email, arg.LastLoginAt)
+	var i UserIdentity
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Provider,
+		&i.Subject,
+		&i.Email,
+		&i.LastLoginAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
 	)
 	return i, err
 }
 
-const deleteRefreshToken = `-- name: DeleteRefreshToken :exec
-delete from refresh_tokens where id = $1
+const createUserRecoveryCode = `-- name: CreateUserRecoveryCode :exec
+insert into user_recovery_codes (user_id, hashed_code)
+values ($1, $2)
+`
+
+type CreateUserRecoveryCodeParams struct {
+	UserID     uuid.UUID
+	HashedCode string
+}
+
+func (q *Queries) CreateUserRecoveryCode(ctx context.Context, arg CreateUserRecoveryCodeParams) error {
+	_, err := q.db.ExecContext(ctx, createUserRecoveryCode, arg.UserID, arg.HashedCode)
+	return err
+}
+
+const deleteStaleRefreshTokens = `-- name: DeleteStaleRefreshTokens :execrows
+delete from refresh_tokens
+where revoked = true or expires_at <= now()
+`
+
+func (q *Queries) DeleteStaleRefreshTokens(ctx context.Context) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteStaleRefreshTokens)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteUserIdentity = `-- name: DeleteUserIdentity :execrows
+delete from user_identities
+where id = $1 and user_id = $2
+`
+
+type DeleteUserIdentityParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) DeleteUserIdentity(ctx context.Context, arg DeleteUserIdentityParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteUserIdentity, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const deleteUserRecoveryCodes = `-- name: DeleteUserRecoveryCodes :exec
+delete from user_recovery_codes where user_id = $1
+`
+
+func (q *Queries) DeleteUserRecoveryCodes(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteUserRecoveryCodes, userID)
+	return err
+}
+
+const deleteUserTwoFactor = `-- name: DeleteUserTwoFactor :exec
+delete from user_two_factor where user_id = $1
 `
 
-func (q *Queries) DeleteRefreshToken(ctx context.Context, id int32) error {
-	_, err := q.db.ExecContext(ctx, deleteRefreshToken, id)
+func (q *Queries) DeleteUserTwoFactor(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteUserTwoFactor, userID)
 	return err
 }
 
+const enableUserTwoFactor = `-- name: EnableUserTwoFactor :execrows
+update user_two_factor
+set enabled = true,
+    enabled_at = now(),
+    last_used_step = $2
+where user_id = $1 and enabled = false
+`
+
+type EnableUserTwoFactorParams struct {
+	UserID       uuid.UUID
+	LastUsedStep sql.NullInt64
+}
+
+func (q *Queries) EnableUserTwoFactor(ctx context.Context, arg EnableUserTwoFactorParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, enableUserTwoFactor, arg.UserID, arg.LastUsedStep)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getEmailChangeRevertBySelector = `-- name: GetEmailChangeRevertBySelector :one
+select id, user_id, previous_email, new_email, selector, hashed_verifier, expires_at, used_at, created_at
+from email_change_reverts
+where selector = $1
+`
+
+func (q *Queries) GetEmailChangeRevertBySelector(ctx context.Context, selector string) (EmailChangeRevert, error) {
+	row := q.db.QueryRowContext(ctx, getEmailChangeRevertBySelector, selector)
+	var i EmailChangeRevert
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.PreviousEmail,
+		&i.NewEmail,
+		&i.Selector,
+		&i.HashedVerifier,
+		&i.ExpiresAt,
+		&i.UsedAt,
+		&i.CreatedAt,
+	)
+	return i, err
+}
+
+const getLoginDeviceHistory = `-- name: GetLoginDeviceHistory :one
+select
+    count(*) > 0 as has_logins,
+    count(*) filter (where user_agent is not distinct from $1) > 0 as seen_user_agent,
+    count(*) filter (where ip_address is not distinct from $2) > 0 as seen_ip_address
+from user_audit_events
+where user_id = $3
+  and event in ('signup', 'login')
+`
+
+type GetLoginDeviceHistoryParams struct {
+	UserAgent sql.NullString
+	IpAddress sql.NullString
+	UserID    uuid.UUID
+}
+
+type GetLoginDeviceHistoryRow struct {
+	HasLogins     bool
+	SeenUserAgent bool
+	SeenIpAddress bool
+}
+
+func (q *Queries) GetLoginDeviceHistory(ctx context.Context, arg GetLoginDeviceHistoryParams) (GetLoginDeviceHistoryRow, error) {
+	row := q.db.QueryRowContext(ctx, getLoginDeviceHistory, arg.UserAgent, arg.IpAddress, arg.UserID)
+	var i GetLoginDeviceHistoryRow
+	err := row.Scan(
+		&i.HasLogins,
+		&i.SeenUserAgent,
+		&i.SeenIpAddress,
+	)
+	return i, err
+}
+
+const getPersonalAccessTokenBySelector = `-- name: GetPersonalAccessTokenBySelector :one
+select pat.id, pat.user_id, pat.hashed_verifier, pat.scopes, pat.expires_at, pat.revoked_at, u.username, u.email
+from personal_access_tokens pat
+join users u on u.id = pat.user_id
+where pat.selector = $1
+`
+
+type GetPersonalAccessTokenBySelectorRow struct {
+	ID             uuid.UUID
+	UserID         uuid.UUID
+	HashedVerifier string
+	Scopes         []string
+	ExpiresAt      time.Time
+	RevokedAt      sql.NullTime
+	Username       string
+	Email          string
+}
+
+func (q *Queries) GetPersonalAccessTokenBySelector(ctx context.Context, selector string) (GetPersonalAccessTokenBySelectorRow, error) {
+	row := q.db.QueryRowContext(ctx, getPersonalAccessTokenBySelector, selector)
+	var i GetPersonalAccessTokenBySelectorRow
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.HashedVerifier,
+		pq.Array(&i.Scopes),
+		&i.ExpiresAt,
+		&i.RevokedAt,
+		&i.Username,
+		&i.Email,
+	)
+	return i, err
+}
+
+const getRefreshTokenBySelector = `-- name: GetRefreshTokenBySelector :one
+select id, user_id, expires_at, revoked, created_at, updated_at, user_agent, ip_address, last_used_at, selector, hashed_verifier, family_id, replaced_at
+from refresh_tokens
+where selector = $1
+`
+
+func (q *Queries) GetRefreshTokenBySelector(ctx context.Context, selector string) (RefreshToken, error) {
+	row := q.db.QueryRowContext(ctx, getRefreshTokenBySelector, selector)
+	var i RefreshToken
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.ExpiresAt,
+		&i.Revoked,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserAgent,
+		&i.IpAddress,
+		&i.LastUsedAt,
+		&i.Selector,
+		&i.HashedVerifier,
+		&i.FamilyID,
+		&i.ReplacedAt,
+	)
+	return i, err
+}
+
 const getRefreshTokensByUserID = `-- name: GetRefreshTokensByUserID :many
-select id, user_id, hashed_token, expires_at, revoked, created_at, updated_at
+select id, user_id, expires_at, revoked, created_at, updated_at, user_agent, ip_address, last_used_at, selector, hashed_verifier, family_id, replaced_at
 from refresh_tokens
 where user_id = $1
   and revoked = false
+  and replaced_at is null
   and expires_at > now()
 `
 
@@ -83,11 +498,17 @@ func (q *Queries) GetRefreshTokensByUserID(ctx context.Context, userID uuid.UUID
 		if err := rows.Scan(
 			&i.ID,
 			&i.UserID,
-			&i.HashedToken,
 			&i.ExpiresAt,
 			&i.Revoked,
 			&i.CreatedAt,
 			&i.UpdatedAt,
+			&i.UserAgent,
+			&i.IpAddress,
+			&i.LastUsedAt,
+			&i.Selector,
+			&i.HashedVerifier,
+			&i.FamilyID,
+			&i.ReplacedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -103,7 +524,7 @@ func (q *Queries) GetRefreshTokensByUserID(ctx context.Context, userID uuid.UUID
 }
 
 const getUser = `-- name: GetUser :one
-select id, username, email, update_email, email_update_requested_at, email_update_otp, email_last_updated_at, name, hashed_password, update_hashed_password, password_update_requested_at, password_update_otp, password_last_updated_at, created_at, updated_at from users where id = $1 limit 1
+select id, username, email, update_email, email_update_requested_at, email_update_otp, email_last_updated_at, name, hashed_password, update_hashed_password, password_update_requested_at, password_update_otp, password_last_updated_at, created_at, updated_at, email_verified, email_verification_otp, email_verification_requested_at, password_update_otp_attempts, email_update_otp_attempts, email_verification_otp_attempts, password_otp_locked_until, email_update_otp_locked_until, email_verification_otp_locked_until, login_otp, login_otp_requested_at, login_otp_attempts, login_otp_locked_until, locale from users where id = $1 limit 1
 `
 
 func (q *Queries) GetUser(ctx context.Context, id uuid.UUID) (User, error) {
@@ -125,12 +546,26 @@ func (q *Queries) GetUser(ctx context.Context, id uuid.UUID) (User, error) {
 		&i.PasswordLastUpdatedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.EmailVerified,
+		&i.EmailVerificationOtp,
+		&i.EmailVerificationRequestedAt,
+		&i.PasswordUpdateOtpAttempts,
+		&i.EmailUpdateOtpAttempts,
+		&i.EmailVerificationOtpAttempts,
+		&i.PasswordOtpLockedUntil,
+		&i.EmailUpdateOtpLockedUntil,
+		&i.EmailVerificationOtpLockedUntil,
+		&i.LoginOtp,
+		&i.LoginOtpRequestedAt,
+		&i.LoginOtpAttempts,
+		&i.LoginOtpLockedUntil,
+		&i.Locale,
 	)
 	return i, err
 }
 
 const getUserByEmail = `-- name: GetUserByEmail :one
-select id, username, email, update_email, email_update_requested_at, email_update_otp, email_last_updated_at, name, hashed_password, update_hashed_password, password_update_requested_at, password_update_otp, password_last_updated_at, created_at, updated_at from users where email = $1 limit 1
+select id, username, email, update_email, email_update_requested_at, email_update_otp, email_last_updated_at, name, hashed_password, update_hashed_password, password_update_requested_at, password_update_otp, password_last_updated_at, created_at, updated_at, email_verified, email_verification_otp, email_verification_requested_at, password_update_otp_attempts, email_update_otp_attempts, email_verification_otp_attempts, password_otp_locked_until, email_update_otp_locked_until, email_verification_otp_locked_until, login_otp, login_otp_requested_at, login_otp_attempts, login_otp_locked_until, locale from users where email = $1 limit 1
 `
 
 func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error) {
@@ -152,12 +587,67 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (User, error
 		&i.PasswordLastUpdatedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.EmailVerified,
+		&i.EmailVerificationOtp,
+		&i.EmailVerificationRequestedAt,
+		&i.PasswordUpdateOtpAttempts,
+		&i.EmailUpdateOtpAttempts,
+		&i.EmailVerificationOtpAttempts,
+		&i.PasswordOtpLockedUntil,
+		&i.EmailUpdateOtpLockedUntil,
+		&i.EmailVerificationOtpLockedUntil,
+		&i.LoginOtp,
+		&i.LoginOtpRequestedAt,
+		&i.LoginOtpAttempts,
+		&i.LoginOtpLockedUntil,
+		&i.Locale,
+	)
+	return i, err
+}
+
+const getUserByEmailForUpdate = `-- name: GetUserByEmailForUpdate :one
+select id, username, email, update_email, email_update_requested_at, email_update_otp, email_last_updated_at, name, hashed_password, update_hashed_password, password_update_requested_at, password_update_otp, password_last_updated_at, created_at, updated_at, email_verified, email_verification_otp, email_verification_requested_at, password_update_otp_attempts, email_update_otp_attempts, email_verification_otp_attempts, password_otp_locked_until, email_update_otp_locked_until, email_verification_otp_locked_until, login_otp, login_otp_requested_at, login_otp_attempts, login_otp_locked_until, locale from users where email = $1 limit 1 for update
+`
+
+func (q *Queries) GetUserByEmailForUpdate(ctx context.Context, email string) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserByEmailForUpdate, email)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Email,
+		&i.UpdateEmail,
+		&i.EmailUpdateRequestedAt,
+		&i.EmailUpdateOtp,
+		&i.EmailLastUpdatedAt,
+		&i.Name,
+		&i.HashedPassword,
+		&i.UpdateHashedPassword,
+		&i.PasswordUpdateRequestedAt,
+		&i.PasswordUpdateOtp,
+		&i.PasswordLastUpdatedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.EmailVerified,
+		&i.EmailVerificationOtp,
+		&i.EmailVerificationRequestedAt,
+		&i.PasswordUpdateOtpAttempts,
+		&i.EmailUpdateOtpAttempts,
+		&i.EmailVerificationOtpAttempts,
+		&i.PasswordOtpLockedUntil,
+		&i.EmailUpdateOtpLockedUntil,
+		&i.EmailVerificationOtpLockedUntil,
+		&i.LoginOtp,
+		&i.LoginOtpRequestedAt,
+		&i.LoginOtpAttempts,
+		&i.LoginOtpLockedUntil,
+		&i.Locale,
 	)
 	return i, err
 }
 
 const getUserByUsername = `-- name: GetUserByUsername :one
-select id, username, email, update_email, email_update_requested_at, email_update_otp, email_last_updated_at, name, hashed_password, update_hashed_password, password_update_requested_at, password_update_otp, password_last_updated_at, created_at, updated_at from users where username = $1 limit 1
+select id, username, email, update_email, email_update_requested_at, email_update_otp, email_last_updated_at, name, hashed_password, update_hashed_password, password_update_requested_at, password_update_otp, password_last_updated_at, created_at, updated_at, email_verified, email_verification_otp, email_verification_requested_at, password_update_otp_attempts, email_update_otp_attempts, email_verification_otp_attempts, password_otp_locked_until, email_update_otp_locked_until, email_verification_otp_locked_until, login_otp, login_otp_requested_at, login_otp_attempts, login_otp_locked_until, locale from users where username = $1 limit 1
 `
 
 func (q *Queries) GetUserByUsername(ctx context.Context, username string) (User, error) {
@@ -179,105 +669,574 @@ func (q *Queries) GetUserByUsername(ctx context.Context, username string) (User,
 		&i.PasswordLastUpdatedAt,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.EmailVerified,
+		&i.EmailVerificationOtp,
+		&i.EmailVerificationRequestedAt,
+		&i.PasswordUpdateOtpAttempts,
+		&i.EmailUpdateOtpAttempts,
+		&i.EmailVerificationOtpAttempts,
+		&i.PasswordOtpLockedUntil,
+		&i.EmailUpdateOtpLockedUntil,
+		&i.EmailVerificationOtpLockedUntil,
+		&i.LoginOtp,
+		&i.LoginOtpRequestedAt,
+		&i.LoginOtpAttempts,
+		&i.LoginOtpLockedUntil,
+		&i.Locale,
 	)
 	return i, err
 }
 
-const initialiseUpdateEmail = `-- name: InitialiseUpdateEmail :exec
-update users
-set update_email = $2,
-    email_update_otp = $3,
-    email_update_requested_at = now()
-where id = $1
+const getUserForUpdate = `-- name: GetUserForUpdate :one
+select id, username, email, update_email, email_update_requested_at, email_update_otp, email_last_updated_at, name, hashed_password, update_hashed_password, password_update_requested_at, password_update_otp, password_last_updated_at, created_at, updated_at, email_verified, email_verification_otp, email_verification_requested_at, password_update_otp_attempts, email_update_otp_attempts, email_verification_otp_attempts, password_otp_locked_until, email_update_otp_locked_until, email_verification_otp_locked_until, login_otp, login_otp_requested_at, login_otp_attempts, login_otp_locked_until, locale from users where id = $1 limit 1 for update
 `
 
-type InitialiseUpdateEmailParams struct {
-	ID             uuid.UUID
-	UpdateEmail    sql.NullString
-	EmailUpdateOtp sql.NullString
+func (q *Queries) GetUserForUpdate(ctx context.Context, id uuid.UUID) (User, error) {
+	row := q.db.QueryRowContext(ctx, getUserForUpdate, id)
+	var i User
+	err := row.Scan(
+		&i.ID,
+		&i.Username,
+		&i.Email,
+		&i.UpdateEmail,
+		&i.EmailUpdateRequestedAt,
+		&i.EmailUpdateOtp,
+		&i.EmailLastUpdatedAt,
+		&i.Name,
+		&i.HashedPassword,
+		&i.UpdateHashedPassword,
+		&i.PasswordUpdateRequestedAt,
+		&i.PasswordUpdateOtp,
+		&i.PasswordLastUpdatedAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.EmailVerified,
+		&i.EmailVerificationOtp,
+		&i.EmailVerificationRequestedAt,
+		&i.PasswordUpdateOtpAttempts,
+		&i.EmailUpdateOtpAttempts,
+		&i.EmailVerificationOtpAttempts,
+		&i.PasswordOtpLockedUntil,
+		&i.EmailUpdateOtpLockedUntil,
+		&i.EmailVerificationOtpLockedUntil,
+		&i.LoginOtp,
+		&i.LoginOtpRequestedAt,
+		&i.LoginOtpAttempts,
+		&i.LoginOtpLockedUntil,
+		&i.Locale,
+	)
+	return i, err
 }
 
-func (q *Queries) InitialiseUpdateEmail(ctx context.Context, arg InitialiseUpdateEmailParams) error {
-	_, err := q.db.ExecContext(ctx, initialiseUpdateEmail, arg.ID, arg.UpdateEmail, arg.EmailUpdateOtp)
-	return err
+const getUserIdentityByProviderSubject = `-- name: GetUserIdentityByProviderSubject :one
+select id, user_id, provider, subject, email, last_login_at, created_at, updated_at
+from user_identities
+where provider = $1 and subject = $2
+`
+
+type GetUserIdentityByProviderSubjectParams struct {
+	Provider string
+	Subject  string
 }
 
-const initializePasswordReset = `-- name: InitializePasswordReset :exec
-update users
-set update_hashed_password = null,
-    password_update_otp = $2,
-    password_update_requested_at = now()
-where id = $1
+func (q *Queries) GetUserIdentityByProviderSubject(ctx context.Context, arg GetUserIdentityByProviderSubjectParams) (UserIdentity, error) {
+	row := q.db.QueryRowContext(ctx, getUserIdentityByProviderSubject, arg.Provider, arg.Subject)
+	var i UserIdentity
+	err := row.Scan(
+		&i.ID,
+		&i.UserID,
+		&i.Provider,
+		&i.Subject,
+		&i.Email,
+		&i.LastLoginAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+	)
+	return i, err
+}
+
+const getUserTwoFactor = `-- name: GetUserTwoFactor :one
+select user_id, secret, enabled, last_used_step, enabled_at, created_at, updated_at, failed_attempts, locked_until from user_two_factor where user_id = $1 limit 1
 `
 
-type InitializePasswordResetParams struct {
-	ID                uuid.UUID
-	PasswordUpdateOtp sql.NullString
+func (q *Queries) GetUserTwoFactor(ctx context.Context, userID uuid.UUID) (UserTwoFactor, error) {
+	row := q.db.QueryRowContext(ctx, getUserTwoFactor, userID)
+	var i UserTwoFactor
+	err := row.Scan(
+		&i.UserID,
+		&i.Secret,
+		&i.Enabled,
+		&i.LastUsedStep,
+		&i.EnabledAt,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.FailedAttempts,
+		&i.LockedUntil,
+	)
+	return i, err
 }
 
-func (q *Queries) InitializePasswordReset(ctx context.Context, arg InitializePasswordResetParams) error {
-	_, err := q.db.ExecContext(ctx, initializePasswordReset, arg.ID, arg.PasswordUpdateOtp)
-	return err
+const incrementEmailUpdateOTPAttempts = `-- name: IncrementEmailUpdateOTPAttempts :one
+update users
+set email_update_otp_attempts = email_update_otp_attempts + 1
+where id = $1
+returning email_update_otp_attempts
+`
+
+func (q *Queries) IncrementEmailUpdateOTPAttempts(ctx context.Context, id uuid.UUID) (int32, error) {
+	row := q.db.QueryRowContext(ctx, incrementEmailUpdateOTPAttempts, id)
+	var email_update_otp_attempts int32
+	err := row.Scan(&email_update_otp_attempts)
+	return email_update_otp_attempts, err
 }
 
-const initializePasswordUpdate = `-- name: InitializePasswordUpdate :exec
+const incrementEmailVerificationOTPAttempts = `-- name: IncrementEmailVerificationOTPAttempts :one
 update users
-set update_hashed_password = $2,
-    password_update_otp = $3,
-    password_update_requested_at = now()
+set email_verification_otp_attempts = email_verification_otp_attempts + 1
 where id = $1
+returning email_verification_otp_attempts
 `
 
-type InitializePasswordUpdateParams struct {
+func (q *Queries) IncrementEmailVerificationOTPAttempts(ctx context.Context, id uuid.UUID) (int32, error) {
+	row := q.db.QueryRowContext(ctx, incrementEmailVerificationOTPAttempts, id)
+	var email_verification_otp_attempts int32
+	err := row.Scan(&email_verification_otp_attempts)
+	return email_verification_otp_attempts, err
+}
+
+const incrementLoginOTPAttempts = `-- name: IncrementLoginOTPAttempts :one
+update users
+set login_otp_attempts = login_otp_attempts + 1
+where id = $1
+returning login_otp_attempts
+`
+
+func (q *Queries) IncrementLoginOTPAttempts(ctx context.Context, id uuid.UUID) (int32, error) {
+	row := q.db.QueryRowContext(ctx, incrementLoginOTPAttempts, id)
+	var login_otp_attempts int32
+	err := row.Scan(&login_otp_attempts)
+	return login_otp_attempts, err
+}
+
+const incrementPasswordUpdateOTPAttempts = `-- name: IncrementPasswordUpdateOTPAttempts :one
+update users
+set password_update_otp_attempts = password_update_otp_attempts + 1
+where id = $1
+returning password_update_otp_attempts
+`
+
+func (q *Queries) IncrementPasswordUpdateOTPAttempts(ctx context.Context, id uuid.UUID) (int32, error) {
+	row := q.db.QueryRowContext(ctx, incrementPasswordUpdateOTPAttempts, id)
+	var password_update_otp_attempts int32
+	err := row.Scan(&password_update_otp_attempts)
+	return password_update_otp_attempts, err
+}
+
+const incrementTwoFactorAttempts = `-- name: IncrementTwoFactorAttempts :one
+update user_two_factor
+set failed_attempts = failed_attempts + 1
+where user_id = $1
+returning failed_attempts
+`
+
+func (q *Queries) IncrementTwoFactorAttempts(ctx context.Context, userID uuid.UUID) (int32, error) {
+	row := q.db.QueryRowContext(ctx, incrementTwoFactorAttempts, userID)
+	var failed_attempts int32
+	err := row.Scan(&failed_attempts)
+	return failed_attempts, err
+}
+
+const initialiseUpdateEmail = `-- name: InitialiseUpdateEmail :execrows
+update users
+set update_email = $2,
+    email_update_otp = $3,
+    email_update_requested_at = now(),
+    email_update_otp_attempts = 0
+where id = $1
+  and (email_update_otp_locked_until is null or email_update_otp_locked_until <= now())
+`
+
+type InitialiseUpdateEmailParams struct {
+	ID             uuid.UUID
+	UpdateEmail    sql.NullString
+	EmailUpdateOtp sql.NullString
+}
+
+func (q *Queries) InitialiseUpdateEmail(ctx context.Context, arg InitialiseUpdateEmailParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, initialiseUpdateEmail, arg.ID, arg.UpdateEmail, arg.EmailUpdateOtp)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const initializeEmailVerification = `-- name: InitializeEmailVerification :execrows
+update users
+set email_verification_otp = $2,
+    email_verification_requested_at = now(),
+    email_verification_otp_attempts = 0
+where id = $1
+  and email_verified = false
+  and (email_verification_otp_locked_until is null or email_verification_otp_locked_until <= now())
+`
+
+type InitializeEmailVerificationParams struct {
+	ID                   uuid.UUID
+	EmailVerificationOtp sql.NullString
+}
+
+func (q *Queries) InitializeEmailVerification(ctx context.Context, arg InitializeEmailVerificationParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, initializeEmailVerification, arg.ID, arg.EmailVerificationOtp)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const initializeLoginOTP = `-- name: InitializeLoginOTP :execrows
+update users
+set login_otp = $2,
+    login_otp_requested_at = now(),
+    login_otp_attempts = 0
+where id = $1
+  and email_verified = true
+  and (login_otp_locked_until is null or login_otp_locked_until <= now())
+`
+
+type InitializeLoginOTPParams struct {
+	ID       uuid.UUID
+	LoginOtp sql.NullString
+}
+
+func (q *Queries) InitializeLoginOTP(ctx context.Context, arg InitializeLoginOTPParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, initializeLoginOTP, arg.ID, arg.LoginOtp)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const initializePasswordReset = `-- name: InitializePasswordReset :execrows
+update users
+set update_hashed_password = null,
+    password_update_otp = $2,
+    password_update_requested_at = now(),
+    password_update_otp_attempts = 0
+where id = $1
+  and (password_otp_locked_until is null or password_otp_locked_until <= now())
+`
+
+type InitializePasswordResetParams struct {
+	ID                uuid.UUID
+	PasswordUpdateOtp sql.NullString
+}
+
+func (q *Queries) InitializePasswordReset(ctx context.Context, arg InitializePasswordResetParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, initializePasswordReset, arg.ID, arg.PasswordUpdateOtp)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const initializePasswordUpdate = `-- name: InitializePasswordUpdate :execrows
+update users
+set update_hashed_password = $2,
+    password_update_otp = $3,
+    password_update_requested_at = now(),
+    password_update_otp_attempts = 0
+where id = $1
+  and (password_otp_locked_until is null or password_otp_locked_until <= now())
+`
+
+type InitializePasswordUpdateParams struct {
 	ID                   uuid.UUID
 	UpdateHashedPassword sql.NullString
 	PasswordUpdateOtp    sql.NullString
 }
 
-func (q *Queries) InitializePasswordUpdate(ctx context.Context, arg InitializePasswordUpdateParams) error {
-	_, err := q.db.ExecContext(ctx, initializePasswordUpdate, arg.ID, arg.UpdateHashedPassword, arg.PasswordUpdateOtp)
+func (q *Queries) InitializePasswordUpdate(ctx context.Context, arg InitializePasswordUpdateParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, initializePasswordUpdate, arg.ID, arg.UpdateHashedPassword, arg.PasswordUpdateOtp)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const listPersonalAccessTokensByUserID = `-- name: ListPersonalAccessTokensByUserID :many
+select id, user_id, name, selector, hashed_verifier, scopes, expires_at, last_used_at, revoked_at, created_at, updated_at
+from personal_access_tokens
+where user_id = $1
+  and revoked_at is null
+order by created_at desc
+`
+
+func (q *Queries) ListPersonalAccessTokensByUserID(ctx context.Context, userID uuid.UUID) ([]PersonalAccessToken, error) {
+	rows, err := q.db.QueryContext(ctx, listPersonalAccessTokensByUserID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []PersonalAccessToken
+	for rows.Next() {
+		var i PersonalAccessToken
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Name,
+			&i.Selector,
+			&i.HashedVerifier,
+			pq.Array(&i.Scopes),
+			&i.ExpiresAt,
+			&i.LastUsedAt,
+			&i.RevokedAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUserAuditEvents = `-- name: ListUserAuditEvents :many
+select id, user_id, event, detail, user_agent, ip_address, created_at
+from user_audit_events
+where user_id = $1
+order by created_at desc
+limit $2
+`
+
+type ListUserAuditEventsParams struct {
+	UserID uuid.UUID
+	Limit  int32
+}
+
+func (q *Queries) ListUserAuditEvents(ctx context.Context, arg ListUserAuditEventsParams) ([]UserAuditEvent, error) {
+	rows, err := q.db.QueryContext(ctx, listUserAuditEvents, arg.UserID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UserAuditEvent
+	for rows.Next() {
+		var i UserAuditEvent
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Event,
+			&i.Detail,
+			&i.UserAgent,
+			&i.IpAddress,
+			&i.CreatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUserIdentitiesByUserID = `-- name: ListUserIdentitiesByUserID :many
+select id, user_id, provider, subject, email, last_login_at, created_at, updated_at
+from user_identities
+where user_id = $1
+order by created_at
+`
+
+func (q *Queries) ListUserIdentitiesByUserID(ctx context.Context, userID uuid.UUID) ([]UserIdentity, error) {
+	rows, err := q.db.QueryContext(ctx, listUserIdentitiesByUserID, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []UserIdentity
+	for rows.Next() {
+		var i UserIdentity
+		if err := rows.Scan(
+			&i.ID,
+			&i.UserID,
+			&i.Provider,
+			&i.Subject,
+			&i.Email,
+			&i.LastLoginAt,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockEmailUpdateOTP = `-- name: LockEmailUpdateOTP :exec
+update users
+set update_email = null,
+    email_update_otp = null,
+    email_update_requested_at = null,
+    email_update_otp_attempts = 0,
+    email_update_otp_locked_until = $2
+where id = $1
+`
+
+type LockEmailUpdateOTPParams struct {
+	ID                        uuid.UUID
+	EmailUpdateOtpLockedUntil sql.NullTime
+}
+
+func (q *Queries) LockEmailUpdateOTP(ctx context.Context, arg LockEmailUpdateOTPParams) error {
+	_, err := q.db.ExecContext(ctx, lockEmailUpdateOTP, arg.ID, arg.EmailUpdateOtpLockedUntil)
 	return err
 }
 
-const invalidateRefreshToken = `-- name: InvalidateRefreshToken :exec
-update refresh_tokens
-set revoked = true
+const lockEmailVerificationOTP = `-- name: LockEmailVerificationOTP :exec
+update users
+set email_verification_otp = null,
+    email_verification_requested_at = null,
+    email_verification_otp_attempts = 0,
+    email_verification_otp_locked_until = $2
+where id = $1
+`
+
+type LockEmailVerificationOTPParams struct {
+	ID                              uuid.UUID
+	EmailVerificationOtpLockedUntil sql.NullTime
+}
+
+func (q *Queries) LockEmailVerificationOTP(ctx context.Context, arg LockEmailVerificationOTPParams) error {
+	_, err := q.db.ExecContext(ctx, lockEmailVerificationOTP, arg.ID, arg.EmailVerificationOtpLockedUntil)
+	return err
+}
+
+const lockLoginOTP = `-- name: LockLoginOTP :exec
+update users
+set login_otp = null,
+    login_otp_requested_at = null,
+    login_otp_attempts = 0,
+    login_otp_locked_until = $2
 where id = $1
 `
 
-func (q *Queries) InvalidateRefreshToken(ctx context.Context, id int32) error {
-	_, err := q.db.ExecContext(ctx, invalidateRefreshToken, id)
+type LockLoginOTPParams struct {
+	ID                  uuid.UUID
+	LoginOtpLockedUntil sql.NullTime
+}
+
+func (q *Queries) LockLoginOTP(ctx context.Context, arg LockLoginOTPParams) error {
+	_, err := q.db.ExecContext(ctx, lockLoginOTP, arg.ID, arg.LoginOtpLockedUntil)
+	return err
+}
+
+const lockPasswordUpdateOTP = `-- name: LockPasswordUpdateOTP :exec
+update users
+set update_hashed_password = null,
+    password_update_otp = null,
+    password_update_requested_at = null,
+    password_update_otp_attempts = 0,
+    password_otp_locked_until = $2
+where id = $1
+`
+
+type LockPasswordUpdateOTPParams struct {
+	ID                     uuid.UUID
+	PasswordOtpLockedUntil sql.NullTime
+}
+
+func (q *Queries) LockPasswordUpdateOTP(ctx context.Context, arg LockPasswordUpdateOTPParams) error {
+	_, err := q.db.ExecContext(ctx, lockPasswordUpdateOTP, arg.ID, arg.PasswordOtpLockedUntil)
+	return err
+}
+
+const lockTwoFactor = `-- name: LockTwoFactor :exec
+update user_two_factor
+set failed_attempts = 0,
+    locked_until = $2
+where user_id = $1
+`
+
+type LockTwoFactorParams struct {
+	UserID      uuid.UUID
+	LockedUntil sql.NullTime
+}
+
+func (q *Queries) LockTwoFactor(ctx context.Context, arg LockTwoFactorParams) error {
+	_, err := q.db.ExecContext(ctx, lockTwoFactor, arg.UserID, arg.LockedUntil)
 	return err
 }
 
 const registerRefreshToken = `-- name: RegisterRefreshToken :exec
-insert into refresh_tokens (user_id, hashed_token, expires_at)
-values ($1, $2, $3)
+insert into refresh_tokens (user_id, family_id, selector, hashed_verifier, expires_at, user_agent, ip_address)
+values ($1, $2, $3, $4, $5, $6, $7)
 `
 
 type RegisterRefreshTokenParams struct {
-	UserID      uuid.UUID
-	HashedToken string
-	ExpiresAt   time.Time
+	UserID         uuid.UUID
+	FamilyID       uuid.UUID
+	Selector       string
+	HashedVerifier string
+	ExpiresAt      time.Time
+	UserAgent      sql.NullString
+	IpAddress      sql.NullString
 }
 
 func (q *Queries) RegisterRefreshToken(ctx context.Context, arg RegisterRefreshTokenParams) error {
-	_, err := q.db.ExecContext(ctx, registerRefreshToken, arg.UserID, arg.HashedToken, arg.ExpiresAt)
+	_, err := q.db.ExecContext(ctx, registerRefreshToken, arg.UserID, arg.FamilyID, arg.Selector, arg.HashedVerifier, arg.ExpiresAt, arg.UserAgent, arg.IpAddress)
+	return err
+}
+
+const rehashUserPassword = `-- name: RehashUserPassword :exec
+update users
+set hashed_password = $1
+where id = $2
+  and hashed_password = $3
+`
+
+type RehashUserPasswordParams struct {
+	NewHashedPassword string
+	ID                uuid.UUID
+	OldHashedPassword string
+}
+
+func (q *Queries) RehashUserPassword(ctx context.Context, arg RehashUserPasswordParams) error {
+	_, err := q.db.ExecContext(ctx, rehashUserPassword, arg.NewHashedPassword, arg.ID, arg.OldHashedPassword)
 	return err
 }
 
-const resetPassword = `-- name: ResetPassword :exec
+const resetPassword = `-- name: ResetPassword :execrows
 with updated as (
     select id, update_hashed_password
     from users updated_users
-    where updated_users.id = $1 and updated_users.password_update_requested_at is not null
+    where updated_users.id = $1
+      and updated_users.update_hashed_password is null
+      and updated_users.password_update_requested_at > $2::timestamptz
 )
 update users u
 set
-    hashed_password = $2,
+    hashed_password = $3,
     update_hashed_password = null,
     password_update_otp = null,
     password_update_requested_at = null,
+    password_update_otp_attempts = 0,
     password_last_updated_at = now()
 from updated
 where u.id = updated.id
@@ -285,40 +1244,228 @@ where u.id = updated.id
 
 type ResetPasswordParams struct {
 	ID             uuid.UUID
+	RequestedAfter time.Time
 	HashedPassword string
 }
 
-func (q *Queries) ResetPassword(ctx context.Context, arg ResetPasswordParams) error {
-	_, err := q.db.ExecContext(ctx, resetPassword, arg.ID, arg.HashedPassword)
+func (q *Queries) ResetPassword(ctx context.Context, arg ResetPasswordParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, resetPassword, arg.ID, arg.RequestedAfter, arg.HashedPassword)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const resetTwoFactorAttempts = `-- name: ResetTwoFactorAttempts :exec
+update user_two_factor
+set failed_attempts = 0
+where user_id = $1
+`
+
+func (q *Queries) ResetTwoFactorAttempts(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, resetTwoFactorAttempts, userID)
+	return err
+}
+
+const revertEmail = `-- name: RevertEmail :execrows
+update users
+set email = $1,
+    update_email = null,
+    email_update_otp = null,
+    email_update_requested_at = null,
+    email_update_otp_attempts = 0,
+    email_last_updated_at = now()
+where id = $2
+  and not exists (
+    select 1
+    from users other_users
+    where other_users.email = $1
+      and other_users.id <> $2
+  )
+`
+
+type RevertEmailParams struct {
+	PreviousEmail string
+	ID            uuid.UUID
+}
+
+func (q *Queries) RevertEmail(ctx context.Context, arg RevertEmailParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, revertEmail, arg.PreviousEmail, arg.ID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const revokeAllUserPersonalAccessTokens = `-- name: RevokeAllUserPersonalAccessTokens :exec
+update personal_access_tokens
+set revoked_at = now()
+where user_id = $1 and revoked_at is null
+`
+
+func (q *Queries) RevokeAllUserPersonalAccessTokens(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, revokeAllUserPersonalAccessTokens, userID)
+	return err
+}
+
+const revokeAllUserRefreshTokens = `-- name: RevokeAllUserRefreshTokens :exec
+update refresh_tokens
+set revoked = true
+where user_id = $1 and revoked = false
+`
+
+func (q *Queries) RevokeAllUserRefreshTokens(ctx context.Context, userID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, revokeAllUserRefreshTokens, userID)
+	return err
+}
+
+const revokeOtherUserRefreshTokenFamilies = `-- name: RevokeOtherUserRefreshTokenFamilies :exec
+update refresh_tokens
+set revoked = true
+where user_id = $1 and family_id <> $2 and revoked = false
+`
+
+type RevokeOtherUserRefreshTokenFamiliesParams struct {
+	UserID   uuid.UUID
+	FamilyID uuid.UUID
+}
+
+func (q *Queries) RevokeOtherUserRefreshTokenFamilies(ctx context.Context, arg RevokeOtherUserRefreshTokenFamiliesParams) error {
+	_, err := q.db.ExecContext(ctx, revokeOtherUserRefreshTokenFamilies, arg.UserID, arg.FamilyID)
+	return err
+}
+
+const revokePersonalAccessToken = `-- name: RevokePersonalAccessToken :execrows
+update personal_access_tokens
+set revoked_at = now()
+where id = $1 and user_id = $2 and revoked_at is null
+`
+
+type RevokePersonalAccessTokenParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) RevokePersonalAccessToken(ctx context.Context, arg RevokePersonalAccessTokenParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, revokePersonalAccessToken, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const revokeRefreshTokenFamily = `-- name: RevokeRefreshTokenFamily :exec
+update refresh_tokens
+set revoked = true
+where family_id = $1 and revoked = false
+`
+
+func (q *Queries) RevokeRefreshTokenFamily(ctx context.Context, familyID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, revokeRefreshTokenFamily, familyID)
 	return err
 }
 
-const updateEmail = `-- name: UpdateEmail :exec
+const revokeUserRefreshTokenFamily = `-- name: RevokeUserRefreshTokenFamily :execrows
+update refresh_tokens
+set revoked = true
+where family_id = $1 and user_id = $2 and revoked = false
+`
+
+type RevokeUserRefreshTokenFamilyParams struct {
+	FamilyID uuid.UUID
+	UserID   uuid.UUID
+}
+
+func (q *Queries) RevokeUserRefreshTokenFamily(ctx context.Context, arg RevokeUserRefreshTokenFamilyParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, revokeUserRefreshTokenFamily, arg.FamilyID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const rotateRefreshToken = `-- name: RotateRefreshToken :execrows
+with replaced as (
+    update refresh_tokens
+    set replaced_at = now(),
+        last_used_at = now()
+    where id = $1
+      and revoked = false
+      and replaced_at is null
+    returning user_id, family_id, created_at
+)
+insert into refresh_tokens (user_id, family_id, selector, hashed_verifier, expires_at, user_agent, ip_address, created_at)
+select user_id, family_id, $2::text, $3::text, $4::timestamptz, $5::text, $6::text, created_at
+from replaced
+`
+
+type RotateRefreshTokenParams struct {
+	ID             int32
+	Selector       string
+	HashedVerifier string
+	ExpiresAt      time.Time
+	UserAgent      sql.NullString
+	IpAddress      sql.NullString
+}
+
+func (q *Queries) RotateRefreshToken(ctx context.Context, arg RotateRefreshTokenParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, rotateRefreshToken, arg.ID, arg.Selector, arg.HashedVerifier, arg.ExpiresAt, arg.UserAgent, arg.IpAddress)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const touchPersonalAccessToken = `-- name: TouchPersonalAccessToken :exec
+update personal_access_tokens
+set last_used_at = now()
+where id = $1
+  and (last_used_at is null or last_used_at < now() - interval '1 minute')
+`
+
+func (q *Queries) TouchPersonalAccessToken(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, touchPersonalAccessToken, id)
+	return err
+}
+
+const updateEmail = `-- name: UpdateEmail :execrows
 with updated as (
     select id, update_email
     from users updated_users
-    where updated_users.id = $1 and updated_users.email_update_requested_at is not null
+    where updated_users.id = $1
+      and updated_users.email_update_requested_at > $2::timestamptz
 )
 update users u
 set email = updated.update_email,
     update_email = null,
     email_update_otp = null,
     email_update_requested_at = null,
+    email_update_otp_attempts = 0,
     email_last_updated_at = now()
 from updated
 where u.id = updated.id
 `
 
-func (q *Queries) UpdateEmail(ctx context.Context, id uuid.UUID) error {
-	_, err := q.db.ExecContext(ctx, updateEmail, id)
-	return err
+type UpdateEmailParams struct {
+	ID             uuid.UUID
+	RequestedAfter time.Time
+}
+
+func (q *Queries) UpdateEmail(ctx context.Context, arg UpdateEmailParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updateEmail, arg.ID, arg.RequestedAfter)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
 }
 
-const updatePassword = `-- name: UpdatePassword :exec
+const updatePassword = `-- name: UpdatePassword :execrows
 with updated as (
     select id, update_hashed_password
     from users updated_users
-    where updated_users.id = $1 and updated_users.password_update_requested_at is not null
+    where updated_users.id = $1
+      and updated_users.update_hashed_password is not null
+      and updated_users.password_update_requested_at > $2::timestamptz
 )
 update users u
 set
@@ -326,16 +1473,78 @@ set
     update_hashed_password = null,
     password_update_otp = null,
     password_update_requested_at = null,
+    password_update_otp_attempts = 0,
     password_last_updated_at = now()
 from updated
 where u.id = updated.id
 `
 
-func (q *Queries) UpdatePassword(ctx context.Context, id uuid.UUID) error {
-	_, err := q.db.ExecContext(ctx, updatePassword, id)
+type UpdatePasswordParams struct {
+	ID             uuid.UUID
+	RequestedAfter time.Time
+}
+
+func (q *Queries) UpdatePassword(ctx context.Context, arg UpdatePasswordParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, updatePassword, arg.ID, arg.RequestedAfter)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const updateUserIdentityLogin = `-- name: UpdateUserIdentityLogin :exec
+update user_identities
+set last_login_at = now(), email = $2
+where id = $1
+`
+
+type UpdateUserIdentityLoginParams struct {
+	ID    uuid.UUID
+	Email sql.NullString
+}
+
+func (q *Queries) UpdateUserIdentityLogin(ctx context.Context, arg UpdateUserIdentityLoginParams) error {
+	_, err := q.db.ExecContext(ctx, updateUserIdentityLogin, arg.ID, arg.Email)
 	return err
 }
 
+const upsertPendingUserTwoFactor = `-- name: UpsertPendingUserTwoFactor :execrows
+insert into user_two_factor (user_id, secret)
+values ($1, $2)
+on conflict (user_id) do update
+set secret = excluded.secret,
+    last_used_step = null
+where user_two_factor.enabled = false
+`
+
+type UpsertPendingUserTwoFactorParams struct {
+	UserID uuid.UUID
+	Secret string
+}
+
+func (q *Queries) UpsertPendingUserTwoFactor(ctx context.Context, arg UpsertPendingUserTwoFactorParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, upsertPendingUserTwoFactor, arg.UserID, arg.Secret)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const useEmailChangeRevert = `-- name: UseEmailChangeRevert :execrows
+update email_change_reverts
+set used_at = now()
+where id = $1
+  and used_at is null
+`
+
+func (q *Queries) UseEmailChangeRevert(ctx context.Context, id uuid.UUID) (int64, error) {
+	result, err := q.db.ExecContext(ctx, useEmailChangeRevert, id)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const userWithUsernameExists = `-- name: UserWithUsernameExists :one
 select exists(select 1 from users where username = $1)
 `
@@ -346,3 +1555,66 @@ func (q *Queries) UserWithUsernameExists(ctx context.Context, username string) (
 	err := row.Scan(&exists)
 	return exists, err
 }
+
+const useUserRecoveryCode = `-- name: UseUserRecoveryCode :execrows
+update user_recovery_codes
+set used_at = now()
+where user_id = $1 and hashed_code = $2 and used_at is null
+`
+
+type UseUserRecoveryCodeParams struct {
+	UserID     uuid.UUID
+	HashedCode string
+}
+
+func (q *Queries) UseUserRecoveryCode(ctx context.Context, arg UseUserRecoveryCodeParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, useUserRecoveryCode, arg.UserID, arg.HashedCode)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const useUserTwoFactorStep = `-- name: UseUserTwoFactorStep :execrows
+update user_two_factor
+set last_used_step = $2
+where user_id = $1
+  and enabled = true
+  and (last_used_step is null or last_used_step < $2)
+`
+
+type UseUserTwoFactorStepParams struct {
+	UserID       uuid.UUID
+	LastUsedStep sql.NullInt64
+}
+
+func (q *Queries) UseUserTwoFactorStep(ctx context.Context, arg UseUserTwoFactorStepParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, useUserTwoFactorStep, arg.UserID, arg.LastUsedStep)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const verifyEmail = `-- name: VerifyEmail :execrows
+update users
+set email_verified = true,
+    email_verification_otp = null,
+    email_verification_requested_at = null,
+    email_verification_otp_attempts = 0
+where id = $1
+  and email_verification_requested_at > $2::timestamptz
+`
+
+type VerifyEmailParams struct {
+	ID             uuid.UUID
+	RequestedAfter time.Time
+}
+
+func (q *Queries) VerifyEmail(ctx context.Context, arg VerifyEmailParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, verifyEmail, arg.ID, arg.RequestedAfter)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"beerbux/internal/auth/command"
+	"beerbux/internal/auth/scope"
+	"beerbux/internal/common/claims"
+	"beerbux/pkg/send"
+	"encoding/json"
+	"errors"
+	oz "github.com/go-ozzo/ozzo-validation/v4"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// MaxPersonalAccessTokenDays is the longest a personal access token can be valid for.
+const MaxPersonalAccessTokenDays = 365
+
+type CreatePersonalAccessTokenHandler struct {
+	createPersonalAccessTokenCommand *command.CreatePersonalAccessTokenCommand
+	logger                           *slog.Logger
+}
+
+func NewCreatePersonalAccessTokenHandler(createPersonalAccessTokenCommand *command.CreatePersonalAccessTokenCommand, logger *slog.Logger) *CreatePersonalAccessTokenHandler {
+	return &CreatePersonalAccessTokenHandler{
+		createPersonalAccessTokenCommand: createPersonalAccessTokenCommand,
+		logger:                           logger,
+	}
+}
+
+type CreatePersonalAccessTokenRequest struct {
+	Name          string   `json:"name"`
+	Scopes        []string `json:"scopes"`
+	ExpiresInDays int      `json:"expiresInDays"`
+}
+
+func (h *CreatePersonalAccessTokenHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c := claims.GetClaims(r)
+	if !c.Authenticated() {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var req CreatePersonalAccessTokenRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		send.BadRequest(w, "Failed to decode request")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		send.ValidationError(w, err)
+		return
+	}
+
+	ttl := time.Duration(req.ExpiresInDays) * 24 * time.Hour
+	token, err := h.createPersonalAccessTokenCommand.Execute(r.Context(), c.Subject, req.Name, req.Scopes, ttl)
+	if err != nil {
+		h.logger.Error("failed to create personal access token", "user", c.Subject, "error", err)
+		send.InternalServerError(w, "There has been an issue creating the access token")
+		return
+	}
+
+	send.JSON(w, token, http.StatusCreated)
+}
+
+func (r CreatePersonalAccessTokenRequest) Validate() error {
+	return oz.ValidateStruct(&r,
+		oz.Field(&r.Name, oz.Required.Error("Name is required"), oz.Length(1, 100)),
+		oz.Field(&r.Scopes, oz.Required.Error("At least one scope is required"), oz.By(validateScopes)),
+		oz.Field(&r.ExpiresInDays, oz.Required, oz.Min(1), oz.Max(MaxPersonalAccessTokenDays)),
+	)
+}
+
+func validateScopes(value interface{}) error {
+	scopes, _ := value.([]string)
+	for _, s := range scopes {
+		if !scope.Valid(s) {
+			return errors.New("unknown scope " + s)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"beerbux/internal/auth/command"
+	"beerbux/pkg/send"
+	"encoding/json"
+	"errors"
+	oz "github.com/go-ozzo/ozzo-validation/v4"
+	"log/slog"
+	"net/http"
+)
+
+type VerifyEmailHandler struct {
+	verifyEmailCommand *command.VerifyEmailCommand
+	logger             *slog.Logger
+}
+
+func NewVerifyEmailHandler(verifyEmailCommand *command.VerifyEmailCommand, logger *slog.Logger) *VerifyEmailHandler {
+	return &VerifyEmailHandler{
+		verifyEmailCommand: verifyEmailCommand,
+		logger:             logger,
+	}
+}
+
+type VerifyEmailRequest struct {
+	Email string `json:"email"`
+	OTP   string `json:"otp"`
+}
+
+func (h *VerifyEmailHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req VerifyEmailRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		send.BadRequest(w, "Failed to decode request body")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		send.ValidationError(w, err)
+		return
+	}
+
+	if err := h.verifyEmailCommand.Execute(r.Context(), req.Email, req.OTP); err != nil {
+		h.handleVerifyEmailError(w, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *VerifyEmailHandler) handleVerifyEmailError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, command.ErrEmailAlreadyVerified):
+		send.BadRequest(w, "Your email address has already been verified")
+	case errors.Is(err, command.ErrUserNotFound), errors.Is(err, command.ErrProcessNotInitialized):
+		send.BadRequest(w, "Email verification has not been requested for this email address")
+	case errors.Is(err, command.ErrOTPExpired):
+		send.BadRequest(w, "The OTP has expired, please request a new verification email")
+	case errors.Is(err, command.ErrTooManyOTPAttempts):
+		send.TooManyRequests(w, "Too many incorrect attempts, please wait before requesting a new OTP")
+	case errors.Is(err, command.ErrIncorrectOTP):
+		send.BadRequest(w, "The provided OTP is incorrect")
+	default:
+		h.logger.Error("failed to verify email", "error", err)
+		send.InternalServerError(w, "There has been an issue verifying your email address")
+	}
+}
+
+func (r VerifyEmailRequest) Validate() error {
+	return oz.ValidateStruct(&r,
+		oz.Field(&r.Email, oz.Required.Error("Email address is required")),
+		oz.Field(&r.OTP, oz.Required.Error("OTP is required")),
+	)
+}
@@ -0,0 +1,100 @@
+package handler
+
+import (
+	"beerbux/internal/auth/command"
+	"beerbux/internal/common/useraccess"
+	"beerbux/pkg/email"
+	"beerbux/pkg/i18n"
+	"beerbux/pkg/send"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+type InitializeEmailVerificationHandler struct {
+	initializeEmailVerificationCommand *command.InitializeEmailVerificationCommand
+	userReader                         useraccess.UserReader
+	emailSender                        email.Sender
+	clientBaseURL                      string
+	logger                             *slog.Logger
+}
+
+func NewInitializeEmailVerificationHandler(
+	initializeEmailVerificationCommand *command.InitializeEmailVerificationCommand,
+	userReader useraccess.UserReader,
+	emailSender email.Sender,
+	clientBaseURL string,
+	logger *slog.Logger,
+) *InitializeEmailVerificationHandler {
+	return &InitializeEmailVerificationHandler{
+		initializeEmailVerificationCommand: initializeEmailVerificationCommand,
+		userReader:                         userReader,
+		emailSender:                        emailSender,
+		clientBaseURL:                      clientBaseURL,
+		logger:                             logger,
+	}
+}
+
+type InitializeEmailVerificationRequest struct {
+	Email string `json:"email"`
+}
+
+func (h *InitializeEmailVerificationHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req InitializeEmailVerificationRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		send.BadRequest(w, "Invalid request")
+		return
+	}
+
+	user, err := h.userReader.GetUserByEmail(r.Context(), req.Email)
+	if err != nil {
+		if errors.Is(err, useraccess.ErrUserNotFound) {
+			// Return a status OK so that the endpoint cannot be used to determine which email addresses are registered
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		h.logger.Error("failed to get user by email", "error", err)
+		send.InternalServerError(w, "There has been an issue checking your email address, please try again")
+		return
+	}
+
+	if user.EmailVerified {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	result, err := h.initializeEmailVerificationCommand.Execute(r.Context(), user.ID)
+	if err != nil {
+		if errors.Is(err, command.ErrEmailAlreadyVerified) || errors.Is(err, command.ErrOTPLocked) {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		h.logger.Error("failed to execute initialize email verification command", "error", err)
+		send.InternalServerError(w, "There has been an issue verifying your email address, please try again")
+		return
+	}
+
+	sendVerifyEmailAddressEmail(h.emailSender, h.logger, h.clientBaseURL, i18n.Resolve(user.Locale, r.Header.Get("Accept-Language")), user.Email, user.Username, result.OTP)
+	w.WriteHeader(http.StatusOK)
+}
+
+func sendVerifyEmailAddressEmail(emailSender email.Sender, logger *slog.Logger, clientBaseURL, locale, emailAddress, username, otp string) {
+	verificationURL := strings.TrimRight(clientBaseURL, "/") + "/verify-email?" + url.Values{
+		"email": {emailAddress},
+		"otp":   {otp},
+	}.Encode()
+
+	tmpl := email.VerifyEmailAddressEmail(locale, email.VerifyEmailAddressEmailData{
+		Username:        username,
+		OTP:             otp,
+		VerificationURL: verificationURL,
+		ExpirationHours: strconv.FormatInt(int64(command.EmailVerificationOTPTimeToLiveHours), 10),
+	})
+	if _, err := email.SendTemplate(emailSender, emailAddress, i18n.Translate(locale, "Verify your email address"), tmpl); err != nil {
+		logger.Error("failed to send email", "error", err)
+	}
+}
@@ -0,0 +1,24 @@
+package handler
+
+import (
+	"beerbux/pkg/jwtkeys"
+	"beerbux/pkg/send"
+	"net/http"
+)
+
+// JWKSHandler publishes the public keys used to sign access tokens so that other
+// services can verify them.
+type JWKSHandler struct {
+	keys *jwtkeys.KeySet
+}
+
+func NewJWKSHandler(keys *jwtkeys.KeySet) *JWKSHandler {
+	return &JWKSHandler{
+		keys: keys,
+	}
+}
+
+func (h *JWKSHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Cache-Control", "public, max-age=300")
+	send.JSON(w, h.keys.JWKS(), http.StatusOK)
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"beerbux/internal/auth/command"
+	"beerbux/internal/common/claims"
+	"beerbux/pkg/send"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type ListAuditEventsHandler struct {
+	listAuditEventsCommand *command.ListAuditEventsCommand
+	logger                 *slog.Logger
+}
+
+func NewListAuditEventsHandler(listAuditEventsCommand *command.ListAuditEventsCommand, logger *slog.Logger) *ListAuditEventsHandler {
+	return &ListAuditEventsHandler{
+		listAuditEventsCommand: listAuditEventsCommand,
+		logger:                 logger,
+	}
+}
+
+// ServeHTTP lists the recent security events on the user's account. The optional limit
+// query parameter caps the number of events returned.
+func (h *ListAuditEventsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c := claims.GetClaims(r)
+	if !c.Authenticated() {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	limit := command.DefaultAuditEventLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			send.BadRequest(w, "The limit must be a positive number")
+			return
+		}
+		limit = n
+	}
+
+	events, err := h.listAuditEventsCommand.Execute(r.Context(), c.Subject, limit)
+	if err != nil {
+		h.logger.Error("failed to list audit events", "user", c.Subject, "error", err)
+		send.InternalServerError(w, "There has been an issue fetching your account activity")
+		return
+	}
+
+	send.JSON(w, events, http.StatusOK)
+}
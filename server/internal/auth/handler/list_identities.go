@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"beerbux/internal/auth/command"
+	"beerbux/internal/common/claims"
+	"beerbux/pkg/send"
+	"log/slog"
+	"net/http"
+)
+
+type ListIdentitiesHandler struct {
+	listIdentitiesCommand *command.ListIdentitiesCommand
+	logger                *slog.Logger
+}
+
+func NewListIdentitiesHandler(listIdentitiesCommand *command.ListIdentitiesCommand, logger *slog.Logger) *ListIdentitiesHandler {
+	return &ListIdentitiesHandler{
+		listIdentitiesCommand: listIdentitiesCommand,
+		logger:                logger,
+	}
+}
+
+func (h *ListIdentitiesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c := claims.GetClaims(r)
+	if !c.Authenticated() {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	identities, err := h.listIdentitiesCommand.Execute(r.Context(), c.Subject)
+	if err != nil {
+		h.logger.Error("failed to list identities", "user", c.Subject, "error", err)
+		send.InternalServerError(w, "There has been an issue fetching your linked accounts")
+		return
+	}
+
+	send.JSON(w, identities, http.StatusOK)
+}
@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"beerbux/internal/auth/command"
+	"beerbux/internal/common/claims"
+	"beerbux/pkg/send"
+	"log/slog"
+	"net/http"
+)
+
+type ListPersonalAccessTokensHandler struct {
+	listPersonalAccessTokensCommand *command.ListPersonalAccessTokensCommand
+	logger                          *slog.Logger
+}
+
+func NewListPersonalAccessTokensHandler(listPersonalAccessTokensCommand *command.ListPersonalAccessTokensCommand, logger *slog.Logger) *ListPersonalAccessTokensHandler {
+	return &ListPersonalAccessTokensHandler{
+		listPersonalAccessTokensCommand: listPersonalAccessTokensCommand,
+		logger:                          logger,
+	}
+}
+
+func (h *ListPersonalAccessTokensHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c := claims.GetClaims(r)
+	if !c.Authenticated() {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	tokens, err := h.listPersonalAccessTokensCommand.Execute(r.Context(), c.Subject)
+	if err != nil {
+		h.logger.Error("failed to list personal access tokens", "user", c.Subject, "error", err)
+		send.InternalServerError(w, "There has been an issue fetching your access tokens")
+		return
+	}
+
+	send.JSON(w, tokens, http.StatusOK)
+}
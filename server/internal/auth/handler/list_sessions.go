@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"beerbux/internal/auth/command"
+	"beerbux/internal/common/claims"
+	"beerbux/pkg/send"
+	"log/slog"
+	"net/http"
+)
+
+type ListSessionsHandler struct {
+	listSessionsCommand *command.ListSessionsCommand
+	logger              *slog.Logger
+}
+
+func NewListSessionsHandler(listSessionsCommand *command.ListSessionsCommand, logger *slog.Logger) *ListSessionsHandler {
+	return &ListSessionsHandler{
+		listSessionsCommand: listSessionsCommand,
+		logger:              logger,
+	}
+}
+
+func (h *ListSessionsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c := claims.GetClaims(r)
+	if !c.Authenticated() {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	refreshToken, _ := claims.GetRefreshToken(r)
+	sessions, err := h.listSessionsCommand.Execute(r.Context(), c.Subject, refreshToken)
+	if err != nil {
+		h.logger.Error("failed to list sessions", "user", c.Subject, "error", err)
+		send.InternalServerError(w, "There has been an issue fetching your sessions")
+		return
+	}
+
+	send.JSON(w, sessions, http.StatusOK)
+}
@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"beerbux/internal/auth/command"
+	"beerbux/internal/auth/shared"
+	"beerbux/pkg/email"
+	"beerbux/pkg/i18n"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// sendNewDeviceLoginEmail alerts the user when the tokens were issued to a device or IP address
+// that has not logged in to their account before. The email is sent in the user's preferred
+// locale, or the locale of the request if they have not chosen one.
+func sendNewDeviceLoginEmail(emailSender email.Sender, logger *slog.Logger, clientBaseURL, acceptLanguage string, tokens *command.TokensResponse, device shared.DeviceInfo) {
+	if !tokens.NewDevice {
+		return
+	}
+
+	userAgent := device.UserAgent
+	if userAgent == "" {
+		userAgent = "Unknown"
+	}
+	ipAddress := device.IPAddress
+	if ipAddress == "" {
+		ipAddress = "Unknown"
+	}
+
+	locale := i18n.Resolve(tokens.User.Locale, acceptLanguage)
+	tmpl := email.NewDeviceLoginEmail(locale, email.NewDeviceLoginEmailData{
+		Username:    tokens.User.Username,
+		UserAgent:   userAgent,
+		IPAddress:   ipAddress,
+		Time:        time.Now().UTC().Format("2 January 2006 15:04 MST"),
+		SettingsURL: strings.TrimRight(clientBaseURL, "/") + "/settings",
+	})
+	if _, err := email.SendTemplate(emailSender, tokens.User.Email, i18n.Translate(locale, "New login to your Beerbux account"), tmpl); err != nil {
+		logger.Error("failed to send email", "error", err)
+	}
+}
@@ -0,0 +1,160 @@
+package handler
+
+import (
+	"beerbux/internal/auth/command"
+	"beerbux/internal/auth/cookie"
+	"beerbux/internal/auth/shared"
+	"beerbux/pkg/email"
+	"beerbux/pkg/send"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+)
+
+// tokenWriter writes the tokens of a completed login to the response.
+type tokenWriter func(w http.ResponseWriter, tokens *command.TokensResponse)
+
+// loginFlow runs the login steps shared by the handlers that set cookies and the handlers that
+// return bearer tokens, which only differ in how the tokens are written. Every login method
+// goes through the same two-factor challenge and completes the login the same way.
+type loginFlow struct {
+	generateTokensCommand      *command.GenerateTokensCommand
+	comparePasswordCommand     *command.ComparePasswordCommand
+	twoFactorChallengeCommand  *command.TwoFactorChallengeCommand
+	verifyTwoFactorCodeCommand *command.VerifyTwoFactorCodeCommand
+	secret                     string
+	emailSender                email.Sender
+	clientBaseURL              string
+	trustProxy                 bool
+	logger                     *slog.Logger
+}
+
+// password checks the username and password. If the user has two-factor authentication
+// enabled an MFA token is returned, otherwise the user is logged in.
+func (f *loginFlow) password(w http.ResponseWriter, r *http.Request, writeTokens tokenWriter) {
+	var req LoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		send.BadRequest(w, "Failed to decode request")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		send.ValidationError(w, err)
+		return
+	}
+
+	if err := f.comparePasswordCommand.Execute(r.Context(), req.Username, req.Password); err != nil {
+		if errors.Is(err, command.ErrPasswordMismatch) || errors.Is(err, command.ErrUserNotFound) {
+			send.Unauthorized(w, "Invalid username or password")
+		} else {
+			f.logger.Error("failed when comparing login password", "error", err)
+			send.InternalServerError(w, "There was an issue logging in")
+		}
+		return
+	}
+
+	f.challenge(w, r, req.Username, command.LoginMethodPassword, writeTokens)
+}
+
+// challenge logs in the user who proved their identity with method, unless they have two-factor
+// authentication enabled, in which case an MFA token is returned to complete the login with.
+func (f *loginFlow) challenge(w http.ResponseWriter, r *http.Request, username string, method command.LoginMethod, writeTokens tokenWriter) {
+	challenge, err := f.twoFactorChallengeCommand.Execute(r.Context(), username, method)
+	if err != nil {
+		f.handleLoginError(w, err)
+		return
+	}
+	if challenge.Required {
+		send.JSON(w, LoginMFARequiredResponse{
+			MFARequired: true,
+			MFAToken:    challenge.MFAToken,
+		}, http.StatusOK)
+		return
+	}
+
+	f.complete(w, r, username, method, writeTokens)
+}
+
+// twoFactor checks the code for the user the MFA token was issued to and logs them in.
+func (f *loginFlow) twoFactor(w http.ResponseWriter, r *http.Request, writeTokens tokenWriter) {
+	var req LoginTwoFactorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		send.BadRequest(w, "Failed to decode request")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		send.ValidationError(w, err)
+		return
+	}
+
+	mfaToken, err := shared.ParseMFAToken(req.MFAToken, f.secret)
+	if err != nil {
+		send.Unauthorized(w, "Your login has expired, please log in again")
+		return
+	}
+
+	if err := f.verifyTwoFactorCodeCommand.Execute(r.Context(), mfaToken.UserID, req.Code); err != nil {
+		switch {
+		case errors.Is(err, command.ErrIncorrectTwoFactorCode):
+			send.Unauthorized(w, "The provided code is incorrect")
+		case errors.Is(err, command.ErrTooManyOTPAttempts), errors.Is(err, command.ErrTwoFactorLocked):
+			send.TooManyRequests(w, "Too many incorrect attempts, please try again later")
+		case errors.Is(err, command.ErrTwoFactorNotEnrolled), errors.Is(err, command.ErrTwoFactorNotEnabled):
+			send.Unauthorized(w, "Your login has expired, please log in again")
+		default:
+			f.logger.Error("failed to verify two-factor code", "error", err)
+			send.InternalServerError(w, "There was an issue logging in")
+		}
+		return
+	}
+
+	f.complete(w, r, mfaToken.Username, command.LoginMethod(mfaToken.Method), writeTokens)
+}
+
+// complete issues the tokens for the user and writes them to the response.
+func (f *loginFlow) complete(w http.ResponseWriter, r *http.Request, username string, method command.LoginMethod, writeTokens tokenWriter) {
+	tokens, err := f.issueTokens(r, username, method)
+	if err != nil {
+		f.handleLoginError(w, err)
+		return
+	}
+
+	writeTokens(w, tokens)
+}
+
+// issueTokens generates the tokens for the user, alerting them if they logged in from a new device.
+func (f *loginFlow) issueTokens(r *http.Request, username string, method command.LoginMethod) (*command.TokensResponse, error) {
+	device := shared.NewDeviceInfo(r, f.trustProxy)
+	tokens, err := f.generateTokensCommand.Execute(r.Context(), username, method, device)
+	if err != nil {
+		return nil, err
+	}
+
+	sendNewDeviceLoginEmail(f.emailSender, f.logger, f.clientBaseURL, r.Header.Get("Accept-Language"), tokens, device)
+	return tokens, nil
+}
+
+func (f *loginFlow) handleLoginError(w http.ResponseWriter, err error) {
+	if errors.Is(err, command.ErrUserNotFound) {
+		send.Unauthorized(w, "Invalid username or password")
+		return
+	}
+
+	f.logger.Error("error signing in", "error", err)
+	send.InternalServerError(w, "There was an issue signing you in")
+}
+
+// writeLoginCookies sets the tokens as cookies and returns the user that logged in.
+func writeLoginCookies(w http.ResponseWriter, tokens *command.TokensResponse) {
+	cookie.SetAccessTokenCookie(w, tokens.AccessToken)
+	cookie.SetRefreshTokenCookie(w, tokens.RefreshToken)
+
+	send.JSON(w, LoginResponse{
+		ID:            tokens.User.ID,
+		Name:          tokens.User.Name,
+		Username:      tokens.User.Username,
+		EmailVerified: tokens.User.EmailVerified,
+	}, http.StatusOK)
+}
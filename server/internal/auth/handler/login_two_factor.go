@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"beerbux/internal/auth/command"
+	"beerbux/pkg/email"
+	oz "github.com/go-ozzo/ozzo-validation/v4"
+	"log/slog"
+	"net/http"
+)
+
+type LoginTwoFactorHandler struct {
+	login loginFlow
+}
+
+func NewLoginTwoFactorHandler(
+	verifyTwoFactorCodeCommand *command.VerifyTwoFactorCodeCommand,
+	generateTokensCommand *command.GenerateTokensCommand,
+	secret string,
+	emailSender email.Sender,
+	clientBaseURL string,
+	trustProxy bool,
+	logger *slog.Logger,
+) *LoginTwoFactorHandler {
+	return &LoginTwoFactorHandler{
+		login: loginFlow{
+			verifyTwoFactorCodeCommand: verifyTwoFactorCodeCommand,
+			generateTokensCommand:      generateTokensCommand,
+			secret:                     secret,
+			emailSender:                emailSender,
+			clientBaseURL:              clientBaseURL,
+			trustProxy:                 trustProxy,
+			logger:                     logger,
+		},
+	}
+}
+
+type LoginTwoFactorRequest struct {
+	MFAToken string `json:"mfaToken"`
+	Code     string `json:"code"`
+}
+
+func (h *LoginTwoFactorHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.login.twoFactor(w, r, writeLoginCookies)
+}
+
+func (r LoginTwoFactorRequest) Validate() error {
+	return oz.ValidateStruct(&r,
+		oz.Field(&r.MFAToken, oz.Required),
+		oz.Field(&r.Code, oz.Required.Error("Code is required")),
+	)
+}
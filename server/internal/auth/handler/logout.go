@@ -30,7 +30,7 @@ func (h *LogoutHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	refreshToken, ok := claims.GetRefreshToken(r)
 	if ok {
-		if err := h.invalidateRefreshTokenCommand.Execute(r.Context(), c.Subject, refreshToken); err != nil {
+		if err := h.invalidateRefreshTokenCommand.Execute(r.Context(), refreshToken); err != nil {
 			h.logger.Error("failed to invalidate refresh token", "user", c.Subject, "error", err)
 		}
 	}
@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"beerbux/internal/auth/command"
+	"beerbux/pkg/email"
+	"beerbux/pkg/send"
+	"encoding/json"
+	"errors"
+	oz "github.com/go-ozzo/ozzo-validation/v4"
+	"log/slog"
+	"net/http"
+)
+
+type MagicLinkLoginHandler struct {
+	magicLinkLoginCommand *command.MagicLinkLoginCommand
+	login                 loginFlow
+	logger                *slog.Logger
+}
+
+func NewMagicLinkLoginHandler(
+	magicLinkLoginCommand *command.MagicLinkLoginCommand,
+	twoFactorChallengeCommand *command.TwoFactorChallengeCommand,
+	generateTokensCommand *command.GenerateTokensCommand,
+	emailSender email.Sender,
+	clientBaseURL string,
+	trustProxy bool,
+	logger *slog.Logger,
+) *MagicLinkLoginHandler {
+	return &MagicLinkLoginHandler{
+		magicLinkLoginCommand: magicLinkLoginCommand,
+		login: loginFlow{
+			generateTokensCommand:     generateTokensCommand,
+			twoFactorChallengeCommand: twoFactorChallengeCommand,
+			emailSender:               emailSender,
+			clientBaseURL:             clientBaseURL,
+			trustProxy:                trustProxy,
+			logger:                    logger,
+		},
+		logger: logger,
+	}
+}
+
+type MagicLinkLoginRequest struct {
+	Email string `json:"email"`
+	OTP   string `json:"otp"`
+}
+
+// ServeHTTP logs the user in with the OTP from their magic link. The magic link replaces
+// the password only, so users with two-factor authentication must still provide a code.
+func (h *MagicLinkLoginHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req MagicLinkLoginRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		send.BadRequest(w, "Failed to decode request")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		send.ValidationError(w, err)
+		return
+	}
+
+	username, err := h.magicLinkLoginCommand.Execute(r.Context(), req.Email, req.OTP)
+	if err != nil {
+		h.handleMagicLinkError(w, err)
+		return
+	}
+
+	h.login.challenge(w, r, username, command.LoginMethodMagicLink, writeLoginCookies)
+}
+
+func (h *MagicLinkLoginHandler) handleMagicLinkError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, command.ErrUserNotFound), errors.Is(err, command.ErrProcessNotInitialized):
+		send.Unauthorized(w, "The login link is invalid, please request a new one")
+	case errors.Is(err, command.ErrOTPExpired):
+		send.Unauthorized(w, "The login link has expired, please request a new one")
+	case errors.Is(err, command.ErrTooManyOTPAttempts):
+		send.TooManyRequests(w, "Too many incorrect attempts, please wait before requesting a new login link")
+	case errors.Is(err, command.ErrIncorrectOTP):
+		send.Unauthorized(w, "The provided OTP is incorrect")
+	default:
+		h.logger.Error("failed to log in with magic link", "error", err)
+		send.InternalServerError(w, "There was an issue signing you in")
+	}
+}
+
+func (r MagicLinkLoginRequest) Validate() error {
+	return oz.ValidateStruct(&r,
+		oz.Field(&r.Email, oz.Required.Error("Email address is required")),
+		oz.Field(&r.OTP, oz.Required.Error("OTP is required")),
+	)
+}
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"beerbux/internal/auth/command"
+	"beerbux/internal/common/useraccess"
+	"beerbux/pkg/email"
+	"beerbux/pkg/i18n"
+	"beerbux/pkg/send"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+type InitializeMagicLinkHandler struct {
+	initializeMagicLinkCommand *command.InitializeMagicLinkCommand
+	userReader                 useraccess.UserReader
+	emailSender                email.Sender
+	clientBaseURL              string
+	logger                     *slog.Logger
+}
+
+func NewInitializeMagicLinkHandler(
+	initializeMagicLinkCommand *command.InitializeMagicLinkCommand,
+	userReader useraccess.UserReader,
+	emailSender email.Sender,
+	clientBaseURL string,
+	logger *slog.Logger,
+) *InitializeMagicLinkHandler {
+	return &InitializeMagicLinkHandler{
+		initializeMagicLinkCommand: initializeMagicLinkCommand,
+		userReader:                 userReader,
+		emailSender:                emailSender,
+		clientBaseURL:              clientBaseURL,
+		logger:                     logger,
+	}
+}
+
+type InitializeMagicLinkRequest struct {
+	Email string `json:"email"`
+}
+
+func (h *InitializeMagicLinkHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req InitializeMagicLinkRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		send.BadRequest(w, "Invalid request")
+		return
+	}
+
+	user, err := h.userReader.GetUserByEmail(r.Context(), req.Email)
+	if err != nil {
+		if errors.Is(err, useraccess.ErrUserNotFound) {
+			// Respond as if the email was sent to avoid revealing which email addresses have accounts
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		h.logger.Error("failed to get user by email", "error", err)
+		send.InternalServerError(w, "There has been an issue checking your email address, please try again")
+		return
+	}
+
+	if !user.EmailVerified {
+		// Magic links can only be sent to a verified email address
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	result, err := h.initializeMagicLinkCommand.Execute(r.Context(), user.ID)
+	if err != nil {
+		if errors.Is(err, command.ErrOTPLocked) {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		h.logger.Error("failed to execute initialize magic link command", "error", err)
+		send.InternalServerError(w, "There was an issue sending your login link, please try again")
+		return
+	}
+
+	h.sendMagicLinkEmail(i18n.Resolve(user.Locale, r.Header.Get("Accept-Language")), user.Email, user.Username, result.OTP)
+	w.WriteHeader(http.StatusOK)
+}
+
+func (h *InitializeMagicLinkHandler) sendMagicLinkEmail(locale, emailAddress, username, otp string) {
+	// The OTP goes in the fragment, which is read by the client and never sent to a server,
+	// keeping it out of access logs and Referer headers.
+	loginURL := strings.TrimRight(h.clientBaseURL, "/") + "/login/magic-link#" + url.Values{
+		"email": {emailAddress},
+		"otp":   {otp},
+	}.Encode()
+
+	tmpl := email.MagicLinkEmail(locale, email.MagicLinkEmailData{
+		Username:          username,
+		OTP:               otp,
+		LoginURL:          loginURL,
+		ExpirationMinutes: strconv.FormatInt(int64(command.MagicLinkOTPTimeToLiveMinutes), 10),
+	})
+	if _, err := email.SendTemplate(h.emailSender, emailAddress, i18n.Translate(locale, "Your Beerbux login link"), tmpl); err != nil {
+		h.logger.Error("failed to send email", "error", err)
+	}
+}
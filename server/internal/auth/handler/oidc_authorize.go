@@ -0,0 +1,75 @@
+package handler
+
+import (
+	"beerbux/internal/auth/cookie"
+	"beerbux/internal/auth/shared"
+	"beerbux/internal/common/claims"
+	"beerbux/pkg/oidc"
+	"beerbux/pkg/send"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// OIDCStateTimeToLive is how long the user has to log in at the provider.
+const OIDCStateTimeToLive = 10 * time.Minute
+
+// OIDCAuthorizeHandler starts the authorization code flow by redirecting the user to the
+// provider. The mode determines if the callback logs the user in or links the identity
+// to the logged-in user.
+type OIDCAuthorizeHandler struct {
+	providers *oidcProviders
+	mode      string
+	jwtSecret string
+	logger    *slog.Logger
+}
+
+func NewOIDCAuthorizeHandler(providers *oidcProviders, mode, jwtSecret string, logger *slog.Logger) *OIDCAuthorizeHandler {
+	return &OIDCAuthorizeHandler{
+		providers: providers,
+		mode:      mode,
+		jwtSecret: jwtSecret,
+		logger:    logger,
+	}
+}
+
+func (h *OIDCAuthorizeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	provider, ok := h.providers.get(r.PathValue("provider"))
+	if !ok {
+		send.NotFound(w, "Unknown login provider")
+		return
+	}
+
+	state := shared.OIDCState{
+		Provider:     provider.name,
+		Mode:         h.mode,
+		State:        oidc.RandomString(),
+		Nonce:        oidc.RandomString(),
+		CodeVerifier: oidc.RandomString(),
+	}
+	if h.mode == shared.OIDCModeLink {
+		c := claims.GetClaims(r)
+		if !c.Authenticated() {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		state.UserID = c.Subject
+	}
+
+	authURL, err := provider.AuthCodeURL(r.Context(), state.State, state.Nonce, state.CodeVerifier)
+	if err != nil {
+		h.logger.Error("failed to build OIDC authorization URL", "provider", provider.name, "error", err)
+		send.Error(w, "The login provider is unavailable", http.StatusBadGateway)
+		return
+	}
+
+	signedState, err := shared.GenerateOIDCState(state, h.jwtSecret, OIDCStateTimeToLive)
+	if err != nil {
+		h.logger.Error("failed to generate OIDC state", "error", err)
+		send.InternalServerError(w, "There was an issue signing you in")
+		return
+	}
+
+	cookie.SetOIDCStateCookie(w, signedState, OIDCStateTimeToLive)
+	http.Redirect(w, r, authURL, http.StatusFound)
+}
@@ -0,0 +1,201 @@
+package handler
+
+import (
+	"beerbux/internal/auth/command"
+	"beerbux/internal/auth/cookie"
+	"beerbux/internal/auth/shared"
+	"beerbux/internal/common/claims"
+	"beerbux/pkg/email"
+	"crypto/subtle"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// Error codes passed to the client when the OIDC flow fails, as the callback is a browser redirect.
+const (
+	oidcErrorFailed         = "oidc_failed"
+	oidcErrorDenied         = "oidc_denied"
+	oidcErrorInvalidState   = "oidc_invalid_state"
+	oidcErrorEmailRequired  = "oidc_email_required"
+	oidcErrorEmailInUse     = "oidc_email_in_use"
+	oidcErrorIdentityInUse  = "oidc_identity_in_use"
+	oidcErrorProviderLinked = "oidc_provider_linked"
+)
+
+// OIDCCallbackHandler completes the authorization code flow when the provider redirects back.
+// The user is redirected to the client once they are logged in or the identity is linked.
+type OIDCCallbackHandler struct {
+	providers           *oidcProviders
+	oidcLoginCommand    *command.OIDCLoginCommand
+	linkIdentityCommand *command.LinkIdentityCommand
+	login               loginFlow
+	jwtSecret           string
+	clientBaseURL       string
+	logger              *slog.Logger
+}
+
+func NewOIDCCallbackHandler(
+	providers *oidcProviders,
+	oidcLoginCommand *command.OIDCLoginCommand,
+	linkIdentityCommand *command.LinkIdentityCommand,
+	twoFactorChallengeCommand *command.TwoFactorChallengeCommand,
+	generateTokensCommand *command.GenerateTokensCommand,
+	jwtSecret string,
+	clientBaseURL string,
+	emailSender email.Sender,
+	trustProxy bool,
+	logger *slog.Logger,
+) *OIDCCallbackHandler {
+	return &OIDCCallbackHandler{
+		providers:           providers,
+		oidcLoginCommand:    oidcLoginCommand,
+		linkIdentityCommand: linkIdentityCommand,
+		login: loginFlow{
+			generateTokensCommand:     generateTokensCommand,
+			twoFactorChallengeCommand: twoFactorChallengeCommand,
+			emailSender:               emailSender,
+			clientBaseURL:             clientBaseURL,
+			trustProxy:                trustProxy,
+			logger:                    logger,
+		},
+		jwtSecret:     jwtSecret,
+		clientBaseURL: clientBaseURL,
+		logger:        logger,
+	}
+}
+
+func (h *OIDCCallbackHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	state, ok := h.validateState(w, r)
+	if !ok {
+		h.redirect(w, r, "/login", url.Values{"error": {oidcErrorInvalidState}})
+		return
+	}
+
+	errorPath := "/login"
+	if state.Mode == shared.OIDCModeLink {
+		errorPath = "/settings"
+	}
+
+	if providerError := r.URL.Query().Get("error"); providerError != "" {
+		h.logger.Info("OIDC provider returned an error", "provider", state.Provider, "error", providerError)
+		h.redirect(w, r, errorPath, url.Values{"error": {oidcErrorDenied}})
+		return
+	}
+
+	provider, ok := h.providers.get(state.Provider)
+	if !ok {
+		h.redirect(w, r, errorPath, url.Values{"error": {oidcErrorInvalidState}})
+		return
+	}
+
+	rawIDToken, err := provider.Exchange(r.Context(), r.URL.Query().Get("code"), state.CodeVerifier)
+	if err != nil {
+		h.logger.Error("failed to exchange OIDC code", "provider", provider.name, "error", err)
+		h.redirect(w, r, errorPath, url.Values{"error": {oidcErrorFailed}})
+		return
+	}
+
+	identity, err := provider.VerifyIDToken(r.Context(), rawIDToken, state.Nonce)
+	if err != nil {
+		h.logger.Warn("failed to verify OIDC ID token", "provider", provider.name, "error", err)
+		h.redirect(w, r, errorPath, url.Values{"error": {oidcErrorFailed}})
+		return
+	}
+
+	if state.Mode == shared.OIDCModeLink {
+		if err := h.linkIdentityCommand.Execute(r.Context(), state.UserID, provider.name, identity); err != nil {
+			h.redirect(w, r, errorPath, url.Values{"error": {h.errorCode(err)}})
+			return
+		}
+		h.redirect(w, r, "/settings", url.Values{"linked": {provider.name}})
+		return
+	}
+
+	username, err := h.oidcLoginCommand.Execute(r.Context(), provider.name, identity)
+	if err != nil {
+		h.redirect(w, r, errorPath, url.Values{"error": {h.errorCode(err)}})
+		return
+	}
+
+	challenge, err := h.login.twoFactorChallengeCommand.Execute(r.Context(), username, command.LoginMethodOIDC)
+	if err != nil {
+		h.logger.Error("failed to determine two-factor challenge", "error", err)
+		h.redirect(w, r, errorPath, url.Values{"error": {oidcErrorFailed}})
+		return
+	}
+	if challenge.Required {
+		// The token goes in the fragment, which is read by the login page and never sent to a server.
+		target := strings.TrimSuffix(h.clientBaseURL, "/") + "/login#" + url.Values{"mfaToken": {challenge.MFAToken}}.Encode()
+		http.Redirect(w, r, target, http.StatusFound)
+		return
+	}
+
+	// The tokens are issued rather than completing the login with a tokenWriter, as failures
+	// must redirect back to the client instead of being written as JSON.
+	tokens, err := h.login.issueTokens(r, username, command.LoginMethodOIDC)
+	if err != nil {
+		h.logger.Error("failed to generate tokens", "error", err)
+		h.redirect(w, r, errorPath, url.Values{"error": {oidcErrorFailed}})
+		return
+	}
+
+	cookie.SetAccessTokenCookie(w, tokens.AccessToken)
+	cookie.SetRefreshTokenCookie(w, tokens.RefreshToken)
+	h.redirect(w, r, "/", nil)
+}
+
+// validateState checks the state returned by the provider matches the state cookie set for
+// this browser when the flow started, preventing login CSRF. The cookie can only be used once.
+func (h *OIDCCallbackHandler) validateState(w http.ResponseWriter, r *http.Request) (*shared.OIDCState, bool) {
+	stateCookie, err := r.Cookie(cookie.OIDCStateKey)
+	if err != nil {
+		return nil, false
+	}
+	cookie.ClearOIDCStateCookie(w)
+
+	state, err := shared.ParseOIDCState(stateCookie.Value, h.jwtSecret)
+	if err != nil || state.Provider != r.PathValue("provider") {
+		return nil, false
+	}
+
+	if subtle.ConstantTimeCompare([]byte(state.State), []byte(r.URL.Query().Get("state"))) != 1 {
+		return nil, false
+	}
+
+	// Identities may only be linked to the user who started linking.
+	if state.Mode == shared.OIDCModeLink {
+		c := claims.GetClaims(r)
+		if !c.Authenticated() || c.Subject != state.UserID {
+			return nil, false
+		}
+	}
+
+	return state, true
+}
+
+func (h *OIDCCallbackHandler) errorCode(err error) string {
+	switch {
+	case errors.Is(err, command.ErrOIDCEmailRequired):
+		return oidcErrorEmailRequired
+	case errors.Is(err, command.ErrOIDCEmailInUse):
+		return oidcErrorEmailInUse
+	case errors.Is(err, command.ErrIdentityLinkedToOtherUser):
+		return oidcErrorIdentityInUse
+	case errors.Is(err, command.ErrProviderAlreadyLinked):
+		return oidcErrorProviderLinked
+	default:
+		h.logger.Error("failed to complete OIDC login", "error", err)
+		return oidcErrorFailed
+	}
+}
+
+func (h *OIDCCallbackHandler) redirect(w http.ResponseWriter, r *http.Request, path string, query url.Values) {
+	target := strings.TrimSuffix(h.clientBaseURL, "/") + path
+	if len(query) > 0 {
+		target += "?" + query.Encode()
+	}
+	http.Redirect(w, r, target, http.StatusFound)
+}
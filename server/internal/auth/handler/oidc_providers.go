@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"beerbux/internal/api/config"
+	"beerbux/pkg/oidc"
+	"beerbux/pkg/send"
+	"net/http"
+	"time"
+)
+
+const oidcHTTPTimeout = 10 * time.Second
+
+type oidcProvider struct {
+	*oidc.Provider
+	name        string
+	displayName string
+}
+
+// oidcProviders holds the configured OpenID Connect providers by name, in configuration order.
+type oidcProviders struct {
+	byName map[string]*oidcProvider
+	order  []*oidcProvider
+}
+
+func newOIDCProviders(configs []config.OIDCProviderConfig) *oidcProviders {
+	httpClient := &http.Client{Timeout: oidcHTTPTimeout}
+	providers := &oidcProviders{byName: make(map[string]*oidcProvider, len(configs))}
+	for _, cfg := range configs {
+		p := &oidcProvider{
+			Provider: oidc.NewProvider(oidc.Config{
+				Issuer:       cfg.Issuer,
+				ClientID:     cfg.ClientID,
+				ClientSecret: cfg.ClientSecret,
+				RedirectURL:  cfg.RedirectURL,
+				Scopes:       cfg.Scopes,
+			}, httpClient),
+			name:        cfg.Name,
+			displayName: cfg.DisplayName,
+		}
+		providers.byName[cfg.Name] = p
+		providers.order = append(providers.order, p)
+	}
+	return providers
+}
+
+func (p *oidcProviders) get(name string) (*oidcProvider, bool) {
+	provider, ok := p.byName[name]
+	return provider, ok
+}
+
+type ListOIDCProvidersHandler struct {
+	providers *oidcProviders
+}
+
+func NewListOIDCProvidersHandler(providers *oidcProviders) *ListOIDCProvidersHandler {
+	return &ListOIDCProvidersHandler{
+		providers: providers,
+	}
+}
+
+type OIDCProviderResponse struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+	LoginURL    string `json:"loginUrl"`
+}
+
+func (h *ListOIDCProvidersHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	resp := make([]OIDCProviderResponse, 0, len(h.providers.order))
+	for _, p := range h.providers.order {
+		resp = append(resp, OIDCProviderResponse{
+			Name:        p.name,
+			DisplayName: p.displayName,
+			LoginURL:    "/api/auth/oidc/" + p.name + "/login",
+		})
+	}
+	send.JSON(w, resp, http.StatusOK)
+}
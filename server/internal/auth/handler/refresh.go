@@ -3,6 +3,7 @@ package handler
 import (
 	"beerbux/internal/auth/command"
 	"beerbux/internal/auth/cookie"
+	"beerbux/internal/auth/shared"
 	"beerbux/internal/common/claims"
 	"errors"
 	"log/slog"
@@ -11,12 +12,14 @@ import (
 
 type RefreshHandler struct {
 	refreshTokenCommand *command.RefreshTokenCommand
+	trustProxy          bool
 	logger              *slog.Logger
 }
 
-func NewRefreshHandler(refreshTokenCommand *command.RefreshTokenCommand, logger *slog.Logger) *RefreshHandler {
+func NewRefreshHandler(refreshTokenCommand *command.RefreshTokenCommand, trustProxy bool, logger *slog.Logger) *RefreshHandler {
 	return &RefreshHandler{
 		refreshTokenCommand: refreshTokenCommand,
+		trustProxy:          trustProxy,
 		logger:              logger,
 	}
 }
@@ -34,12 +37,17 @@ func (h *RefreshHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	resp, err := h.refreshTokenCommand.Execute(r.Context(), c.Subject, refreshToken)
+	resp, err := h.refreshTokenCommand.Execute(r.Context(), refreshToken, shared.NewDeviceInfo(r, h.trustProxy))
 	if err != nil {
 		if errors.Is(err, command.ErrRefreshTokenNotFound) {
 			w.WriteHeader(http.StatusUnauthorized)
 			return
 		}
+		if errors.Is(err, command.ErrRefreshTokenReused) {
+			h.logger.Warn("refresh token reused, revoked token family", "user", c.Subject)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
 		w.WriteHeader(http.StatusInternalServerError)
 		return
 	}
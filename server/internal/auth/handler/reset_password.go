@@ -2,22 +2,24 @@ package handler
 
 import (
 	"beerbux/internal/auth/command"
+	"beerbux/internal/auth/shared"
 	"beerbux/pkg/send"
 	"encoding/json"
 	"errors"
-	"fmt"
 	"log/slog"
 	"net/http"
 )
 
 type ResetPasswordHandler struct {
 	resetPasswordCommand *command.ResetPasswordCommand
+	trustProxy           bool
 	logger               *slog.Logger
 }
 
-func NewResetPasswordHandler(resetPasswordCommand *command.ResetPasswordCommand, logger *slog.Logger) *ResetPasswordHandler {
+func NewResetPasswordHandler(resetPasswordCommand *command.ResetPasswordCommand, trustProxy bool, logger *slog.Logger) *ResetPasswordHandler {
 	return &ResetPasswordHandler{
 		resetPasswordCommand: resetPasswordCommand,
+		trustProxy:           trustProxy,
 		logger:               logger,
 	}
 }
@@ -35,24 +37,30 @@ func (h *ResetPasswordHandler) ServeHTTP(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
-	if passwordErr := validatePassword(req.NewPassword); passwordErr != nil {
-		send.BadRequest(w, fmt.Sprintf("Invalid password: %s", passwordErr))
-	}
-
-	if err := h.resetPasswordCommand.Execute(r.Context(), req.Email, req.OTP, req.NewPassword); err != nil {
-		h.handleResetPasswordError(w, err)
+	if err := h.resetPasswordCommand.Execute(r.Context(), req.Email, req.OTP, req.NewPassword, shared.NewDeviceInfo(r, h.trustProxy)); err != nil {
+		h.handleResetPasswordError(w, r, err)
 		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
 
-func (h *ResetPasswordHandler) handleResetPasswordError(w http.ResponseWriter, err error) {
+func (h *ResetPasswordHandler) handleResetPasswordError(w http.ResponseWriter, r *http.Request, err error) {
+	if sendPasswordPolicyViolation(w, r, err) {
+		return
+	}
+
 	switch {
+	case errors.Is(err, command.ErrEmailNotVerified):
+		send.BadRequest(w, "The email address for this account has not been verified")
 	case errors.Is(err, command.ErrPasswordResetNotInitialized):
 		send.BadRequest(w, "A password reset was not requested for this account")
+	case errors.Is(err, command.ErrTooManyOTPAttempts):
+		send.TooManyRequests(w, "Too many incorrect attempts, please wait before requesting a new OTP")
 	case errors.Is(err, command.ErrIncorrectOTP):
 		send.BadRequest(w, "The provided OTP is incorrect")
+	case errors.Is(err, command.ErrOTPExpired):
+		send.BadRequest(w, "Your OTP has expired, please start the process again")
 	default:
 		h.logger.Error("failed to reset the password", "error", err)
 		send.InternalServerError(w, "There has been an issue resetting the password")
@@ -4,6 +4,7 @@ import (
 	"beerbux/internal/auth/command"
 	"beerbux/internal/common/useraccess"
 	"beerbux/pkg/email"
+	"beerbux/pkg/i18n"
 	"beerbux/pkg/send"
 	"encoding/json"
 	"errors"
@@ -56,28 +57,35 @@ func (h *InitializePasswordResetHandler) ServeHTTP(w http.ResponseWriter, r *htt
 		return
 	}
 
+	if !user.EmailVerified {
+		// Passwords can only be reset via a verified email address
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
 	result, err := h.initializePasswordResetCommand.Execute(r.Context(), user.ID)
 	if err != nil {
+		if errors.Is(err, command.ErrOTPLocked) {
+			// Respond as if the email was sent to avoid revealing anything about the account
+			w.WriteHeader(http.StatusOK)
+			return
+		}
 		h.logger.Error("failed to execute initialize password reset command", "error", err)
 		send.InternalServerError(w, "There was an issue resetting your password, please try again")
 		return
 	}
 
-	h.sendPasswordResetEmail(user.Email, user.Username, result.OTP)
+	h.sendPasswordResetEmail(i18n.Resolve(user.Locale, r.Header.Get("Accept-Language")), user.Email, user.Username, result.OTP)
 	w.WriteHeader(http.StatusOK)
 }
 
-func (h *InitializePasswordResetHandler) sendPasswordResetEmail(emailAddress, username, otp string) {
-	html, err := email.GenerateResetPasswordEmail(email.ResetPasswordEmailData{
+func (h *InitializePasswordResetHandler) sendPasswordResetEmail(locale, emailAddress, username, otp string) {
+	tmpl := email.ResetPasswordEmail(locale, email.ResetPasswordEmailData{
 		Username:          username,
 		OTP:               otp,
 		ExpirationMinutes: strconv.FormatInt(int64(command.OTPTimeToLiveMinutes), 10),
 	})
-	if err != nil {
-		h.logger.Error("failed to generate password reset email template", "error", err)
-		return
-	}
-	if _, err := h.emailSender.Send(emailAddress, "Password reset request", html); err != nil {
+	if _, err := email.SendTemplate(h.emailSender, emailAddress, i18n.Translate(locale, "Password reset request"), tmpl); err != nil {
 		h.logger.Error("failed to send email", "error", err)
 	}
 }
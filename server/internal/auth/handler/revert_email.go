@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"beerbux/internal/auth/command"
+	"beerbux/internal/auth/shared"
+	"beerbux/pkg/send"
+	"encoding/json"
+	"errors"
+	oz "github.com/go-ozzo/ozzo-validation/v4"
+	"log/slog"
+	"net/http"
+)
+
+type RevertEmailHandler struct {
+	revertEmailCommand *command.RevertEmailCommand
+	trustProxy         bool
+	logger             *slog.Logger
+}
+
+func NewRevertEmailHandler(revertEmailCommand *command.RevertEmailCommand, trustProxy bool, logger *slog.Logger) *RevertEmailHandler {
+	return &RevertEmailHandler{
+		revertEmailCommand: revertEmailCommand,
+		trustProxy:         trustProxy,
+		logger:             logger,
+	}
+}
+
+type RevertEmailRequest struct {
+	Token string `json:"token"`
+}
+
+// ServeHTTP restores the previous email address using the token from the email sent to it
+// when the address was changed. All refresh tokens are revoked, logging the user out of every device.
+func (h *RevertEmailHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req RevertEmailRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		send.BadRequest(w, "Failed to decode request")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		send.ValidationError(w, err)
+		return
+	}
+
+	if err := h.revertEmailCommand.Execute(r.Context(), req.Token, shared.NewDeviceInfo(r, h.trustProxy)); err != nil {
+		switch {
+		case errors.Is(err, command.ErrEmailRevertTokenInvalid):
+			send.BadRequest(w, "The link is invalid or has expired")
+		case errors.Is(err, command.ErrPreviousEmailTaken):
+			send.Error(w, "The previous email address is now used by another account", http.StatusConflict)
+		default:
+			h.logger.Error("failed to revert email address", "error", err)
+			send.InternalServerError(w, "There has been an issue restoring your email address")
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (r RevertEmailRequest) Validate() error {
+	return oz.ValidateStruct(&r,
+		oz.Field(&r.Token, oz.Required),
+	)
+}
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"beerbux/internal/auth/command"
+	"beerbux/internal/common/claims"
+	"beerbux/pkg/send"
+	"errors"
+	"log/slog"
+	"net/http"
+)
+
+type RevokeOtherSessionsHandler struct {
+	revokeOtherSessionsCommand *command.RevokeOtherSessionsCommand
+	logger                     *slog.Logger
+}
+
+func NewRevokeOtherSessionsHandler(revokeOtherSessionsCommand *command.RevokeOtherSessionsCommand, logger *slog.Logger) *RevokeOtherSessionsHandler {
+	return &RevokeOtherSessionsHandler{
+		revokeOtherSessionsCommand: revokeOtherSessionsCommand,
+		logger:                     logger,
+	}
+}
+
+// ServeHTTP logs the user out everywhere except the current device.
+func (h *RevokeOtherSessionsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c := claims.GetClaims(r)
+	if !c.Authenticated() {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	refreshToken, ok := claims.GetRefreshToken(r)
+	if !ok {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	if err := h.revokeOtherSessionsCommand.Execute(r.Context(), c.Subject, refreshToken); err != nil {
+		if errors.Is(err, command.ErrRefreshTokenNotFound) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		h.logger.Error("failed to revoke other sessions", "user", c.Subject, "error", err)
+		send.InternalServerError(w, "There has been an issue logging out of your other sessions")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"beerbux/internal/auth/command"
+	"beerbux/internal/common/claims"
+	"beerbux/pkg/send"
+	"errors"
+	"github.com/google/uuid"
+	"log/slog"
+	"net/http"
+)
+
+type RevokePersonalAccessTokenHandler struct {
+	revokePersonalAccessTokenCommand *command.RevokePersonalAccessTokenCommand
+	logger                           *slog.Logger
+}
+
+func NewRevokePersonalAccessTokenHandler(revokePersonalAccessTokenCommand *command.RevokePersonalAccessTokenCommand, logger *slog.Logger) *RevokePersonalAccessTokenHandler {
+	return &RevokePersonalAccessTokenHandler{
+		revokePersonalAccessTokenCommand: revokePersonalAccessTokenCommand,
+		logger:                           logger,
+	}
+}
+
+func (h *RevokePersonalAccessTokenHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c := claims.GetClaims(r)
+	if !c.Authenticated() {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	tokenID, err := uuid.Parse(r.PathValue("tokenId"))
+	if err != nil {
+		send.BadRequest(w, "Invalid token ID")
+		return
+	}
+
+	if err := h.revokePersonalAccessTokenCommand.Execute(r.Context(), c.Subject, tokenID); err != nil {
+		if errors.Is(err, command.ErrPersonalAccessTokenNotFound) {
+			send.NotFound(w, "Access token not found")
+			return
+		}
+		h.logger.Error("failed to revoke personal access token", "user", c.Subject, "token", tokenID, "error", err)
+		send.InternalServerError(w, "There has been an issue revoking the access token")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
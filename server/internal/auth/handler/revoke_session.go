@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"beerbux/internal/auth/command"
+	"beerbux/internal/common/claims"
+	"beerbux/pkg/send"
+	"errors"
+	"github.com/google/uuid"
+	"log/slog"
+	"net/http"
+)
+
+type RevokeSessionHandler struct {
+	revokeSessionCommand *command.RevokeSessionCommand
+	logger               *slog.Logger
+}
+
+func NewRevokeSessionHandler(revokeSessionCommand *command.RevokeSessionCommand, logger *slog.Logger) *RevokeSessionHandler {
+	return &RevokeSessionHandler{
+		revokeSessionCommand: revokeSessionCommand,
+		logger:               logger,
+	}
+}
+
+func (h *RevokeSessionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c := claims.GetClaims(r)
+	if !c.Authenticated() {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	sessionID, err := uuid.Parse(r.PathValue("sessionId"))
+	if err != nil {
+		send.BadRequest(w, "Invalid session ID")
+		return
+	}
+
+	if err := h.revokeSessionCommand.Execute(r.Context(), c.Subject, sessionID); err != nil {
+		if errors.Is(err, command.ErrSessionNotFound) {
+			send.NotFound(w, "Session not found")
+			return
+		}
+		h.logger.Error("failed to revoke session", "user", c.Subject, "session", sessionID, "error", err)
+		send.InternalServerError(w, "There has been an issue logging out of the session")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -4,6 +4,7 @@ import (
 	"beerbux/internal/api/config"
 	"beerbux/internal/auth/command"
 	"beerbux/internal/auth/db"
+	"beerbux/internal/auth/shared"
 	"beerbux/internal/common/useraccess"
 	useraccessQueries "beerbux/internal/common/useraccess/db"
 	"beerbux/pkg/email"
@@ -26,25 +27,76 @@ func BuildRoutes(
 	signupCommand := command.NewSignupCommand(queries, options)
 	refreshCommand := command.NewRefreshTokenCommand(queries, options)
 	invalidateRefreshTokenCommand := command.NewInvalidateRefreshTokenCommand(queries)
-	initializeUpdatePasswordCommand := command.NewInitializeUpdatePasswordCommand(queries)
-	updatePasswordCommand := command.NewUpdatePasswordCommand(queries)
+	initializeUpdatePasswordCommand := command.NewInitializeUpdatePasswordCommand(queries, options)
+	updatePasswordCommand := command.NewUpdatePasswordCommand(database, queries)
 	initializeUpdateEmailCommand := command.NewInitializeUpdateEmailCommand(queries)
-	updateEmailCommand := command.NewUpdateEmailCommand(queries)
-	comparePasswordCommand := command.NewComparePasswordCommand(queries)
+	updateEmailCommand := command.NewUpdateEmailCommand(database, queries)
+	revertEmailCommand := command.NewRevertEmailCommand(database, queries)
+	comparePasswordCommand := command.NewComparePasswordCommand(queries, options.PasswordHasher)
 	initializePasswordResetCommand := command.NewInitializePasswordResetCommand(queries)
-	resetPasswordCommand := command.NewResetPasswordCommand(queries)
+	resetPasswordCommand := command.NewResetPasswordCommand(database, queries, options)
+	initializeEmailVerificationCommand := command.NewInitializeEmailVerificationCommand(queries)
+	verifyEmailCommand := command.NewVerifyEmailCommand(database, queries)
+	twoFactorChallengeCommand := command.NewTwoFactorChallengeCommand(queries, options)
+	verifyTwoFactorCodeCommand := command.NewVerifyTwoFactorCodeCommand(queries)
+	twoFactorStatusCommand := command.NewTwoFactorStatusCommand(queries)
+	enrollTwoFactorCommand := command.NewEnrollTwoFactorCommand(queries)
+	enableTwoFactorCommand := command.NewEnableTwoFactorCommand(database, queries)
+	disableTwoFactorCommand := command.NewDisableTwoFactorCommand(database, queries)
+	listSessionsCommand := command.NewListSessionsCommand(queries)
+	revokeSessionCommand := command.NewRevokeSessionCommand(queries)
+	revokeOtherSessionsCommand := command.NewRevokeOtherSessionsCommand(queries)
+	listPersonalAccessTokensCommand := command.NewListPersonalAccessTokensCommand(queries)
+	createPersonalAccessTokenCommand := command.NewCreatePersonalAccessTokenCommand(queries)
+	revokePersonalAccessTokenCommand := command.NewRevokePersonalAccessTokenCommand(queries)
+	initializeMagicLinkCommand := command.NewInitializeMagicLinkCommand(queries)
+	magicLinkLoginCommand := command.NewMagicLinkLoginCommand(database, queries)
+	oidcLoginCommand := command.NewOIDCLoginCommand(database, queries, options)
+	linkIdentityCommand := command.NewLinkIdentityCommand(queries)
+	listIdentitiesCommand := command.NewListIdentitiesCommand(queries)
+	unlinkIdentityCommand := command.NewUnlinkIdentityCommand(queries)
+	listAuditEventsCommand := command.NewListAuditEventsCommand(queries)
+
+	oidcProviders := newOIDCProviders(cfg.OIDC)
 
 	userAccessQueries := useraccessQueries.New(database)
 	userReaderService := useraccess.NewUserReaderService(userAccessQueries)
 
-	mux.Handle("POST /auth/login", NewLoginHandler(generateTokensCommand, comparePasswordCommand, logger))
-	mux.Handle("POST /auth/signup", NewSignupHandler(signupCommand, logger))
-	mux.Handle("POST /auth/refresh", NewRefreshHandler(refreshCommand, logger))
+	mux.Handle("POST /auth/login", NewLoginHandler(generateTokensCommand, comparePasswordCommand, twoFactorChallengeCommand, emailSender, cfg.CORSClientBaseURL, cfg.TrustProxy, logger))
+	mux.Handle("POST /auth/login/2fa", NewLoginTwoFactorHandler(verifyTwoFactorCodeCommand, generateTokensCommand, options.JWTSecret, emailSender, cfg.CORSClientBaseURL, cfg.TrustProxy, logger))
+	mux.Handle("POST /auth/magic-link", NewInitializeMagicLinkHandler(initializeMagicLinkCommand, userReaderService, emailSender, cfg.CORSClientBaseURL, logger))
+	mux.Handle("POST /auth/magic-link/login", NewMagicLinkLoginHandler(magicLinkLoginCommand, twoFactorChallengeCommand, generateTokensCommand, emailSender, cfg.CORSClientBaseURL, cfg.TrustProxy, logger))
+	mux.Handle("POST /auth/signup", NewSignupHandler(signupCommand, initializeEmailVerificationCommand, emailSender, cfg.CORSClientBaseURL, cfg.TrustProxy, logger))
+	mux.Handle("POST /auth/token", NewTokenHandler(generateTokensCommand, comparePasswordCommand, twoFactorChallengeCommand, options.AccessTokenTTL, emailSender, cfg.CORSClientBaseURL, cfg.TrustProxy, logger))
+	mux.Handle("POST /auth/token/2fa", NewTokenTwoFactorHandler(verifyTwoFactorCodeCommand, generateTokensCommand, options.JWTSecret, options.AccessTokenTTL, emailSender, cfg.CORSClientBaseURL, cfg.TrustProxy, logger))
+	mux.Handle("POST /auth/token/refresh", NewTokenRefreshHandler(refreshCommand, options.AccessTokenTTL, cfg.TrustProxy, logger))
+	mux.Handle("POST /auth/token/revoke", NewTokenRevokeHandler(invalidateRefreshTokenCommand, logger))
+	mux.Handle("POST /auth/refresh", NewRefreshHandler(refreshCommand, cfg.TrustProxy, logger))
 	mux.Handle("POST /auth/logout", NewLogoutHandler(invalidateRefreshTokenCommand, logger))
+	mux.Handle("GET /auth/sessions", NewListSessionsHandler(listSessionsCommand, logger))
+	mux.Handle("DELETE /auth/sessions", NewRevokeOtherSessionsHandler(revokeOtherSessionsCommand, logger))
+	mux.Handle("DELETE /auth/sessions/{sessionId}", NewRevokeSessionHandler(revokeSessionCommand, logger))
+	mux.Handle("GET /auth/personal-access-tokens", NewListPersonalAccessTokensHandler(listPersonalAccessTokensCommand, logger))
+	mux.Handle("POST /auth/personal-access-tokens", NewCreatePersonalAccessTokenHandler(createPersonalAccessTokenCommand, logger))
+	mux.Handle("DELETE /auth/personal-access-tokens/{tokenId}", NewRevokePersonalAccessTokenHandler(revokePersonalAccessTokenCommand, logger))
+	mux.Handle("GET /auth/oidc/providers", NewListOIDCProvidersHandler(oidcProviders))
+	mux.Handle("GET /auth/oidc/{provider}/login", NewOIDCAuthorizeHandler(oidcProviders, shared.OIDCModeLogin, options.JWTSecret, logger))
+	mux.Handle("GET /auth/oidc/{provider}/link", NewOIDCAuthorizeHandler(oidcProviders, shared.OIDCModeLink, options.JWTSecret, logger))
+	mux.Handle("GET /auth/oidc/{provider}/callback", NewOIDCCallbackHandler(oidcProviders, oidcLoginCommand, linkIdentityCommand, twoFactorChallengeCommand, generateTokensCommand, options.JWTSecret, cfg.CORSClientBaseURL, emailSender, cfg.TrustProxy, logger))
+	mux.Handle("GET /auth/identities", NewListIdentitiesHandler(listIdentitiesCommand, logger))
+	mux.Handle("DELETE /auth/identities/{identityId}", NewUnlinkIdentityHandler(unlinkIdentityCommand, logger))
+	mux.Handle("GET /auth/audit", NewListAuditEventsHandler(listAuditEventsCommand, logger))
 	mux.Handle("POST /auth/password/initialize-update", NewInitializeUpdatePasswordHandler(initializeUpdatePasswordCommand, emailSender, logger))
-	mux.Handle("PUT /auth/password", NewUpdatePasswordHandler(updatePasswordCommand, logger))
+	mux.Handle("PUT /auth/password", NewUpdatePasswordHandler(updatePasswordCommand, cfg.TrustProxy, logger))
 	mux.Handle("POST /auth/password/initialize-reset", NewInitializePasswordResetHandler(initializePasswordResetCommand, userReaderService, emailSender, logger))
-	mux.Handle("PUT /auth/password/reset", NewResetPasswordHandler(resetPasswordCommand, logger))
+	mux.Handle("PUT /auth/password/reset", NewResetPasswordHandler(resetPasswordCommand, cfg.TrustProxy, logger))
 	mux.Handle("POST /auth/email/initialize-update", NewInitializeEmailUpdateHandler(initializeUpdateEmailCommand, userReaderService, emailSender, logger))
-	mux.Handle("PUT /auth/email", NewUpdateEmailHandler(updateEmailCommand, generateTokensCommand, logger))
+	mux.Handle("PUT /auth/email", NewUpdateEmailHandler(updateEmailCommand, generateTokensCommand, emailSender, cfg.CORSClientBaseURL, cfg.TrustProxy, logger))
+	mux.Handle("POST /auth/email/revert", NewRevertEmailHandler(revertEmailCommand, cfg.TrustProxy, logger))
+	mux.Handle("POST /auth/email/verify", NewVerifyEmailHandler(verifyEmailCommand, logger))
+	mux.Handle("GET /auth/2fa", NewTwoFactorStatusHandler(twoFactorStatusCommand, logger))
+	mux.Handle("POST /auth/2fa/enroll", NewEnrollTwoFactorHandler(enrollTwoFactorCommand, logger))
+	mux.Handle("POST /auth/2fa/verify", NewEnableTwoFactorHandler(enableTwoFactorCommand, cfg.TrustProxy, logger))
+	mux.Handle("DELETE /auth/2fa", NewDisableTwoFactorHandler(comparePasswordCommand, disableTwoFactorCommand, cfg.TrustProxy, logger))
+	mux.Handle("POST /auth/email/verify/resend", NewInitializeEmailVerificationHandler(initializeEmailVerificationCommand, userReaderService, emailSender, cfg.CORSClientBaseURL, logger))
 }
@@ -2,10 +2,12 @@ package handler
 
 import (
 	"beerbux/internal/auth/command"
+	"beerbux/internal/auth/shared"
+	"beerbux/pkg/email"
+	"beerbux/pkg/i18n"
 	"beerbux/pkg/send"
 	"encoding/json"
 	"errors"
-	"fmt"
 	oz "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/go-ozzo/ozzo-validation/v4/is"
 	"log/slog"
@@ -15,14 +17,29 @@ import (
 var ErrPasswordsDoNotMatch = errors.New("passwords do not match")
 
 type SignupHandler struct {
-	signupCommand *command.SignupCommand
-	logger        *slog.Logger
+	signupCommand                      *command.SignupCommand
+	initializeEmailVerificationCommand *command.InitializeEmailVerificationCommand
+	emailSender                        email.Sender
+	clientBaseURL                      string
+	trustProxy                         bool
+	logger                             *slog.Logger
 }
 
-func NewSignupHandler(signupCommand *command.SignupCommand, logger *slog.Logger) *SignupHandler {
+func NewSignupHandler(
+	signupCommand *command.SignupCommand,
+	initializeEmailVerificationCommand *command.InitializeEmailVerificationCommand,
+	emailSender email.Sender,
+	clientBaseURL string,
+	trustProxy bool,
+	logger *slog.Logger,
+) *SignupHandler {
 	return &SignupHandler{
-		signupCommand: signupCommand,
-		logger:        logger,
+		signupCommand:                      signupCommand,
+		initializeEmailVerificationCommand: initializeEmailVerificationCommand,
+		emailSender:                        emailSender,
+		clientBaseURL:                      clientBaseURL,
+		trustProxy:                         trustProxy,
+		logger:                             logger,
 	}
 }
 
@@ -47,22 +64,34 @@ func (h *SignupHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	_, err := h.signupCommand.Execute(r.Context(), req.Name, req.Username, req.Email, req.Password, req.VerificationPassword)
+	result, err := h.signupCommand.Execute(r.Context(), req.Name, req.Username, req.Email, req.Password, req.VerificationPassword, shared.NewDeviceInfo(r, h.trustProxy))
 	if err != nil {
-		h.handleSignupError(w, req, err)
+		h.handleSignupError(w, r, req, err)
 		return
 	}
 
+	// The account has been created at this point, so a failure here should not fail the signup.
+	// The user can request another verification email via the resend endpoint.
+	verification, err := h.initializeEmailVerificationCommand.Execute(r.Context(), result.User.ID)
+	if err != nil {
+		h.logger.Error("failed to initialize email verification", "user", result.User.ID, "error", err)
+	} else {
+		sendVerifyEmailAddressEmail(h.emailSender, h.logger, h.clientBaseURL, i18n.FromContext(r.Context()), result.User.Email, result.User.Username, verification.OTP)
+	}
+
 	w.WriteHeader(http.StatusCreated)
 }
 
-func (h *SignupHandler) handleSignupError(w http.ResponseWriter, req SignupRequest, err error) {
+func (h *SignupHandler) handleSignupError(w http.ResponseWriter, r *http.Request, req SignupRequest, err error) {
+	if sendPasswordPolicyViolation(w, r, err) {
+		return
+	}
 	if errors.Is(err, command.ErrPasswordMismatch) {
 		send.Error(w, "The provided passwords do not match", http.StatusBadRequest)
 		return
 	}
 	if errors.Is(err, command.ErrUsernameTaken) {
-		send.Error(w, fmt.Sprintf("Username %s already taken", req.Username), http.StatusBadRequest)
+		send.Error(w, i18n.Sprintf(i18n.FromContext(r.Context()), "Username %s already taken", req.Username), http.StatusBadRequest)
 		return
 	}
 
@@ -82,8 +111,7 @@ func (r SignupRequest) Validate() error {
 			oz.Required.Error("Email address is required"),
 			is.Email.Error("The provided email is not a valid email address")),
 		oz.Field(&r.Password,
-			oz.Required.Error("Password is required"),
-			oz.Length(8, 0).Error("Password must be at least 8 characters")),
+			oz.Required.Error("Password is required")),
 		oz.Field(&r.VerificationPassword,
 			oz.Required.Error("Verification password is required")),
 		oz.Field(&r.Password,
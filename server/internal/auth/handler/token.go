@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"beerbux/internal/auth/command"
+	"beerbux/pkg/email"
+	"beerbux/pkg/send"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// TokenHandler logs the user in and returns the tokens in the response body rather
+// than in cookies, for clients that send the access token in an Authorization header.
+type TokenHandler struct {
+	login          loginFlow
+	accessTokenTTL time.Duration
+}
+
+func NewTokenHandler(
+	generateTokensCommand *command.GenerateTokensCommand,
+	comparePasswordCommand *command.ComparePasswordCommand,
+	twoFactorChallengeCommand *command.TwoFactorChallengeCommand,
+	accessTokenTTL time.Duration,
+	emailSender email.Sender,
+	clientBaseURL string,
+	trustProxy bool,
+	logger *slog.Logger,
+) *TokenHandler {
+	return &TokenHandler{
+		login: loginFlow{
+			generateTokensCommand:     generateTokensCommand,
+			comparePasswordCommand:    comparePasswordCommand,
+			twoFactorChallengeCommand: twoFactorChallengeCommand,
+			emailSender:               emailSender,
+			clientBaseURL:             clientBaseURL,
+			trustProxy:                trustProxy,
+			logger:                    logger,
+		},
+		accessTokenTTL: accessTokenTTL,
+	}
+}
+
+const bearerTokenType = "Bearer"
+
+type BearerTokenResponse struct {
+	AccessToken  string         `json:"accessToken"`
+	RefreshToken string         `json:"refreshToken"`
+	TokenType    string         `json:"tokenType"`
+	ExpiresIn    int64          `json:"expiresIn"`
+	User         *LoginResponse `json:"user,omitempty"`
+}
+
+func newBearerTokenResponse(accessToken, refreshToken string, accessTokenTTL time.Duration) BearerTokenResponse {
+	return BearerTokenResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		TokenType:    bearerTokenType,
+		ExpiresIn:    int64(accessTokenTTL.Seconds()),
+	}
+}
+
+func newBearerTokenResponseWithUser(tokens *command.TokensResponse, accessTokenTTL time.Duration) BearerTokenResponse {
+	resp := newBearerTokenResponse(tokens.AccessToken, tokens.RefreshToken, accessTokenTTL)
+	resp.User = &LoginResponse{
+		ID:            tokens.User.ID,
+		Name:          tokens.User.Name,
+		Username:      tokens.User.Username,
+		EmailVerified: tokens.User.EmailVerified,
+	}
+	return resp
+}
+
+func (h *TokenHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.login.password(w, r, writeBearerTokens(h.accessTokenTTL))
+}
+
+// writeBearerTokens returns a tokenWriter that returns the tokens and the user that logged in.
+func writeBearerTokens(accessTokenTTL time.Duration) tokenWriter {
+	return func(w http.ResponseWriter, tokens *command.TokensResponse) {
+		send.JSON(w, newBearerTokenResponseWithUser(tokens, accessTokenTTL), http.StatusOK)
+	}
+}
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"beerbux/internal/auth/command"
+	"beerbux/internal/auth/shared"
+	"beerbux/pkg/send"
+	"encoding/json"
+	"errors"
+	oz "github.com/go-ozzo/ozzo-validation/v4"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+type TokenRefreshHandler struct {
+	refreshTokenCommand *command.RefreshTokenCommand
+	accessTokenTTL      time.Duration
+	trustProxy          bool
+	logger              *slog.Logger
+}
+
+func NewTokenRefreshHandler(refreshTokenCommand *command.RefreshTokenCommand, accessTokenTTL time.Duration, trustProxy bool, logger *slog.Logger) *TokenRefreshHandler {
+	return &TokenRefreshHandler{
+		refreshTokenCommand: refreshTokenCommand,
+		accessTokenTTL:      accessTokenTTL,
+		trustProxy:          trustProxy,
+		logger:              logger,
+	}
+}
+
+type TokenRefreshRequest struct {
+	RefreshToken string `json:"refreshToken"`
+}
+
+func (h *TokenRefreshHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req TokenRefreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		send.BadRequest(w, "Failed to decode request")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		send.ValidationError(w, err)
+		return
+	}
+
+	resp, err := h.refreshTokenCommand.Execute(r.Context(), req.RefreshToken, shared.NewDeviceInfo(r, h.trustProxy))
+	if err != nil {
+		switch {
+		case errors.Is(err, command.ErrRefreshTokenNotFound):
+			send.Unauthorized(w, "Your login has expired, please log in again")
+		case errors.Is(err, command.ErrRefreshTokenReused):
+			h.logger.Warn("refresh token reused, revoked token family")
+			send.Unauthorized(w, "Your login has expired, please log in again")
+		default:
+			h.logger.Error("failed to refresh token", "error", err)
+			send.InternalServerError(w, "There was an issue refreshing your login")
+		}
+		return
+	}
+
+	send.JSON(w, newBearerTokenResponse(resp.AccessToken, resp.RefreshToken, h.accessTokenTTL), http.StatusOK)
+}
+
+func (r TokenRefreshRequest) Validate() error {
+	return oz.ValidateStruct(&r,
+		oz.Field(&r.RefreshToken, oz.Required),
+	)
+}
@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"beerbux/internal/auth/command"
+	"beerbux/pkg/send"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+)
+
+type TokenRevokeHandler struct {
+	invalidateRefreshTokenCommand *command.InvalidateRefreshTokenCommand
+	logger                        *slog.Logger
+}
+
+func NewTokenRevokeHandler(invalidateRefreshTokenCommand *command.InvalidateRefreshTokenCommand, logger *slog.Logger) *TokenRevokeHandler {
+	return &TokenRevokeHandler{
+		invalidateRefreshTokenCommand: invalidateRefreshTokenCommand,
+		logger:                        logger,
+	}
+}
+
+// ServeHTTP revokes the refresh token, logging the client out. Unknown tokens are
+// ignored so that the response does not reveal whether a token was valid.
+func (h *TokenRevokeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req TokenRefreshRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		send.BadRequest(w, "Failed to decode request")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		send.ValidationError(w, err)
+		return
+	}
+
+	if err := h.invalidateRefreshTokenCommand.Execute(r.Context(), req.RefreshToken); err != nil {
+		h.logger.Error("failed to revoke refresh token", "error", err)
+		send.InternalServerError(w, "There was an issue logging you out")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
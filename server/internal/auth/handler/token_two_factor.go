@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"beerbux/internal/auth/command"
+	"beerbux/pkg/email"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+type TokenTwoFactorHandler struct {
+	login          loginFlow
+	accessTokenTTL time.Duration
+}
+
+func NewTokenTwoFactorHandler(
+	verifyTwoFactorCodeCommand *command.VerifyTwoFactorCodeCommand,
+	generateTokensCommand *command.GenerateTokensCommand,
+	secret string,
+	accessTokenTTL time.Duration,
+	emailSender email.Sender,
+	clientBaseURL string,
+	trustProxy bool,
+	logger *slog.Logger,
+) *TokenTwoFactorHandler {
+	return &TokenTwoFactorHandler{
+		login: loginFlow{
+			verifyTwoFactorCodeCommand: verifyTwoFactorCodeCommand,
+			generateTokensCommand:      generateTokensCommand,
+			secret:                     secret,
+			emailSender:                emailSender,
+			clientBaseURL:              clientBaseURL,
+			trustProxy:                 trustProxy,
+			logger:                     logger,
+		},
+		accessTokenTTL: accessTokenTTL,
+	}
+}
+
+func (h *TokenTwoFactorHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.login.twoFactor(w, r, writeBearerTokens(h.accessTokenTTL))
+}
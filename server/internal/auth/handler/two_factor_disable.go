@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"beerbux/internal/auth/command"
+	"beerbux/internal/auth/shared"
+	"beerbux/internal/common/claims"
+	"beerbux/pkg/send"
+	"encoding/json"
+	"errors"
+	oz "github.com/go-ozzo/ozzo-validation/v4"
+	"log/slog"
+	"net/http"
+)
+
+type DisableTwoFactorHandler struct {
+	comparePasswordCommand  *command.ComparePasswordCommand
+	disableTwoFactorCommand *command.DisableTwoFactorCommand
+	trustProxy              bool
+	logger                  *slog.Logger
+}
+
+func NewDisableTwoFactorHandler(
+	comparePasswordCommand *command.ComparePasswordCommand,
+	disableTwoFactorCommand *command.DisableTwoFactorCommand,
+	trustProxy bool,
+	logger *slog.Logger,
+) *DisableTwoFactorHandler {
+	return &DisableTwoFactorHandler{
+		comparePasswordCommand:  comparePasswordCommand,
+		disableTwoFactorCommand: disableTwoFactorCommand,
+		trustProxy:              trustProxy,
+		logger:                  logger,
+	}
+}
+
+type DisableTwoFactorRequest struct {
+	Password string `json:"password"`
+}
+
+func (h *DisableTwoFactorHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c := claims.GetClaims(r)
+	if !c.Authenticated() {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var req DisableTwoFactorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		send.BadRequest(w, "Failed to decode request body")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		send.ValidationError(w, err)
+		return
+	}
+
+	if err := h.comparePasswordCommand.Execute(r.Context(), c.Username, req.Password); err != nil {
+		if errors.Is(err, command.ErrPasswordMismatch) || errors.Is(err, command.ErrUserNotFound) {
+			send.Unauthorized(w, "The provided password is incorrect")
+			return
+		}
+		h.logger.Error("failed when comparing password", "error", err)
+		send.InternalServerError(w, "There has been an issue disabling two-factor authentication")
+		return
+	}
+
+	if err := h.disableTwoFactorCommand.Execute(r.Context(), c.Subject, shared.NewDeviceInfo(r, h.trustProxy)); err != nil {
+		h.logger.Error("failed to disable two-factor authentication", "error", err)
+		send.InternalServerError(w, "There has been an issue disabling two-factor authentication")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (r DisableTwoFactorRequest) Validate() error {
+	return oz.ValidateStruct(&r,
+		oz.Field(&r.Password, oz.Required.Error("Password is required")),
+	)
+}
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"beerbux/internal/auth/command"
+	"beerbux/internal/auth/shared"
+	"beerbux/internal/common/claims"
+	"beerbux/pkg/send"
+	"encoding/json"
+	"errors"
+	oz "github.com/go-ozzo/ozzo-validation/v4"
+	"log/slog"
+	"net/http"
+)
+
+type EnableTwoFactorHandler struct {
+	enableTwoFactorCommand *command.EnableTwoFactorCommand
+	trustProxy             bool
+	logger                 *slog.Logger
+}
+
+func NewEnableTwoFactorHandler(enableTwoFactorCommand *command.EnableTwoFactorCommand, trustProxy bool, logger *slog.Logger) *EnableTwoFactorHandler {
+	return &EnableTwoFactorHandler{
+		enableTwoFactorCommand: enableTwoFactorCommand,
+		trustProxy:             trustProxy,
+		logger:                 logger,
+	}
+}
+
+type EnableTwoFactorRequest struct {
+	Code string `json:"code"`
+}
+
+func (h *EnableTwoFactorHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c := claims.GetClaims(r)
+	if !c.Authenticated() {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var req EnableTwoFactorRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		send.BadRequest(w, "Failed to decode request body")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		send.ValidationError(w, err)
+		return
+	}
+
+	result, err := h.enableTwoFactorCommand.Execute(r.Context(), c.Subject, req.Code, shared.NewDeviceInfo(r, h.trustProxy))
+	if err != nil {
+		h.handleEnableTwoFactorError(w, err)
+		return
+	}
+
+	send.JSON(w, result, http.StatusOK)
+}
+
+func (h *EnableTwoFactorHandler) handleEnableTwoFactorError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, command.ErrTwoFactorNotEnrolled):
+		send.BadRequest(w, "Two-factor authentication has not been set up")
+	case errors.Is(err, command.ErrTwoFactorAlreadyEnabled):
+		send.BadRequest(w, "Two-factor authentication is already enabled")
+	case errors.Is(err, command.ErrIncorrectTwoFactorCode):
+		send.BadRequest(w, "The provided code is incorrect")
+	default:
+		h.logger.Error("failed to enable two-factor authentication", "error", err)
+		send.InternalServerError(w, "There has been an issue enabling two-factor authentication")
+	}
+}
+
+func (r EnableTwoFactorRequest) Validate() error {
+	return oz.ValidateStruct(&r,
+		oz.Field(&r.Code, oz.Required.Error("Code is required")),
+	)
+}
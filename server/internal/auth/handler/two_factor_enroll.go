@@ -0,0 +1,44 @@
+package handler
+
+import (
+	"beerbux/internal/auth/command"
+	"beerbux/internal/common/claims"
+	"beerbux/pkg/send"
+	"errors"
+	"log/slog"
+	"net/http"
+)
+
+type EnrollTwoFactorHandler struct {
+	enrollTwoFactorCommand *command.EnrollTwoFactorCommand
+	logger                 *slog.Logger
+}
+
+func NewEnrollTwoFactorHandler(enrollTwoFactorCommand *command.EnrollTwoFactorCommand, logger *slog.Logger) *EnrollTwoFactorHandler {
+	return &EnrollTwoFactorHandler{
+		enrollTwoFactorCommand: enrollTwoFactorCommand,
+		logger:                 logger,
+	}
+}
+
+// ServeHTTP returns the secret and the otpauth provisioning URI for the client to display as a QR code.
+func (h *EnrollTwoFactorHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c := claims.GetClaims(r)
+	if !c.Authenticated() {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	result, err := h.enrollTwoFactorCommand.Execute(r.Context(), c.Subject, c.Username)
+	if err != nil {
+		if errors.Is(err, command.ErrTwoFactorAlreadyEnabled) {
+			send.BadRequest(w, "Two-factor authentication is already enabled")
+			return
+		}
+		h.logger.Error("failed to enroll two-factor authentication", "error", err)
+		send.InternalServerError(w, "There has been an issue setting up two-factor authentication")
+		return
+	}
+
+	send.JSON(w, result, http.StatusOK)
+}
@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"beerbux/internal/auth/command"
+	"beerbux/internal/common/claims"
+	"beerbux/pkg/send"
+	"log/slog"
+	"net/http"
+)
+
+type TwoFactorStatusHandler struct {
+	twoFactorStatusCommand *command.TwoFactorStatusCommand
+	logger                 *slog.Logger
+}
+
+func NewTwoFactorStatusHandler(twoFactorStatusCommand *command.TwoFactorStatusCommand, logger *slog.Logger) *TwoFactorStatusHandler {
+	return &TwoFactorStatusHandler{
+		twoFactorStatusCommand: twoFactorStatusCommand,
+		logger:                 logger,
+	}
+}
+
+type TwoFactorStatusResponse struct {
+	Enabled                bool `json:"enabled"`
+	RemainingRecoveryCodes int  `json:"remainingRecoveryCodes"`
+}
+
+func (h *TwoFactorStatusHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c := claims.GetClaims(r)
+	if !c.Authenticated() {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	status, err := h.twoFactorStatusCommand.Execute(r.Context(), c.Subject)
+	if err != nil {
+		h.logger.Error("failed to get two-factor status", "error", err)
+		send.InternalServerError(w, "There has been an issue fetching your two-factor authentication settings")
+		return
+	}
+
+	send.JSON(w, TwoFactorStatusResponse{
+		Enabled:                status.Enabled,
+		RemainingRecoveryCodes: status.RemainingRecoveryCodes,
+	}, http.StatusOK)
+}
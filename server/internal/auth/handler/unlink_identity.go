@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"beerbux/internal/auth/command"
+	"beerbux/internal/common/claims"
+	"beerbux/pkg/send"
+	"errors"
+	"github.com/google/uuid"
+	"log/slog"
+	"net/http"
+)
+
+type UnlinkIdentityHandler struct {
+	unlinkIdentityCommand *command.UnlinkIdentityCommand
+	logger                *slog.Logger
+}
+
+func NewUnlinkIdentityHandler(unlinkIdentityCommand *command.UnlinkIdentityCommand, logger *slog.Logger) *UnlinkIdentityHandler {
+	return &UnlinkIdentityHandler{
+		unlinkIdentityCommand: unlinkIdentityCommand,
+		logger:                logger,
+	}
+}
+
+func (h *UnlinkIdentityHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c := claims.GetClaims(r)
+	if !c.Authenticated() {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	identityID, err := uuid.Parse(r.PathValue("identityId"))
+	if err != nil {
+		send.BadRequest(w, "Invalid identity ID")
+		return
+	}
+
+	if err := h.unlinkIdentityCommand.Execute(r.Context(), c.Subject, identityID); err != nil {
+		switch {
+		case errors.Is(err, command.ErrIdentityNotFound):
+			send.NotFound(w, "Linked account not found")
+		case errors.Is(err, command.ErrLastIdentity):
+			send.BadRequest(w, "Verify your email address before unlinking your only linked account")
+		default:
+			h.logger.Error("failed to unlink identity", "user", c.Subject, "identity", identityID, "error", err)
+			send.InternalServerError(w, "There has been an issue unlinking the account")
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
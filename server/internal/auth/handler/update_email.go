@@ -3,28 +3,43 @@ package handler
 import (
 	"beerbux/internal/auth/command"
 	"beerbux/internal/auth/cookie"
+	"beerbux/internal/auth/shared"
 	"beerbux/internal/common/claims"
+	"beerbux/pkg/email"
+	"beerbux/pkg/i18n"
 	"beerbux/pkg/send"
 	"encoding/json"
 	"errors"
 	"log/slog"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 )
 
 type UpdateEmailHandler struct {
 	updateEmailCommand    *command.UpdateEmailCommand
 	generateTokensCommand *command.GenerateTokensCommand
+	emailSender           email.Sender
+	clientBaseURL         string
+	trustProxy            bool
 	logger                *slog.Logger
 }
 
 func NewUpdateEmailHandler(
 	updateEmailCommand *command.UpdateEmailCommand,
 	generateTokensCommand *command.GenerateTokensCommand,
+	emailSender email.Sender,
+	clientBaseURL string,
+	trustProxy bool,
 	logger *slog.Logger,
 ) *UpdateEmailHandler {
 	return &UpdateEmailHandler{
 		updateEmailCommand:    updateEmailCommand,
 		generateTokensCommand: generateTokensCommand,
+		emailSender:           emailSender,
+		clientBaseURL:         clientBaseURL,
+		trustProxy:            trustProxy,
 		logger:                logger,
 	}
 }
@@ -46,12 +61,16 @@ func (h *UpdateEmailHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := h.updateEmailCommand.Execute(r.Context(), c.Subject, req.OTP); err != nil {
+	device := shared.NewDeviceInfo(r, h.trustProxy)
+	result, err := h.updateEmailCommand.Execute(r.Context(), c.Subject, req.OTP, device)
+	if err != nil {
 		h.handleUpdateEmailError(w, err)
 		return
 	}
 
-	tokens, err := h.generateTokensCommand.Execute(r.Context(), c.Username)
+	h.sendEmailChangedEmail(i18n.FromContext(r.Context()), c.Username, result)
+
+	tokens, err := h.generateTokensCommand.Execute(r.Context(), c.Username, command.LoginMethodReissue, device)
 	if err != nil {
 		send.InternalServerError(w, "There has been an issue re-authenticating you following updating your email address. Please try logging out and back in.")
 		return
@@ -68,6 +87,8 @@ func (h *UpdateEmailHandler) handleUpdateEmailError(w http.ResponseWriter, err e
 		send.BadRequest(w, "An update for your email address was not requested")
 	case errors.Is(err, command.ErrUserNotFound):
 		send.NotFound(w, "User not found")
+	case errors.Is(err, command.ErrTooManyOTPAttempts):
+		send.TooManyRequests(w, "Too many incorrect attempts, please wait before requesting a new OTP")
 	case errors.Is(err, command.ErrIncorrectOTP):
 		send.BadRequest(w, "The provided OTP is incorrect")
 	case errors.Is(err, command.ErrOTPExpired):
@@ -77,3 +98,21 @@ func (h *UpdateEmailHandler) handleUpdateEmailError(w http.ResponseWriter, err e
 		send.InternalServerError(w, "There has been an issue updating your email address")
 	}
 }
+
+// sendEmailChangedEmail tells the previous email address about the change, with a link to revert it.
+func (h *UpdateEmailHandler) sendEmailChangedEmail(locale, username string, result *command.UpdateEmailResponse) {
+	// The token goes in the fragment, which is read by the client and never sent to a server.
+	revertURL := strings.TrimRight(h.clientBaseURL, "/") + "/revert-email#" + url.Values{
+		"token": {result.RevertToken},
+	}.Encode()
+
+	tmpl := email.EmailChangedEmail(locale, email.EmailChangedEmailData{
+		Username:        username,
+		NewEmail:        result.NewEmail,
+		RevertURL:       revertURL,
+		ExpirationHours: strconv.FormatInt(int64(command.EmailRevertTimeToLiveHours), 10),
+	})
+	if _, err := email.SendTemplate(h.emailSender, result.PreviousEmail, i18n.Translate(locale, "Your email address was changed"), tmpl); err != nil {
+		h.logger.Error("failed to send email", "error", err)
+	}
+}
@@ -5,8 +5,10 @@ import (
 	"beerbux/internal/common/claims"
 	"beerbux/internal/common/useraccess"
 	"beerbux/pkg/email"
+	"beerbux/pkg/i18n"
 	"beerbux/pkg/send"
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"net/http"
 	"strconv"
@@ -68,27 +70,27 @@ func (h *InitializeEmailUpdateHandler) ServeHTTP(w http.ResponseWriter, r *http.
 
 	result, err := h.initializeUpdateEmailCommand.Execute(r.Context(), c.Subject, req.NewEmail)
 	if err != nil {
+		if errors.Is(err, command.ErrOTPLocked) {
+			send.TooManyRequests(w, "Too many incorrect attempts, please try again later")
+			return
+		}
 		h.logger.Error("failed to execute initialize update email command", "error", err)
 		send.InternalServerError(w, "There has been an issue updating your email address")
 		return
 	}
 
-	h.sendUpdateEmailAddressEmail(c, req.NewEmail, result.OTP)
+	h.sendUpdateEmailAddressEmail(i18n.FromContext(r.Context()), c, req.NewEmail, result.OTP)
 	w.WriteHeader(http.StatusOK)
 }
 
-func (h *InitializeEmailUpdateHandler) sendUpdateEmailAddressEmail(c claims.JWTClaims, newEmail, otp string) {
-	html, err := email.GenerateUpdateEmailAddressEmail(email.UpdateEmailAddressData{
+func (h *InitializeEmailUpdateHandler) sendUpdateEmailAddressEmail(locale string, c claims.JWTClaims, newEmail, otp string) {
+	tmpl := email.UpdateEmailAddressEmail(locale, email.UpdateEmailAddressData{
 		Username:          c.Username,
 		NewEmail:          newEmail,
 		OTP:               otp,
 		ExpirationMinutes: strconv.FormatInt(int64(command.OTPTimeToLiveMinutes), 10),
 	})
-	if err != nil {
-		h.logger.Error("failed to generate update email address email template", "error", err)
-		return
-	}
-	if _, err := h.emailSender.Send(c.Email, "Update email address", html); err != nil {
+	if _, err := email.SendTemplate(h.emailSender, newEmail, i18n.Translate(locale, "Update email address"), tmpl); err != nil {
 		h.logger.Error("failed to send email", "error", err)
 	}
 }
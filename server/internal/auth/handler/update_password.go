@@ -2,6 +2,7 @@ package handler
 
 import (
 	"beerbux/internal/auth/command"
+	"beerbux/internal/auth/shared"
 	"beerbux/internal/common/claims"
 	"beerbux/pkg/send"
 	"encoding/json"
@@ -12,12 +13,14 @@ import (
 
 type UpdatePasswordHandler struct {
 	updatePasswordCommand *command.UpdatePasswordCommand
+	trustProxy            bool
 	logger                *slog.Logger
 }
 
-func NewUpdatePasswordHandler(updatePasswordCommand *command.UpdatePasswordCommand, logger *slog.Logger) *UpdatePasswordHandler {
+func NewUpdatePasswordHandler(updatePasswordCommand *command.UpdatePasswordCommand, trustProxy bool, logger *slog.Logger) *UpdatePasswordHandler {
 	return &UpdatePasswordHandler{
 		updatePasswordCommand: updatePasswordCommand,
+		trustProxy:            trustProxy,
 		logger:                logger,
 	}
 }
@@ -39,7 +42,7 @@ func (h *UpdatePasswordHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 		return
 	}
 
-	if err := h.updatePasswordCommand.Execute(r.Context(), c.Subject, req.OTP); err != nil {
+	if err := h.updatePasswordCommand.Execute(r.Context(), c.Subject, req.OTP, shared.NewDeviceInfo(r, h.trustProxy)); err != nil {
 		h.handleUpdatePasswordError(w, err)
 		return
 	}
@@ -51,6 +54,8 @@ func (h *UpdatePasswordHandler) handleUpdatePasswordError(w http.ResponseWriter,
 	switch {
 	case errors.Is(err, command.ErrUserNotFound):
 		send.NotFound(w, "User not found")
+	case errors.Is(err, command.ErrTooManyOTPAttempts):
+		send.TooManyRequests(w, "Too many incorrect attempts, please wait before requesting a new OTP")
 	case errors.Is(err, command.ErrIncorrectOTP):
 		send.BadRequest(w, "The provided OTP is incorrect")
 	case errors.Is(err, command.ErrOTPExpired):
@@ -4,8 +4,10 @@ import (
 	"beerbux/internal/auth/command"
 	"beerbux/internal/common/claims"
 	"beerbux/pkg/email"
+	"beerbux/pkg/i18n"
 	"beerbux/pkg/send"
 	"encoding/json"
+	"errors"
 	"log/slog"
 	"net/http"
 	"strconv"
@@ -48,25 +50,28 @@ func (h *InitializePasswordUpdateHandler) ServeHTTP(w http.ResponseWriter, r *ht
 
 	result, err := h.initializeUpdatePasswordCommand.Execute(r.Context(), c.Subject, req.NewPassword)
 	if err != nil {
+		if sendPasswordPolicyViolation(w, r, err) {
+			return
+		}
+		if errors.Is(err, command.ErrOTPLocked) {
+			send.TooManyRequests(w, "Too many incorrect attempts, please try again later")
+			return
+		}
 		send.InternalServerError(w, "There has been an issue updating your password")
 		return
 	}
 
-	h.sendUpdatePasswordEmail(c, result.OTP)
+	h.sendUpdatePasswordEmail(i18n.FromContext(r.Context()), c, result.OTP)
 	w.WriteHeader(http.StatusOK)
 }
 
-func (h *InitializePasswordUpdateHandler) sendUpdatePasswordEmail(c claims.JWTClaims, otp string) {
-	html, err := email.GenerateUpdatePasswordEmail(email.UpdatePasswordEmailData{
+func (h *InitializePasswordUpdateHandler) sendUpdatePasswordEmail(locale string, c claims.JWTClaims, otp string) {
+	tmpl := email.UpdatePasswordEmail(locale, email.UpdatePasswordEmailData{
 		Username:          c.Username,
 		OTP:               otp,
 		ExpirationMinutes: strconv.FormatInt(int64(command.OTPTimeToLiveMinutes), 10),
 	})
-	if err != nil {
-		h.logger.Error("failed to generate update password email template", "error", err)
-		return
-	}
-	if _, err := h.emailSender.Send(c.Email, "Password update request", html); err != nil {
+	if _, err := email.SendTemplate(h.emailSender, c.Email, i18n.Translate(locale, "Password update request"), tmpl); err != nil {
 		h.logger.Error("failed to send email", "error", err)
 	}
 }
@@ -1,11 +1,20 @@
 package handler
 
-import "errors"
+import (
+	"beerbux/pkg/i18n"
+	"beerbux/pkg/password"
+	"beerbux/pkg/send"
+	"errors"
+	"net/http"
+)
 
-// validatePassword validates the given password and returns an error if validation fails.
-func validatePassword(p string) error {
-	if len(p) < 8 {
-		return errors.New("password must be at least 8 characters long")
+// sendPasswordPolicyViolation responds with the reason the password was rejected by the
+// password policy and reports whether the error was a policy violation.
+func sendPasswordPolicyViolation(w http.ResponseWriter, r *http.Request, err error) bool {
+	var violation *password.PolicyViolation
+	if !errors.As(err, &violation) {
+		return false
 	}
-	return nil
+	send.BadRequest(w, i18n.Sprintf(i18n.FromContext(r.Context()), violation.Format, violation.Args...))
+	return true
 }
@@ -0,0 +1,77 @@
+// Package scope defines the scopes that can be granted to personal access tokens
+// and the routes each scope gives access to.
+package scope
+
+import (
+	"slices"
+)
+
+const (
+	SessionsRead       = "sessions:read"
+	SessionsWrite      = "sessions:write"
+	TransactionsWrite  = "transactions:write"
+	UserRead           = "user:read"
+	FriendsRead        = "friends:read"
+	NotificationsRead  = "notifications:read"
+	NotificationsWrite = "notifications:write"
+	WebhooksRead       = "webhooks:read"
+	WebhooksWrite      = "webhooks:write"
+)
+
+// All returns every scope that can be granted to a personal access token.
+func All() []string {
+	return []string{
+		SessionsRead,
+		SessionsWrite,
+		TransactionsWrite,
+		UserRead,
+		FriendsRead,
+		NotificationsRead,
+		NotificationsWrite,
+		WebhooksRead,
+		WebhooksWrite,
+	}
+}
+
+// Valid reports whether the scope can be granted to a personal access token.
+func Valid(s string) bool {
+	return slices.Contains(All(), s)
+}
+
+// DefaultRoutes maps route patterns, as registered on the http.ServeMux, to the scope a
+// personal access token requires to call them. Routes that are not listed, such as the
+// authentication routes, cannot be called with a personal access token.
+func DefaultRoutes() map[string]string {
+	return map[string]string{
+		"GET /user":                                         UserRead,
+		"GET /user/{userId}/balance":                        UserRead,
+		"GET /user/sessions":                                SessionsRead,
+		"GET /session/{sessionId}":                          SessionsRead,
+		"GET /session/{sessionId}/history":                  SessionsRead,
+		"GET /events/session":                               SessionsRead,
+		"POST /session":                                     SessionsWrite,
+		"POST /session/{sessionId}/member":                  SessionsWrite,
+		"POST /session/{sessionId}/member/{memberId}/admin": SessionsWrite,
+		"DELETE /session/{sessionId}/member/{memberId}":     SessionsWrite,
+		"DELETE /session/{sessionId}/leave":                 SessionsWrite,
+		"PUT /session/{sessionId}/state/{command}":          SessionsWrite,
+		"POST /session/{sessionId}/transaction":             TransactionsWrite,
+		"GET /friends":                                      FriendsRead,
+		"GET /friend/{friendId}":                            FriendsRead,
+		"GET /friend/{friendId}/sessions":                   FriendsRead,
+		"GET /notifications":                                NotificationsRead,
+		"GET /notifications/unread-count":                   NotificationsRead,
+		"GET /events/notifications":                         NotificationsRead,
+		"POST /notifications/{notificationId}/read":         NotificationsWrite,
+		"POST /notifications/read-all":                      NotificationsWrite,
+
+		"GET /session/{sessionId}/webhooks":                            WebhooksRead,
+		"GET /user/webhooks":                                           WebhooksRead,
+		"GET /webhooks/{webhookId}/deliveries":                         WebhooksRead,
+		"POST /session/{sessionId}/webhooks":                           WebhooksWrite,
+		"POST /user/webhooks":                                          WebhooksWrite,
+		"DELETE /webhooks/{webhookId}":                                 WebhooksWrite,
+		"POST /webhooks/{webhookId}/enable":                            WebhooksWrite,
+		"POST /webhooks/{webhookId}/deliveries/{deliveryId}/redeliver": WebhooksWrite,
+	}
+}
@@ -0,0 +1,30 @@
+package scope
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestDefaultRoutes(t *testing.T) {
+	// Registering the patterns panics if any of them is invalid or conflicts with another.
+	mux := http.NewServeMux()
+	for pattern, s := range DefaultRoutes() {
+		mux.Handle(pattern, http.NotFoundHandler())
+		if !Valid(s) {
+			t.Errorf("%s requires the unknown scope %q", pattern, s)
+		}
+	}
+}
+
+func TestValid(t *testing.T) {
+	for _, s := range All() {
+		if !Valid(s) {
+			t.Errorf("Valid(%q) = false", s)
+		}
+	}
+	for _, s := range []string{"", "admin", "user:write", "USER:READ"} {
+		if Valid(s) {
+			t.Errorf("Valid(%q) = true", s)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package shared
+
+import (
+	"beerbux/pkg/clientip"
+	"database/sql"
+	"net/http"
+)
+
+// maxUserAgentLength limits how much of the User-Agent header is stored against a refresh token.
+const maxUserAgentLength = 512
+
+// DeviceInfo describes the device a refresh token was issued to.
+type DeviceInfo struct {
+	UserAgent string
+	IPAddress string
+}
+
+func NewDeviceInfo(r *http.Request, trustProxy bool) DeviceInfo {
+	userAgent := r.UserAgent()
+	if len(userAgent) > maxUserAgentLength {
+		userAgent = userAgent[:maxUserAgentLength]
+	}
+
+	return DeviceInfo{
+		UserAgent: userAgent,
+		IPAddress: clientip.FromRequest(r, trustProxy),
+	}
+}
+
+func (d DeviceInfo) NullUserAgent() sql.NullString {
+	return sql.NullString{String: d.UserAgent, Valid: d.UserAgent != ""}
+}
+
+func (d DeviceInfo) NullIPAddress() sql.NullString {
+	return sql.NullString{String: d.IPAddress, Valid: d.IPAddress != ""}
+}
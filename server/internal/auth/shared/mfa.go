@@ -0,0 +1,81 @@
+package shared
+
+import (
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"time"
+)
+
+const mfaTokenAudience = "beerbux:mfa"
+
+var ErrInvalidMFAToken = errors.New("invalid MFA token")
+
+// mfaSigningKey derives the key used to sign MFA tokens so that an MFA token can
+// never be mistaken for an access token, which is signed with the secret itself.
+func mfaSigningKey(secret string) []byte {
+	return []byte(secret + ":" + mfaTokenAudience)
+}
+
+// MFAToken identifies the user who has proved their identity and may complete the login
+// with a second factor. Method is how they proved it, so that the login is recorded as such.
+type MFAToken struct {
+	UserID   uuid.UUID
+	Username string
+	Method   string
+}
+
+// GenerateMFAToken returns a short-lived token proving that the user has entered
+// their password, or used another login method, and may complete the login with a second factor.
+func GenerateMFAToken(mfaToken MFAToken, secret string, duration time.Duration) (string, error) {
+	claims := jwt.MapClaims{
+		"sub":      mfaToken.UserID,
+		"username": mfaToken.Username,
+		"method":   mfaToken.Method,
+		"aud":      mfaTokenAudience,
+		"exp":      time.Now().Add(duration).Unix(),
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(mfaSigningKey(secret))
+}
+
+// ParseMFAToken validates the MFA token and returns the user and login method it was issued for.
+func ParseMFAToken(value, secret string) (*MFAToken, error) {
+	token, err := jwt.Parse(value, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return mfaSigningKey(secret), nil
+	})
+	if err != nil || !token.Valid {
+		return nil, ErrInvalidMFAToken
+	}
+
+	mapClaims, ok := token.Claims.(jwt.MapClaims)
+	if !ok || !mapClaims.VerifyAudience(mfaTokenAudience, true) {
+		return nil, ErrInvalidMFAToken
+	}
+
+	subValue, _ := mapClaims["sub"].(string)
+	userID, err := uuid.Parse(subValue)
+	if err != nil {
+		return nil, ErrInvalidMFAToken
+	}
+
+	username, ok := mapClaims["username"].(string)
+	if !ok || username == "" {
+		return nil, ErrInvalidMFAToken
+	}
+
+	method, ok := mapClaims["method"].(string)
+	if !ok || method == "" {
+		return nil, ErrInvalidMFAToken
+	}
+
+	return &MFAToken{
+		UserID:   userID,
+		Username: username,
+		Method:   method,
+	}, nil
+}
@@ -0,0 +1,94 @@
+package shared
+
+import (
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"time"
+)
+
+const oidcStateAudience = "beerbux:oidc"
+
+const (
+	OIDCModeLogin = "login"
+	OIDCModeLink  = "link"
+)
+
+var ErrInvalidOIDCState = errors.New("invalid OIDC state")
+
+// OIDCState is kept in a cookie between redirecting the user to the provider and the
+// provider redirecting back, binding the callback to the browser that started the flow.
+type OIDCState struct {
+	Provider     string
+	Mode         string
+	State        string
+	Nonce        string
+	CodeVerifier string
+	// UserID is the user linking the identity when Mode is OIDCModeLink.
+	UserID uuid.UUID
+}
+
+type oidcStateClaims struct {
+	jwt.RegisteredClaims
+	Provider     string `json:"provider"`
+	Mode         string `json:"mode"`
+	State        string `json:"state"`
+	Nonce        string `json:"nonce"`
+	CodeVerifier string `json:"codeVerifier"`
+}
+
+// oidcStateSigningKey derives the key used to sign the OIDC state so that it can never
+// be mistaken for an access token or MFA token.
+func oidcStateSigningKey(secret string) []byte {
+	return []byte(secret + ":" + oidcStateAudience)
+}
+
+// GenerateOIDCState signs the state so that it cannot be tampered with while stored in the browser.
+func GenerateOIDCState(state OIDCState, secret string, duration time.Duration) (string, error) {
+	claims := oidcStateClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Audience:  jwt.ClaimStrings{oidcStateAudience},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(duration)),
+		},
+		Provider:     state.Provider,
+		Mode:         state.Mode,
+		State:        state.State,
+		Nonce:        state.Nonce,
+		CodeVerifier: state.CodeVerifier,
+	}
+	if state.UserID != uuid.Nil {
+		claims.Subject = state.UserID.String()
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(oidcStateSigningKey(secret))
+}
+
+// ParseOIDCState validates the signed state and returns its contents.
+func ParseOIDCState(value, secret string) (*OIDCState, error) {
+	var claims oidcStateClaims
+	token, err := jwt.ParseWithClaims(value, &claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return oidcStateSigningKey(secret), nil
+	})
+	if err != nil || !token.Valid || !claims.VerifyAudience(oidcStateAudience, true) {
+		return nil, ErrInvalidOIDCState
+	}
+
+	state := &OIDCState{
+		Provider:     claims.Provider,
+		Mode:         claims.Mode,
+		State:        claims.State,
+		Nonce:        claims.Nonce,
+		CodeVerifier: claims.CodeVerifier,
+	}
+	if state.Mode == OIDCModeLink {
+		if state.UserID, err = uuid.Parse(claims.Subject); err != nil {
+			return nil, ErrInvalidOIDCState
+		}
+	}
+	return state, nil
+}
@@ -0,0 +1,15 @@
+package shared
+
+import (
+	"strings"
+)
+
+// PersonalAccessTokenPrefix marks a bearer token as a personal access token rather than a JWT.
+// Personal access tokens are long-lived "bbx_pat_selector.verifier" tokens created by a user
+// for scripts and integrations.
+const PersonalAccessTokenPrefix = "bbx_pat_"
+
+// IsPersonalAccessToken reports whether the bearer token is a personal access token.
+func IsPersonalAccessToken(token string) bool {
+	return strings.HasPrefix(token, PersonalAccessTokenPrefix)
+}
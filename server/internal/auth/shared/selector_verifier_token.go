@@ -0,0 +1,63 @@
+package shared
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+)
+
+// SelectorVerifierToken is an opaque "selector.verifier" token handed to the client, used for
+// refresh tokens, personal access tokens and email revert tokens. The selector is stored in
+// plain text so the token can be looked up directly, while only a hash of the verifier is stored.
+//
+// The token may start with a prefix identifying its kind, such as PersonalAccessTokenPrefix.
+type SelectorVerifierToken struct {
+	Token          string
+	Selector       string
+	HashedVerifier string
+}
+
+// GenerateSelectorVerifierToken returns a new token starting with prefix, which may be empty.
+func GenerateSelectorVerifierToken(prefix string) SelectorVerifierToken {
+	selector := randomToken(16)
+	verifier := randomToken(32)
+	return SelectorVerifierToken{
+		Token:          prefix + selector + "." + verifier,
+		Selector:       selector,
+		HashedVerifier: hashVerifier(verifier),
+	}
+}
+
+// ParseSelectorVerifierToken splits a token starting with prefix into its selector and verifier.
+func ParseSelectorVerifierToken(token, prefix string) (selector, verifier string, ok bool) {
+	token, ok = strings.CutPrefix(token, prefix)
+	if !ok {
+		return "", "", false
+	}
+	selector, verifier, ok = strings.Cut(token, ".")
+	if !ok || selector == "" || verifier == "" {
+		return "", "", false
+	}
+	return selector, verifier, true
+}
+
+// VerifySelectorVerifierToken reports whether the verifier matches the stored hash in constant time.
+func VerifySelectorVerifierToken(verifier, hashedVerifier string) bool {
+	return subtle.ConstantTimeCompare([]byte(hashVerifier(verifier)), []byte(hashedVerifier)) == 1
+}
+
+// hashVerifier hashes the verifier with SHA-256. The verifier is 256 bits of randomness,
+// so a slow password hash is not needed.
+func hashVerifier(verifier string) string {
+	sum := sha256.Sum256([]byte(verifier))
+	return hex.EncodeToString(sum[:])
+}
+
+func randomToken(n int) string {
+	b := make([]byte, n)
+	_, _ = rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
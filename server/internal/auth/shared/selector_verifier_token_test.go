@@ -0,0 +1,57 @@
+package shared
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSelectorVerifierTokenRoundTrip(t *testing.T) {
+	for _, prefix := range []string{"", PersonalAccessTokenPrefix} {
+		token := GenerateSelectorVerifierToken(prefix)
+		if !strings.HasPrefix(token.Token, prefix) {
+			t.Errorf("token %q does not start with %q", token.Token, prefix)
+		}
+
+		selector, verifier, ok := ParseSelectorVerifierToken(token.Token, prefix)
+		if !ok {
+			t.Fatalf("ParseSelectorVerifierToken(%q) failed", token.Token)
+		}
+		if selector != token.Selector {
+			t.Errorf("selector = %q, want %q", selector, token.Selector)
+		}
+		if !VerifySelectorVerifierToken(verifier, token.HashedVerifier) {
+			t.Errorf("verifier of %q does not match its hash", token.Token)
+		}
+		if strings.Contains(token.HashedVerifier, verifier) {
+			t.Error("the verifier is stored in plain text")
+		}
+	}
+}
+
+func TestParseSelectorVerifierTokenRejectsMalformedTokens(t *testing.T) {
+	for _, tt := range []struct {
+		token  string
+		prefix string
+	}{
+		{token: "", prefix: ""},
+		{token: "selector", prefix: ""},
+		{token: ".verifier", prefix: ""},
+		{token: "selector.", prefix: ""},
+		{token: "selector.verifier", prefix: PersonalAccessTokenPrefix},
+		{token: PersonalAccessTokenPrefix + "selector", prefix: PersonalAccessTokenPrefix},
+	} {
+		if _, _, ok := ParseSelectorVerifierToken(tt.token, tt.prefix); ok {
+			t.Errorf("ParseSelectorVerifierToken(%q, %q) accepted a malformed token", tt.token, tt.prefix)
+		}
+	}
+}
+
+func TestVerifySelectorVerifierTokenRejectsOtherVerifiers(t *testing.T) {
+	token := GenerateSelectorVerifierToken("")
+	other := GenerateSelectorVerifierToken("")
+
+	_, verifier, _ := ParseSelectorVerifierToken(other.Token, "")
+	if VerifySelectorVerifierToken(verifier, token.HashedVerifier) {
+		t.Error("the verifier of another token was accepted")
+	}
+}
@@ -1,35 +1,24 @@
 package shared
 
 import (
-	"crypto/rand"
-	"encoding/base64"
+	"beerbux/pkg/jwtkeys"
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/google/uuid"
-	"golang.org/x/crypto/bcrypt"
 	"time"
 )
 
-func GenerateJWT(userID uuid.UUID, username, email, secret string, duration time.Duration) (string, error) {
+// JWTIssuer is the iss claim of access tokens, for services verifying tokens against the JWKS.
+const JWTIssuer = "beerbux"
+
+func GenerateJWT(userID uuid.UUID, username, email string, keys *jwtkeys.KeySet, duration time.Duration) (string, error) {
+	now := time.Now()
 	claims := jwt.MapClaims{
+		"iss":      JWTIssuer,
 		"sub":      userID,
 		"username": username,
 		"email":    email,
-		"exp":      time.Now().Add(duration).Unix(),
-	}
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString([]byte(secret))
-}
-
-func GenerateRefreshToken() string {
-	b := make([]byte, 32)
-	_, _ = rand.Read(b)
-	return base64.URLEncoding.EncodeToString(b)
-}
-
-func HashRefreshToken(token string) (string, error) {
-	hashedToken, err := bcrypt.GenerateFromPassword([]byte(token), bcrypt.DefaultCost)
-	if err != nil {
-		return "", err
+		"iat":      now.Unix(),
+		"exp":      now.Add(duration).Unix(),
 	}
-	return string(hashedToken), nil
+	return keys.Sign(claims)
 }
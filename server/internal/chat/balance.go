@@ -0,0 +1,66 @@
+package chat
+
+import (
+	"beerbux/internal/common/sessionaccess"
+	"beerbux/pkg/i18n"
+	"beerbux/pkg/slack"
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+)
+
+// memberBalance is how many beers a member has bought for and received from the other members of a session.
+type memberBalance struct {
+	Bought   float64
+	Received float64
+}
+
+// Net is positive when the member has bought more beers than they have received.
+func (b memberBalance) Net() float64 {
+	return b.Bought - b.Received
+}
+
+// sessionBalances totals the beers bought and received by each member of the session.
+func sessionBalances(session *sessionaccess.SessionWithTransactions) map[uuid.UUID]memberBalance {
+	balances := make(map[uuid.UUID]memberBalance, len(session.Members))
+	for _, t := range session.Transactions {
+		for _, l := range t.Lines {
+			creator := balances[t.UserID]
+			creator.Bought += l.Amount
+			balances[t.UserID] = creator
+
+			member := balances[l.UserID]
+			member.Received += l.Amount
+			balances[l.UserID] = member
+		}
+	}
+	return balances
+}
+
+// balance shows the caller how many beers they have bought and received in the session linked to the channel.
+func (b *Bot) balance(ctx context.Context, c caller) (slack.Response, error) {
+	channelSession, err := b.channelSession(ctx, c)
+	if err != nil {
+		return slack.Response{}, err
+	}
+
+	session, err := b.sessionReader.GetSessionByID(ctx, channelSession.ID)
+	if err != nil {
+		return slack.Response{}, fmt.Errorf("failed to get session: %w", err)
+	}
+	if !session.HasMember(c.ID) {
+		return slack.Response{}, errReply{i18n.Sprintf(c.Locale, "You are not a member of %s", session.Name)}
+	}
+
+	balance := sessionBalances(session)[c.ID]
+	text := i18n.Sprintf(c.Locale, "In %s you have bought %s beers and received %s.", session.Name, formatBeers(balance.Bought), formatBeers(balance.Received))
+	switch net := balance.Net(); {
+	case net > 0:
+		text += " " + i18n.Sprintf(c.Locale, "You are owed %s.", formatBeers(net))
+	case net < 0:
+		text += " " + i18n.Sprintf(c.Locale, "You owe %s.", formatBeers(-net))
+	default:
+		text += " " + i18n.Translate(c.Locale, "You are all square.")
+	}
+	return slack.Ephemeral(text), nil
+}
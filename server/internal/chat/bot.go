@@ -0,0 +1,205 @@
+package chat
+
+import (
+	"beerbux/internal/chat/db"
+	"beerbux/internal/common/sessionaccess"
+	sessionCommand "beerbux/internal/session/command"
+	"beerbux/internal/sse"
+	"beerbux/pkg/i18n"
+	"beerbux/pkg/slack"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"log/slog"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidLinkCode     = errors.New("chat link code is invalid or has expired")
+	ErrChatAccountNotFound = errors.New("chat account not found")
+)
+
+// LinkCodeTTL is how long a chat user has to follow the link to their Beerbux account.
+const LinkCodeTTL = 15 * time.Minute
+
+// errReply is returned by the command handlers with a reply explaining why the command failed.
+type errReply struct {
+	text string
+}
+
+func (e errReply) Error() string {
+	return e.text
+}
+
+// Bot runs chat commands on behalf of the Beerbux user linked to the chat user who sent them,
+// through the same commands as the API, so rounds logged from chat are notified and published
+// like any other.
+type Bot struct {
+	queries                         *db.Queries
+	sessionReader                   sessionaccess.SessionReader
+	createTransactionCommand        *sessionCommand.CreateTransactionCommand
+	updateSessionActiveStateCommand *sessionCommand.UpdateSessionActiveStateCommand
+	msgChan                         chan<- *sse.Message
+	clientBaseURL                   string
+	logger                          *slog.Logger
+}
+
+func NewBot(
+	queries *db.Queries,
+	sessionReader sessionaccess.SessionReader,
+	createTransactionCommand *sessionCommand.CreateTransactionCommand,
+	updateSessionActiveStateCommand *sessionCommand.UpdateSessionActiveStateCommand,
+	msgChan chan<- *sse.Message,
+	clientBaseURL string,
+	logger *slog.Logger,
+) *Bot {
+	return &Bot{
+		queries:                         queries,
+		sessionReader:                   sessionReader,
+		createTransactionCommand:        createTransactionCommand,
+		updateSessionActiveStateCommand: updateSessionActiveStateCommand,
+		msgChan:                         msgChan,
+		clientBaseURL:                   clientBaseURL,
+		logger:                          logger,
+	}
+}
+
+// caller is the Beerbux user running a command and the chat they are running it from.
+type caller struct {
+	ID        uuid.UUID
+	Name      string
+	Locale    string
+	TeamID    string
+	ChannelID string
+	ChatUser  string
+}
+
+// Handle runs the slash command and returns the reply. Chat users who have not linked their
+// Beerbux account are sent a link to do so instead.
+func (b *Bot) Handle(ctx context.Context, sc slack.SlashCommand) slack.Response {
+	user, err := b.queries.GetChatAccountUser(ctx, db.GetChatAccountUserParams{
+		TeamID:     sc.TeamID,
+		ChatUserID: sc.UserID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return b.linkAccount(ctx, sc)
+		}
+		b.logger.Error("failed to get chat account", "team", sc.TeamID, "chatUser", sc.UserID, "error", err)
+		return slack.Ephemeral(i18n.Translate("", "Something went wrong, please try again"))
+	}
+
+	c := caller{
+		ID:        user.ID,
+		Name:      user.Name,
+		Locale:    i18n.Resolve(user.Locale.String, ""),
+		TeamID:    sc.TeamID,
+		ChannelID: sc.ChannelID,
+		ChatUser:  sc.UserID,
+	}
+
+	cmd := ParseCommand(sc.Text)
+	var res slack.Response
+	switch cmd.Name {
+	case CommandRound:
+		res, err = b.round(ctx, c, cmd.Args)
+	case CommandBalance:
+		res, err = b.balance(ctx, c)
+	case CommandWhoseRound:
+		res, err = b.whoseRound(ctx, c)
+	case CommandClose:
+		res, err = b.closeSession(ctx, c)
+	case CommandUse:
+		res, err = b.useSession(ctx, c, strings.Join(cmd.Args, " "))
+	case CommandUnlink:
+		res, err = b.unlink(ctx, c)
+	case CommandHelp:
+		res = b.help(c, sc.Command)
+	default:
+		res = slack.Ephemeral(i18n.Sprintf(c.Locale, "Unknown command %s", cmd.Name) + "\n\n" + b.help(c, sc.Command).Text)
+	}
+
+	if err != nil {
+		var reply errReply
+		if errors.As(err, &reply) {
+			return slack.Ephemeral(reply.text)
+		}
+		b.logger.Error("failed to run chat command", "command", cmd.Name, "user", c.ID, "error", err)
+		return slack.Ephemeral(i18n.Translate(c.Locale, "Something went wrong, please try again"))
+	}
+	return res
+}
+
+func (b *Bot) help(c caller, slashCommand string) slack.Response {
+	lines := []string{
+		i18n.Translate(c.Locale, "Log rounds and check balances for the session linked to this channel:"),
+		fmt.Sprintf("`%s use <session name>` %s", slashCommand, i18n.Translate(c.Locale, "links this channel to one of your sessions")),
+		fmt.Sprintf("`%s round @name @name` %s", slashCommand, i18n.Translate(c.Locale, "buys a beer for each member mentioned")),
+		fmt.Sprintf("`%s balance` %s", slashCommand, i18n.Translate(c.Locale, "shows how many beers you have bought and received")),
+		fmt.Sprintf("`%s whose-round` %s", slashCommand, i18n.Translate(c.Locale, "shows who should buy the next round")),
+		fmt.Sprintf("`%s close` %s", slashCommand, i18n.Translate(c.Locale, "closes the session, for admins")),
+		fmt.Sprintf("`%s unlink` %s", slashCommand, i18n.Translate(c.Locale, "unlinks your Beerbux account")),
+	}
+	return slack.Ephemeral(strings.Join(lines, "\n"))
+}
+
+// linkAccount creates a one-time code linking the chat user to the Beerbux user who follows the link.
+func (b *Bot) linkAccount(ctx context.Context, sc slack.SlashCommand) slack.Response {
+	code := make([]byte, 32)
+	_, _ = rand.Read(code)
+	encodedCode := base64.RawURLEncoding.EncodeToString(code)
+
+	if _, err := b.queries.DeleteExpiredChatLinkCodes(ctx); err != nil {
+		b.logger.Error("failed to delete expired chat link codes", "error", err)
+	}
+	err := b.queries.CreateChatLinkCode(ctx, db.CreateChatLinkCodeParams{
+		CodeHash:     HashLinkCode(encodedCode),
+		TeamID:       sc.TeamID,
+		ChatUserID:   sc.UserID,
+		ChatUsername: sc.UserName,
+		ExpiresAt:    time.Now().Add(LinkCodeTTL),
+	})
+	if err != nil {
+		b.logger.Error("failed to create chat link code", "team", sc.TeamID, "chatUser", sc.UserID, "error", err)
+		return slack.Ephemeral(i18n.Translate("", "Something went wrong, please try again"))
+	}
+
+	// The code goes in the fragment, which is read by the client and never sent to a server.
+	link := b.clientBaseURL + "/chat/link#" + url.Values{"code": {encodedCode}}.Encode()
+	return slack.Ephemeral(i18n.Sprintf("", "Link your Beerbux account to use %s: %s\nThe link expires in %d minutes.", sc.Command, link, int(LinkCodeTTL/time.Minute)))
+}
+
+// HashLinkCode hashes a link code with SHA-256. The code is 256 bits of randomness,
+// so a slow password hash is not needed.
+func HashLinkCode(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
+
+// channelSession returns the session linked to the caller's channel.
+func (b *Bot) channelSession(ctx context.Context, c caller) (db.GetChatChannelSessionRow, error) {
+	session, err := b.queries.GetChatChannelSession(ctx, db.GetChatChannelSessionParams{
+		TeamID:    c.TeamID,
+		ChannelID: c.ChannelID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return db.GetChatChannelSessionRow{}, errReply{i18n.Translate(c.Locale, "This channel is not linked to a session yet. Link one with use and the name of the session.")}
+		}
+		return db.GetChatChannelSessionRow{}, fmt.Errorf("failed to get channel session: %w", err)
+	}
+	return session, nil
+}
+
+func formatBeers(beers float64) string {
+	return strconv.FormatFloat(beers, 'f', -1, 64)
+}
@@ -0,0 +1,33 @@
+package chat
+
+import (
+	"beerbux/pkg/i18n"
+	"beerbux/pkg/slack"
+	"context"
+	"fmt"
+)
+
+// closeSession closes the session linked to the channel. Only admins of the session can close it.
+func (b *Bot) closeSession(ctx context.Context, c caller) (slack.Response, error) {
+	channelSession, err := b.channelSession(ctx, c)
+	if err != nil {
+		return slack.Response{}, err
+	}
+
+	session, err := b.sessionReader.GetSessionDetails(ctx, channelSession.ID)
+	if err != nil {
+		return slack.Response{}, fmt.Errorf("failed to get session: %w", err)
+	}
+	if !session.HasAdminMember(c.ID) {
+		return slack.Response{}, errReply{i18n.Sprintf(c.Locale, "You must be an admin of %s to close it", session.Name)}
+	}
+	if !session.IsActive {
+		return slack.Response{}, errReply{i18n.Sprintf(c.Locale, "%s has already been closed", session.Name)}
+	}
+
+	if err := b.updateSessionActiveStateCommand.Execute(ctx, session.ID, c.ID, false); err != nil {
+		return slack.Response{}, fmt.Errorf("failed to close session: %w", err)
+	}
+
+	return slack.InChannel(i18n.Sprintf(c.Locale, "%s closed %s", c.Name, session.Name)), nil
+}
@@ -0,0 +1,45 @@
+package command
+
+import (
+	"beerbux/internal/chat"
+	"beerbux/internal/chat/db"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+)
+
+type LinkAccountCommand struct {
+	queries *db.Queries
+}
+
+func NewLinkAccountCommand(queries *db.Queries) *LinkAccountCommand {
+	return &LinkAccountCommand{
+		queries: queries,
+	}
+}
+
+// Execute links the chat user the code was sent to with the user. A chat user already linked
+// to another Beerbux account is moved to this one. ErrInvalidLinkCode is returned if the code
+// does not exist, has been used or has expired.
+func (c *LinkAccountCommand) Execute(ctx context.Context, userID uuid.UUID, code string) (db.ChatAccount, error) {
+	linkCode, err := c.queries.ConsumeChatLinkCode(ctx, chat.HashLinkCode(code))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return db.ChatAccount{}, chat.ErrInvalidLinkCode
+		}
+		return db.ChatAccount{}, fmt.Errorf("failed to consume chat link code: %w", err)
+	}
+
+	account, err := c.queries.UpsertChatAccount(ctx, db.UpsertChatAccountParams{
+		TeamID:       linkCode.TeamID,
+		ChatUserID:   linkCode.ChatUserID,
+		ChatUsername: linkCode.ChatUsername,
+		UserID:       userID,
+	})
+	if err != nil {
+		return db.ChatAccount{}, fmt.Errorf("failed to link chat account for user %s: %w", userID, err)
+	}
+	return account, nil
+}
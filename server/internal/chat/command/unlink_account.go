@@ -0,0 +1,35 @@
+package command
+
+import (
+	"beerbux/internal/chat"
+	"beerbux/internal/chat/db"
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+)
+
+type UnlinkAccountCommand struct {
+	queries *db.Queries
+}
+
+func NewUnlinkAccountCommand(queries *db.Queries) *UnlinkAccountCommand {
+	return &UnlinkAccountCommand{
+		queries: queries,
+	}
+}
+
+// Execute removes the user's linked chat account. ErrChatAccountNotFound is returned if the
+// account does not exist or is linked to another user.
+func (c *UnlinkAccountCommand) Execute(ctx context.Context, userID, accountID uuid.UUID) error {
+	n, err := c.queries.DeleteChatAccount(ctx, db.DeleteChatAccountParams{
+		ID:     accountID,
+		UserID: userID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to unlink chat account %s: %w", accountID, err)
+	}
+	if n == 0 {
+		return chat.ErrChatAccountNotFound
+	}
+	return nil
+}
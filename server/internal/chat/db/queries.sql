@@ -0,0 +1,67 @@
+-- name: GetChatAccountUser :one
+select u.id, u.username, u.name, u.locale
+from chat_accounts a
+join users u on u.id = a.user_id
+where a.team_id = $1
+  and a.chat_user_id = $2;
+
+-- name: CreateChatLinkCode :exec
+insert into chat_link_codes (code_hash, team_id, chat_user_id, chat_username, expires_at)
+values ($1, $2, $3, $4, $5);
+
+-- name: DeleteExpiredChatLinkCodes :execrows
+delete from chat_link_codes
+where expires_at <= now();
+
+-- name: ConsumeChatLinkCode :one
+delete from chat_link_codes
+where code_hash = $1
+  and expires_at > now()
+returning team_id, chat_user_id, chat_username;
+
+-- name: UpsertChatAccount :one
+insert into chat_accounts (team_id, chat_user_id, chat_username, user_id)
+values ($1, $2, $3, $4)
+on conflict (team_id, chat_user_id) do update
+set chat_username = excluded.chat_username,
+    user_id = excluded.user_id
+returning *;
+
+-- name: ListChatAccounts :many
+select *
+from chat_accounts
+where user_id = $1
+order by created_at;
+
+-- name: DeleteChatAccount :execrows
+delete from chat_accounts
+where id = $1
+  and user_id = $2;
+
+-- name: DeleteChatAccountByChatUser :execrows
+delete from chat_accounts
+where team_id = $1
+  and chat_user_id = $2;
+
+-- name: GetChatChannelSession :one
+select s.id, s.name, s.is_active
+from chat_channels c
+join sessions s on s.id = c.session_id
+where c.team_id = $1
+  and c.channel_id = $2;
+
+-- name: UpsertChatChannel :exec
+insert into chat_channels (team_id, channel_id, session_id, linked_by)
+values ($1, $2, $3, $4)
+on conflict (team_id, channel_id) do update
+set session_id = excluded.session_id,
+    linked_by = excluded.linked_by;
+
+-- name: ListMemberSessionsByName :many
+select s.id, s.name, s.is_active
+from sessions s
+join session_members m on m.session_id = s.id
+where m.member_id = $1
+  and not m.is_deleted
+  and lower(s.name) = lower($2)
+order by s.created_at desc;
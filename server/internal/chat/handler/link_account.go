@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"beerbux/internal/chat"
+	"beerbux/internal/chat/command"
+	"beerbux/internal/chat/query"
+	"beerbux/internal/common/claims"
+	"beerbux/pkg/send"
+	"encoding/json"
+	"errors"
+	oz "github.com/go-ozzo/ozzo-validation/v4"
+	"log/slog"
+	"net/http"
+)
+
+type LinkAccountHandler struct {
+	linkAccountCommand *command.LinkAccountCommand
+	logger             *slog.Logger
+}
+
+func NewLinkAccountHandler(linkAccountCommand *command.LinkAccountCommand, logger *slog.Logger) *LinkAccountHandler {
+	return &LinkAccountHandler{
+		linkAccountCommand: linkAccountCommand,
+		logger:             logger,
+	}
+}
+
+// LinkAccountRequest carries the code from the link the chat user was sent.
+type LinkAccountRequest struct {
+	Code string `json:"code"`
+}
+
+// ServeHTTP links the chat user the code was sent to with the current user.
+func (h *LinkAccountHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c := claims.GetClaims(r)
+	if !c.Authenticated() {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var req LinkAccountRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		send.BadRequest(w, "Failed to decode request")
+		return
+	}
+	if err := req.Validate(); err != nil {
+		send.ValidationError(w, err)
+		return
+	}
+
+	account, err := h.linkAccountCommand.Execute(r.Context(), c.Subject, req.Code)
+	if err != nil {
+		if errors.Is(err, chat.ErrInvalidLinkCode) {
+			send.BadRequest(w, "The link is invalid or has expired")
+			return
+		}
+		h.logger.Error("failed to link chat account", "user", c.Subject, "error", err)
+		send.InternalServerError(w, "There has been an issue linking the chat account")
+		return
+	}
+
+	send.JSON(w, query.NewChatAccountResponse(account), http.StatusCreated)
+}
+
+func (r LinkAccountRequest) Validate() error {
+	return oz.ValidateStruct(&r,
+		oz.Field(&r.Code, oz.Required),
+	)
+}
@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"beerbux/internal/chat/query"
+	"beerbux/internal/common/claims"
+	"beerbux/pkg/send"
+	"log/slog"
+	"net/http"
+)
+
+type ListAccountsHandler struct {
+	listAccountsQuery *query.ListAccountsQuery
+	logger            *slog.Logger
+}
+
+func NewListAccountsHandler(listAccountsQuery *query.ListAccountsQuery, logger *slog.Logger) *ListAccountsHandler {
+	return &ListAccountsHandler{
+		listAccountsQuery: listAccountsQuery,
+		logger:            logger,
+	}
+}
+
+func (h *ListAccountsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c := claims.GetClaims(r)
+	if !c.Authenticated() {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	accounts, err := h.listAccountsQuery.Execute(r.Context(), c.Subject)
+	if err != nil {
+		h.logger.Error("failed to list chat accounts", "user", c.Subject, "error", err)
+		send.InternalServerError(w, "There has been an issue listing the chat accounts")
+		return
+	}
+
+	send.JSON(w, accounts, http.StatusOK)
+}
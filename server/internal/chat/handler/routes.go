@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"beerbux/internal/api/config"
+	"beerbux/internal/chat"
+	"beerbux/internal/chat/command"
+	"beerbux/internal/chat/db"
+	"beerbux/internal/chat/query"
+	"beerbux/internal/common/history"
+	"beerbux/internal/common/sessionaccess"
+	sessionaccessQueries "beerbux/internal/common/sessionaccess/db"
+	"beerbux/internal/notifications"
+	notificationsQueries "beerbux/internal/notifications/db"
+	sessionCommand "beerbux/internal/session/command"
+	sessionQueries "beerbux/internal/session/db"
+	"beerbux/internal/sse"
+	"beerbux/internal/webhooks"
+	"database/sql"
+	"log/slog"
+	"net/http"
+)
+
+func BuildRoutes(
+	conf *config.Config,
+	logger *slog.Logger,
+	database *sql.DB,
+	mux *http.ServeMux,
+	msgChan chan<- *sse.Message,
+	pusher *notifications.PushDispatcher,
+	webhookOutbox *webhooks.Outbox,
+) {
+	queries := db.New(database)
+
+	listAccountsQuery := query.NewListAccountsQuery(queries)
+	linkAccountCommand := command.NewLinkAccountCommand(queries)
+	unlinkAccountCommand := command.NewUnlinkAccountCommand(queries)
+
+	mux.Handle("GET /chat/accounts", NewListAccountsHandler(listAccountsQuery, logger))
+	mux.Handle("POST /chat/accounts/link", NewLinkAccountHandler(linkAccountCommand, logger))
+	mux.Handle("DELETE /chat/accounts/{accountId}", NewUnlinkAccountHandler(unlinkAccountCommand, logger))
+
+	if !conf.Chat.Enabled() {
+		return
+	}
+
+	// Commands run from chat go through the same history chain as the session routes, so they
+	// are notified and published to webhooks like any other.
+	sessionQuerier := sessionQueries.New(database)
+	sessionHistoryService := history.NewSessionHistoryService(sessionQuerier, logger)
+	sessionEventPublisher := webhooks.NewSessionEventPublisher(sessionHistoryService, webhookOutbox, logger)
+	sessionEventNotifier := notifications.NewSessionEventNotifier(sessionEventPublisher, notificationsQueries.New(database), msgChan, pusher, logger)
+
+	bot := chat.NewBot(
+		queries,
+		sessionaccess.NewSessionService(sessionaccessQueries.New(database)),
+		sessionCommand.NewCreateTransactionCommand(database, sessionQuerier, sessionEventNotifier),
+		sessionCommand.NewUpdateSessionActionStateCommand(sessionQuerier, sessionEventNotifier),
+		msgChan,
+		conf.CORSClientBaseURL,
+		logger,
+	)
+
+	mux.Handle("POST /chat/slack/commands", NewSlashCommandHandler(conf.Chat.SigningSecret, bot, logger))
+}
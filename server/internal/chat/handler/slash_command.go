@@ -0,0 +1,57 @@
+package handler
+
+import (
+	"beerbux/internal/chat"
+	"beerbux/pkg/send"
+	"beerbux/pkg/slack"
+	"io"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+const (
+	// maxSlashCommandSize is far larger than any slash command Slack sends.
+	maxSlashCommandSize = 64 << 10
+	// signatureTolerance is how old a signed request can be before it is rejected as a replay.
+	signatureTolerance = 5 * time.Minute
+)
+
+type SlashCommandHandler struct {
+	signingSecret string
+	bot           *chat.Bot
+	logger        *slog.Logger
+}
+
+func NewSlashCommandHandler(signingSecret string, bot *chat.Bot, logger *slog.Logger) *SlashCommandHandler {
+	return &SlashCommandHandler{
+		signingSecret: signingSecret,
+		bot:           bot,
+		logger:        logger,
+	}
+}
+
+// ServeHTTP runs a slash command sent by a Slack-compatible chat workspace. The request is
+// authenticated by its signature rather than a user session, and the reply is always sent
+// with a 200 status since chat clients only show the text of successful responses.
+func (h *SlashCommandHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxSlashCommandSize))
+	if err != nil {
+		send.BadRequest(w, "Failed to read request")
+		return
+	}
+
+	if err := slack.Verify(h.signingSecret, r.Header, body, signatureTolerance); err != nil {
+		h.logger.Warn("rejected slash command", "error", err)
+		send.Unauthorized(w, "Invalid signature")
+		return
+	}
+
+	slashCommand, err := slack.ParseSlashCommand(body)
+	if err != nil {
+		send.BadRequest(w, "Invalid slash command")
+		return
+	}
+
+	send.JSON(w, h.bot.Handle(r.Context(), slashCommand), http.StatusOK)
+}
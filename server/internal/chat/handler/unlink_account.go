@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"beerbux/internal/chat"
+	"beerbux/internal/chat/command"
+	"beerbux/internal/common/claims"
+	"beerbux/pkg/send"
+	"beerbux/pkg/url"
+	"errors"
+	"log/slog"
+	"net/http"
+)
+
+type UnlinkAccountHandler struct {
+	unlinkAccountCommand *command.UnlinkAccountCommand
+	logger               *slog.Logger
+}
+
+func NewUnlinkAccountHandler(unlinkAccountCommand *command.UnlinkAccountCommand, logger *slog.Logger) *UnlinkAccountHandler {
+	return &UnlinkAccountHandler{
+		unlinkAccountCommand: unlinkAccountCommand,
+		logger:               logger,
+	}
+}
+
+func (h *UnlinkAccountHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c := claims.GetClaims(r)
+	if !c.Authenticated() {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	accountID, ok := url.Path.GetUUID(r, "accountId")
+	if !ok {
+		send.BadRequest(w, "Missing params")
+		return
+	}
+
+	if err := h.unlinkAccountCommand.Execute(r.Context(), c.Subject, accountID); err != nil {
+		if errors.Is(err, chat.ErrChatAccountNotFound) {
+			send.NotFound(w, "Chat account not found")
+			return
+		}
+		h.logger.Error("failed to unlink chat account", "account", accountID, "error", err)
+		send.InternalServerError(w, "There has been an issue unlinking the chat account")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
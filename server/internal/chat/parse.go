@@ -0,0 +1,76 @@
+package chat
+
+import (
+	"regexp"
+	"strings"
+)
+
+const (
+	CommandHelp       = "help"
+	CommandRound      = "round"
+	CommandBalance    = "balance"
+	CommandWhoseRound = "whose-round"
+	CommandClose      = "close"
+	CommandUse        = "use"
+	CommandUnlink     = "unlink"
+)
+
+// Command is a chat command parsed from the text following the slash command.
+type Command struct {
+	Name string
+	Args []string
+}
+
+// ParseCommand parses the text of a slash command, such as "round @julian @connor".
+// The name is case-insensitive and empty text is the help command. "whose round" and
+// "close session" are accepted as the whose-round and close commands.
+func ParseCommand(text string) Command {
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		return Command{Name: CommandHelp}
+	}
+
+	cmd := Command{Name: strings.ToLower(words[0]), Args: words[1:]}
+	switch {
+	case (cmd.Name == "whose" || cmd.Name == "whos") && len(cmd.Args) > 0 && strings.EqualFold(cmd.Args[0], "round"):
+		cmd = Command{Name: CommandWhoseRound, Args: cmd.Args[1:]}
+	case cmd.Name == "whoseround" || cmd.Name == "whose-round" || cmd.Name == "whose_round":
+		cmd.Name = CommandWhoseRound
+	case cmd.Name == CommandClose && len(cmd.Args) > 0 && strings.EqualFold(cmd.Args[0], "session"):
+		cmd.Args = cmd.Args[1:]
+	}
+	return cmd
+}
+
+// escapedMention matches a mention escaped by Slack, <@U024BE7LH> or <@U024BE7LH|julian>.
+var escapedMention = regexp.MustCompile(`^<@([A-Z0-9]+)(?:\|([^>]*))?>$`)
+
+// Mention is a user mentioned in a command. Mentions escaped by the chat service identify
+// the chat user, while plain mentions, such as @julian, name a Beerbux username.
+type Mention struct {
+	ChatUserID string
+	Username   string
+}
+
+// Display is how the mention was written, for replies about it.
+func (m Mention) Display() string {
+	return "@" + m.Username
+}
+
+// ParseMention parses a word of a command as a mention, reporting whether it is one.
+func ParseMention(word string) (Mention, bool) {
+	if m := escapedMention.FindStringSubmatch(word); m != nil {
+		username := m[2]
+		if username == "" {
+			username = m[1]
+		}
+		return Mention{ChatUserID: m[1], Username: username}, true
+	}
+
+	username, ok := strings.CutPrefix(word, "@")
+	username = strings.TrimRight(username, ",")
+	if !ok || username == "" {
+		return Mention{}, false
+	}
+	return Mention{Username: username}, true
+}
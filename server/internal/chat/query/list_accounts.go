@@ -0,0 +1,54 @@
+package query
+
+import (
+	"beerbux/internal/chat/db"
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"time"
+)
+
+type ChatAccountResponse struct {
+	ID           uuid.UUID `json:"id"`
+	TeamID       string    `json:"teamId"`
+	ChatUserID   string    `json:"chatUserId"`
+	ChatUsername string    `json:"chatUsername"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
+type ChatAccountsResponse struct {
+	Accounts []ChatAccountResponse `json:"accounts"`
+}
+
+func NewChatAccountResponse(a db.ChatAccount) ChatAccountResponse {
+	return ChatAccountResponse{
+		ID:           a.ID,
+		TeamID:       a.TeamID,
+		ChatUserID:   a.ChatUserID,
+		ChatUsername: a.ChatUsername,
+		CreatedAt:    a.CreatedAt,
+	}
+}
+
+type ListAccountsQuery struct {
+	queries *db.Queries
+}
+
+func NewListAccountsQuery(queries *db.Queries) *ListAccountsQuery {
+	return &ListAccountsQuery{
+		queries: queries,
+	}
+}
+
+func (q *ListAccountsQuery) Execute(ctx context.Context, userID uuid.UUID) (ChatAccountsResponse, error) {
+	accounts, err := q.queries.ListChatAccounts(ctx, userID)
+	if err != nil {
+		return ChatAccountsResponse{}, fmt.Errorf("failed to list user %s chat accounts: %w", userID, err)
+	}
+
+	res := ChatAccountsResponse{Accounts: make([]ChatAccountResponse, len(accounts))}
+	for i, a := range accounts {
+		res.Accounts[i] = NewChatAccountResponse(a)
+	}
+	return res, nil
+}
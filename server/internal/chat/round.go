@@ -0,0 +1,133 @@
+package chat
+
+import (
+	"beerbux/internal/chat/db"
+	"beerbux/internal/common/sessionaccess"
+	sessionCommand "beerbux/internal/session/command"
+	sessionErr "beerbux/internal/session/errors"
+	sessionHandler "beerbux/internal/session/handler"
+	"beerbux/internal/sse"
+	"beerbux/pkg/i18n"
+	"beerbux/pkg/slack"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"strings"
+)
+
+// round buys a beer for each member mentioned, in the session linked to the channel.
+func (b *Bot) round(ctx context.Context, c caller, args []string) (slack.Response, error) {
+	if len(args) == 0 {
+		return slack.Response{}, errReply{i18n.Translate(c.Locale, "Mention the members you are buying a round for, such as round @julian @connor")}
+	}
+
+	channelSession, err := b.channelSession(ctx, c)
+	if err != nil {
+		return slack.Response{}, err
+	}
+
+	session, err := b.sessionReader.GetSessionDetails(ctx, channelSession.ID)
+	if err != nil {
+		return slack.Response{}, fmt.Errorf("failed to get session: %w", err)
+	}
+	if !session.HasMember(c.ID) {
+		return slack.Response{}, errReply{i18n.Sprintf(c.Locale, "You are not a member of %s", session.Name)}
+	}
+
+	lines := make([]sessionCommand.TransactionLine, 0, len(args))
+	names := make([]string, 0, len(args))
+	for _, arg := range args {
+		mention, ok := ParseMention(arg)
+		if !ok {
+			return slack.Response{}, errReply{i18n.Sprintf(c.Locale, "%s is not a mention of a member", arg)}
+		}
+		member, err := b.resolveMention(ctx, c, session, mention)
+		if err != nil {
+			return slack.Response{}, err
+		}
+		if member.ID == c.ID {
+			return slack.Response{}, errReply{i18n.Translate(c.Locale, "You cannot buy a round for yourself")}
+		}
+		if containsMember(lines, member.ID) {
+			continue
+		}
+		lines = append(lines, sessionCommand.TransactionLine{MemberID: member.ID, Amount: 1})
+		names = append(names, member.Name)
+	}
+
+	transaction, err := b.createTransactionCommand.Execute(ctx, sessionCommand.CreateTransactionRequest{
+		SessionID: session.ID,
+		CreatorID: c.ID,
+		Lines:     lines,
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, sessionErr.ErrInactiveSession):
+			return slack.Response{}, errReply{i18n.Sprintf(c.Locale, "%s has been closed", session.Name)}
+		case errors.Is(err, sessionErr.ErrNotAllMembersPartOfSession):
+			return slack.Response{}, errReply{i18n.Sprintf(c.Locale, "Everyone in the round must be a member of %s", session.Name)}
+		}
+		return slack.Response{}, fmt.Errorf("failed to create transaction: %w", err)
+	}
+
+	b.sendTransactionCreatedMessage(session.ID, c.ID, transaction.ID, float64(len(lines)))
+
+	return slack.InChannel(i18n.Sprintf(c.Locale, "%s bought a round for %s in %s", c.Name, strings.Join(names, ", "), session.Name)), nil
+}
+
+// resolveMention returns the member of the session mentioned. Mentions are only resolved among
+// the members of the session, so the same reply is given whether or not a user with the
+// mentioned username exists.
+func (b *Bot) resolveMention(ctx context.Context, c caller, session *sessionaccess.Session, m Mention) (sessionaccess.SessionMember, error) {
+	var match func(sessionaccess.SessionMember) bool
+	if m.ChatUserID != "" {
+		user, err := b.queries.GetChatAccountUser(ctx, db.GetChatAccountUserParams{
+			TeamID:     c.TeamID,
+			ChatUserID: m.ChatUserID,
+		})
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return sessionaccess.SessionMember{}, errReply{i18n.Sprintf(c.Locale, "%s has not linked their Beerbux account", m.Display())}
+			}
+			return sessionaccess.SessionMember{}, fmt.Errorf("failed to get chat account: %w", err)
+		}
+		match = func(member sessionaccess.SessionMember) bool { return member.ID == user.ID }
+	} else {
+		match = func(member sessionaccess.SessionMember) bool { return member.Username == m.Username }
+	}
+
+	for _, member := range session.Members {
+		if !member.IsDeleted && match(member) {
+			return member, nil
+		}
+	}
+	return sessionaccess.SessionMember{}, errReply{i18n.Sprintf(c.Locale, "%s is not a member of %s", m.Display(), session.Name)}
+}
+
+// sendTransactionCreatedMessage updates the session for members who have it open, as when
+// the transaction is created through the API.
+func (b *Bot) sendTransactionCreatedMessage(sessionID, creatorID, transactionID uuid.UUID, total float64) {
+	data, err := json.Marshal(sessionHandler.TransactionCreatedMessage{
+		TransactionID: transactionID,
+		SessionID:     sessionID,
+		CreatorID:     creatorID,
+		Total:         total,
+	})
+	if err != nil {
+		b.logger.Error("failed to marshal session.transaction.created message", "error", err)
+		return
+	}
+	b.msgChan <- sse.NewMessage("session.transaction.created", sessionID.String(), data)
+}
+
+func containsMember(lines []sessionCommand.TransactionLine, memberID uuid.UUID) bool {
+	for _, l := range lines {
+		if l.MemberID == memberID {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,21 @@
+package chat
+
+import (
+	"beerbux/internal/chat/db"
+	"beerbux/pkg/i18n"
+	"beerbux/pkg/slack"
+	"context"
+	"fmt"
+)
+
+// unlink removes the link between the chat user and their Beerbux account.
+func (b *Bot) unlink(ctx context.Context, c caller) (slack.Response, error) {
+	_, err := b.queries.DeleteChatAccountByChatUser(ctx, db.DeleteChatAccountByChatUserParams{
+		TeamID:     c.TeamID,
+		ChatUserID: c.ChatUser,
+	})
+	if err != nil {
+		return slack.Response{}, fmt.Errorf("failed to unlink chat account: %w", err)
+	}
+	return slack.Ephemeral(i18n.Translate(c.Locale, "Your Beerbux account has been unlinked")), nil
+}
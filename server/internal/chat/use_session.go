@@ -0,0 +1,42 @@
+package chat
+
+import (
+	"beerbux/internal/chat/db"
+	"beerbux/pkg/i18n"
+	"beerbux/pkg/slack"
+	"context"
+	"fmt"
+)
+
+// useSession links the channel to the caller's session with the given name, so the other
+// commands run in the channel apply to it. The most recent session is used when the caller
+// is a member of several sessions with the name.
+func (b *Bot) useSession(ctx context.Context, c caller, name string) (slack.Response, error) {
+	if name == "" {
+		return slack.Response{}, errReply{i18n.Translate(c.Locale, "Name the session to link to this channel")}
+	}
+
+	sessions, err := b.queries.ListMemberSessionsByName(ctx, db.ListMemberSessionsByNameParams{
+		MemberID: c.ID,
+		Name:     name,
+	})
+	if err != nil {
+		return slack.Response{}, fmt.Errorf("failed to list sessions by name: %w", err)
+	}
+	if len(sessions) == 0 {
+		return slack.Response{}, errReply{i18n.Sprintf(c.Locale, "You are not a member of a session called %s", name)}
+	}
+	session := sessions[0]
+
+	err = b.queries.UpsertChatChannel(ctx, db.UpsertChatChannelParams{
+		TeamID:    c.TeamID,
+		ChannelID: c.ChannelID,
+		SessionID: session.ID,
+		LinkedBy:  c.ID,
+	})
+	if err != nil {
+		return slack.Response{}, fmt.Errorf("failed to link channel to session: %w", err)
+	}
+
+	return slack.InChannel(i18n.Sprintf(c.Locale, "%s linked this channel to %s", c.Name, session.Name)), nil
+}
@@ -0,0 +1,50 @@
+package chat
+
+import (
+	"beerbux/internal/common/sessionaccess"
+	"beerbux/pkg/i18n"
+	"beerbux/pkg/slack"
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+)
+
+// whoseRound names the member who should buy the next round in the session linked to the
+// channel: the member who has received the most beers more than they have bought. Ties go
+// to the member who has bought the fewest beers, then to the member whose name comes first.
+func (b *Bot) whoseRound(ctx context.Context, c caller) (slack.Response, error) {
+	channelSession, err := b.channelSession(ctx, c)
+	if err != nil {
+		return slack.Response{}, err
+	}
+
+	session, err := b.sessionReader.GetSessionByID(ctx, channelSession.ID)
+	if err != nil {
+		return slack.Response{}, fmt.Errorf("failed to get session: %w", err)
+	}
+	if !session.HasMember(c.ID) {
+		return slack.Response{}, errReply{i18n.Sprintf(c.Locale, "You are not a member of %s", session.Name)}
+	}
+
+	balances := sessionBalances(session)
+	members := slices.DeleteFunc(slices.Clone(session.Members), func(m sessionaccess.SessionMember) bool {
+		return m.IsDeleted
+	})
+	if len(members) == 0 {
+		return slack.Response{}, errReply{i18n.Sprintf(c.Locale, "%s has no members", session.Name)}
+	}
+	slices.SortFunc(members, func(a, b sessionaccess.SessionMember) int {
+		return cmp.Or(
+			cmp.Compare(balances[a.ID].Net(), balances[b.ID].Net()),
+			cmp.Compare(balances[a.ID].Bought, balances[b.ID].Bought),
+			cmp.Compare(a.Name, b.Name),
+		)
+	})
+
+	next := members[0]
+	if owed := -balances[next.ID].Net(); owed > 0 {
+		return slack.InChannel(i18n.Sprintf(c.Locale, "It is %s's round in %s, who owes %s.", next.Name, session.Name, formatBeers(owed))), nil
+	}
+	return slack.InChannel(i18n.Sprintf(c.Locale, "Everyone is square in %s, so it is %s's round.", session.Name, next.Name)), nil
+}
@@ -3,6 +3,7 @@ package claims
 import (
 	"github.com/google/uuid"
 	"net/http"
+	"slices"
 	"time"
 )
 
@@ -17,12 +18,25 @@ type JWTClaims struct {
 	Subject    uuid.UUID `json:"sub"`
 	Username   string    `json:"username"`
 	Email      string    `json:"email"`
+	// Scopes limits the request to the granted scopes when authenticated with a
+	// personal access token. It is nil for users who logged in, who have full access.
+	Scopes []string `json:"scopes,omitempty"`
 }
 
 func (c JWTClaims) Authenticated() bool {
 	return c.Subject != uuid.Nil && c.Username != "" && !c.Expired()
 }
 
+// PersonalAccessToken reports whether the request was authenticated with a personal access token.
+func (c JWTClaims) PersonalAccessToken() bool {
+	return c.Scopes != nil
+}
+
+// HasScope reports whether the request may act within the given scope.
+func (c JWTClaims) HasScope(scope string) bool {
+	return !c.PersonalAccessToken() || slices.Contains(c.Scopes, scope)
+}
+
 func (c JWTClaims) Expired() bool {
 	return time.Unix(c.Expiration, 0).Before(time.Now())
 }
@@ -0,0 +1,88 @@
+// Package outbox contains the parts shared by the workers that deliver queued messages, such as
+// emails and webhooks: polling for due messages in batches and retrying failures with backoff.
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"math/rand/v2"
+	"time"
+)
+
+// Poller delivers due messages whenever it is woken or the poll interval elapses, and
+// periodically purges old messages.
+type Poller struct {
+	// Interval is how often due messages are checked for when the poller is not woken.
+	Interval time.Duration
+	// Wake is signalled when a message is queued, so that it is delivered straight away.
+	Wake <-chan struct{}
+	// BatchSize is the number of messages DeliverBatch claims at a time.
+	BatchSize int
+	// DeliverBatch claims up to BatchSize due messages, delivers them and returns how many
+	// were claimed. Messages are claimed with a lease, so that messages left sending when a
+	// worker stops are retried once the lease expires.
+	DeliverBatch func(ctx context.Context) int
+	// PurgeInterval is how often Purge is called, if it is set.
+	PurgeInterval time.Duration
+	Purge         func(ctx context.Context)
+}
+
+// Run delivers messages until ctx is cancelled.
+func (p Poller) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.Interval)
+	defer ticker.Stop()
+
+	var purge <-chan time.Time
+	if p.Purge != nil {
+		purgeTicker := time.NewTicker(p.PurgeInterval)
+		defer purgeTicker.Stop()
+		purge = purgeTicker.C
+	}
+
+	for {
+		p.deliverDue(ctx)
+
+		select {
+		case <-ticker.C:
+		case <-p.Wake:
+		case <-purge:
+			p.Purge(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// deliverDue delivers due messages in batches until none are left.
+func (p Poller) deliverDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		if p.DeliverBatch(ctx) < p.BatchSize {
+			return
+		}
+	}
+}
+
+// Backoff is an exponential backoff for retrying failed deliveries.
+type Backoff struct {
+	Base time.Duration
+	Max  time.Duration
+}
+
+// Delay doubles the delay after each attempt, up to Max, with up to 20% jitter so that
+// messages failing together are not all retried at the same moment.
+func (b Backoff) Delay(attempt int) time.Duration {
+	delay := b.Max
+	if attempt < 20 {
+		delay = min(b.Base<<(attempt-1), b.Max)
+	}
+	jitter := time.Duration(rand.Int64N(int64(delay) / 5))
+	return delay - jitter
+}
+
+// ErrorString returns the error message to record for a delivery attempt, if there was an error.
+func ErrorString(err error) sql.NullString {
+	if err == nil {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: err.Error(), Valid: true}
+}
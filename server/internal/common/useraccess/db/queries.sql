@@ -1,6 +1,6 @@
 -- name: GetUserByID :one
 select
-    u.id, u.username, u.email, u.name, u.created_at, u.updated_at,
+    u.id, u.username, u.email, u.name, u.created_at, u.updated_at, u.email_verified, u.locale,
     coalesce(ut.debit, 0) as debit,
     coalesce(ut.credit, 0) as credit,
     coalesce(ucs.credit_score, 0) as credit_score
@@ -12,7 +12,7 @@ limit 1;
 
 -- name: GetByUsername :one
 select
-    u.id, u.username, u.email, u.name, u.created_at, u.updated_at,
+    u.id, u.username, u.email, u.name, u.created_at, u.updated_at, u.email_verified, u.locale,
     coalesce(ut.debit, 0) as debit,
     coalesce(ut.credit, 0) as credit,
     coalesce(ucs.credit_score, 0) as credit_score
@@ -24,7 +24,7 @@ limit 1;
 
 -- name: GetUserByEmail :one
 select
-    u.id, u.username, u.email, u.name, u.created_at, u.updated_at,
+    u.id, u.username, u.email, u.name, u.created_at, u.updated_at, u.email_verified, u.locale,
     coalesce(ut.debit, 0) as debit,
     coalesce(ut.credit, 0) as credit,
     coalesce(ucs.credit_score, 0) as credit_score
@@ -43,3 +43,5 @@ select exists(select 1 from users where email = $1);
 -- name: GetUserCreditScore :one
 select * from user_credit_score where user_id = $1 limit 1;
 
+-- name: GetUserLocale :one
+select locale from users where id = $1;
@@ -7,6 +7,7 @@ package db
 
 import (
 	"context"
+	"database/sql"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,7 +15,7 @@ import (
 
 const getByUsername = `-- name: GetByUsername :one
 select
-    u.id, u.username, u.email, u.name, u.created_at, u.updated_at,
+    u.id, u.username, u.email, u.name, u.created_at, u.updated_at, u.email_verified, u.locale,
     coalesce(ut.debit, 0) as debit,
     coalesce(ut.credit, 0) as credit,
     coalesce(ucs.credit_score, 0) as credit_score
@@ -26,15 +27,17 @@ limit 1
 `
 
 type GetByUsernameRow struct {
-	ID          uuid.UUID
-	Username    string
-	Email       string
-	Name        string
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
-	Debit       float64
-	Credit      float64
-	CreditScore float64
+	ID            uuid.UUID
+	Username      string
+	Email         string
+	Name          string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	EmailVerified bool
+	Locale        sql.NullString
+	Debit         float64
+	Credit        float64
+	CreditScore   float64
 }
 
 func (q *Queries) GetByUsername(ctx context.Context, username string) (GetByUsernameRow, error) {
@@ -47,6 +50,8 @@ func (q *Queries) GetByUsername(ctx context.Context, username string) (GetByUser
 		&i.Name,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.EmailVerified,
+		&i.Locale,
 		&i.Debit,
 		&i.Credit,
 		&i.CreditScore,
@@ -56,7 +61,7 @@ func (q *Queries) GetByUsername(ctx context.Context, username string) (GetByUser
 
 const getUserByEmail = `-- name: GetUserByEmail :one
 select
-    u.id, u.username, u.email, u.name, u.created_at, u.updated_at,
+    u.id, u.username, u.email, u.name, u.created_at, u.updated_at, u.email_verified, u.locale,
     coalesce(ut.debit, 0) as debit,
     coalesce(ut.credit, 0) as credit,
     coalesce(ucs.credit_score, 0) as credit_score
@@ -68,15 +73,17 @@ limit 1
 `
 
 type GetUserByEmailRow struct {
-	ID          uuid.UUID
-	Username    string
-	Email       string
-	Name        string
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
-	Debit       float64
-	Credit      float64
-	CreditScore float64
+	ID            uuid.UUID
+	Username      string
+	Email         string
+	Name          string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	EmailVerified bool
+	Locale        sql.NullString
+	Debit         float64
+	Credit        float64
+	CreditScore   float64
 }
 
 func (q *Queries) GetUserByEmail(ctx context.Context, email string) (GetUserByEmailRow, error) {
@@ -89,6 +96,8 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (GetUserByEm
 		&i.Name,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.EmailVerified,
+		&i.Locale,
 		&i.Debit,
 		&i.Credit,
 		&i.CreditScore,
@@ -98,7 +107,7 @@ func (q *Queries) GetUserByEmail(ctx context.Context, email string) (GetUserByEm
 
 const getUserByID = `-- name: GetUserByID :one
 select
-    u.id, u.username, u.email, u.name, u.created_at, u.updated_at,
+    u.id, u.username, u.email, u.name, u.created_at, u.updated_at, u.email_verified, u.locale,
     coalesce(ut.debit, 0) as debit,
     coalesce(ut.credit, 0) as credit,
     coalesce(ucs.credit_score, 0) as credit_score
@@ -110,15 +119,17 @@ limit 1
 `
 
 type GetUserByIDRow struct {
-	ID          uuid.UUID
-	Username    string
-	Email       string
-	Name        string
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
-	Debit       float64
-	Credit      float64
-	CreditScore float64
+	ID            uuid.UUID
+	Username      string
+	Email         string
+	Name          string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	EmailVerified bool
+	Locale        sql.NullString
+	Debit         float64
+	Credit        float64
+	CreditScore   float64
 }
 
 func (q *Queries) GetUserByID(ctx context.Context, id uuid.UUID) (GetUserByIDRow, error) {
@@ -131,6 +142,8 @@ func (q *Queries) GetUserByID(ctx context.Context, id uuid.UUID) (GetUserByIDRow
 		&i.Name,
 		&i.CreatedAt,
 		&i.UpdatedAt,
+		&i.EmailVerified,
+		&i.Locale,
 		&i.Debit,
 		&i.Credit,
 		&i.CreditScore,
@@ -158,6 +171,17 @@ func (q *Queries) GetUserCreditScore(ctx context.Context, userID uuid.UUID) (Use
 	return i, err
 }
 
+const getUserLocale = `-- name: GetUserLocale :one
+select locale from users where id = $1
+`
+
+func (q *Queries) GetUserLocale(ctx context.Context, id uuid.UUID) (sql.NullString, error) {
+	row := q.db.QueryRowContext(ctx, getUserLocale, id)
+	var locale sql.NullString
+	err := row.Scan(&locale)
+	return locale, err
+}
+
 const userWithEmailExists = `-- name: UserWithEmailExists :one
 select exists(select 1 from users where email = $1)
 `
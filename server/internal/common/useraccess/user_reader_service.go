@@ -18,6 +18,7 @@ type UserReader interface {
 	GetUserByEmail(ctx context.Context, username string) (*UserResponse, error)
 	UserWithUsernameExists(ctx context.Context, username string) (bool, error)
 	UserWithEmailExists(ctx context.Context, username string) (bool, error)
+	GetUserLocale(ctx context.Context, userID uuid.UUID) (string, error)
 }
 
 type UserReaderService struct {
@@ -37,13 +38,15 @@ type UserAccount struct {
 }
 
 type UserResponse struct {
-	ID        uuid.UUID   `json:"id"`
-	Username  string      `json:"username"`
-	Email     string      `json:"email"`
-	Name      string      `json:"name"`
-	CreatedAt time.Time   `json:"createdAt"`
-	UpdatedAt time.Time   `json:"updatedAt"`
-	Account   UserAccount `json:"account"`
+	ID            uuid.UUID   `json:"id"`
+	Username      string      `json:"username"`
+	Email         string      `json:"email"`
+	EmailVerified bool        `json:"emailVerified"`
+	Name          string      `json:"name"`
+	Locale        string      `json:"locale"`
+	CreatedAt     time.Time   `json:"createdAt"`
+	UpdatedAt     time.Time   `json:"updatedAt"`
+	Account       UserAccount `json:"account"`
 }
 
 func (q *UserReaderService) GetUserByID(ctx context.Context, userID uuid.UUID) (*UserResponse, error) {
@@ -56,12 +59,14 @@ func (q *UserReaderService) GetUserByID(ctx context.Context, userID uuid.UUID) (
 	}
 
 	return &UserResponse{
-		ID:        usr.ID,
-		Username:  usr.Username,
-		Email:     usr.Email,
-		Name:      usr.Name,
-		CreatedAt: usr.CreatedAt,
-		UpdatedAt: usr.UpdatedAt,
+		ID:            usr.ID,
+		Username:      usr.Username,
+		Email:         usr.Email,
+		EmailVerified: usr.EmailVerified,
+		Name:          usr.Name,
+		Locale:        usr.Locale.String,
+		CreatedAt:     usr.CreatedAt,
+		UpdatedAt:     usr.UpdatedAt,
 		Account: UserAccount{
 			Debit:       usr.Debit,
 			Credit:      usr.Credit,
@@ -80,12 +85,14 @@ func (q *UserReaderService) GetUserByUsername(ctx context.Context, username stri
 	}
 
 	return &UserResponse{
-		ID:        usr.ID,
-		Username:  usr.Username,
-		Email:     usr.Email,
-		Name:      usr.Name,
-		CreatedAt: usr.CreatedAt,
-		UpdatedAt: usr.UpdatedAt,
+		ID:            usr.ID,
+		Username:      usr.Username,
+		Email:         usr.Email,
+		EmailVerified: usr.EmailVerified,
+		Name:          usr.Name,
+		Locale:        usr.Locale.String,
+		CreatedAt:     usr.CreatedAt,
+		UpdatedAt:     usr.UpdatedAt,
 		Account: UserAccount{
 			Debit:       usr.Debit,
 			Credit:      usr.Credit,
@@ -104,12 +111,14 @@ func (q *UserReaderService) GetUserByEmail(ctx context.Context, email string) (*
 	}
 
 	return &UserResponse{
-		ID:        usr.ID,
-		Username:  usr.Username,
-		Email:     usr.Email,
-		Name:      usr.Name,
-		CreatedAt: usr.CreatedAt,
-		UpdatedAt: usr.UpdatedAt,
+		ID:            usr.ID,
+		Username:      usr.Username,
+		Email:         usr.Email,
+		EmailVerified: usr.EmailVerified,
+		Name:          usr.Name,
+		Locale:        usr.Locale.String,
+		CreatedAt:     usr.CreatedAt,
+		UpdatedAt:     usr.UpdatedAt,
 		Account: UserAccount{
 			Debit:       usr.Debit,
 			Credit:      usr.Credit,
@@ -125,3 +134,15 @@ func (q *UserReaderService) UserWithUsernameExists(ctx context.Context, username
 func (q *UserReaderService) UserWithEmailExists(ctx context.Context, username string) (bool, error) {
 	return q.Queries.UserWithEmailExists(ctx, username)
 }
+
+// GetUserLocale returns the locale the user prefers, or an empty string if they have not chosen one.
+func (q *UserReaderService) GetUserLocale(ctx context.Context, userID uuid.UUID) (string, error) {
+	locale, err := q.Queries.GetUserLocale(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return "", ErrUserNotFound
+		}
+		return "", fmt.Errorf("failed to fetch locale of user with id %s: %w", userID, err)
+	}
+	return locale.String, nil
+}
@@ -0,0 +1,21 @@
+package handler
+
+import (
+	"beerbux/pkg/email"
+	"net/http"
+)
+
+type ClearMailHandler struct {
+	mailbox *email.Mailbox
+}
+
+func NewClearMailHandler(mailbox *email.Mailbox) *ClearMailHandler {
+	return &ClearMailHandler{
+		mailbox: mailbox,
+	}
+}
+
+func (h *ClearMailHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.mailbox.Clear()
+	w.WriteHeader(http.StatusNoContent)
+}
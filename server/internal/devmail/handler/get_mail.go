@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"beerbux/pkg/email"
+	"beerbux/pkg/send"
+	"net/http"
+)
+
+type GetMailHandler struct {
+	mailbox *email.Mailbox
+}
+
+func NewGetMailHandler(mailbox *email.Mailbox) *GetMailHandler {
+	return &GetMailHandler{
+		mailbox: mailbox,
+	}
+}
+
+// ServeHTTP renders a captured email as it would appear in an email client.
+// The format query parameter can be set to text for the plain-text body or json for
+// the full message including the extracted OTP.
+func (h *GetMailHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	msg, ok := h.mailbox.Get(r.PathValue("id"))
+	if !ok {
+		send.NotFound(w, "Email not found")
+		return
+	}
+
+	switch r.URL.Query().Get("format") {
+	case "json":
+		send.JSON(w, msg, http.StatusOK)
+	case "text":
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		_, _ = w.Write([]byte(msg.Text))
+	default:
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write([]byte(msg.HTML))
+	}
+}
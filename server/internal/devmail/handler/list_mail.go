@@ -0,0 +1,45 @@
+package handler
+
+import (
+	"beerbux/pkg/email"
+	"beerbux/pkg/send"
+	"net/http"
+	"time"
+)
+
+type ListMailHandler struct {
+	mailbox *email.Mailbox
+}
+
+func NewListMailHandler(mailbox *email.Mailbox) *ListMailHandler {
+	return &ListMailHandler{
+		mailbox: mailbox,
+	}
+}
+
+type MailSummaryResponse struct {
+	ID      string    `json:"id"`
+	To      string    `json:"to"`
+	Subject string    `json:"subject"`
+	OTP     string    `json:"otp,omitempty"`
+	SentAt  time.Time `json:"sentAt"`
+}
+
+// ServeHTTP lists the captured emails, newest first. The optional to query parameter
+// filters the emails by recipient, e.g. to find the latest OTP sent to a test user.
+func (h *ListMailHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	messages := h.mailbox.List(r.URL.Query().Get("to"))
+
+	resp := make([]MailSummaryResponse, 0, len(messages))
+	for _, m := range messages {
+		resp = append(resp, MailSummaryResponse{
+			ID:      m.ID,
+			To:      m.To,
+			Subject: m.Subject,
+			OTP:     m.OTP,
+			SentAt:  m.SentAt,
+		})
+	}
+
+	send.JSON(w, resp, http.StatusOK)
+}
@@ -0,0 +1,14 @@
+package handler
+
+import (
+	"beerbux/pkg/email"
+	"net/http"
+)
+
+// BuildRoutes exposes the emails captured by the development mailbox.
+// These routes must only be registered in development.
+func BuildRoutes(mailbox *email.Mailbox, mux *http.ServeMux) {
+	mux.Handle("GET /dev/mail", NewListMailHandler(mailbox))
+	mux.Handle("DELETE /dev/mail", NewClearMailHandler(mailbox))
+	mux.Handle("GET /dev/mail/{id}", NewGetMailHandler(mailbox))
+}
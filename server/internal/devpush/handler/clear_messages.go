@@ -0,0 +1,21 @@
+package handler
+
+import (
+	"beerbux/pkg/webpush"
+	"net/http"
+)
+
+type ClearMessagesHandler struct {
+	service *webpush.DevService
+}
+
+func NewClearMessagesHandler(service *webpush.DevService) *ClearMessagesHandler {
+	return &ClearMessagesHandler{
+		service: service,
+	}
+}
+
+func (h *ClearMessagesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	h.service.Clear()
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,29 @@
+package handler
+
+import (
+	"beerbux/pkg/send"
+	"beerbux/pkg/webpush"
+	"net/http"
+)
+
+type CreateSubscriptionHandler struct {
+	service *webpush.DevService
+}
+
+func NewCreateSubscriptionHandler(service *webpush.DevService) *CreateSubscriptionHandler {
+	return &CreateSubscriptionHandler{
+		service: service,
+	}
+}
+
+// ServeHTTP creates a subscription with the stand-in push service, as a browser would. The
+// response can be registered as is with POST /api/notifications/push/subscriptions.
+func (h *CreateSubscriptionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	subscription, err := h.service.Subscribe(origin(r) + "/dev/push/endpoint")
+	if err != nil {
+		send.InternalServerError(w, "Failed to create the push subscription")
+		return
+	}
+
+	send.JSON(w, subscription, http.StatusCreated)
+}
@@ -0,0 +1,27 @@
+package handler
+
+import (
+	"beerbux/pkg/send"
+	"beerbux/pkg/webpush"
+	"net/http"
+)
+
+type ExpireSubscriptionHandler struct {
+	service *webpush.DevService
+}
+
+func NewExpireSubscriptionHandler(service *webpush.DevService) *ExpireSubscriptionHandler {
+	return &ExpireSubscriptionHandler{
+		service: service,
+	}
+}
+
+// ServeHTTP expires a subscription, so that messages sent to it are rejected with 410 Gone
+// and the API deletes it.
+func (h *ExpireSubscriptionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !h.service.Expire(r.PathValue("id")) {
+		send.NotFound(w, "Push subscription not found")
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
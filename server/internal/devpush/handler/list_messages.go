@@ -0,0 +1,23 @@
+package handler
+
+import (
+	"beerbux/pkg/send"
+	"beerbux/pkg/webpush"
+	"net/http"
+)
+
+type ListMessagesHandler struct {
+	service *webpush.DevService
+}
+
+func NewListMessagesHandler(service *webpush.DevService) *ListMessagesHandler {
+	return &ListMessagesHandler{
+		service: service,
+	}
+}
+
+// ServeHTTP lists the decrypted messages, newest first. The optional subscription query
+// parameter filters the messages by the ID of the subscription they were sent to.
+func (h *ListMessagesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	send.JSON(w, h.service.List(r.URL.Query().Get("subscription")), http.StatusOK)
+}
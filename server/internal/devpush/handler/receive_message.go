@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"beerbux/pkg/send"
+	"beerbux/pkg/webpush"
+	"errors"
+	"net/http"
+)
+
+type ReceiveMessageHandler struct {
+	service *webpush.DevService
+}
+
+func NewReceiveMessageHandler(service *webpush.DevService) *ReceiveMessageHandler {
+	return &ReceiveMessageHandler{
+		service: service,
+	}
+}
+
+// ServeHTTP is the push endpoint of a subscription, responding as a push service would.
+func (h *ReceiveMessageHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	msg, err := h.service.Receive(r.PathValue("id"), origin(r), r)
+	if err != nil {
+		switch {
+		case errors.Is(err, webpush.ErrUnknownSubscription):
+			send.NotFound(w, err.Error())
+		case errors.Is(err, webpush.ErrSubscriptionGone):
+			send.Error(w, err.Error(), http.StatusGone)
+		case errors.Is(err, webpush.ErrInvalidVAPIDAuthorization):
+			send.Unauthorized(w, err.Error())
+		case errors.Is(err, webpush.ErrPayloadTooLarge):
+			send.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+		default:
+			send.BadRequest(w, err.Error())
+		}
+		return
+	}
+
+	w.Header().Set("Location", origin(r)+"/dev/push/messages?subscription="+msg.SubscriptionID)
+	w.WriteHeader(http.StatusCreated)
+}
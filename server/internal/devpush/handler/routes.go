@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"beerbux/pkg/webpush"
+	"net/http"
+)
+
+// BuildRoutes exposes the development stand-in push service.
+// These routes must only be registered in development.
+func BuildRoutes(service *webpush.DevService, mux *http.ServeMux) {
+	mux.Handle("POST /dev/push/subscriptions", NewCreateSubscriptionHandler(service))
+	mux.Handle("DELETE /dev/push/subscriptions/{id}", NewExpireSubscriptionHandler(service))
+	mux.Handle("POST /dev/push/endpoint/{id}", NewReceiveMessageHandler(service))
+	mux.Handle("GET /dev/push/messages", NewListMessagesHandler(service))
+	mux.Handle("DELETE /dev/push/messages", NewClearMessagesHandler(service))
+}
+
+// origin returns the origin the request was made to, which push endpoints are created under.
+func origin(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	return scheme + "://" + r.Host
+}
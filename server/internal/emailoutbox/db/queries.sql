@@ -0,0 +1,89 @@
+-- name: EnqueueEmail :one
+insert into email_outbox (recipient, subject, html, template, locale, template_data, headers)
+values ($1, $2, $3, $4, $5, $6, $7)
+returning id;
+
+-- name: ClaimDueEmails :many
+update email_outbox
+set status = 'sending',
+    attempts = attempts + 1,
+    next_attempt_at = now() + make_interval(secs => @lease_seconds::int)
+where id in (
+    select id
+    from email_outbox
+    where status in ('pending', 'sending')
+      and next_attempt_at <= now()
+    order by next_attempt_at
+    limit @batch_size
+    for update skip locked
+)
+returning id, recipient, subject, html, template, locale, template_data, headers, attempts;
+
+-- name: MarkEmailSent :exec
+update email_outbox
+set status = 'sent',
+    provider_message_id = $2,
+    last_error = null,
+    sent_at = now(),
+    html = null,
+    template_data = null
+where id = $1;
+
+-- name: MarkEmailForRetry :exec
+update email_outbox
+set status = 'pending',
+    last_error = $2,
+    next_attempt_at = $3
+where id = $1;
+
+-- name: MarkEmailFailed :exec
+update email_outbox
+set status = 'failed',
+    last_error = $2,
+    html = null
+where id = $1;
+
+-- name: CreateEmailDeliveryAttempt :exec
+insert into email_delivery_attempts (email_id, attempt, succeeded, provider_message_id, error)
+values ($1, $2, $3, $4, $5);
+
+-- name: GetEmail :one
+select *
+from email_outbox
+where id = $1;
+
+-- name: ListEmails :many
+select *
+from email_outbox
+where sqlc.narg('status')::text is null
+   or status = sqlc.narg('status')::text
+order by created_at desc
+limit @max_results;
+
+-- name: ListEmailDeliveryAttempts :many
+select *
+from email_delivery_attempts
+where email_id = $1
+order by attempt;
+
+-- name: ResendEmail :execrows
+update email_outbox
+set status = 'pending',
+    attempts = 0,
+    next_attempt_at = now()
+where id = $1
+  and status = 'failed'
+  and template is not null;
+
+-- name: ResendFailedEmails :execrows
+update email_outbox
+set status = 'pending',
+    attempts = 0,
+    next_attempt_at = now()
+where status = 'failed'
+  and template is not null;
+
+-- name: PurgeEmails :execrows
+delete from email_outbox
+where status in ('sent', 'failed')
+  and updated_at < now() - make_interval(days => @retention_days::int);
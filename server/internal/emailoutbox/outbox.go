@@ -0,0 +1,132 @@
+package emailoutbox
+
+import (
+	"beerbux/internal/emailoutbox/db"
+	"beerbux/pkg/email"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/sqlc-dev/pqtype"
+	"time"
+)
+
+var ErrEmailNotFailed = errors.New("email not found, has not failed or cannot be resent")
+
+// enqueueTimeout bounds how long queueing an email may take, as Send has no context.
+const enqueueTimeout = 10 * time.Second
+
+// Outbox is an email.Sender that stores emails in the email_outbox table to be delivered by
+// the Worker, so a failing email provider neither fails nor slows down the request sending it.
+//
+// Emails sent with email.SendTemplate are stored as their template and data rather than the
+// generated HTML, which the Worker generates when delivering them. The body of an email is
+// cleared once it has been sent, but the data of failed emails is kept so that they can be
+// generated again when resent. Templates that are not storable, as they contain secrets, are
+// stored as HTML like emails sent with Send, which is cleared once the email is sent or has
+// failed, so they are never resent.
+type Outbox struct {
+	queries *db.Queries
+	notify  chan struct{}
+}
+
+func New(queries *db.Queries) *Outbox {
+	return &Outbox{
+		queries: queries,
+		notify:  make(chan struct{}, 1),
+	}
+}
+
+// Send queues the email and returns its outbox ID.
+func (o *Outbox) Send(to, subject, html string) (string, error) {
+	return o.enqueue(db.EnqueueEmailParams{
+		Recipient: to,
+		Subject:   subject,
+		Html:      sql.NullString{String: html, Valid: true},
+	})
+}
+
+// SendTemplate queues the email to be generated from the template when it is delivered and
+// returns its outbox ID.
+func (o *Outbox) SendTemplate(to, subject string, tmpl email.Template) (string, error) {
+	// Generate the email up front so that a broken template fails the caller rather than the delivery.
+	html, err := tmpl.Generate()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate email: %w", err)
+	}
+
+	params := db.EnqueueEmailParams{
+		Recipient: to,
+		Subject:   subject,
+	}
+	if len(tmpl.Headers) > 0 {
+		headers, err := json.Marshal(tmpl.Headers)
+		if err != nil {
+			return "", fmt.Errorf("failed to encode email headers: %w", err)
+		}
+		params.Headers = pqtype.NullRawMessage{RawMessage: headers, Valid: true}
+	}
+
+	if !tmpl.Storable() {
+		params.Html = sql.NullString{String: html, Valid: true}
+		return o.enqueue(params)
+	}
+
+	data, err := json.Marshal(tmpl.Data)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode email template data: %w", err)
+	}
+	params.Template = sql.NullString{String: tmpl.Name, Valid: true}
+	params.Locale = sql.NullString{String: tmpl.Locale, Valid: tmpl.Locale != ""}
+	params.TemplateData = pqtype.NullRawMessage{RawMessage: data, Valid: true}
+
+	return o.enqueue(params)
+}
+
+func (o *Outbox) enqueue(params db.EnqueueEmailParams) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), enqueueTimeout)
+	defer cancel()
+
+	id, err := o.queries.EnqueueEmail(ctx, params)
+	if err != nil {
+		return "", fmt.Errorf("failed to queue email: %w", err)
+	}
+
+	// Wake the worker without blocking if it has already been woken.
+	select {
+	case o.notify <- struct{}{}:
+	default:
+	}
+
+	return id.String(), nil
+}
+
+// Queued signals when emails have been queued since the last signal was received.
+func (o *Outbox) Queued() <-chan struct{} {
+	return o.notify
+}
+
+// Resend queues a failed email to be generated from its template and delivered again with a
+// fresh set of attempts. Emails that were not stored as a template, including every email
+// containing a one-time password or link, cannot be resent, as their body is not kept.
+func (o *Outbox) Resend(ctx context.Context, id uuid.UUID) error {
+	rowsAffected, err := o.queries.ResendEmail(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to resend email: %w", err)
+	}
+	if rowsAffected == 0 {
+		return ErrEmailNotFailed
+	}
+	return nil
+}
+
+// ResendFailed queues every failed email that can be resent to be delivered again, returning how many were queued.
+func (o *Outbox) ResendFailed(ctx context.Context) (int64, error) {
+	n, err := o.queries.ResendFailedEmails(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to resend failed emails: %w", err)
+	}
+	return n, nil
+}
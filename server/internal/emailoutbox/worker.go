@@ -0,0 +1,152 @@
+package emailoutbox
+
+import (
+	"beerbux/internal/common/outbox"
+	"beerbux/internal/emailoutbox/db"
+	"beerbux/pkg/email"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+const (
+	// MaxAttempts is the number of delivery attempts before an email is marked as failed.
+	MaxAttempts = 8
+	// RetentionDays is how long sent and failed emails are kept, along with their delivery log.
+	RetentionDays = 30
+	// pollInterval is how often the outbox is checked for due emails when the worker is not woken.
+	pollInterval = 15 * time.Second
+	// purgeInterval is how often emails older than RetentionDays are deleted.
+	purgeInterval = time.Hour
+	// batchSize is the number of emails claimed at a time.
+	batchSize = 10
+	// leaseDuration is how long a claimed email is reserved for the worker that claimed it.
+	// Emails left sending when a worker stops are retried once the lease expires.
+	leaseDuration = 5 * time.Minute
+)
+
+var retryBackoff = outbox.Backoff{Base: 30 * time.Second, Max: 6 * time.Hour}
+
+// Worker delivers queued emails with the provider Sender, retrying failures with
+// exponential backoff and recording every attempt in the delivery log.
+type Worker struct {
+	queries  *db.Queries
+	provider email.Sender
+	queued   <-chan struct{}
+	logger   *slog.Logger
+}
+
+func NewWorker(queries *db.Queries, provider email.Sender, queued <-chan struct{}, logger *slog.Logger) *Worker {
+	return &Worker{
+		queries:  queries,
+		provider: provider,
+		queued:   queued,
+		logger:   logger,
+	}
+}
+
+// Run delivers emails until ctx is cancelled.
+func (w *Worker) Run(ctx context.Context) {
+	outbox.Poller{
+		Interval:      pollInterval,
+		Wake:          w.queued,
+		BatchSize:     batchSize,
+		DeliverBatch:  w.deliverBatch,
+		PurgeInterval: purgeInterval,
+		Purge:         w.purge,
+	}.Run(ctx)
+}
+
+func (w *Worker) deliverBatch(ctx context.Context) int {
+	emails, err := w.queries.ClaimDueEmails(ctx, db.ClaimDueEmailsParams{
+		LeaseSeconds: int32(leaseDuration / time.Second),
+		BatchSize:    batchSize,
+	})
+	if err != nil {
+		w.logger.Error("failed to claim queued emails", "error", err)
+		return 0
+	}
+
+	for _, e := range emails {
+		w.deliver(ctx, e)
+	}
+	return len(emails)
+}
+
+func (w *Worker) deliver(ctx context.Context, e db.ClaimDueEmailsRow) {
+	providerID, sendErr := w.send(e)
+
+	err := w.queries.CreateEmailDeliveryAttempt(ctx, db.CreateEmailDeliveryAttemptParams{
+		EmailID:           e.ID,
+		Attempt:           e.Attempts,
+		Succeeded:         sendErr == nil,
+		ProviderMessageID: nullString(providerID),
+		Error:             outbox.ErrorString(sendErr),
+	})
+	if err != nil {
+		w.logger.Error("failed to record email delivery attempt", "email", e.ID, "error", err)
+	}
+
+	switch {
+	case sendErr == nil:
+		err = w.queries.MarkEmailSent(ctx, db.MarkEmailSentParams{
+			ID:                e.ID,
+			ProviderMessageID: nullString(providerID),
+		})
+	case e.Attempts >= MaxAttempts:
+		w.logger.Error("giving up sending email", "email", e.ID, "attempts", e.Attempts, "error", sendErr)
+		err = w.queries.MarkEmailFailed(ctx, db.MarkEmailFailedParams{
+			ID:        e.ID,
+			LastError: outbox.ErrorString(sendErr),
+		})
+	default:
+		delay := retryBackoff.Delay(int(e.Attempts))
+		w.logger.Warn("failed to send email, retrying", "email", e.ID, "attempt", e.Attempts, "retryIn", delay, "error", sendErr)
+		err = w.queries.MarkEmailForRetry(ctx, db.MarkEmailForRetryParams{
+			ID:            e.ID,
+			LastError:     outbox.ErrorString(sendErr),
+			NextAttemptAt: time.Now().Add(delay),
+		})
+	}
+	if err != nil {
+		w.logger.Error("failed to update queued email", "email", e.ID, "error", err)
+	}
+}
+
+// send generates the email from its template, if it has one, and sends it with the provider.
+func (w *Worker) send(e db.ClaimDueEmailsRow) (string, error) {
+	html := e.Html.String
+	if e.Template.Valid {
+		tmpl, err := email.DecodeTemplate(e.Template.String, e.Locale.String, e.TemplateData.RawMessage)
+		if err != nil {
+			return "", err
+		}
+		if html, err = tmpl.Generate(); err != nil {
+			return "", fmt.Errorf("failed to generate email: %w", err)
+		}
+	}
+
+	var headers map[string]string
+	if e.Headers.Valid {
+		if err := json.Unmarshal(e.Headers.RawMessage, &headers); err != nil {
+			return "", fmt.Errorf("failed to decode email headers: %w", err)
+		}
+	}
+	return email.SendWithHeaders(w.provider, e.Recipient, e.Subject, html, headers)
+}
+
+func (w *Worker) purge(ctx context.Context) {
+	deleted, err := w.queries.PurgeEmails(ctx, RetentionDays)
+	if err != nil {
+		w.logger.Error("failed to purge emails", "error", err)
+		return
+	}
+	w.logger.Debug("purged emails", "deleted", deleted)
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
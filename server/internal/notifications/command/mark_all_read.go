@@ -0,0 +1,27 @@
+package command
+
+import (
+	"beerbux/internal/notifications/db"
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+)
+
+type MarkAllReadCommand struct {
+	queries *db.Queries
+}
+
+func NewMarkAllReadCommand(queries *db.Queries) *MarkAllReadCommand {
+	return &MarkAllReadCommand{
+		queries: queries,
+	}
+}
+
+// Execute marks all the user's unread notifications as read and returns how many were marked.
+func (c *MarkAllReadCommand) Execute(ctx context.Context, userID uuid.UUID) (int64, error) {
+	updated, err := c.queries.MarkAllNotificationsRead(ctx, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark notifications as read: %w", err)
+	}
+	return updated, nil
+}
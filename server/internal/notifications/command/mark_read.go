@@ -0,0 +1,37 @@
+package command
+
+import (
+	"beerbux/internal/notifications/db"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+)
+
+var ErrNotificationNotFound = errors.New("notification not found")
+
+type MarkReadCommand struct {
+	queries *db.Queries
+}
+
+func NewMarkReadCommand(queries *db.Queries) *MarkReadCommand {
+	return &MarkReadCommand{
+		queries: queries,
+	}
+}
+
+// Execute marks one of the user's notifications as read. Marking a notification that has
+// already been read keeps the time it was first read.
+func (c *MarkReadCommand) Execute(ctx context.Context, userID, notificationID uuid.UUID) error {
+	updated, err := c.queries.MarkNotificationRead(ctx, db.MarkNotificationReadParams{
+		ID:     notificationID,
+		UserID: userID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to mark notification as read: %w", err)
+	}
+	if updated == 0 {
+		return ErrNotificationNotFound
+	}
+	return nil
+}
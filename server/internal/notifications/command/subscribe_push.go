@@ -0,0 +1,76 @@
+package command
+
+import (
+	"beerbux/internal/notifications/db"
+	"beerbux/pkg/safehttp"
+	"beerbux/pkg/webpush"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"net/url"
+	"time"
+)
+
+var ErrInvalidPushSubscription = errors.New("invalid push subscription")
+
+type SubscribePushCommand struct {
+	queries *db.Queries
+	// allowInsecureEndpoints permits http and local push endpoints, such as the development stand-in push service.
+	allowInsecureEndpoints bool
+}
+
+func NewSubscribePushCommand(queries *db.Queries, allowInsecureEndpoints bool) *SubscribePushCommand {
+	return &SubscribePushCommand{
+		queries:                queries,
+		allowInsecureEndpoints: allowInsecureEndpoints,
+	}
+}
+
+type SubscribePushRequest struct {
+	Endpoint  string
+	P256DH    string
+	Auth      string
+	ExpiresAt *time.Time
+	UserAgent string
+}
+
+// Execute saves the browser's push subscription for the user. A subscription that has been
+// saved before is moved to the user, as the browser keeps the same endpoint when another
+// user logs in.
+func (c *SubscribePushCommand) Execute(ctx context.Context, userID uuid.UUID, r SubscribePushRequest) (uuid.UUID, error) {
+	endpoint, err := url.Parse(r.Endpoint)
+	if err != nil || (endpoint.Scheme != "https" && !(c.allowInsecureEndpoints && endpoint.Scheme == "http")) {
+		return uuid.Nil, ErrInvalidPushSubscription
+	}
+	if !c.allowInsecureEndpoints && !safehttp.IsPublicHost(endpoint.Hostname()) {
+		return uuid.Nil, ErrInvalidPushSubscription
+	}
+	subscription := webpush.Subscription{
+		Endpoint: r.Endpoint,
+		P256DH:   r.P256DH,
+		Auth:     r.Auth,
+	}
+	if err := subscription.Validate(); err != nil {
+		return uuid.Nil, ErrInvalidPushSubscription
+	}
+
+	var expiresAt sql.NullTime
+	if r.ExpiresAt != nil {
+		expiresAt = sql.NullTime{Time: *r.ExpiresAt, Valid: true}
+	}
+
+	id, err := c.queries.UpsertPushSubscription(ctx, db.UpsertPushSubscriptionParams{
+		UserID:    userID,
+		Endpoint:  r.Endpoint,
+		P256dh:    r.P256DH,
+		Auth:      r.Auth,
+		UserAgent: sql.NullString{String: r.UserAgent, Valid: r.UserAgent != ""},
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to save push subscription: %w", err)
+	}
+	return id, nil
+}
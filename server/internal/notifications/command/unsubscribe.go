@@ -0,0 +1,63 @@
+package command
+
+import (
+	"beerbux/internal/notifications/db"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+)
+
+// The lists of notification emails that can be unsubscribed from.
+const (
+	ListWeeklyDigest      = "weekly_digest"
+	ListSettleUpReminders = "settle_up_reminders"
+	ListAll               = "all"
+)
+
+var (
+	ErrUnsubscribeTokenInvalid = errors.New("unsubscribe token invalid")
+	ErrUnknownList             = errors.New("unknown notification list")
+)
+
+type UnsubscribeCommand struct {
+	queries *db.Queries
+}
+
+func NewUnsubscribeCommand(queries *db.Queries) *UnsubscribeCommand {
+	return &UnsubscribeCommand{
+		queries: queries,
+	}
+}
+
+// Execute opts the owner of the unsubscribe token out of the given list, or out of every
+// list when list is ListAll. The token is sent in every notification email so that users
+// can unsubscribe without logging in.
+func (c *UnsubscribeCommand) Execute(ctx context.Context, token, list string) error {
+	unsubscribeToken, err := uuid.Parse(token)
+	if err != nil {
+		return ErrUnsubscribeTokenInvalid
+	}
+
+	params := db.UnsubscribeParams{UnsubscribeToken: unsubscribeToken}
+	switch list {
+	case ListWeeklyDigest:
+		params.WeeklyDigest = true
+	case ListSettleUpReminders:
+		params.SettleUpReminders = true
+	case ListAll:
+		params.WeeklyDigest = true
+		params.SettleUpReminders = true
+	default:
+		return ErrUnknownList
+	}
+
+	updated, err := c.queries.Unsubscribe(ctx, params)
+	if err != nil {
+		return fmt.Errorf("failed to unsubscribe: %w", err)
+	}
+	if updated == 0 {
+		return ErrUnsubscribeTokenInvalid
+	}
+	return nil
+}
@@ -0,0 +1,36 @@
+package command
+
+import (
+	"beerbux/internal/notifications/db"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+)
+
+var ErrPushSubscriptionNotFound = errors.New("push subscription not found")
+
+type UnsubscribePushCommand struct {
+	queries *db.Queries
+}
+
+func NewUnsubscribePushCommand(queries *db.Queries) *UnsubscribePushCommand {
+	return &UnsubscribePushCommand{
+		queries: queries,
+	}
+}
+
+// Execute deletes the user's push subscription with the given endpoint.
+func (c *UnsubscribePushCommand) Execute(ctx context.Context, userID uuid.UUID, endpoint string) error {
+	deleted, err := c.queries.DeletePushSubscription(ctx, db.DeletePushSubscriptionParams{
+		UserID:   userID,
+		Endpoint: endpoint,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete push subscription: %w", err)
+	}
+	if deleted == 0 {
+		return ErrPushSubscriptionNotFound
+	}
+	return nil
+}
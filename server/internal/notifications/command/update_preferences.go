@@ -0,0 +1,53 @@
+package command
+
+import (
+	"beerbux/internal/notifications/db"
+	"beerbux/internal/notifications/query"
+	"context"
+	"database/sql"
+	"github.com/google/uuid"
+)
+
+type UpdatePreferencesCommand struct {
+	queries *db.Queries
+}
+
+func NewUpdatePreferencesCommand(queries *db.Queries) *UpdatePreferencesCommand {
+	return &UpdatePreferencesCommand{
+		queries: queries,
+	}
+}
+
+type UpdatePreferencesRequest struct {
+	WeeklyDigest      bool
+	SettleUpReminders bool
+	// The push preferences are left unchanged when nil.
+	PushInvitations    *bool
+	PushRounds         *bool
+	PushSessionUpdates *bool
+}
+
+// Execute saves the user's notification preferences. The first weekly digest after opting
+// in is sent a week later so that it covers a full week.
+func (c *UpdatePreferencesCommand) Execute(ctx context.Context, userID uuid.UUID, r UpdatePreferencesRequest) (*query.PreferencesResponse, error) {
+	prefs, err := c.queries.UpsertNotificationPreferences(ctx, db.UpsertNotificationPreferencesParams{
+		UserID:             userID,
+		WeeklyDigest:       r.WeeklyDigest,
+		SettleUpReminders:  r.SettleUpReminders,
+		PushInvitations:    nullBool(r.PushInvitations),
+		PushRounds:         nullBool(r.PushRounds),
+		PushSessionUpdates: nullBool(r.PushSessionUpdates),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return query.NewPreferencesResponse(prefs), nil
+}
+
+func nullBool(b *bool) sql.NullBool {
+	if b == nil {
+		return sql.NullBool{}
+	}
+	return sql.NullBool{Bool: *b, Valid: true}
+}
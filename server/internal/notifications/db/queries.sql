@@ -0,0 +1,201 @@
+-- name: GetNotificationPreferences :one
+select * from user_notification_preferences where user_id = $1 limit 1;
+
+-- name: UpsertNotificationPreferences :one
+insert into user_notification_preferences (user_id, weekly_digest, settle_up_reminders, push_invitations, push_rounds, push_session_updates)
+values (
+    @user_id,
+    @weekly_digest,
+    @settle_up_reminders,
+    coalesce(sqlc.narg(push_invitations), true),
+    coalesce(sqlc.narg(push_rounds), true),
+    coalesce(sqlc.narg(push_session_updates), true)
+)
+on conflict (user_id) do update
+set weekly_digest = excluded.weekly_digest,
+    settle_up_reminders = excluded.settle_up_reminders,
+    push_invitations = coalesce(sqlc.narg(push_invitations), user_notification_preferences.push_invitations),
+    push_rounds = coalesce(sqlc.narg(push_rounds), user_notification_preferences.push_rounds),
+    push_session_updates = coalesce(sqlc.narg(push_session_updates), user_notification_preferences.push_session_updates),
+    next_digest_at = case
+        when user_notification_preferences.weekly_digest then user_notification_preferences.next_digest_at
+        else now() + interval '7 days'
+    end
+returning *;
+
+-- name: Unsubscribe :execrows
+update user_notification_preferences
+set weekly_digest = weekly_digest and not @weekly_digest::bool,
+    settle_up_reminders = settle_up_reminders and not @settle_up_reminders::bool
+where unsubscribe_token = @unsubscribe_token;
+
+-- name: ClaimDueDigests :many
+-- ClaimDueDigests schedules the next digest for a batch of users whose weekly digest is due
+-- and returns them, skipping rows claimed by other instances.
+update user_notification_preferences p
+set next_digest_at = now() + interval '7 days'
+from users u
+where u.id = p.user_id
+    and p.user_id in (
+        select dp.user_id
+        from user_notification_preferences dp
+            join users du on du.id = dp.user_id
+        where dp.weekly_digest
+            and dp.next_digest_at <= now()
+            and du.email_verified
+        order by dp.next_digest_at
+        limit @batch_size
+        for update of dp skip locked
+    )
+returning p.user_id, p.unsubscribe_token, u.username, u.email, u.locale;
+
+-- name: ClaimDueSettleUpReminders :many
+-- ClaimDueSettleUpReminders records a reminder for a batch of users who owe more than the threshold
+-- and have not been reminded since reminded_before, and returns them.
+update user_notification_preferences p
+set last_reminder_sent_at = now()
+from users u
+where u.id = p.user_id
+    and p.user_id in (
+        select rp.user_id
+        from user_notification_preferences rp
+            join users ru on ru.id = rp.user_id
+            join user_totals ut on ut.user_id = rp.user_id
+        where rp.settle_up_reminders
+            and ru.email_verified
+            and ut.credit - ut.debit > @threshold::numeric
+            and (rp.last_reminder_sent_at is null or rp.last_reminder_sent_at <= @reminded_before)
+        order by rp.user_id
+        limit @batch_size
+        for update of rp skip locked
+    )
+returning p.user_id, p.unsubscribe_token, u.username, u.email, u.locale;
+
+-- name: GetDigestActivity :one
+-- GetDigestActivity summarises the rounds the user bought and received since the given time.
+select
+    count(distinct st.id) filter (where st.member_id = @user_id) as rounds_bought,
+    coalesce(sum(stl.amount) filter (where st.member_id = @user_id), 0)::float8 as beers_bought,
+    count(distinct st.id) filter (where stl.member_id = @user_id) as rounds_received,
+    coalesce(sum(stl.amount) filter (where stl.member_id = @user_id), 0)::float8 as beers_received
+from session_transactions st
+    join session_transaction_lines stl on stl.transaction_id = st.id
+where st.created_at >= @since
+    and (st.member_id = @user_id or stl.member_id = @user_id);
+
+-- name: ListDigestSessions :many
+-- ListDigestSessions returns the sessions in which the user bought or received a round since the given time.
+select s.id, s.name, count(distinct st.id) as round_count
+from sessions s
+    join session_transactions st on st.session_id = s.id
+    join session_transaction_lines stl on stl.transaction_id = st.id
+where st.created_at >= @since
+    and (st.member_id = @user_id or stl.member_id = @user_id)
+group by s.id, s.name
+order by max(st.created_at) desc;
+
+-- name: ListFriendBalances :many
+-- ListFriendBalances returns the number of beers the user owes each friend they have an outstanding balance with.
+-- A negative balance means that the friend owes the user.
+with balances as (
+    select st.member_id as friend_id, stl.amount
+    from session_transaction_lines stl
+        join session_transactions st on st.id = stl.transaction_id
+    where stl.member_id = @user_id
+    union all
+    select stl.member_id as friend_id, -stl.amount
+    from session_transaction_lines stl
+        join session_transactions st on st.id = stl.transaction_id
+    where st.member_id = @user_id
+)
+select u.id, u.username, u.name, sum(b.amount)::float8 as balance
+from balances b
+    join users u on u.id = b.friend_id
+group by u.id, u.username, u.name
+having sum(b.amount) <> 0
+order by abs(sum(b.amount)) desc, u.username;
+
+-- name: CreateNotification :one
+insert into notifications (user_id, type, session_id, actor_id, transaction_id)
+values ($1, $2, $3, $4, $5)
+returning id;
+
+-- name: GetNotification :one
+select n.id, n.type, n.session_id, s.name as session_name, n.actor_id, a.name as actor_name, a.username as actor_username,
+    n.transaction_id, coalesce(l.amount, 0)::float as amount, n.read_at, n.created_at
+from notifications n
+    join sessions s on s.id = n.session_id
+    join users a on a.id = n.actor_id
+    left join session_transaction_lines l on l.transaction_id = n.transaction_id and l.member_id = n.user_id
+where n.id = $1;
+
+-- name: ListNotifications :many
+select n.id, n.type, n.session_id, s.name as session_name, n.actor_id, a.name as actor_name, a.username as actor_username,
+    n.transaction_id, coalesce(l.amount, 0)::float as amount, n.read_at, n.created_at
+from notifications n
+    join sessions s on s.id = n.session_id
+    join users a on a.id = n.actor_id
+    left join session_transaction_lines l on l.transaction_id = n.transaction_id and l.member_id = n.user_id
+where n.user_id = @user_id
+    and (not @unread_only::bool or n.read_at is null)
+    and (sqlc.narg(before)::uuid is null or (n.created_at, n.id) < (
+        select b.created_at, b.id from notifications b where b.id = sqlc.narg(before)::uuid
+    ))
+order by n.created_at desc, n.id desc
+limit @page_size;
+
+-- name: CountUnreadNotifications :one
+select count(*) from notifications where user_id = $1 and read_at is null;
+
+-- name: MarkNotificationRead :execrows
+update notifications
+set read_at = coalesce(read_at, now())
+where id = $1 and user_id = $2;
+
+-- name: MarkAllNotificationsRead :execrows
+update notifications
+set read_at = now()
+where user_id = $1 and read_at is null;
+
+-- name: ListSessionMemberIDs :many
+select member_id
+from session_members
+where session_id = $1 and is_deleted = false;
+
+-- name: UpsertPushSubscription :one
+insert into push_subscriptions (user_id, endpoint, p256dh, auth, user_agent, expires_at)
+values ($1, $2, $3, $4, $5, $6)
+on conflict (endpoint) do update
+set user_id = excluded.user_id,
+    p256dh = excluded.p256dh,
+    auth = excluded.auth,
+    user_agent = excluded.user_agent,
+    expires_at = excluded.expires_at
+returning id;
+
+-- name: DeletePushSubscription :execrows
+delete from push_subscriptions where user_id = $1 and endpoint = $2;
+
+-- name: DeletePushSubscriptionByID :exec
+delete from push_subscriptions where id = $1;
+
+-- name: ListPushSubscriptions :many
+select id, endpoint, p256dh, auth
+from push_subscriptions
+where user_id = $1 and (expires_at is null or expires_at > now());
+
+-- name: GetPushRecipient :one
+select
+    u.locale,
+    coalesce(p.push_invitations, true)::bool as push_invitations,
+    coalesce(p.push_rounds, true)::bool as push_rounds,
+    coalesce(p.push_session_updates, true)::bool as push_session_updates
+from users u
+    left join user_notification_preferences p on p.user_id = u.id
+where u.id = $1;
+
+-- name: MarkPushSubscriptionUsed :exec
+update push_subscriptions set last_used_at = now() where id = $1;
+
+-- name: PurgeExpiredPushSubscriptions :execrows
+delete from push_subscriptions where expires_at <= now();
@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"beerbux/internal/common/claims"
+	"beerbux/internal/notifications/query"
+	"beerbux/pkg/send"
+	"log/slog"
+	"net/http"
+)
+
+type GetPreferencesHandler struct {
+	getPreferencesQuery *query.GetPreferencesQuery
+	logger              *slog.Logger
+}
+
+func NewGetPreferencesHandler(getPreferencesQuery *query.GetPreferencesQuery, logger *slog.Logger) *GetPreferencesHandler {
+	return &GetPreferencesHandler{
+		getPreferencesQuery: getPreferencesQuery,
+		logger:              logger,
+	}
+}
+
+func (h *GetPreferencesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c := claims.GetClaims(r)
+	if !c.Authenticated() {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	prefs, err := h.getPreferencesQuery.Execute(r.Context(), c.Subject)
+	if err != nil {
+		h.logger.Error("failed to get notification preferences", "user", c.Subject, "error", err)
+		send.InternalServerError(w, "There has been an issue fetching your notification preferences")
+		return
+	}
+
+	send.JSON(w, prefs, http.StatusOK)
+}
@@ -0,0 +1,26 @@
+package handler
+
+import (
+	"beerbux/pkg/send"
+	"net/http"
+)
+
+type GetPushPublicKeyHandler struct {
+	publicKey string
+}
+
+func NewGetPushPublicKeyHandler(publicKey string) *GetPushPublicKeyHandler {
+	return &GetPushPublicKeyHandler{
+		publicKey: publicKey,
+	}
+}
+
+type PushPublicKeyResponse struct {
+	PublicKey string `json:"publicKey"`
+}
+
+// ServeHTTP returns the VAPID public key, which the browser needs as the applicationServerKey
+// when subscribing to push notifications.
+func (h *GetPushPublicKeyHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	send.JSON(w, PushPublicKeyResponse{PublicKey: h.publicKey}, http.StatusOK)
+}
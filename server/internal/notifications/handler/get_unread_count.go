@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"beerbux/internal/common/claims"
+	"beerbux/internal/notifications/query"
+	"beerbux/pkg/send"
+	"log/slog"
+	"net/http"
+)
+
+type GetUnreadCountHandler struct {
+	getUnreadCountQuery *query.GetUnreadCountQuery
+	logger              *slog.Logger
+}
+
+func NewGetUnreadCountHandler(getUnreadCountQuery *query.GetUnreadCountQuery, logger *slog.Logger) *GetUnreadCountHandler {
+	return &GetUnreadCountHandler{
+		getUnreadCountQuery: getUnreadCountQuery,
+		logger:              logger,
+	}
+}
+
+func (h *GetUnreadCountHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c := claims.GetClaims(r)
+	if !c.Authenticated() {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	count, err := h.getUnreadCountQuery.Execute(r.Context(), c.Subject)
+	if err != nil {
+		h.logger.Error("failed to count unread notifications", "user", c.Subject, "error", err)
+		send.InternalServerError(w, "There has been an issue fetching your notifications")
+		return
+	}
+
+	send.JSON(w, count, http.StatusOK)
+}
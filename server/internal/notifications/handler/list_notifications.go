@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"beerbux/internal/common/claims"
+	"beerbux/internal/notifications/query"
+	"beerbux/pkg/send"
+	"github.com/google/uuid"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type ListNotificationsHandler struct {
+	listNotificationsQuery *query.ListNotificationsQuery
+	logger                 *slog.Logger
+}
+
+func NewListNotificationsHandler(listNotificationsQuery *query.ListNotificationsQuery, logger *slog.Logger) *ListNotificationsHandler {
+	return &ListNotificationsHandler{
+		listNotificationsQuery: listNotificationsQuery,
+		logger:                 logger,
+	}
+}
+
+// ServeHTTP lists the user's notifications, newest first. The optional query parameters are
+// limit, which caps the number of notifications returned; unread, which excludes notifications
+// that have been read when true; and before, the ID of the last notification of the previous page.
+func (h *ListNotificationsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c := claims.GetClaims(r)
+	if !c.Authenticated() {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	opts := query.ListNotificationsOptions{Limit: query.DefaultNotificationLimit}
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			send.BadRequest(w, "The limit must be a positive number")
+			return
+		}
+		opts.Limit = n
+	}
+	if v := r.URL.Query().Get("unread"); v != "" {
+		unreadOnly, err := strconv.ParseBool(v)
+		if err != nil {
+			send.BadRequest(w, "The unread parameter must be true or false")
+			return
+		}
+		opts.UnreadOnly = unreadOnly
+	}
+	if v := r.URL.Query().Get("before"); v != "" {
+		before, err := uuid.Parse(v)
+		if err != nil {
+			send.BadRequest(w, "The before parameter must be a notification ID")
+			return
+		}
+		opts.Before = uuid.NullUUID{UUID: before, Valid: true}
+	}
+
+	notifications, err := h.listNotificationsQuery.Execute(r.Context(), c.Subject, opts)
+	if err != nil {
+		h.logger.Error("failed to list notifications", "user", c.Subject, "error", err)
+		send.InternalServerError(w, "There has been an issue fetching your notifications")
+		return
+	}
+
+	send.JSON(w, notifications, http.StatusOK)
+}
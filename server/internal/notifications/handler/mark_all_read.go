@@ -0,0 +1,42 @@
+package handler
+
+import (
+	"beerbux/internal/common/claims"
+	"beerbux/internal/notifications/command"
+	"beerbux/pkg/send"
+	"log/slog"
+	"net/http"
+)
+
+type MarkAllReadHandler struct {
+	markAllReadCommand *command.MarkAllReadCommand
+	logger             *slog.Logger
+}
+
+func NewMarkAllReadHandler(markAllReadCommand *command.MarkAllReadCommand, logger *slog.Logger) *MarkAllReadHandler {
+	return &MarkAllReadHandler{
+		markAllReadCommand: markAllReadCommand,
+		logger:             logger,
+	}
+}
+
+type MarkAllReadResponse struct {
+	Marked int64 `json:"marked"`
+}
+
+func (h *MarkAllReadHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c := claims.GetClaims(r)
+	if !c.Authenticated() {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	marked, err := h.markAllReadCommand.Execute(r.Context(), c.Subject)
+	if err != nil {
+		h.logger.Error("failed to mark all notifications as read", "user", c.Subject, "error", err)
+		send.InternalServerError(w, "There has been an issue updating your notifications")
+		return
+	}
+
+	send.JSON(w, MarkAllReadResponse{Marked: marked}, http.StatusOK)
+}
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"beerbux/internal/common/claims"
+	"beerbux/internal/notifications/command"
+	"beerbux/pkg/send"
+	"beerbux/pkg/url"
+	"errors"
+	"log/slog"
+	"net/http"
+)
+
+type MarkReadHandler struct {
+	markReadCommand *command.MarkReadCommand
+	logger          *slog.Logger
+}
+
+func NewMarkReadHandler(markReadCommand *command.MarkReadCommand, logger *slog.Logger) *MarkReadHandler {
+	return &MarkReadHandler{
+		markReadCommand: markReadCommand,
+		logger:          logger,
+	}
+}
+
+func (h *MarkReadHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c := claims.GetClaims(r)
+	if !c.Authenticated() {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	notificationID, ok := url.Path.GetUUID(r, "notificationId")
+	if !ok {
+		send.BadRequest(w, "Notification ID is required")
+		return
+	}
+
+	if err := h.markReadCommand.Execute(r.Context(), c.Subject, notificationID); err != nil {
+		if errors.Is(err, command.ErrNotificationNotFound) {
+			send.NotFound(w, "Notification not found")
+			return
+		}
+		h.logger.Error("failed to mark notification as read", "user", c.Subject, "notification", notificationID, "error", err)
+		send.InternalServerError(w, "There has been an issue updating your notifications")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
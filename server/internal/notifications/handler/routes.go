@@ -0,0 +1,41 @@
+package handler
+
+import (
+	"beerbux/internal/api/config"
+	"beerbux/internal/notifications/command"
+	"beerbux/internal/notifications/db"
+	"beerbux/internal/notifications/query"
+	"database/sql"
+	"log/slog"
+	"net/http"
+)
+
+func BuildRoutes(conf *config.Config, logger *slog.Logger, database *sql.DB, mux *http.ServeMux) {
+	queries := db.New(database)
+
+	getPreferencesQuery := query.NewGetPreferencesQuery(queries)
+	updatePreferencesCommand := command.NewUpdatePreferencesCommand(queries)
+	unsubscribeCommand := command.NewUnsubscribeCommand(queries)
+	listNotificationsQuery := query.NewListNotificationsQuery(queries)
+	getUnreadCountQuery := query.NewGetUnreadCountQuery(queries)
+	markReadCommand := command.NewMarkReadCommand(queries)
+	markAllReadCommand := command.NewMarkAllReadCommand(queries)
+
+	mux.Handle("GET /notifications", NewListNotificationsHandler(listNotificationsQuery, logger))
+	mux.Handle("GET /notifications/unread-count", NewGetUnreadCountHandler(getUnreadCountQuery, logger))
+	mux.Handle("POST /notifications/{notificationId}/read", NewMarkReadHandler(markReadCommand, logger))
+	mux.Handle("POST /notifications/read-all", NewMarkAllReadHandler(markAllReadCommand, logger))
+
+	mux.Handle("GET /notifications/preferences", NewGetPreferencesHandler(getPreferencesQuery, logger))
+	mux.Handle("PUT /notifications/preferences", NewUpdatePreferencesHandler(updatePreferencesCommand, logger))
+	mux.Handle("POST /notifications/unsubscribe", NewUnsubscribeHandler(unsubscribeCommand, logger))
+
+	if conf.WebPush.Enabled() {
+		subscribePushCommand := command.NewSubscribePushCommand(queries, conf.Environment.IsDevelopment())
+		unsubscribePushCommand := command.NewUnsubscribePushCommand(queries)
+
+		mux.Handle("GET /notifications/push/public-key", NewGetPushPublicKeyHandler(conf.WebPush.Keys.PublicKey))
+		mux.Handle("POST /notifications/push/subscriptions", NewSubscribePushHandler(subscribePushCommand, logger))
+		mux.Handle("DELETE /notifications/push/subscriptions", NewUnsubscribePushHandler(unsubscribePushCommand, logger))
+	}
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"beerbux/internal/common/claims"
+	"beerbux/internal/notifications/command"
+	"beerbux/pkg/send"
+	"encoding/json"
+	"errors"
+	oz "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/google/uuid"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+type SubscribePushHandler struct {
+	subscribePushCommand *command.SubscribePushCommand
+	logger               *slog.Logger
+}
+
+func NewSubscribePushHandler(subscribePushCommand *command.SubscribePushCommand, logger *slog.Logger) *SubscribePushHandler {
+	return &SubscribePushHandler{
+		subscribePushCommand: subscribePushCommand,
+		logger:               logger,
+	}
+}
+
+// SubscribePushRequest is the browser's PushSubscription serialised with toJSON.
+type SubscribePushRequest struct {
+	Endpoint string `json:"endpoint"`
+	// ExpirationTime is the number of milliseconds since the epoch at which the subscription expires.
+	ExpirationTime *int64               `json:"expirationTime"`
+	Keys           PushSubscriptionKeys `json:"keys"`
+}
+
+type PushSubscriptionKeys struct {
+	P256DH string `json:"p256dh"`
+	Auth   string `json:"auth"`
+}
+
+type SubscribePushResponse struct {
+	ID uuid.UUID `json:"id"`
+}
+
+// ServeHTTP registers a device to receive push notifications for the user.
+func (h *SubscribePushHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c := claims.GetClaims(r)
+	if !c.Authenticated() {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var req SubscribePushRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		send.BadRequest(w, "Failed to decode request")
+		return
+	}
+	if err := req.Validate(); err != nil {
+		send.ValidationError(w, err)
+		return
+	}
+
+	var expiresAt *time.Time
+	if req.ExpirationTime != nil {
+		t := time.UnixMilli(*req.ExpirationTime)
+		expiresAt = &t
+	}
+
+	id, err := h.subscribePushCommand.Execute(r.Context(), c.Subject, command.SubscribePushRequest{
+		Endpoint:  req.Endpoint,
+		P256DH:    req.Keys.P256DH,
+		Auth:      req.Keys.Auth,
+		ExpiresAt: expiresAt,
+		UserAgent: r.UserAgent(),
+	})
+	if err != nil {
+		if errors.Is(err, command.ErrInvalidPushSubscription) {
+			send.BadRequest(w, "The push subscription is invalid")
+			return
+		}
+		h.logger.Error("failed to subscribe to push notifications", "user", c.Subject, "error", err)
+		send.InternalServerError(w, "There has been an issue enabling push notifications")
+		return
+	}
+
+	send.JSON(w, SubscribePushResponse{ID: id}, http.StatusCreated)
+}
+
+func (r SubscribePushRequest) Validate() error {
+	return oz.ValidateStruct(&r,
+		oz.Field(&r.Endpoint, oz.Required, is.URL),
+		oz.Field(&r.Keys),
+	)
+}
+
+func (k PushSubscriptionKeys) Validate() error {
+	return oz.ValidateStruct(&k,
+		oz.Field(&k.P256DH, oz.Required),
+		oz.Field(&k.Auth, oz.Required),
+	)
+}
@@ -0,0 +1,71 @@
+package handler
+
+import (
+	"beerbux/internal/notifications/command"
+	"beerbux/pkg/send"
+	"encoding/json"
+	"errors"
+	oz "github.com/go-ozzo/ozzo-validation/v4"
+	"log/slog"
+	"net/http"
+)
+
+type UnsubscribeHandler struct {
+	unsubscribeCommand *command.UnsubscribeCommand
+	logger             *slog.Logger
+}
+
+func NewUnsubscribeHandler(unsubscribeCommand *command.UnsubscribeCommand, logger *slog.Logger) *UnsubscribeHandler {
+	return &UnsubscribeHandler{
+		unsubscribeCommand: unsubscribeCommand,
+		logger:             logger,
+	}
+}
+
+type UnsubscribeRequest struct {
+	Token string `json:"token"`
+	List  string `json:"list"`
+}
+
+// ServeHTTP opts the user out of notification emails using the token from the unsubscribe
+// link in the email, so it does not require the user to be logged in.
+//
+// Mail clients unsubscribing with the List-Unsubscribe header post a form to the URL in the
+// header, which carries the token and list in the query string, rather than a JSON body.
+func (h *UnsubscribeHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var req UnsubscribeRequest
+	if query := r.URL.Query(); query.Has("token") {
+		req.Token = query.Get("token")
+		req.List = query.Get("list")
+	} else if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		send.BadRequest(w, "Failed to decode request")
+		return
+	}
+
+	if req.List == "" {
+		req.List = command.ListAll
+	}
+	if err := req.Validate(); err != nil {
+		send.ValidationError(w, err)
+		return
+	}
+
+	if err := h.unsubscribeCommand.Execute(r.Context(), req.Token, req.List); err != nil {
+		if errors.Is(err, command.ErrUnsubscribeTokenInvalid) {
+			send.BadRequest(w, "The unsubscribe link is invalid")
+			return
+		}
+		h.logger.Error("failed to unsubscribe from notifications", "list", req.List, "error", err)
+		send.InternalServerError(w, "There has been an issue unsubscribing you")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (r UnsubscribeRequest) Validate() error {
+	return oz.ValidateStruct(&r,
+		oz.Field(&r.Token, oz.Required),
+		oz.Field(&r.List, oz.In(command.ListWeeklyDigest, command.ListSettleUpReminders, command.ListAll)),
+	)
+}
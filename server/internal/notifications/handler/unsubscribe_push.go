@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"beerbux/internal/common/claims"
+	"beerbux/internal/notifications/command"
+	"beerbux/pkg/send"
+	"beerbux/pkg/url"
+	"errors"
+	"log/slog"
+	"net/http"
+)
+
+type UnsubscribePushHandler struct {
+	unsubscribePushCommand *command.UnsubscribePushCommand
+	logger                 *slog.Logger
+}
+
+func NewUnsubscribePushHandler(unsubscribePushCommand *command.UnsubscribePushCommand, logger *slog.Logger) *UnsubscribePushHandler {
+	return &UnsubscribePushHandler{
+		unsubscribePushCommand: unsubscribePushCommand,
+		logger:                 logger,
+	}
+}
+
+// ServeHTTP stops sending push notifications to a device. The device is identified by the
+// endpoint query parameter, the endpoint of its push subscription.
+func (h *UnsubscribePushHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c := claims.GetClaims(r)
+	if !c.Authenticated() {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	endpoint, ok := url.Query.GetString(r, "endpoint")
+	if !ok {
+		send.BadRequest(w, "The endpoint is required")
+		return
+	}
+
+	if err := h.unsubscribePushCommand.Execute(r.Context(), c.Subject, endpoint); err != nil {
+		if errors.Is(err, command.ErrPushSubscriptionNotFound) {
+			send.NotFound(w, "Push subscription not found")
+			return
+		}
+		h.logger.Error("failed to unsubscribe from push notifications", "user", c.Subject, "error", err)
+		send.InternalServerError(w, "There has been an issue disabling push notifications")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"beerbux/internal/common/claims"
+	"beerbux/internal/notifications/command"
+	"beerbux/pkg/send"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+)
+
+type UpdatePreferencesHandler struct {
+	updatePreferencesCommand *command.UpdatePreferencesCommand
+	logger                   *slog.Logger
+}
+
+func NewUpdatePreferencesHandler(updatePreferencesCommand *command.UpdatePreferencesCommand, logger *slog.Logger) *UpdatePreferencesHandler {
+	return &UpdatePreferencesHandler{
+		updatePreferencesCommand: updatePreferencesCommand,
+		logger:                   logger,
+	}
+}
+
+type UpdatePreferencesRequest struct {
+	WeeklyDigest      bool                          `json:"weeklyDigest"`
+	SettleUpReminders bool                          `json:"settleUpReminders"`
+	Push              *UpdatePushPreferencesRequest `json:"push"`
+}
+
+// UpdatePushPreferencesRequest holds the push preferences to change; omitted preferences keep their value.
+type UpdatePushPreferencesRequest struct {
+	Invitations    *bool `json:"invitations"`
+	Rounds         *bool `json:"rounds"`
+	SessionUpdates *bool `json:"sessionUpdates"`
+}
+
+func (h *UpdatePreferencesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c := claims.GetClaims(r)
+	if !c.Authenticated() {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var req UpdatePreferencesRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		send.BadRequest(w, "Failed to decode request")
+		return
+	}
+
+	update := command.UpdatePreferencesRequest{
+		WeeklyDigest:      req.WeeklyDigest,
+		SettleUpReminders: req.SettleUpReminders,
+	}
+	if req.Push != nil {
+		update.PushInvitations = req.Push.Invitations
+		update.PushRounds = req.Push.Rounds
+		update.PushSessionUpdates = req.Push.SessionUpdates
+	}
+
+	prefs, err := h.updatePreferencesCommand.Execute(r.Context(), c.Subject, update)
+	if err != nil {
+		h.logger.Error("failed to update notification preferences", "user", c.Subject, "error", err)
+		send.InternalServerError(w, "There has been an issue updating your notification preferences")
+		return
+	}
+
+	send.JSON(w, prefs, http.StatusOK)
+}
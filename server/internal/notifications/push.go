@@ -0,0 +1,192 @@
+package notifications
+
+import (
+	"beerbux/internal/common/history"
+	"beerbux/internal/notifications/db"
+	"beerbux/internal/notifications/query"
+	"beerbux/pkg/i18n"
+	"beerbux/pkg/webpush"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+const (
+	// pushQueueSize is the number of notifications waiting to be pushed before new ones are dropped.
+	pushQueueSize = 256
+	// pushTTL is how long push services keep a notification for a device that is offline.
+	pushTTL = 24 * time.Hour
+	// pushSubscriptionPurgeInterval is how often subscriptions past their expiration time are deleted.
+	pushSubscriptionPurgeInterval = time.Hour
+)
+
+type pushJob struct {
+	userID       uuid.UUID
+	notification query.NotificationResponse
+}
+
+// PushPayload is the message the service worker receives, with the text to display already
+// translated into the user's locale.
+type PushPayload struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+	URL   string `json:"url"`
+	// Tag groups the notifications of a session, so that devices can replace older ones.
+	Tag          string                     `json:"tag"`
+	Notification query.NotificationResponse `json:"notification"`
+}
+
+// PushDispatcher sends notifications to the devices users have subscribed to Web Push.
+// Notifications are queued in memory and sent in the background: the notification inbox
+// remains the record of every notification, so pushes are dropped rather than retried.
+type PushDispatcher struct {
+	queries       *db.Queries
+	client        *webpush.Client
+	clientBaseURL string
+	queue         chan pushJob
+	logger        *slog.Logger
+}
+
+// NewPushDispatcher creates a PushDispatcher. When client is nil, Web Push is disabled and
+// notifications are not pushed.
+func NewPushDispatcher(queries *db.Queries, client *webpush.Client, clientBaseURL string, logger *slog.Logger) *PushDispatcher {
+	return &PushDispatcher{
+		queries:       queries,
+		client:        client,
+		clientBaseURL: strings.TrimRight(clientBaseURL, "/"),
+		queue:         make(chan pushJob, pushQueueSize),
+		logger:        logger,
+	}
+}
+
+// Push queues a notification to be sent to the user's devices without blocking.
+func (d *PushDispatcher) Push(userID uuid.UUID, notification query.NotificationResponse) {
+	if d.client == nil {
+		return
+	}
+
+	select {
+	case d.queue <- pushJob{userID: userID, notification: notification}:
+	default:
+		d.logger.Warn("push queue is full, dropping notification", "user", userID, "notification", notification.ID)
+	}
+}
+
+// Run sends queued notifications and deletes expired subscriptions until ctx is cancelled.
+func (d *PushDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(pushSubscriptionPurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case job := <-d.queue:
+			if err := d.deliver(ctx, job); err != nil {
+				d.logger.Error("failed to push notification", "user", job.userID, "notification", job.notification.ID, "error", err)
+			}
+		case <-ticker.C:
+			purged, err := d.queries.PurgeExpiredPushSubscriptions(ctx)
+			if err != nil {
+				d.logger.Error("failed to purge expired push subscriptions", "error", err)
+				continue
+			}
+			d.logger.Debug("Purged expired push subscriptions", "deleted", purged)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (d *PushDispatcher) deliver(ctx context.Context, job pushJob) error {
+	recipient, err := d.queries.GetPushRecipient(ctx, job.userID)
+	if err != nil {
+		return fmt.Errorf("failed to get push recipient: %w", err)
+	}
+	if !pushEnabled(recipient, job.notification.Type) {
+		return nil
+	}
+
+	subscriptions, err := d.queries.ListPushSubscriptions(ctx, job.userID)
+	if err != nil {
+		return fmt.Errorf("failed to list push subscriptions: %w", err)
+	}
+	if len(subscriptions) == 0 {
+		return nil
+	}
+
+	locale := i18n.Resolve(recipient.Locale.String, "")
+	payload, err := json.Marshal(PushPayload{
+		Title:        job.notification.Session.Name,
+		Body:         pushBody(locale, job.notification),
+		URL:          d.clientBaseURL + "/session/" + job.notification.Session.ID.String(),
+		Tag:          job.notification.Session.ID.String(),
+		Notification: job.notification,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal push payload: %w", err)
+	}
+
+	msg := webpush.Message{
+		Payload: payload,
+		TTL:     pushTTL,
+		Urgency: webpush.UrgencyNormal,
+	}
+	for _, s := range subscriptions {
+		err := d.client.Send(ctx, webpush.Subscription{
+			Endpoint: s.Endpoint,
+			P256DH:   s.P256dh,
+			Auth:     s.Auth,
+		}, msg)
+		switch {
+		case errors.Is(err, webpush.ErrSubscriptionGone):
+			d.logger.Debug("Deleting expired push subscription", "user", job.userID, "subscription", s.ID)
+			if err := d.queries.DeletePushSubscriptionByID(ctx, s.ID); err != nil {
+				d.logger.Error("failed to delete expired push subscription", "subscription", s.ID, "error", err)
+			}
+		case err != nil:
+			d.logger.Error("failed to send push notification", "user", job.userID, "subscription", s.ID, "error", err)
+		default:
+			if err := d.queries.MarkPushSubscriptionUsed(ctx, s.ID); err != nil {
+				d.logger.Error("failed to mark push subscription as used", "subscription", s.ID, "error", err)
+			}
+		}
+	}
+	return nil
+}
+
+// pushEnabled reports whether the user wants notifications of the given type pushed to their devices.
+func pushEnabled(recipient db.GetPushRecipientRow, notificationType string) bool {
+	switch notificationType {
+	case history.EventMemberAdded:
+		return recipient.PushInvitations
+	case history.EventTransactionCreated:
+		return recipient.PushRounds
+	default:
+		return recipient.PushSessionUpdates
+	}
+}
+
+// pushBody describes the notification in the user's locale; the session name is the title.
+func pushBody(locale string, n query.NotificationResponse) string {
+	switch n.Type {
+	case history.EventMemberAdded:
+		return i18n.Sprintf(locale, "%s added you to %s", n.Actor.Name, n.Session.Name)
+	case history.EventMemberRemoved:
+		return i18n.Sprintf(locale, "%s removed you from %s", n.Actor.Name, n.Session.Name)
+	case history.EventMemberPromotedToAdmin:
+		return i18n.Sprintf(locale, "%s made you an admin of %s", n.Actor.Name, n.Session.Name)
+	case history.EventSessionClosed:
+		return i18n.Sprintf(locale, "%s closed %s", n.Actor.Name, n.Session.Name)
+	case history.EventTransactionCreated:
+		if n.Amount != nil && *n.Amount != 1 {
+			return i18n.Sprintf(locale, "%s bought you %s beers", n.Actor.Name, formatBeers(*n.Amount))
+		}
+		return i18n.Sprintf(locale, "%s bought you a beer", n.Actor.Name)
+	default:
+		return i18n.Sprintf(locale, "New activity in %s", n.Session.Name)
+	}
+}
@@ -0,0 +1,67 @@
+package query
+
+import (
+	"beerbux/internal/notifications/db"
+	"context"
+	"database/sql"
+	"errors"
+	"github.com/google/uuid"
+)
+
+type GetPreferencesQuery struct {
+	queries *db.Queries
+}
+
+func NewGetPreferencesQuery(queries *db.Queries) *GetPreferencesQuery {
+	return &GetPreferencesQuery{
+		queries: queries,
+	}
+}
+
+type PreferencesResponse struct {
+	WeeklyDigest      bool            `json:"weeklyDigest"`
+	SettleUpReminders bool            `json:"settleUpReminders"`
+	Push              PushPreferences `json:"push"`
+}
+
+// PushPreferences are the kinds of notification sent to the user's devices with Web Push.
+type PushPreferences struct {
+	// Invitations are sent when the user is added to a session.
+	Invitations bool `json:"invitations"`
+	// Rounds are sent when someone buys the user a round.
+	Rounds bool `json:"rounds"`
+	// SessionUpdates are sent when the user is removed or promoted, or a session is closed.
+	SessionUpdates bool `json:"sessionUpdates"`
+}
+
+// Execute returns the user's notification preferences. Users who have never saved their
+// preferences have not opted in to any notification emails, but receive every push notification.
+func (q *GetPreferencesQuery) Execute(ctx context.Context, userID uuid.UUID) (*PreferencesResponse, error) {
+	prefs, err := q.queries.GetNotificationPreferences(ctx, userID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return &PreferencesResponse{
+				Push: PushPreferences{
+					Invitations:    true,
+					Rounds:         true,
+					SessionUpdates: true,
+				},
+			}, nil
+		}
+		return nil, err
+	}
+
+	return NewPreferencesResponse(prefs), nil
+}
+
+func NewPreferencesResponse(prefs db.UserNotificationPreference) *PreferencesResponse {
+	return &PreferencesResponse{
+		WeeklyDigest:      prefs.WeeklyDigest,
+		SettleUpReminders: prefs.SettleUpReminders,
+		Push: PushPreferences{
+			Invitations:    prefs.PushInvitations,
+			Rounds:         prefs.PushRounds,
+			SessionUpdates: prefs.PushSessionUpdates,
+		},
+	}
+}
@@ -0,0 +1,29 @@
+package query
+
+import (
+	"beerbux/internal/notifications/db"
+	"context"
+	"github.com/google/uuid"
+)
+
+type GetUnreadCountQuery struct {
+	queries *db.Queries
+}
+
+func NewGetUnreadCountQuery(queries *db.Queries) *GetUnreadCountQuery {
+	return &GetUnreadCountQuery{
+		queries: queries,
+	}
+}
+
+type UnreadCountResponse struct {
+	Count int64 `json:"count"`
+}
+
+func (q *GetUnreadCountQuery) Execute(ctx context.Context, userID uuid.UUID) (*UnreadCountResponse, error) {
+	count, err := q.queries.CountUnreadNotifications(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &UnreadCountResponse{Count: count}, nil
+}
@@ -0,0 +1,118 @@
+package query
+
+import (
+	"beerbux/internal/notifications/db"
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"time"
+)
+
+const (
+	DefaultNotificationLimit = 20
+	MaxNotificationLimit     = 100
+)
+
+type ListNotificationsQuery struct {
+	queries *db.Queries
+}
+
+func NewListNotificationsQuery(queries *db.Queries) *ListNotificationsQuery {
+	return &ListNotificationsQuery{
+		queries: queries,
+	}
+}
+
+type NotificationSession struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+}
+
+type NotificationActor struct {
+	ID       uuid.UUID `json:"id"`
+	Name     string    `json:"name"`
+	Username string    `json:"username"`
+}
+
+// NotificationResponse is a notification in the user's inbox. Type is the session history
+// event that caused it; Amount is only set for transactions, where it is the number of
+// beers the user received.
+type NotificationResponse struct {
+	ID            uuid.UUID           `json:"id"`
+	Type          string              `json:"type"`
+	Session       NotificationSession `json:"session"`
+	Actor         NotificationActor   `json:"actor"`
+	TransactionID *uuid.UUID          `json:"transactionId,omitempty"`
+	Amount        *float64            `json:"amount,omitempty"`
+	Read          bool                `json:"read"`
+	CreatedAt     time.Time           `json:"createdAt"`
+}
+
+type NotificationsResponse struct {
+	Notifications []NotificationResponse `json:"notifications"`
+	UnreadCount   int64                  `json:"unreadCount"`
+}
+
+type ListNotificationsOptions struct {
+	// UnreadOnly excludes notifications that have been read.
+	UnreadOnly bool
+	// Before is the ID of the last notification of the previous page, if any.
+	Before uuid.NullUUID
+	Limit  int
+}
+
+// Execute lists the user's notifications, newest first, along with the number of
+// notifications they have not read.
+func (q *ListNotificationsQuery) Execute(ctx context.Context, userID uuid.UUID, opts ListNotificationsOptions) (*NotificationsResponse, error) {
+	if opts.Limit <= 0 {
+		opts.Limit = DefaultNotificationLimit
+	}
+	opts.Limit = min(opts.Limit, MaxNotificationLimit)
+
+	notifications, err := q.queries.ListNotifications(ctx, db.ListNotificationsParams{
+		UserID:     userID,
+		UnreadOnly: opts.UnreadOnly,
+		Before:     opts.Before,
+		PageSize:   int32(opts.Limit),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list notifications: %w", err)
+	}
+
+	unreadCount, err := q.queries.CountUnreadNotifications(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count unread notifications: %w", err)
+	}
+
+	resp := &NotificationsResponse{
+		Notifications: make([]NotificationResponse, 0, len(notifications)),
+		UnreadCount:   unreadCount,
+	}
+	for _, n := range notifications {
+		resp.Notifications = append(resp.Notifications, NewNotificationResponse(n))
+	}
+	return resp, nil
+}
+
+func NewNotificationResponse(n db.ListNotificationsRow) NotificationResponse {
+	resp := NotificationResponse{
+		ID:   n.ID,
+		Type: n.Type,
+		Session: NotificationSession{
+			ID:   n.SessionID,
+			Name: n.SessionName,
+		},
+		Actor: NotificationActor{
+			ID:       n.ActorID,
+			Name:     n.ActorName,
+			Username: n.ActorUsername,
+		},
+		Read:      n.ReadAt.Valid,
+		CreatedAt: n.CreatedAt,
+	}
+	if n.TransactionID.Valid {
+		resp.TransactionID = &n.TransactionID.UUID
+		resp.Amount = &n.Amount
+	}
+	return resp
+}
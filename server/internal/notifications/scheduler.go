@@ -0,0 +1,231 @@
+package notifications
+
+import (
+	"beerbux/internal/api/config"
+	"beerbux/internal/notifications/command"
+	"beerbux/internal/notifications/db"
+	"beerbux/pkg/email"
+	"beerbux/pkg/i18n"
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+	"log/slog"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// pollInterval is how often the scheduler looks for digests and reminders that are due.
+	pollInterval = 15 * time.Minute
+	// batchSize is the number of users claimed at a time.
+	batchSize = 50
+	// digestPeriod is the period covered by the weekly digest.
+	digestPeriod = 7 * 24 * time.Hour
+)
+
+// Scheduler sends the weekly digest and settle-up reminder emails to the users who have
+// opted in to them. Users are claimed in the database before their email is sent, so
+// several instances of the API can run the scheduler without sending duplicates.
+type Scheduler struct {
+	queries       *db.Queries
+	emailSender   email.Sender
+	clientBaseURL string
+	conf          config.NotificationsConfig
+	logger        *slog.Logger
+}
+
+func NewScheduler(queries *db.Queries, emailSender email.Sender, clientBaseURL string, conf config.NotificationsConfig, logger *slog.Logger) *Scheduler {
+	return &Scheduler{
+		queries:       queries,
+		emailSender:   emailSender,
+		clientBaseURL: strings.TrimRight(clientBaseURL, "/"),
+		conf:          conf,
+		logger:        logger,
+	}
+}
+
+// Run sends due notification emails until ctx is cancelled.
+func (s *Scheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		s.sendDueDigests(ctx)
+		s.sendDueReminders(ctx)
+
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (s *Scheduler) sendDueDigests(ctx context.Context) {
+	for ctx.Err() == nil {
+		recipients, err := s.queries.ClaimDueDigests(ctx, batchSize)
+		if err != nil {
+			s.logger.Error("failed to claim due weekly digests", "error", err)
+			return
+		}
+
+		for _, r := range recipients {
+			if err := s.sendDigest(ctx, r); err != nil {
+				s.logger.Error("failed to send weekly digest", "user", r.UserID, "error", err)
+			}
+		}
+
+		if len(recipients) < batchSize {
+			return
+		}
+	}
+}
+
+func (s *Scheduler) sendDueReminders(ctx context.Context) {
+	for ctx.Err() == nil {
+		recipients, err := s.queries.ClaimDueSettleUpReminders(ctx, db.ClaimDueSettleUpRemindersParams{
+			Threshold:      s.conf.SettleUpThreshold,
+			RemindedBefore: time.Now().Add(-s.conf.SettleUpReminderInterval),
+			BatchSize:      batchSize,
+		})
+		if err != nil {
+			s.logger.Error("failed to claim due settle-up reminders", "error", err)
+			return
+		}
+
+		for _, r := range recipients {
+			if err := s.sendReminder(ctx, r); err != nil {
+				s.logger.Error("failed to send settle-up reminder", "user", r.UserID, "error", err)
+			}
+		}
+
+		if len(recipients) < batchSize {
+			return
+		}
+	}
+}
+
+func (s *Scheduler) sendDigest(ctx context.Context, r db.ClaimDueDigestsRow) error {
+	now := time.Now()
+	since := now.Add(-digestPeriod)
+
+	activity, err := s.queries.GetDigestActivity(ctx, db.GetDigestActivityParams{
+		UserID: r.UserID,
+		Since:  since,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to get activity: %w", err)
+	}
+	sessions, err := s.queries.ListDigestSessions(ctx, db.ListDigestSessionsParams{
+		Since:  since,
+		UserID: r.UserID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to list sessions: %w", err)
+	}
+	balances, err := s.queries.ListFriendBalances(ctx, r.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to list balances: %w", err)
+	}
+
+	// There is nothing worth telling the user about if they were not out and are all square.
+	if len(sessions) == 0 && len(balances) == 0 {
+		return nil
+	}
+
+	data := email.WeeklyDigestEmailData{
+		Username:       r.Username,
+		PeriodStart:    since.UTC().Format(time.DateOnly),
+		PeriodEnd:      now.UTC().Format(time.DateOnly),
+		RoundsBought:   activity.RoundsBought,
+		BeersBought:    formatBeers(activity.BeersBought),
+		RoundsReceived: activity.RoundsReceived,
+		BeersReceived:  formatBeers(activity.BeersReceived),
+		PreferencesURL: s.preferencesURL(),
+		UnsubscribeURL: s.unsubscribeURL(r.UnsubscribeToken, command.ListWeeklyDigest),
+	}
+	for _, session := range sessions {
+		data.Sessions = append(data.Sessions, email.DigestSession{
+			Name:   session.Name,
+			Rounds: session.RoundCount,
+		})
+	}
+	for _, b := range balances {
+		data.Balances = append(data.Balances, email.FriendBalance{
+			Name:   b.Name,
+			Beers:  formatBeers(math.Abs(b.Balance)),
+			YouOwe: b.Balance > 0,
+		})
+	}
+
+	locale := i18n.Resolve(r.Locale.String, "")
+	tmpl := email.WeeklyDigestEmail(locale, data)
+	tmpl.Headers = email.ListUnsubscribeHeaders(s.oneClickUnsubscribeURL(r.UnsubscribeToken, command.ListWeeklyDigest))
+	if _, err := email.SendTemplate(s.emailSender, r.Email, i18n.Translate(locale, "Your weekly Beerbux digest"), tmpl); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
+
+func (s *Scheduler) sendReminder(ctx context.Context, r db.ClaimDueSettleUpRemindersRow) error {
+	balances, err := s.queries.ListFriendBalances(ctx, r.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to list balances: %w", err)
+	}
+
+	var netDebt float64
+	var owed []email.FriendBalance
+	for _, b := range balances {
+		netDebt += b.Balance
+		if b.Balance > 0 {
+			owed = append(owed, email.FriendBalance{
+				Name:   b.Name,
+				Beers:  formatBeers(b.Balance),
+				YouOwe: true,
+			})
+		}
+	}
+
+	locale := i18n.Resolve(r.Locale.String, "")
+	tmpl := email.SettleUpReminderEmail(locale, email.SettleUpReminderEmailData{
+		Username:       r.Username,
+		NetDebt:        formatBeers(netDebt),
+		Threshold:      formatBeers(s.conf.SettleUpThreshold),
+		Balances:       owed,
+		PreferencesURL: s.preferencesURL(),
+		UnsubscribeURL: s.unsubscribeURL(r.UnsubscribeToken, command.ListSettleUpReminders),
+	})
+	tmpl.Headers = email.ListUnsubscribeHeaders(s.oneClickUnsubscribeURL(r.UnsubscribeToken, command.ListSettleUpReminders))
+	if _, err := email.SendTemplate(s.emailSender, r.Email, i18n.Translate(locale, "Time to settle up on Beerbux"), tmpl); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
+
+func (s *Scheduler) preferencesURL() string {
+	return s.clientBaseURL + "/settings/notifications"
+}
+
+func (s *Scheduler) unsubscribeURL(token uuid.UUID, list string) string {
+	return s.clientBaseURL + "/unsubscribe?" + unsubscribeQuery(token, list)
+}
+
+// oneClickUnsubscribeURL is the API endpoint mail clients post to when the user unsubscribes
+// using the List-Unsubscribe header, which is served under /api alongside the webapp.
+func (s *Scheduler) oneClickUnsubscribeURL(token uuid.UUID, list string) string {
+	return s.clientBaseURL + "/api/notifications/unsubscribe?" + unsubscribeQuery(token, list)
+}
+
+func unsubscribeQuery(token uuid.UUID, list string) string {
+	return url.Values{
+		"token": {token.String()},
+		"list":  {list},
+	}.Encode()
+}
+
+func formatBeers(beers float64) string {
+	return strconv.FormatFloat(beers, 'f', -1, 64)
+}
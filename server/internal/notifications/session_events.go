@@ -0,0 +1,137 @@
+package notifications
+
+import (
+	"beerbux/internal/common/history"
+	"beerbux/internal/notifications/db"
+	"beerbux/internal/notifications/query"
+	"beerbux/internal/sse"
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/google/uuid"
+	"log/slog"
+)
+
+// SessionEventNotifier records session history events with the wrapped writer and adds a
+// notification to the inbox of each member the event concerns, other than the member who
+// performed it. New notifications are sent to the user's event stream and pushed to their devices.
+//
+// Members are only notified once the event has been recorded. Failing to notify a member is
+// logged rather than returned, as the event itself has been recorded.
+type SessionEventNotifier struct {
+	history.SessionHistoryWriter
+	queries *db.Queries
+	msgChan chan<- *sse.Message
+	pusher  *PushDispatcher
+	logger  *slog.Logger
+}
+
+func NewSessionEventNotifier(
+	historyWriter history.SessionHistoryWriter,
+	queries *db.Queries,
+	msgChan chan<- *sse.Message,
+	pusher *PushDispatcher,
+	logger *slog.Logger,
+) *SessionEventNotifier {
+	return &SessionEventNotifier{
+		SessionHistoryWriter: historyWriter,
+		queries:              queries,
+		msgChan:              msgChan,
+		pusher:               pusher,
+		logger:               logger,
+	}
+}
+
+func (n *SessionEventNotifier) CreateMemberAddedEvent(ctx context.Context, sessionID, memberID, performedByMemberId uuid.UUID) error {
+	if err := n.SessionHistoryWriter.CreateMemberAddedEvent(ctx, sessionID, memberID, performedByMemberId); err != nil {
+		return err
+	}
+	n.notify(ctx, memberID, history.EventMemberAdded, sessionID, performedByMemberId, uuid.NullUUID{})
+	return nil
+}
+
+func (n *SessionEventNotifier) CreateMemberRemovedEvent(ctx context.Context, sessionID, memberID, performedByMemberId uuid.UUID) error {
+	if err := n.SessionHistoryWriter.CreateMemberRemovedEvent(ctx, sessionID, memberID, performedByMemberId); err != nil {
+		return err
+	}
+	n.notify(ctx, memberID, history.EventMemberRemoved, sessionID, performedByMemberId, uuid.NullUUID{})
+	return nil
+}
+
+func (n *SessionEventNotifier) CreateMemberPromotedToAdminEvent(ctx context.Context, sessionID, memberID, performedByMemberId uuid.UUID) error {
+	if err := n.SessionHistoryWriter.CreateMemberPromotedToAdminEvent(ctx, sessionID, memberID, performedByMemberId); err != nil {
+		return err
+	}
+	n.notify(ctx, memberID, history.EventMemberPromotedToAdmin, sessionID, performedByMemberId, uuid.NullUUID{})
+	return nil
+}
+
+func (n *SessionEventNotifier) CreateSessionClosedEvent(ctx context.Context, sessionID, memberID uuid.UUID) error {
+	if err := n.SessionHistoryWriter.CreateSessionClosedEvent(ctx, sessionID, memberID); err != nil {
+		return err
+	}
+	memberIDs, err := n.queries.ListSessionMemberIDs(ctx, sessionID)
+	if err != nil {
+		n.logger.Error("failed to list session members to notify", "session", sessionID, "error", err)
+		return nil
+	}
+	for _, id := range memberIDs {
+		n.notify(ctx, id, history.EventSessionClosed, sessionID, memberID, uuid.NullUUID{})
+	}
+	return nil
+}
+
+func (n *SessionEventNotifier) CreateTransactionCreatedEvent(
+	ctx context.Context,
+	sessionID,
+	performedByMemberId uuid.UUID,
+	transactionLines history.TransactionHistory,
+) error {
+	if err := n.SessionHistoryWriter.CreateTransactionCreatedEvent(ctx, sessionID, performedByMemberId, transactionLines); err != nil {
+		return err
+	}
+	transactionID := uuid.NullUUID{UUID: transactionLines.TransactionID, Valid: true}
+	for _, line := range transactionLines.Lines {
+		n.notify(ctx, line.MemberID, history.EventTransactionCreated, sessionID, performedByMemberId, transactionID)
+	}
+	return nil
+}
+
+// notify adds a notification to the user's inbox, sends it to their event stream and pushes it to their devices.
+// Users are not notified of their own actions.
+func (n *SessionEventNotifier) notify(ctx context.Context, userID uuid.UUID, eventType string, sessionID, actorID uuid.UUID, transactionID uuid.NullUUID) {
+	if userID == actorID {
+		return
+	}
+
+	if err := n.createAndPublish(ctx, db.CreateNotificationParams{
+		UserID:        userID,
+		Type:          eventType,
+		SessionID:     sessionID,
+		ActorID:       actorID,
+		TransactionID: transactionID,
+	}); err != nil {
+		n.logger.Error("failed to notify user", "user", userID, "type", eventType, "session", sessionID, "error", err)
+	}
+}
+
+func (n *SessionEventNotifier) createAndPublish(ctx context.Context, params db.CreateNotificationParams) error {
+	id, err := n.queries.CreateNotification(ctx, params)
+	if err != nil {
+		return fmt.Errorf("failed to create notification: %w", err)
+	}
+
+	notification, err := n.queries.GetNotification(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to get notification %s: %w", id, err)
+	}
+	resp := query.NewNotificationResponse(db.ListNotificationsRow(notification))
+	data, err := json.Marshal(resp)
+	if err != nil {
+		return fmt.Errorf("failed to marshal notification %s: %w", id, err)
+	}
+
+	n.msgChan <- sse.NewMessage("notification.created", sse.UserRoomID(params.UserID), data)
+	n.pusher.Push(params.UserID, resp)
+	return nil
+}
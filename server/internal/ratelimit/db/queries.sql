@@ -0,0 +1,16 @@
+-- name: HitRateLimitCounter :one
+insert into rate_limit_counters (key, hits, expires_at)
+values ($1, 1, $2)
+on conflict (key) do update
+set hits = case
+        when rate_limit_counters.expires_at <= now() then 1
+        else rate_limit_counters.hits + 1
+    end,
+    expires_at = case
+        when rate_limit_counters.expires_at <= now() then excluded.expires_at
+        else rate_limit_counters.expires_at
+    end
+returning hits, expires_at;
+
+-- name: DeleteExpiredRateLimitCounters :exec
+delete from rate_limit_counters where expires_at <= now();
@@ -0,0 +1,61 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// memoryCleanupInterval is how often expired counters are removed from the MemoryStore.
+const memoryCleanupInterval = time.Minute
+
+type memoryCounter struct {
+	hits      int
+	expiresAt time.Time
+}
+
+// MemoryStore keeps rate limit counters in memory.
+// Counters are not shared between instances, use the PostgresStore when running more than one instance of the API.
+type MemoryStore struct {
+	mu          sync.Mutex
+	counters    map[string]*memoryCounter
+	lastCleanup time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		counters:    make(map[string]*memoryCounter),
+		lastCleanup: time.Now(),
+	}
+}
+
+func (s *MemoryStore) Hit(_ context.Context, key string, limit int, window time.Duration) (Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.removeExpired(now)
+
+	counter, ok := s.counters[key]
+	if !ok || !counter.expiresAt.After(now) {
+		counter = &memoryCounter{expiresAt: now.Add(window)}
+		s.counters[key] = counter
+	}
+	counter.hits++
+
+	return newResult(counter.hits, limit, counter.expiresAt), nil
+}
+
+// removeExpired periodically deletes expired counters so that the map does not grow unbounded.
+// The caller must hold the lock.
+func (s *MemoryStore) removeExpired(now time.Time) {
+	if now.Sub(s.lastCleanup) < memoryCleanupInterval {
+		return
+	}
+	for key, counter := range s.counters {
+		if !counter.expiresAt.After(now) {
+			delete(s.counters, key)
+		}
+	}
+	s.lastCleanup = now
+}
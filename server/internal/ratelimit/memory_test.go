@@ -0,0 +1,95 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestMemoryStoreHit(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+
+	for i := 1; i <= 3; i++ {
+		result, err := store.Hit(ctx, "key", 3, time.Minute)
+		if err != nil {
+			t.Fatalf("Hit %d: %v", i, err)
+		}
+		if !result.Allowed {
+			t.Fatalf("Hit %d was not allowed", i)
+		}
+		if result.Remaining != 3-i {
+			t.Errorf("Hit %d remaining = %d, want %d", i, result.Remaining, 3-i)
+		}
+	}
+
+	result, err := store.Hit(ctx, "key", 3, time.Minute)
+	if err != nil {
+		t.Fatalf("Hit: %v", err)
+	}
+	if result.Allowed {
+		t.Error("Hit over the limit was allowed")
+	}
+	if result.Remaining != 0 {
+		t.Errorf("remaining = %d, want 0", result.Remaining)
+	}
+	if d := result.RetryAfter(); d <= 0 || d > time.Minute {
+		t.Errorf("RetryAfter = %s, want within the window", d)
+	}
+}
+
+func TestMemoryStoreKeysAreIndependent(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+
+	if _, err := store.Hit(ctx, "a", 1, time.Minute); err != nil {
+		t.Fatalf("Hit: %v", err)
+	}
+	result, err := store.Hit(ctx, "b", 1, time.Minute)
+	if err != nil {
+		t.Fatalf("Hit: %v", err)
+	}
+	if !result.Allowed {
+		t.Error("Hit on another key was not allowed")
+	}
+}
+
+func TestMemoryStoreWindowResets(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+	window := 20 * time.Millisecond
+
+	for range 2 {
+		if _, err := store.Hit(ctx, "key", 1, window); err != nil {
+			t.Fatalf("Hit: %v", err)
+		}
+	}
+
+	time.Sleep(2 * window)
+
+	result, err := store.Hit(ctx, "key", 1, window)
+	if err != nil {
+		t.Fatalf("Hit: %v", err)
+	}
+	if !result.Allowed {
+		t.Error("Hit after the window reset was not allowed")
+	}
+}
+
+func TestMemoryStoreRemovesExpiredCounters(t *testing.T) {
+	store := NewMemoryStore()
+	ctx := context.Background()
+
+	if _, err := store.Hit(ctx, "expired", 1, time.Millisecond); err != nil {
+		t.Fatalf("Hit: %v", err)
+	}
+	store.lastCleanup = time.Now().Add(-memoryCleanupInterval)
+	time.Sleep(2 * time.Millisecond)
+
+	if _, err := store.Hit(ctx, "key", 1, time.Minute); err != nil {
+		t.Fatalf("Hit: %v", err)
+	}
+	if _, ok := store.counters["expired"]; ok {
+		t.Error("expired counter was not removed")
+	}
+}
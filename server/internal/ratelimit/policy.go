@@ -0,0 +1,153 @@
+package ratelimit
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+type KeyBy int
+
+const (
+	// KeyByIP limits requests by the IP address of the client.
+	KeyByIP KeyBy = iota
+	// KeyByUser limits requests by the authenticated user, falling back to the
+	// IP address of the client for unauthenticated requests.
+	KeyByUser
+)
+
+// Policy describes how many requests may be made within a window.
+type Policy struct {
+	Name   string
+	Limit  int
+	Window time.Duration
+	KeyBy  KeyBy
+}
+
+// Policies maps route patterns, as registered on the http.ServeMux, to the policies applied to them.
+//
+// Write policies are applied to any POST, PUT, PATCH or DELETE request that does not match a route.
+type Policies struct {
+	Routes map[string][]Policy
+	Write  []Policy
+}
+
+// DefaultPolicies returns the policies applied to the API.
+func DefaultPolicies() Policies {
+	login := Policy{Name: "login", Limit: 10, Window: time.Minute, KeyBy: KeyByIP}
+	signup := Policy{Name: "signup", Limit: 10, Window: time.Hour, KeyBy: KeyByIP}
+	// Endpoints that send emails share a policy so that they cannot be used together to flood an inbox.
+	sendEmail := Policy{Name: "send-email", Limit: 5, Window: 15 * time.Minute, KeyBy: KeyByIP}
+	otp := Policy{Name: "otp", Limit: 10, Window: 15 * time.Minute, KeyBy: KeyByIP}
+	refresh := Policy{Name: "refresh", Limit: 30, Window: time.Minute, KeyBy: KeyByIP}
+	transaction := Policy{Name: "transaction", Limit: 30, Window: time.Minute, KeyBy: KeyByUser}
+
+	return Policies{
+		Routes: map[string][]Policy{
+			"POST /auth/login":                      {login},
+			"POST /auth/login/2fa":                  {otp},
+			"POST /auth/token":                      {login},
+			"POST /auth/token/2fa":                  {otp},
+			"POST /auth/token/refresh":              {refresh},
+			"GET /auth/oidc/{provider}/login":       {login},
+			"GET /auth/oidc/{provider}/callback":    {login},
+			"POST /auth/2fa/verify":                 {otp},
+			"DELETE /auth/2fa":                      {otp},
+			"POST /auth/signup":                     {signup, sendEmail},
+			"POST /auth/magic-link":                 {sendEmail},
+			"POST /auth/magic-link/login":           {otp},
+			"POST /auth/password/initialize-reset":  {sendEmail},
+			"POST /auth/password/initialize-update": {sendEmail},
+			"POST /auth/email/initialize-update":    {sendEmail},
+			"POST /auth/email/verify/resend":        {sendEmail},
+			"PUT /auth/password/reset":              {otp},
+			"PUT /auth/password":                    {otp},
+			"PUT /auth/email":                       {otp},
+			"POST /auth/email/verify":               {otp},
+			"POST /auth/email/revert":               {otp},
+			"POST /session/{sessionId}/transaction": {transaction},
+			"POST /notifications/unsubscribe":       {otp},
+		},
+		Write: []Policy{
+			{Name: "write", Limit: 120, Window: time.Minute, KeyBy: KeyByUser},
+		},
+	}
+}
+
+// PolicyOverride changes the limit and window of the policy with the given name.
+type PolicyOverride struct {
+	Name   string
+	Limit  int
+	Window time.Duration
+}
+
+// RouteOverride replaces the policies applied to a route pattern with the named policies.
+// Routes without any policies are not limited, other than by the write policies.
+type RouteOverride struct {
+	Pattern  string
+	Policies []string
+}
+
+// NewPolicies returns the DefaultPolicies with the overrides applied. Overridden policies keep
+// their name, so routes sharing a policy continue to share its counter.
+func NewPolicies(policyOverrides []PolicyOverride, routeOverrides []RouteOverride) (Policies, error) {
+	defaults := DefaultPolicies()
+
+	named := make(map[string]Policy)
+	for _, policies := range defaults.Routes {
+		for _, policy := range policies {
+			named[policy.Name] = policy
+		}
+	}
+	for _, policy := range defaults.Write {
+		named[policy.Name] = policy
+	}
+
+	for _, override := range policyOverrides {
+		policy, ok := named[override.Name]
+		if !ok {
+			return Policies{}, fmt.Errorf("unknown rate limit policy: %s", override.Name)
+		}
+		if override.Limit <= 0 || override.Window <= 0 {
+			return Policies{}, fmt.Errorf("invalid rate limit for policy %s", override.Name)
+		}
+		policy.Limit = override.Limit
+		policy.Window = override.Window
+		named[override.Name] = policy
+	}
+
+	routes := make(map[string][]string, len(defaults.Routes))
+	for pattern, policies := range defaults.Routes {
+		for _, policy := range policies {
+			routes[pattern] = append(routes[pattern], policy.Name)
+		}
+	}
+	for _, override := range routeOverrides {
+		method, path, ok := strings.Cut(override.Pattern, " ")
+		if !ok || method == "" || !strings.HasPrefix(path, "/") {
+			return Policies{}, fmt.Errorf("invalid rate limit route pattern: %s", override.Pattern)
+		}
+		for _, name := range override.Policies {
+			if _, ok := named[name]; !ok {
+				return Policies{}, fmt.Errorf("unknown rate limit policy for %s: %s", override.Pattern, name)
+			}
+		}
+		routes[override.Pattern] = override.Policies
+	}
+
+	policies := Policies{
+		Routes: make(map[string][]Policy, len(routes)),
+	}
+	for pattern, names := range routes {
+		if len(names) == 0 {
+			continue
+		}
+		for _, name := range names {
+			policies.Routes[pattern] = append(policies.Routes[pattern], named[name])
+		}
+	}
+	for _, policy := range defaults.Write {
+		policies.Write = append(policies.Write, named[policy.Name])
+	}
+	return policies, nil
+}
@@ -0,0 +1,99 @@
+package ratelimit
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewPoliciesWithoutOverrides(t *testing.T) {
+	policies, err := NewPolicies(nil, nil)
+	if err != nil {
+		t.Fatalf("NewPolicies: %v", err)
+	}
+
+	defaults := DefaultPolicies()
+	if len(policies.Routes) != len(defaults.Routes) {
+		t.Errorf("got %d routes, want %d", len(policies.Routes), len(defaults.Routes))
+	}
+	for pattern, want := range defaults.Routes {
+		got := policies.Routes[pattern]
+		if len(got) != len(want) {
+			t.Errorf("%s has %d policies, want %d", pattern, len(got), len(want))
+			continue
+		}
+		for i := range want {
+			if got[i] != want[i] {
+				t.Errorf("%s policy %d = %+v, want %+v", pattern, i, got[i], want[i])
+			}
+		}
+	}
+}
+
+func TestNewPoliciesOverridesPolicy(t *testing.T) {
+	policies, err := NewPolicies([]PolicyOverride{{Name: "send-email", Limit: 3, Window: time.Hour}}, nil)
+	if err != nil {
+		t.Fatalf("NewPolicies: %v", err)
+	}
+
+	// The override applies to every route sharing the policy.
+	for _, pattern := range []string{"POST /auth/magic-link", "POST /auth/password/initialize-reset"} {
+		policy := policies.Routes[pattern][0]
+		if policy.Name != "send-email" || policy.Limit != 3 || policy.Window != time.Hour {
+			t.Errorf("%s policy = %+v, want the overridden send-email policy", pattern, policy)
+		}
+	}
+
+	login := policies.Routes["POST /auth/login"][0]
+	if login != DefaultPolicies().Routes["POST /auth/login"][0] {
+		t.Errorf("login policy = %+v, want the default", login)
+	}
+}
+
+func TestNewPoliciesOverridesWritePolicy(t *testing.T) {
+	policies, err := NewPolicies([]PolicyOverride{{Name: "write", Limit: 10, Window: time.Second}}, nil)
+	if err != nil {
+		t.Fatalf("NewPolicies: %v", err)
+	}
+	if len(policies.Write) != 1 || policies.Write[0].Limit != 10 || policies.Write[0].Window != time.Second {
+		t.Errorf("write policies = %+v, want the overridden write policy", policies.Write)
+	}
+}
+
+func TestNewPoliciesOverridesRoutes(t *testing.T) {
+	policies, err := NewPolicies(nil, []RouteOverride{
+		{Pattern: "POST /auth/login", Policies: []string{"login", "otp"}},
+		{Pattern: "GET /users/{userId}", Policies: []string{"refresh"}},
+		{Pattern: "POST /auth/signup"},
+	})
+	if err != nil {
+		t.Fatalf("NewPolicies: %v", err)
+	}
+
+	if got := policies.Routes["POST /auth/login"]; len(got) != 2 || got[0].Name != "login" || got[1].Name != "otp" {
+		t.Errorf("POST /auth/login policies = %+v, want login and otp", got)
+	}
+	if got := policies.Routes["GET /users/{userId}"]; len(got) != 1 || got[0].Name != "refresh" {
+		t.Errorf("GET /users/{userId} policies = %+v, want refresh", got)
+	}
+	if _, ok := policies.Routes["POST /auth/signup"]; ok {
+		t.Error("POST /auth/signup is still limited")
+	}
+}
+
+func TestNewPoliciesRejectsInvalidOverrides(t *testing.T) {
+	for _, tt := range []struct {
+		name     string
+		policies []PolicyOverride
+		routes   []RouteOverride
+	}{
+		{name: "unknown policy", policies: []PolicyOverride{{Name: "unknown", Limit: 1, Window: time.Minute}}},
+		{name: "zero limit", policies: []PolicyOverride{{Name: "login", Limit: 0, Window: time.Minute}}},
+		{name: "zero window", policies: []PolicyOverride{{Name: "login", Limit: 1}}},
+		{name: "route without method", routes: []RouteOverride{{Pattern: "/auth/login", Policies: []string{"login"}}}},
+		{name: "route with unknown policy", routes: []RouteOverride{{Pattern: "POST /auth/login", Policies: []string{"unknown"}}}},
+	} {
+		if _, err := NewPolicies(tt.policies, tt.routes); err == nil {
+			t.Errorf("%s: NewPolicies returned no error", tt.name)
+		}
+	}
+}
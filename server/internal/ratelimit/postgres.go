@@ -0,0 +1,64 @@
+package ratelimit
+
+import (
+	"beerbux/internal/ratelimit/db"
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// postgresCleanupInterval is how often expired counters are deleted from the database.
+const postgresCleanupInterval = 5 * time.Minute
+
+// PostgresStore keeps rate limit counters in the rate_limit_counters table so
+// that limits are shared between multiple instances of the API.
+type PostgresStore struct {
+	queries *db.Queries
+	logger  *slog.Logger
+
+	mu          sync.Mutex
+	lastCleanup time.Time
+}
+
+func NewPostgresStore(queries *db.Queries, logger *slog.Logger) *PostgresStore {
+	return &PostgresStore{
+		queries:     queries,
+		logger:      logger,
+		lastCleanup: time.Now(),
+	}
+}
+
+func (s *PostgresStore) Hit(ctx context.Context, key string, limit int, window time.Duration) (Result, error) {
+	s.removeExpired()
+
+	counter, err := s.queries.HitRateLimitCounter(ctx, db.HitRateLimitCounterParams{
+		Key:       key,
+		ExpiresAt: time.Now().Add(window),
+	})
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to record rate limit hit: %w", err)
+	}
+
+	return newResult(int(counter.Hits), limit, counter.ExpiresAt), nil
+}
+
+// removeExpired periodically deletes expired counters in the background.
+func (s *PostgresStore) removeExpired() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if time.Since(s.lastCleanup) < postgresCleanupInterval {
+		return
+	}
+	s.lastCleanup = time.Now()
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := s.queries.DeleteExpiredRateLimitCounters(ctx); err != nil {
+			s.logger.Error("failed to delete expired rate limit counters", "error", err)
+		}
+	}()
+}
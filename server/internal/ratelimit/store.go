@@ -0,0 +1,41 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Result describes the state of a rate limit counter after a hit has been recorded.
+type Result struct {
+	Allowed   bool
+	Limit     int
+	Remaining int
+	ResetAt   time.Time
+}
+
+// RetryAfter returns the duration until the current window resets.
+func (r Result) RetryAfter() time.Duration {
+	d := time.Until(r.ResetAt)
+	if d < 0 {
+		return 0
+	}
+	return d
+}
+
+// Store records hits against a key using a fixed window.
+type Store interface {
+	Hit(ctx context.Context, key string, limit int, window time.Duration) (Result, error)
+}
+
+func newResult(hits, limit int, resetAt time.Time) Result {
+	remaining := limit - hits
+	if remaining < 0 {
+		remaining = 0
+	}
+	return Result{
+		Allowed:   hits <= limit,
+		Limit:     limit,
+		Remaining: remaining,
+		ResetAt:   resetAt,
+	}
+}
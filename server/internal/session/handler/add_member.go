@@ -5,10 +5,10 @@ import (
 	"beerbux/internal/common/sessionaccess"
 	"beerbux/internal/common/useraccess"
 	"beerbux/internal/session/command"
+	"beerbux/pkg/i18n"
 	"beerbux/pkg/send"
 	"encoding/json"
 	"errors"
-	"fmt"
 	oz "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/google/uuid"
 	"log/slog"
@@ -74,7 +74,7 @@ func (h *AddSessionMemberHandler) ServeHTTP(w http.ResponseWriter, r *http.Reque
 	userToAdd, err := h.userReader.GetUserByUsername(r.Context(), req.Username)
 	if err != nil {
 		if errors.Is(err, useraccess.ErrUserNotFound) {
-			send.NotFound(w, fmt.Sprintf("User %s not found", req.Username))
+			send.NotFound(w, i18n.Sprintf(i18n.FromContext(r.Context()), "User %s not found", req.Username))
 			return
 		}
 		h.logger.Error("failed to find the user to add to the session", "username", req.Username, "session", sessionID, "error", err)
@@ -82,6 +82,11 @@ func (h *AddSessionMemberHandler) ServeHTTP(w http.ResponseWriter, r *http.Reque
 		return
 	}
 
+	if !userToAdd.EmailVerified {
+		send.BadRequest(w, i18n.Sprintf(i18n.FromContext(r.Context()), "%s must verify their email address before they can be added to a session", userToAdd.Username))
+		return
+	}
+
 	if userIsMember, err := h.sessionReader.UserIsMemberOfSession(r.Context(), sessionID, userToAdd.ID); err != nil {
 		send.InternalServerError(w, "There has been an issue determining if the user is already a member")
 		return
@@ -93,7 +98,7 @@ func (h *AddSessionMemberHandler) ServeHTTP(w http.ResponseWriter, r *http.Reque
 
 	if err := h.addSessionMemberCommand.Execute(r.Context(), sessionID, userToAdd.ID, currentMember.ID); err != nil {
 		h.logger.Error("failed to add the user to the session", "error", err)
-		send.InternalServerError(w, fmt.Sprintf("There has been an issue adding %s to the session", userToAdd.Username))
+		send.InternalServerError(w, i18n.Sprintf(i18n.FromContext(r.Context()), "There has been an issue adding %s to the session", userToAdd.Username))
 		return
 	}
 
@@ -6,28 +6,40 @@ import (
 	sessionaccessQueries "beerbux/internal/common/sessionaccess/db"
 	"beerbux/internal/common/useraccess"
 	useraccessQueries "beerbux/internal/common/useraccess/db"
+	"beerbux/internal/notifications"
+	notificationsQueries "beerbux/internal/notifications/db"
 	"beerbux/internal/session/command"
 	"beerbux/internal/session/db"
 	"beerbux/internal/session/query"
 	"beerbux/internal/sse"
+	"beerbux/internal/webhooks"
 	"database/sql"
 	"log/slog"
 	"net/http"
 )
 
-func BuildRoutes(logger *slog.Logger, database *sql.DB, mux *http.ServeMux, msgChan chan<- *sse.Message) {
+func BuildRoutes(
+	logger *slog.Logger,
+	database *sql.DB,
+	mux *http.ServeMux,
+	msgChan chan<- *sse.Message,
+	pusher *notifications.PushDispatcher,
+	webhookOutbox *webhooks.Outbox,
+) {
 	queries := db.New(database)
 	sessionHistoryService := history.NewSessionHistoryService(queries, logger)
+	sessionEventPublisher := webhooks.NewSessionEventPublisher(sessionHistoryService, webhookOutbox, logger)
+	sessionEventNotifier := notifications.NewSessionEventNotifier(sessionEventPublisher, notificationsQueries.New(database), msgChan, pusher, logger)
 	userReaderService := useraccess.NewUserReaderService(useraccessQueries.New(database))
 	sessionReaderService := sessionaccess.NewSessionService(sessionaccessQueries.New(database))
 
 	listSessionsByUserIDQuery := query.NewListSessionsByUserIDQuery(queries)
 	createSessionCommand := command.NewCreateSessionCommand(database, queries, userReaderService)
-	addSessionMemberCommand := command.NewAddSessionMemberCommand(database, queries, sessionHistoryService)
-	removeSessionMemberCommand := command.NewRemoveSessionMemberCommand(queries, sessionHistoryService)
-	updateSessionMemberAdminStateCommand := command.NewUpdateSessionMemberAdminStateCommand(queries, sessionHistoryService)
-	updateSessionActiveStateCommand := command.NewUpdateSessionActionStateCommand(queries, sessionHistoryService)
-	createTransactionCommand := command.NewCreateTransactionCommand(database, queries, sessionHistoryService)
+	addSessionMemberCommand := command.NewAddSessionMemberCommand(database, queries, sessionEventNotifier)
+	removeSessionMemberCommand := command.NewRemoveSessionMemberCommand(queries, sessionEventNotifier)
+	updateSessionMemberAdminStateCommand := command.NewUpdateSessionMemberAdminStateCommand(queries, sessionEventNotifier)
+	updateSessionActiveStateCommand := command.NewUpdateSessionActionStateCommand(queries, sessionEventNotifier)
+	createTransactionCommand := command.NewCreateTransactionCommand(database, queries, sessionEventNotifier)
 
 	mux.Handle("GET /user/sessions", NewListCurrentUserSessionsHandler(listSessionsByUserIDQuery, logger))
 
@@ -1,6 +1,9 @@
 package sse
 
-import "sync"
+import (
+	"github.com/google/uuid"
+	"sync"
+)
 
 type Room struct {
 	id      string
@@ -8,6 +11,12 @@ type Room struct {
 	mu      sync.RWMutex
 }
 
+// UserRoomID returns the ID of the room whose clients receive the messages for a single user,
+// such as their notifications.
+func UserRoomID(userID uuid.UUID) string {
+	return "user:" + userID.String()
+}
+
 func NewRoom(id string) *Room {
 	return &Room{
 		id:      id,
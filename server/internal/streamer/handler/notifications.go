@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"beerbux/internal/common/claims"
+	"beerbux/internal/sse"
+	"beerbux/pkg/send"
+	"errors"
+	"github.com/google/uuid"
+	"log/slog"
+	"net/http"
+)
+
+type NotificationsHandler struct {
+	Server *sse.Server
+	logger *slog.Logger
+}
+
+func NewNotificationsHandler(logger *slog.Logger, server *sse.Server) http.Handler {
+	return &NotificationsHandler{
+		Server: server,
+		logger: logger,
+	}
+}
+
+// ServeHTTP streams new notifications to the authenticated user as notification.created events.
+// Each connection is a separate client, so every open tab or device receives the notifications.
+func (h *NotificationsHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c := claims.GetClaims(r)
+	if !c.Authenticated() {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	setServerSentEventHeaders(w)
+
+	eventStreamWriter, err := NewEventStreamWriter(w)
+	if err != nil {
+		if errors.Is(err, ErrStreamingUnsupported) {
+			send.InternalServerError(w, "Streaming unsupported")
+		} else {
+			h.logger.Error("Error creating stream writer", "error", err)
+			send.InternalServerError(w, "Could not connect to streaming")
+		}
+		return
+	}
+
+	clientID := uuid.NewString()
+	room := h.Server.GetOrCreateRoom(sse.UserRoomID(c.Subject))
+	client := sse.NewClient(clientID)
+	room.AddClient(client)
+
+	notify := r.Context().Done()
+	go func() {
+		<-notify
+		room.RemoveClient(clientID)
+	}()
+
+	for {
+		select {
+		case <-client.Done:
+			return
+		case message := <-client.Ch:
+			if err := eventStreamWriter.Write(message); err != nil {
+				h.logger.Error("Failed to write SSE message", "error", err)
+				room.RemoveClient(clientID)
+				return
+			}
+		}
+	}
+}
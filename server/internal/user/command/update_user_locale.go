@@ -0,0 +1,27 @@
+package command
+
+import (
+	"beerbux/internal/user/db"
+	"context"
+	"database/sql"
+	"github.com/google/uuid"
+)
+
+type UpdateUserLocaleCommand struct {
+	Queries *db.Queries
+}
+
+func NewUpdateUserLocaleCommand(queries *db.Queries) *UpdateUserLocaleCommand {
+	return &UpdateUserLocaleCommand{
+		Queries: queries,
+	}
+}
+
+// Execute saves the user's preferred locale. An empty locale clears the preference so that
+// the Accept-Language header of each request is used instead.
+func (c *UpdateUserLocaleCommand) Execute(ctx context.Context, userID uuid.UUID, locale string) error {
+	return c.Queries.UpdateUserLocale(ctx, db.UpdateUserLocaleParams{
+		ID:     userID,
+		Locale: sql.NullString{String: locale, Valid: locale != ""},
+	})
+}
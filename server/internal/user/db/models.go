@@ -6,12 +6,82 @@ package db
 
 import (
 	"database/sql"
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/sqlc-dev/pqtype"
 )
 
+type ChatAccount struct {
+	ID           uuid.UUID
+	TeamID       string
+	ChatUserID   string
+	ChatUsername string
+	UserID       uuid.UUID
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+}
+
+type ChatChannel struct {
+	TeamID    string
+	ChannelID string
+	SessionID uuid.UUID
+	LinkedBy  uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+type ChatLinkCode struct {
+	CodeHash     string
+	TeamID       string
+	ChatUserID   string
+	ChatUsername string
+	ExpiresAt    time.Time
+	CreatedAt    time.Time
+}
+
+type EmailChangeRevert struct {
+	ID             uuid.UUID
+	UserID         uuid.UUID
+	PreviousEmail  string
+	NewEmail       string
+	Selector       string
+	HashedVerifier string
+	ExpiresAt      time.Time
+	UsedAt         sql.NullTime
+	CreatedAt      time.Time
+}
+
+type EmailDeliveryAttempt struct {
+	ID                uuid.UUID
+	EmailID           uuid.UUID
+	Attempt           int32
+	Succeeded         bool
+	ProviderMessageID sql.NullString
+	Error             sql.NullString
+	CreatedAt         time.Time
+}
+
+type EmailOutbox struct {
+	ID                uuid.UUID
+	Recipient         string
+	Subject           string
+	Html              sql.NullString
+	Status            string
+	Attempts          int32
+	NextAttemptAt     time.Time
+	LastError         sql.NullString
+	ProviderMessageID sql.NullString
+	SentAt            sql.NullTime
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+	Template          sql.NullString
+	Locale            sql.NullString
+	TemplateData      pqtype.NullRawMessage
+	Headers           pqtype.NullRawMessage
+}
+
 type Ledger struct {
 	ID            uuid.UUID
 	TransactionID uuid.UUID
@@ -20,14 +90,64 @@ type Ledger struct {
 	CreatedAt     time.Time
 }
 
+type Notification struct {
+	ID            uuid.UUID
+	UserID        uuid.UUID
+	Type          string
+	SessionID     uuid.UUID
+	ActorID       uuid.UUID
+	TransactionID uuid.NullUUID
+	ReadAt        sql.NullTime
+	CreatedAt     time.Time
+}
+
+type PersonalAccessToken struct {
+	ID             uuid.UUID
+	UserID         uuid.UUID
+	Name           string
+	Selector       string
+	HashedVerifier string
+	Scopes         []string
+	ExpiresAt      time.Time
+	LastUsedAt     sql.NullTime
+	RevokedAt      sql.NullTime
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+type PushSubscription struct {
+	ID         uuid.UUID
+	UserID     uuid.UUID
+	Endpoint   string
+	P256dh     string
+	Auth       string
+	UserAgent  sql.NullString
+	ExpiresAt  sql.NullTime
+	LastUsedAt sql.NullTime
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+type RateLimitCounter struct {
+	Key       string
+	Hits      int32
+	ExpiresAt time.Time
+}
+
 type RefreshToken struct {
-	ID          int32
-	UserID      uuid.UUID
-	HashedToken string
-	ExpiresAt   time.Time
-	Revoked     bool
-	CreatedAt   time.Time
-	UpdatedAt   time.Time
+	ID             int32
+	UserID         uuid.UUID
+	ExpiresAt      time.Time
+	Revoked        bool
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	UserAgent      sql.NullString
+	IpAddress      sql.NullString
+	LastUsedAt     time.Time
+	Selector       string
+	HashedVerifier string
+	FamilyID       uuid.UUID
+	ReplacedAt     sql.NullTime
 }
 
 type Session struct {
@@ -71,21 +191,45 @@ type SessionTransactionLine struct {
 }
 
 type User struct {
-	ID                        uuid.UUID
-	Username                  string
-	Email                     string
-	UpdateEmail               sql.NullString
-	EmailUpdateRequestedAt    sql.NullTime
-	EmailUpdateOtp            sql.NullString
-	EmailLastUpdatedAt        sql.NullTime
-	Name                      string
-	HashedPassword            string
-	UpdateHashedPassword      sql.NullString
-	PasswordUpdateRequestedAt sql.NullTime
-	PasswordUpdateOtp         sql.NullString
-	PasswordLastUpdatedAt     sql.NullTime
-	CreatedAt                 time.Time
-	UpdatedAt                 time.Time
+	ID                              uuid.UUID
+	Username                        string
+	Email                           string
+	UpdateEmail                     sql.NullString
+	EmailUpdateRequestedAt          sql.NullTime
+	EmailUpdateOtp                  sql.NullString
+	EmailLastUpdatedAt              sql.NullTime
+	Name                            string
+	HashedPassword                  string
+	UpdateHashedPassword            sql.NullString
+	PasswordUpdateRequestedAt       sql.NullTime
+	PasswordUpdateOtp               sql.NullString
+	PasswordLastUpdatedAt           sql.NullTime
+	CreatedAt                       time.Time
+	UpdatedAt                       time.Time
+	EmailVerified                   bool
+	EmailVerificationOtp            sql.NullString
+	EmailVerificationRequestedAt    sql.NullTime
+	PasswordUpdateOtpAttempts       int32
+	EmailUpdateOtpAttempts          int32
+	EmailVerificationOtpAttempts    int32
+	PasswordOtpLockedUntil          sql.NullTime
+	EmailUpdateOtpLockedUntil       sql.NullTime
+	EmailVerificationOtpLockedUntil sql.NullTime
+	LoginOtp                        sql.NullString
+	LoginOtpRequestedAt             sql.NullTime
+	LoginOtpAttempts                int32
+	LoginOtpLockedUntil             sql.NullTime
+	Locale                          sql.NullString
+}
+
+type UserAuditEvent struct {
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	Event     string
+	Detail    sql.NullString
+	UserAgent sql.NullString
+	IpAddress sql.NullString
+	CreatedAt time.Time
 }
 
 type UserCreditScore struct {
@@ -99,8 +243,95 @@ type UserCreditScore struct {
 	StatusLabel           string
 }
 
+type UserIdentity struct {
+	ID          uuid.UUID
+	UserID      uuid.UUID
+	Provider    string
+	Subject     string
+	Email       sql.NullString
+	LastLoginAt sql.NullTime
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+type UserNotificationPreference struct {
+	UserID             uuid.UUID
+	WeeklyDigest       bool
+	SettleUpReminders  bool
+	UnsubscribeToken   uuid.UUID
+	NextDigestAt       time.Time
+	LastReminderSentAt sql.NullTime
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+	PushInvitations    bool
+	PushRounds         bool
+	PushSessionUpdates bool
+}
+
+type UserRecoveryCode struct {
+	ID         uuid.UUID
+	UserID     uuid.UUID
+	HashedCode string
+	UsedAt     sql.NullTime
+	CreatedAt  time.Time
+}
+
 type UserTotal struct {
 	UserID uuid.UUID
 	Credit float64
 	Debit  float64
 }
+
+type UserTwoFactor struct {
+	UserID         uuid.UUID
+	Secret         string
+	Enabled        bool
+	LastUsedStep   sql.NullInt64
+	EnabledAt      sql.NullTime
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	FailedAttempts int32
+	LockedUntil    sql.NullTime
+}
+
+type Webhook struct {
+	ID                  uuid.UUID
+	SessionID           uuid.NullUUID
+	UserID              uuid.NullUUID
+	CreatedBy           uuid.UUID
+	Url                 string
+	Secret              string
+	EventTypes          []string
+	Enabled             bool
+	ConsecutiveFailures int32
+	DisabledAt          sql.NullTime
+	DisabledReason      sql.NullString
+	CreatedAt           time.Time
+	UpdatedAt           time.Time
+}
+
+type WebhookDelivery struct {
+	ID            uuid.UUID
+	WebhookID     uuid.UUID
+	EventID       uuid.UUID
+	EventType     string
+	Payload       json.RawMessage
+	Status        string
+	Attempts      int32
+	NextAttemptAt time.Time
+	LastError     sql.NullString
+	DeliveredAt   sql.NullTime
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+type WebhookDeliveryAttempt struct {
+	ID             uuid.UUID
+	DeliveryID     uuid.UUID
+	Attempt        int32
+	Succeeded      bool
+	ResponseStatus sql.NullInt32
+	Error          sql.NullString
+	DurationMs     int32
+	CreatedAt      time.Time
+}
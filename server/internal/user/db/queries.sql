@@ -3,3 +3,8 @@ update users
 set name = $2, username = $3
 where id = $1
 returning name, username;
+
+-- name: UpdateUserLocale :exec
+update users
+set locale = $2
+where id = $1;
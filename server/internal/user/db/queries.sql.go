@@ -7,6 +7,7 @@ package db
 
 import (
 	"context"
+	"database/sql"
 
 	"github.com/google/uuid"
 )
@@ -35,3 +36,19 @@ func (q *Queries) UpdateUser(ctx context.Context, arg UpdateUserParams) (UpdateU
 	err := row.Scan(&i.Name, &i.Username)
 	return i, err
 }
+
+const updateUserLocale = `-- name: UpdateUserLocale :exec
+update users
+set locale = $2
+where id = $1
+`
+
+type UpdateUserLocaleParams struct {
+	ID     uuid.UUID
+	Locale sql.NullString
+}
+
+func (q *Queries) UpdateUserLocale(ctx context.Context, arg UpdateUserLocaleParams) error {
+	_, err := q.db.ExecContext(ctx, updateUserLocale, arg.ID, arg.Locale)
+	return err
+}
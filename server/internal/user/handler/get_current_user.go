@@ -23,9 +23,11 @@ func NewGetCurrentUserHandler(userReader useraccess.UserReader, logger *slog.Log
 }
 
 type GetCurrentUserResponse struct {
-	ID       uuid.UUID `json:"id"`
-	Name     string    `json:"name"`
-	Username string    `json:"username"`
+	ID            uuid.UUID `json:"id"`
+	Name          string    `json:"name"`
+	Username      string    `json:"username"`
+	EmailVerified bool      `json:"emailVerified"`
+	Locale        string    `json:"locale"`
 }
 
 func (h *GetCurrentUserHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -47,8 +49,10 @@ func (h *GetCurrentUserHandler) ServeHTTP(w http.ResponseWriter, r *http.Request
 	}
 
 	send.JSON(w, GetCurrentUserResponse{
-		ID:       user.ID,
-		Name:     user.Name,
-		Username: user.Username,
+		ID:            user.ID,
+		Name:          user.Name,
+		Username:      user.Username,
+		EmailVerified: user.EmailVerified,
+		Locale:        user.Locale,
 	}, http.StatusOK)
 }
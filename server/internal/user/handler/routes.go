@@ -20,8 +20,10 @@ func BuildRoutes(
 	userReaderService := useraccess.NewUserReaderService(uaQueries)
 
 	updateUserCommand := command.NewUpdateUserCommand(queries)
+	updateUserLocaleCommand := command.NewUpdateUserLocaleCommand(queries)
 
 	mux.Handle("GET /user", NewGetCurrentUserHandler(userReaderService, logger))
 	mux.Handle("PUT /user", NewUpdateUserHandler(updateUserCommand, userReaderService, logger))
+	mux.Handle("PUT /user/locale", NewUpdateUserLocaleHandler(updateUserLocaleCommand, logger))
 	mux.Handle("GET /user/{userId}/balance", NewGetCurrentUserBalanceHandler(userReaderService, logger))
 }
@@ -4,9 +4,9 @@ import (
 	"beerbux/internal/common/claims"
 	"beerbux/internal/common/useraccess"
 	"beerbux/internal/user/command"
+	"beerbux/pkg/i18n"
 	"beerbux/pkg/send"
 	"encoding/json"
-	"fmt"
 	"log/slog"
 	"net/http"
 	"strings"
@@ -56,7 +56,7 @@ func (h *UpdateUserHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 			return
 		}
 		if usernameTaken {
-			send.BadRequest(w, fmt.Sprintf("Username %s is already taken", newUsername))
+			send.BadRequest(w, i18n.Sprintf(i18n.FromContext(r.Context()), "Username %s is already taken", newUsername))
 			return
 		}
 	}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"beerbux/internal/common/claims"
+	"beerbux/internal/user/command"
+	"beerbux/pkg/i18n"
+	"beerbux/pkg/send"
+	"encoding/json"
+	"errors"
+	oz "github.com/go-ozzo/ozzo-validation/v4"
+	"log/slog"
+	"net/http"
+)
+
+type UpdateUserLocaleHandler struct {
+	updateUserLocaleCommand *command.UpdateUserLocaleCommand
+	logger                  *slog.Logger
+}
+
+func NewUpdateUserLocaleHandler(updateUserLocaleCommand *command.UpdateUserLocaleCommand, logger *slog.Logger) *UpdateUserLocaleHandler {
+	return &UpdateUserLocaleHandler{
+		updateUserLocaleCommand: updateUserLocaleCommand,
+		logger:                  logger,
+	}
+}
+
+type UpdateUserLocaleRequest struct {
+	Locale string `json:"locale"`
+}
+
+type UpdateUserLocaleResponse struct {
+	Locale           string   `json:"locale"`
+	SupportedLocales []string `json:"supportedLocales"`
+}
+
+func (h *UpdateUserLocaleHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c := claims.GetClaims(r)
+	if !c.Authenticated() {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var req UpdateUserLocaleRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		send.BadRequest(w, "Failed to decode request")
+		return
+	}
+
+	if err := req.Validate(); err != nil {
+		send.ValidationError(w, err)
+		return
+	}
+
+	if err := h.updateUserLocaleCommand.Execute(r.Context(), c.Subject, req.Locale); err != nil {
+		h.logger.Error("failed to update user locale", "user", c.Subject, "error", err)
+		send.InternalServerError(w, "There has been an issue updating your language")
+		return
+	}
+
+	send.JSON(w, UpdateUserLocaleResponse{
+		Locale:           req.Locale,
+		SupportedLocales: i18n.SupportedLocales(),
+	}, http.StatusOK)
+}
+
+func (r UpdateUserLocaleRequest) Validate() error {
+	return oz.ValidateStruct(&r,
+		oz.Field(&r.Locale, oz.By(func(value interface{}) error {
+			locale, _ := value.(string)
+			if locale != "" && !i18n.Supported(locale) {
+				return errors.New("the language is not supported")
+			}
+			return nil
+		})),
+	)
+}
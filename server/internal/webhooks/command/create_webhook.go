@@ -0,0 +1,120 @@
+package command
+
+import (
+	"beerbux/internal/common/history"
+	"beerbux/internal/webhooks/db"
+	"beerbux/internal/webhooks/query"
+	"beerbux/pkg/webhook"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"net/url"
+	"slices"
+)
+
+// MaxWebhooksPerOwner is the number of webhooks a session or a user may have.
+const MaxWebhooksPerOwner = 10
+
+var (
+	ErrInvalidWebhookURL    = errors.New("invalid webhook url")
+	ErrInvalidEventType     = errors.New("invalid webhook event type")
+	ErrTooManyWebhooks      = errors.New("too many webhooks")
+	ErrWebhookOwnerRequired = errors.New("webhook must belong to either a session or a user")
+)
+
+// EventTypes are the session history event types webhooks can subscribe to.
+var EventTypes = []string{
+	history.EventSessionOpened,
+	history.EventSessionClosed,
+	history.EventMemberAdded,
+	history.EventMemberRemoved,
+	history.EventMemberLeft,
+	history.EventMemberPromotedToAdmin,
+	history.EventMemberDemotedFromAdmin,
+	history.EventTransactionCreated,
+}
+
+type CreateWebhookCommand struct {
+	queries *db.Queries
+	// allowInsecureURLs permits http webhook URLs, such as a receiver running locally in development.
+	allowInsecureURLs bool
+}
+
+func NewCreateWebhookCommand(queries *db.Queries, allowInsecureURLs bool) *CreateWebhookCommand {
+	return &CreateWebhookCommand{
+		queries:           queries,
+		allowInsecureURLs: allowInsecureURLs,
+	}
+}
+
+// CreateWebhookRequest describes a webhook for either a session or a user.
+// An empty EventTypes subscribes the webhook to every event type.
+type CreateWebhookRequest struct {
+	SessionID  uuid.NullUUID
+	UserID     uuid.NullUUID
+	CreatedBy  uuid.UUID
+	URL        string
+	EventTypes []string
+}
+
+// CreatedWebhookResponse includes the secret used to sign the webhook's requests,
+// which cannot be retrieved again.
+type CreatedWebhookResponse struct {
+	query.WebhookResponse
+	Secret string `json:"secret"`
+}
+
+func (c *CreateWebhookCommand) Execute(ctx context.Context, r CreateWebhookRequest) (CreatedWebhookResponse, error) {
+	if r.SessionID.Valid == r.UserID.Valid {
+		return CreatedWebhookResponse{}, ErrWebhookOwnerRequired
+	}
+	u, err := url.Parse(r.URL)
+	if err != nil || u.Host == "" || (u.Scheme != "https" && !(c.allowInsecureURLs && u.Scheme == "http")) {
+		return CreatedWebhookResponse{}, ErrInvalidWebhookURL
+	}
+	eventTypes := make([]string, 0, len(r.EventTypes))
+	for _, t := range r.EventTypes {
+		if !slices.Contains(EventTypes, t) {
+			return CreatedWebhookResponse{}, fmt.Errorf("%w: %s", ErrInvalidEventType, t)
+		}
+		if !slices.Contains(eventTypes, t) {
+			eventTypes = append(eventTypes, t)
+		}
+	}
+
+	var count int64
+	if r.SessionID.Valid {
+		count, err = c.queries.CountSessionWebhooks(ctx, r.SessionID)
+	} else {
+		count, err = c.queries.CountUserWebhooks(ctx, r.UserID)
+	}
+	if err != nil {
+		return CreatedWebhookResponse{}, fmt.Errorf("failed to count webhooks: %w", err)
+	}
+	if count >= MaxWebhooksPerOwner {
+		return CreatedWebhookResponse{}, ErrTooManyWebhooks
+	}
+
+	secret, err := webhook.GenerateSecret()
+	if err != nil {
+		return CreatedWebhookResponse{}, fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+
+	w, err := c.queries.CreateWebhook(ctx, db.CreateWebhookParams{
+		SessionID:  r.SessionID,
+		UserID:     r.UserID,
+		CreatedBy:  r.CreatedBy,
+		Url:        r.URL,
+		Secret:     secret,
+		EventTypes: eventTypes,
+	})
+	if err != nil {
+		return CreatedWebhookResponse{}, fmt.Errorf("failed to create webhook: %w", err)
+	}
+
+	return CreatedWebhookResponse{
+		WebhookResponse: query.NewWebhookResponse(w),
+		Secret:          secret,
+	}, nil
+}
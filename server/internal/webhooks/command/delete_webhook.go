@@ -0,0 +1,35 @@
+package command
+
+import (
+	"beerbux/internal/webhooks"
+	"beerbux/internal/webhooks/db"
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+)
+
+type DeleteWebhookCommand struct {
+	queries *db.Queries
+}
+
+func NewDeleteWebhookCommand(queries *db.Queries) *DeleteWebhookCommand {
+	return &DeleteWebhookCommand{
+		queries: queries,
+	}
+}
+
+// Execute deletes the webhook and its delivery log. ErrWebhookNotFound is returned if the
+// webhook does not exist or the user cannot manage it.
+func (c *DeleteWebhookCommand) Execute(ctx context.Context, userID, webhookID uuid.UUID) error {
+	n, err := c.queries.DeleteAccessibleWebhook(ctx, db.DeleteAccessibleWebhookParams{
+		ID:     webhookID,
+		UserID: userID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook %s: %w", webhookID, err)
+	}
+	if n == 0 {
+		return webhooks.ErrWebhookNotFound
+	}
+	return nil
+}
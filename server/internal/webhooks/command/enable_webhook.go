@@ -0,0 +1,36 @@
+package command
+
+import (
+	"beerbux/internal/webhooks"
+	"beerbux/internal/webhooks/db"
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+)
+
+type EnableWebhookCommand struct {
+	queries *db.Queries
+}
+
+func NewEnableWebhookCommand(queries *db.Queries) *EnableWebhookCommand {
+	return &EnableWebhookCommand{
+		queries: queries,
+	}
+}
+
+// Execute enables a webhook that was disabled after repeated failures, clearing its failure count.
+// Deliveries failed while it was disabled are not sent; they can be redelivered individually.
+// ErrWebhookNotFound is returned if the webhook does not exist or the user cannot manage it.
+func (c *EnableWebhookCommand) Execute(ctx context.Context, userID, webhookID uuid.UUID) error {
+	n, err := c.queries.EnableAccessibleWebhook(ctx, db.EnableAccessibleWebhookParams{
+		ID:     webhookID,
+		UserID: userID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to enable webhook %s: %w", webhookID, err)
+	}
+	if n == 0 {
+		return webhooks.ErrWebhookNotFound
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package command
+
+import (
+	"beerbux/internal/webhooks"
+	"beerbux/internal/webhooks/db"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+)
+
+type RedeliverCommand struct {
+	queries *db.Queries
+	outbox  *webhooks.Outbox
+}
+
+func NewRedeliverCommand(queries *db.Queries, outbox *webhooks.Outbox) *RedeliverCommand {
+	return &RedeliverCommand{
+		queries: queries,
+		outbox:  outbox,
+	}
+}
+
+// Execute queues a failed delivery of the webhook to be sent again. ErrWebhookNotFound is
+// returned if the webhook does not exist or the user cannot manage it, and ErrDeliveryNotFailed
+// if the webhook has no such failed delivery.
+func (c *RedeliverCommand) Execute(ctx context.Context, userID, webhookID, deliveryID uuid.UUID) error {
+	w, err := c.queries.GetAccessibleWebhook(ctx, db.GetAccessibleWebhookParams{
+		ID:     webhookID,
+		UserID: userID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return webhooks.ErrWebhookNotFound
+		}
+		return fmt.Errorf("failed to get webhook %s: %w", webhookID, err)
+	}
+	if !w.Enabled {
+		return webhooks.ErrWebhookDisabled
+	}
+
+	return c.outbox.Redeliver(ctx, webhookID, deliveryID)
+}
@@ -0,0 +1,181 @@
+-- name: CreateWebhook :one
+insert into webhooks (session_id, user_id, created_by, url, secret, event_types)
+values ($1, $2, $3, $4, $5, $6)
+returning *;
+
+-- name: CountSessionWebhooks :one
+select count(*)
+from webhooks
+where session_id = $1;
+
+-- name: CountUserWebhooks :one
+select count(*)
+from webhooks
+where user_id = $1;
+
+-- name: ListSessionWebhooks :many
+select *
+from webhooks
+where session_id = $1
+order by created_at;
+
+-- name: ListUserWebhooks :many
+select *
+from webhooks
+where user_id = $1
+order by created_at;
+
+-- name: GetAccessibleWebhook :one
+select w.*
+from webhooks w
+where w.id = @id
+  and (w.user_id = @user_id::uuid
+       or exists (
+           select 1
+           from session_members m
+           where m.session_id = w.session_id
+             and m.member_id = @user_id::uuid
+             and m.is_admin
+             and not m.is_deleted
+       ));
+
+-- name: DeleteAccessibleWebhook :execrows
+delete from webhooks w
+where w.id = @id
+  and (w.user_id = @user_id::uuid
+       or exists (
+           select 1
+           from session_members m
+           where m.session_id = w.session_id
+             and m.member_id = @user_id::uuid
+             and m.is_admin
+             and not m.is_deleted
+       ));
+
+-- name: EnableAccessibleWebhook :execrows
+update webhooks w
+set enabled = true,
+    consecutive_failures = 0,
+    disabled_at = null,
+    disabled_reason = null
+where w.id = @id
+  and (w.user_id = @user_id::uuid
+       or exists (
+           select 1
+           from session_members m
+           where m.session_id = w.session_id
+             and m.member_id = @user_id::uuid
+             and m.is_admin
+             and not m.is_deleted
+       ));
+
+-- name: EnqueueWebhookDeliveries :execrows
+insert into webhook_deliveries (webhook_id, event_id, event_type, payload)
+select w.id, @event_id, @event_type::text, @payload
+from webhooks w
+where w.enabled
+  and (cardinality(w.event_types) = 0 or @event_type::text = any(w.event_types))
+  and (w.session_id = @session_id::uuid
+       or w.user_id in (
+           select m.member_id
+           from session_members m
+           where m.session_id = @session_id::uuid
+             and not m.is_deleted
+       )
+       or w.user_id = sqlc.narg('subject_id'));
+
+-- name: ClaimDueWebhookDeliveries :many
+update webhook_deliveries d
+set status = 'sending',
+    attempts = d.attempts + 1,
+    next_attempt_at = now() + make_interval(secs => @lease_seconds::int)
+from webhooks w
+where w.id = d.webhook_id
+  and d.id in (
+    select id
+    from webhook_deliveries
+    where status in ('pending', 'sending')
+      and next_attempt_at <= now()
+    order by next_attempt_at
+    limit @batch_size
+    for update skip locked
+)
+returning d.id, d.webhook_id, d.event_type, d.payload, d.attempts, w.url, w.secret;
+
+-- name: MarkWebhookDeliverySucceeded :exec
+update webhook_deliveries
+set status = 'succeeded',
+    last_error = null,
+    delivered_at = now()
+where id = $1;
+
+-- name: MarkWebhookDeliveryForRetry :exec
+update webhook_deliveries
+set status = 'pending',
+    last_error = $2,
+    next_attempt_at = $3
+where id = $1;
+
+-- name: MarkWebhookDeliveryFailed :exec
+update webhook_deliveries
+set status = 'failed',
+    last_error = $2
+where id = $1;
+
+-- name: FailPendingWebhookDeliveries :execrows
+update webhook_deliveries
+set status = 'failed',
+    last_error = $2
+where webhook_id = $1
+  and status in ('pending', 'sending');
+
+-- name: CreateWebhookDeliveryAttempt :exec
+insert into webhook_delivery_attempts (delivery_id, attempt, succeeded, response_status, error, duration_ms)
+values ($1, $2, $3, $4, $5, $6);
+
+-- name: ResetWebhookFailures :exec
+update webhooks
+set consecutive_failures = 0
+where id = $1
+  and consecutive_failures > 0;
+
+-- name: IncrementWebhookFailures :one
+update webhooks
+set consecutive_failures = consecutive_failures + 1
+where id = $1
+returning consecutive_failures;
+
+-- name: DisableWebhook :exec
+update webhooks
+set enabled = false,
+    disabled_at = now(),
+    disabled_reason = $2
+where id = $1
+  and enabled;
+
+-- name: ListWebhookDeliveries :many
+select *
+from webhook_deliveries
+where webhook_id = @webhook_id
+order by created_at desc
+limit @max_results;
+
+-- name: ListWebhookDeliveryAttempts :many
+select *
+from webhook_delivery_attempts
+where delivery_id = any(@delivery_ids::uuid[])
+order by delivery_id, attempt;
+
+-- name: RedeliverWebhookDelivery :execrows
+update webhook_deliveries
+set status = 'pending',
+    attempts = 0,
+    next_attempt_at = now()
+where id = $1
+  and webhook_id = $2
+  and status = 'failed';
+
+-- name: PurgeWebhookDeliveries :execrows
+delete from webhook_deliveries
+where status in ('succeeded', 'failed')
+  and created_at < now() - make_interval(days => @retention_days::int);
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"beerbux/internal/common/sessionaccess"
+	"beerbux/internal/webhooks/command"
+	"beerbux/pkg/send"
+	"errors"
+	"github.com/google/uuid"
+	"log/slog"
+	"net/http"
+)
+
+// handleCreateWebhookError sends the response for an error creating a webhook.
+func handleCreateWebhookError(w http.ResponseWriter, logger *slog.Logger, err error, owner string, ownerID uuid.UUID) {
+	switch {
+	case errors.Is(err, command.ErrInvalidWebhookURL):
+		send.BadRequest(w, "The webhook URL must be an https URL")
+	case errors.Is(err, command.ErrInvalidEventType):
+		send.BadRequest(w, "The event types include an unknown event type")
+	case errors.Is(err, command.ErrTooManyWebhooks):
+		send.BadRequest(w, "The maximum number of webhooks has been reached")
+	default:
+		logger.Error("failed to create webhook", owner, ownerID, "error", err)
+		send.InternalServerError(w, "There has been an issue creating the webhook")
+	}
+}
+
+// authorizeSessionAdmin sends an error response and returns false unless the user is an admin of the session.
+func authorizeSessionAdmin(w http.ResponseWriter, r *http.Request, sr sessionaccess.SessionReader, sessionID, userID uuid.UUID) bool {
+	session, err := sr.GetSessionDetails(r.Context(), sessionID)
+	if err != nil {
+		if errors.Is(err, sessionaccess.ErrSessionNotFound) {
+			send.NotFound(w, "The session could not be found")
+			return false
+		}
+		send.InternalServerError(w, "There was an issue finding the session")
+		return false
+	}
+
+	if !session.HasMember(userID) {
+		send.Unauthorized(w, "You are not a member of this session")
+		return false
+	}
+	if !session.HasAdminMember(userID) {
+		send.Unauthorized(w, "You must be an admin to manage the session's webhooks")
+		return false
+	}
+	return true
+}
@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"beerbux/internal/common/claims"
+	"beerbux/internal/common/sessionaccess"
+	"beerbux/internal/webhooks/command"
+	"beerbux/pkg/send"
+	"beerbux/pkg/url"
+	"encoding/json"
+	oz "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/google/uuid"
+	"log/slog"
+	"net/http"
+)
+
+type CreateSessionWebhookHandler struct {
+	sessionReader        sessionaccess.SessionReader
+	createWebhookCommand *command.CreateWebhookCommand
+	logger               *slog.Logger
+}
+
+func NewCreateSessionWebhookHandler(
+	sr sessionaccess.SessionReader,
+	createWebhookCommand *command.CreateWebhookCommand,
+	logger *slog.Logger,
+) *CreateSessionWebhookHandler {
+	return &CreateSessionWebhookHandler{
+		sessionReader:        sr,
+		createWebhookCommand: createWebhookCommand,
+		logger:               logger,
+	}
+}
+
+// CreateWebhookRequest subscribes a URL to session history events.
+// Leaving out EventTypes subscribes the webhook to every event type.
+type CreateWebhookRequest struct {
+	URL        string   `json:"url"`
+	EventTypes []string `json:"eventTypes"`
+}
+
+// ServeHTTP creates a webhook receiving the events of the session. Only admins of the session
+// can manage its webhooks.
+func (h *CreateSessionWebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c := claims.GetClaims(r)
+	if !c.Authenticated() {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	sessionID, ok := url.Path.GetUUID(r, "sessionId")
+	if !ok {
+		send.BadRequest(w, "Missing params")
+		return
+	}
+
+	var req CreateWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		send.BadRequest(w, "Failed to decode request")
+		return
+	}
+	if err := req.Validate(); err != nil {
+		send.ValidationError(w, err)
+		return
+	}
+
+	if !authorizeSessionAdmin(w, r, h.sessionReader, sessionID, c.Subject) {
+		return
+	}
+
+	webhook, err := h.createWebhookCommand.Execute(r.Context(), command.CreateWebhookRequest{
+		SessionID:  uuid.NullUUID{UUID: sessionID, Valid: true},
+		CreatedBy:  c.Subject,
+		URL:        req.URL,
+		EventTypes: req.EventTypes,
+	})
+	if err != nil {
+		handleCreateWebhookError(w, h.logger, err, "session", sessionID)
+		return
+	}
+
+	send.JSON(w, webhook, http.StatusCreated)
+}
+
+func (r CreateWebhookRequest) Validate() error {
+	return oz.ValidateStruct(&r,
+		oz.Field(&r.URL, oz.Required, is.URL),
+	)
+}
@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"beerbux/internal/common/claims"
+	"beerbux/internal/webhooks/command"
+	"beerbux/pkg/send"
+	"encoding/json"
+	"github.com/google/uuid"
+	"log/slog"
+	"net/http"
+)
+
+type CreateUserWebhookHandler struct {
+	createWebhookCommand *command.CreateWebhookCommand
+	logger               *slog.Logger
+}
+
+func NewCreateUserWebhookHandler(createWebhookCommand *command.CreateWebhookCommand, logger *slog.Logger) *CreateUserWebhookHandler {
+	return &CreateUserWebhookHandler{
+		createWebhookCommand: createWebhookCommand,
+		logger:               logger,
+	}
+}
+
+// ServeHTTP creates a webhook receiving the events of every session the user is a member of.
+func (h *CreateUserWebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c := claims.GetClaims(r)
+	if !c.Authenticated() {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	var req CreateWebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		send.BadRequest(w, "Failed to decode request")
+		return
+	}
+	if err := req.Validate(); err != nil {
+		send.ValidationError(w, err)
+		return
+	}
+
+	webhook, err := h.createWebhookCommand.Execute(r.Context(), command.CreateWebhookRequest{
+		UserID:     uuid.NullUUID{UUID: c.Subject, Valid: true},
+		CreatedBy:  c.Subject,
+		URL:        req.URL,
+		EventTypes: req.EventTypes,
+	})
+	if err != nil {
+		handleCreateWebhookError(w, h.logger, err, "user", c.Subject)
+		return
+	}
+
+	send.JSON(w, webhook, http.StatusCreated)
+}
@@ -0,0 +1,50 @@
+package handler
+
+import (
+	"beerbux/internal/common/claims"
+	"beerbux/internal/webhooks"
+	"beerbux/internal/webhooks/command"
+	"beerbux/pkg/send"
+	"beerbux/pkg/url"
+	"errors"
+	"log/slog"
+	"net/http"
+)
+
+type DeleteWebhookHandler struct {
+	deleteWebhookCommand *command.DeleteWebhookCommand
+	logger               *slog.Logger
+}
+
+func NewDeleteWebhookHandler(deleteWebhookCommand *command.DeleteWebhookCommand, logger *slog.Logger) *DeleteWebhookHandler {
+	return &DeleteWebhookHandler{
+		deleteWebhookCommand: deleteWebhookCommand,
+		logger:               logger,
+	}
+}
+
+func (h *DeleteWebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c := claims.GetClaims(r)
+	if !c.Authenticated() {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	webhookID, ok := url.Path.GetUUID(r, "webhookId")
+	if !ok {
+		send.BadRequest(w, "Missing params")
+		return
+	}
+
+	if err := h.deleteWebhookCommand.Execute(r.Context(), c.Subject, webhookID); err != nil {
+		if errors.Is(err, webhooks.ErrWebhookNotFound) {
+			send.NotFound(w, "Webhook not found")
+			return
+		}
+		h.logger.Error("failed to delete webhook", "webhook", webhookID, "error", err)
+		send.InternalServerError(w, "There has been an issue deleting the webhook")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,51 @@
+package handler
+
+import (
+	"beerbux/internal/common/claims"
+	"beerbux/internal/webhooks"
+	"beerbux/internal/webhooks/command"
+	"beerbux/pkg/send"
+	"beerbux/pkg/url"
+	"errors"
+	"log/slog"
+	"net/http"
+)
+
+type EnableWebhookHandler struct {
+	enableWebhookCommand *command.EnableWebhookCommand
+	logger               *slog.Logger
+}
+
+func NewEnableWebhookHandler(enableWebhookCommand *command.EnableWebhookCommand, logger *slog.Logger) *EnableWebhookHandler {
+	return &EnableWebhookHandler{
+		enableWebhookCommand: enableWebhookCommand,
+		logger:               logger,
+	}
+}
+
+// ServeHTTP enables a webhook that was disabled after repeated failures.
+func (h *EnableWebhookHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c := claims.GetClaims(r)
+	if !c.Authenticated() {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	webhookID, ok := url.Path.GetUUID(r, "webhookId")
+	if !ok {
+		send.BadRequest(w, "Missing params")
+		return
+	}
+
+	if err := h.enableWebhookCommand.Execute(r.Context(), c.Subject, webhookID); err != nil {
+		if errors.Is(err, webhooks.ErrWebhookNotFound) {
+			send.NotFound(w, "Webhook not found")
+			return
+		}
+		h.logger.Error("failed to enable webhook", "webhook", webhookID, "error", err)
+		send.InternalServerError(w, "There has been an issue enabling the webhook")
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"beerbux/internal/common/claims"
+	"beerbux/internal/webhooks"
+	"beerbux/internal/webhooks/query"
+	"beerbux/pkg/send"
+	"beerbux/pkg/url"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+)
+
+type ListDeliveriesHandler struct {
+	listDeliveriesQuery *query.ListDeliveriesQuery
+	logger              *slog.Logger
+}
+
+func NewListDeliveriesHandler(listDeliveriesQuery *query.ListDeliveriesQuery, logger *slog.Logger) *ListDeliveriesHandler {
+	return &ListDeliveriesHandler{
+		listDeliveriesQuery: listDeliveriesQuery,
+		logger:              logger,
+	}
+}
+
+// ServeHTTP returns the delivery log of the webhook, newest first. The optional limit query
+// parameter caps the number of deliveries returned.
+func (h *ListDeliveriesHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c := claims.GetClaims(r)
+	if !c.Authenticated() {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	webhookID, ok := url.Path.GetUUID(r, "webhookId")
+	if !ok {
+		send.BadRequest(w, "Missing params")
+		return
+	}
+
+	limit := query.DefaultDeliveryLimit
+	if v := r.URL.Query().Get("limit"); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			send.BadRequest(w, "The limit must be a positive number")
+			return
+		}
+		limit = n
+	}
+
+	deliveries, err := h.listDeliveriesQuery.Execute(r.Context(), c.Subject, webhookID, limit)
+	if err != nil {
+		if errors.Is(err, webhooks.ErrWebhookNotFound) {
+			send.NotFound(w, "Webhook not found")
+			return
+		}
+		h.logger.Error("failed to list webhook deliveries", "webhook", webhookID, "error", err)
+		send.InternalServerError(w, "There has been an issue fetching the webhook deliveries")
+		return
+	}
+
+	send.JSON(w, deliveries, http.StatusOK)
+}
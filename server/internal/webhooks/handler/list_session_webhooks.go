@@ -0,0 +1,55 @@
+package handler
+
+import (
+	"beerbux/internal/common/claims"
+	"beerbux/internal/common/sessionaccess"
+	"beerbux/internal/webhooks/query"
+	"beerbux/pkg/send"
+	"beerbux/pkg/url"
+	"log/slog"
+	"net/http"
+)
+
+type ListSessionWebhooksHandler struct {
+	sessionReader            sessionaccess.SessionReader
+	listSessionWebhooksQuery *query.ListSessionWebhooksQuery
+	logger                   *slog.Logger
+}
+
+func NewListSessionWebhooksHandler(
+	sr sessionaccess.SessionReader,
+	listSessionWebhooksQuery *query.ListSessionWebhooksQuery,
+	logger *slog.Logger,
+) *ListSessionWebhooksHandler {
+	return &ListSessionWebhooksHandler{
+		sessionReader:            sr,
+		listSessionWebhooksQuery: listSessionWebhooksQuery,
+		logger:                   logger,
+	}
+}
+
+func (h *ListSessionWebhooksHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c := claims.GetClaims(r)
+	if !c.Authenticated() {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	sessionID, ok := url.Path.GetUUID(r, "sessionId")
+	if !ok {
+		send.BadRequest(w, "Missing params")
+		return
+	}
+	if !authorizeSessionAdmin(w, r, h.sessionReader, sessionID, c.Subject) {
+		return
+	}
+
+	webhooks, err := h.listSessionWebhooksQuery.Execute(r.Context(), sessionID)
+	if err != nil {
+		h.logger.Error("failed to list session webhooks", "session", sessionID, "error", err)
+		send.InternalServerError(w, "There has been an issue fetching the webhooks")
+		return
+	}
+
+	send.JSON(w, webhooks, http.StatusOK)
+}
@@ -0,0 +1,38 @@
+package handler
+
+import (
+	"beerbux/internal/common/claims"
+	"beerbux/internal/webhooks/query"
+	"beerbux/pkg/send"
+	"log/slog"
+	"net/http"
+)
+
+type ListUserWebhooksHandler struct {
+	listUserWebhooksQuery *query.ListUserWebhooksQuery
+	logger                *slog.Logger
+}
+
+func NewListUserWebhooksHandler(listUserWebhooksQuery *query.ListUserWebhooksQuery, logger *slog.Logger) *ListUserWebhooksHandler {
+	return &ListUserWebhooksHandler{
+		listUserWebhooksQuery: listUserWebhooksQuery,
+		logger:                logger,
+	}
+}
+
+func (h *ListUserWebhooksHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c := claims.GetClaims(r)
+	if !c.Authenticated() {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	webhooks, err := h.listUserWebhooksQuery.Execute(r.Context(), c.Subject)
+	if err != nil {
+		h.logger.Error("failed to list user webhooks", "user", c.Subject, "error", err)
+		send.InternalServerError(w, "There has been an issue fetching the webhooks")
+		return
+	}
+
+	send.JSON(w, webhooks, http.StatusOK)
+}
@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"beerbux/internal/common/claims"
+	"beerbux/internal/webhooks"
+	"beerbux/internal/webhooks/command"
+	"beerbux/pkg/send"
+	"beerbux/pkg/url"
+	"errors"
+	"log/slog"
+	"net/http"
+)
+
+type RedeliverHandler struct {
+	redeliverCommand *command.RedeliverCommand
+	logger           *slog.Logger
+}
+
+func NewRedeliverHandler(redeliverCommand *command.RedeliverCommand, logger *slog.Logger) *RedeliverHandler {
+	return &RedeliverHandler{
+		redeliverCommand: redeliverCommand,
+		logger:           logger,
+	}
+}
+
+// ServeHTTP queues a failed delivery to be sent again.
+func (h *RedeliverHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	c := claims.GetClaims(r)
+	if !c.Authenticated() {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+
+	webhookID, ok := url.Path.GetUUID(r, "webhookId")
+	if !ok {
+		send.BadRequest(w, "Missing params")
+		return
+	}
+	deliveryID, ok := url.Path.GetUUID(r, "deliveryId")
+	if !ok {
+		send.BadRequest(w, "Missing params")
+		return
+	}
+
+	if err := h.redeliverCommand.Execute(r.Context(), c.Subject, webhookID, deliveryID); err != nil {
+		switch {
+		case errors.Is(err, webhooks.ErrWebhookNotFound):
+			send.NotFound(w, "Webhook not found")
+		case errors.Is(err, webhooks.ErrWebhookDisabled):
+			send.BadRequest(w, "The webhook must be enabled to redeliver")
+		case errors.Is(err, webhooks.ErrDeliveryNotFailed):
+			send.NotFound(w, "Failed delivery not found")
+		default:
+			h.logger.Error("failed to redeliver webhook delivery", "delivery", deliveryID, "error", err)
+			send.InternalServerError(w, "There has been an issue redelivering the webhook")
+		}
+		return
+	}
+
+	w.WriteHeader(http.StatusAccepted)
+}
@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"beerbux/internal/api/config"
+	"beerbux/internal/common/sessionaccess"
+	sessionaccessQueries "beerbux/internal/common/sessionaccess/db"
+	"beerbux/internal/webhooks"
+	"beerbux/internal/webhooks/command"
+	"beerbux/internal/webhooks/db"
+	"beerbux/internal/webhooks/query"
+	"database/sql"
+	"log/slog"
+	"net/http"
+)
+
+func BuildRoutes(conf *config.Config, logger *slog.Logger, database *sql.DB, outbox *webhooks.Outbox, mux *http.ServeMux) {
+	queries := db.New(database)
+	sessionReaderService := sessionaccess.NewSessionService(sessionaccessQueries.New(database))
+
+	listSessionWebhooksQuery := query.NewListSessionWebhooksQuery(queries)
+	listUserWebhooksQuery := query.NewListUserWebhooksQuery(queries)
+	listDeliveriesQuery := query.NewListDeliveriesQuery(queries)
+	createWebhookCommand := command.NewCreateWebhookCommand(queries, conf.Environment.IsDevelopment())
+	deleteWebhookCommand := command.NewDeleteWebhookCommand(queries)
+	enableWebhookCommand := command.NewEnableWebhookCommand(queries)
+	redeliverCommand := command.NewRedeliverCommand(queries, outbox)
+
+	mux.Handle("GET /session/{sessionId}/webhooks", NewListSessionWebhooksHandler(sessionReaderService, listSessionWebhooksQuery, logger))
+	mux.Handle("POST /session/{sessionId}/webhooks", NewCreateSessionWebhookHandler(sessionReaderService, createWebhookCommand, logger))
+	mux.Handle("GET /user/webhooks", NewListUserWebhooksHandler(listUserWebhooksQuery, logger))
+	mux.Handle("POST /user/webhooks", NewCreateUserWebhookHandler(createWebhookCommand, logger))
+
+	mux.Handle("DELETE /webhooks/{webhookId}", NewDeleteWebhookHandler(deleteWebhookCommand, logger))
+	mux.Handle("POST /webhooks/{webhookId}/enable", NewEnableWebhookHandler(enableWebhookCommand, logger))
+	mux.Handle("GET /webhooks/{webhookId}/deliveries", NewListDeliveriesHandler(listDeliveriesQuery, logger))
+	mux.Handle("POST /webhooks/{webhookId}/deliveries/{deliveryId}/redeliver", NewRedeliverHandler(redeliverCommand, logger))
+}
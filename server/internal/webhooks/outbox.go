@@ -0,0 +1,97 @@
+package webhooks
+
+import (
+	"beerbux/internal/webhooks/db"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"time"
+)
+
+var (
+	ErrWebhookNotFound   = errors.New("webhook not found")
+	ErrWebhookDisabled   = errors.New("webhook is disabled")
+	ErrDeliveryNotFailed = errors.New("webhook delivery not found or has not failed")
+)
+
+// Event is the JSON body sent to webhooks. It mirrors a session history event, with EventData
+// holding the same data as the event in the session history.
+type Event struct {
+	ID        uuid.UUID `json:"id"`
+	EventType string    `json:"eventType"`
+	SessionID uuid.UUID `json:"sessionId"`
+	// MemberID is the member who performed the action.
+	MemberID  uuid.UUID   `json:"memberId"`
+	EventData interface{} `json:"eventData,omitempty"`
+	CreatedAt time.Time   `json:"createdAt"`
+}
+
+// Outbox queues a delivery of each event for every webhook subscribed to it, to be sent by the Worker.
+type Outbox struct {
+	queries *db.Queries
+	notify  chan struct{}
+}
+
+func NewOutbox(queries *db.Queries) *Outbox {
+	return &Outbox{
+		queries: queries,
+		notify:  make(chan struct{}, 1),
+	}
+}
+
+// Enqueue queues the event for the webhooks of its session, the webhooks of the session's
+// members and, when valid, the webhooks of subjectID, who may no longer be a member of the
+// session. It returns how many deliveries were queued.
+func (o *Outbox) Enqueue(ctx context.Context, event Event, subjectID uuid.NullUUID) (int64, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal webhook event: %w", err)
+	}
+
+	n, err := o.queries.EnqueueWebhookDeliveries(ctx, db.EnqueueWebhookDeliveriesParams{
+		EventID:   event.ID,
+		EventType: event.EventType,
+		Payload:   payload,
+		SessionID: event.SessionID,
+		SubjectID: subjectID,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to queue webhook deliveries: %w", err)
+	}
+
+	if n > 0 {
+		// Wake the worker without blocking if it has already been woken.
+		select {
+		case o.notify <- struct{}{}:
+		default:
+		}
+	}
+	return n, nil
+}
+
+// Queued signals when deliveries have been queued since the last signal was received.
+func (o *Outbox) Queued() <-chan struct{} {
+	return o.notify
+}
+
+// Redeliver queues a failed delivery of the webhook to be sent again with a fresh set of attempts.
+func (o *Outbox) Redeliver(ctx context.Context, webhookID, deliveryID uuid.UUID) error {
+	n, err := o.queries.RedeliverWebhookDelivery(ctx, db.RedeliverWebhookDeliveryParams{
+		ID:        deliveryID,
+		WebhookID: webhookID,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to redeliver webhook delivery: %w", err)
+	}
+	if n == 0 {
+		return ErrDeliveryNotFailed
+	}
+
+	select {
+	case o.notify <- struct{}{}:
+	default:
+	}
+	return nil
+}
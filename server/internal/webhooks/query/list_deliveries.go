@@ -0,0 +1,102 @@
+package query
+
+import (
+	"beerbux/internal/webhooks"
+	"beerbux/internal/webhooks/db"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+)
+
+const (
+	DefaultDeliveryLimit = 20
+	MaxDeliveryLimit     = 100
+)
+
+type ListDeliveriesQuery struct {
+	queries *db.Queries
+}
+
+func NewListDeliveriesQuery(queries *db.Queries) *ListDeliveriesQuery {
+	return &ListDeliveriesQuery{
+		queries: queries,
+	}
+}
+
+// Execute returns the most recent deliveries of the webhook with every attempt made to send
+// them. ErrWebhookNotFound is returned if the webhook does not exist or the user cannot manage it.
+func (q *ListDeliveriesQuery) Execute(ctx context.Context, userID, webhookID uuid.UUID, limit int) (DeliveriesResponse, error) {
+	_, err := q.queries.GetAccessibleWebhook(ctx, db.GetAccessibleWebhookParams{
+		ID:     webhookID,
+		UserID: userID,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return DeliveriesResponse{}, webhooks.ErrWebhookNotFound
+		}
+		return DeliveriesResponse{}, fmt.Errorf("failed to get webhook %s: %w", webhookID, err)
+	}
+
+	deliveries, err := q.queries.ListWebhookDeliveries(ctx, db.ListWebhookDeliveriesParams{
+		WebhookID:  webhookID,
+		MaxResults: int32(min(limit, MaxDeliveryLimit)),
+	})
+	if err != nil {
+		return DeliveriesResponse{}, fmt.Errorf("failed to list webhook %s deliveries: %w", webhookID, err)
+	}
+
+	ids := make([]uuid.UUID, 0, len(deliveries))
+	for _, d := range deliveries {
+		ids = append(ids, d.ID)
+	}
+	attempts, err := q.queries.ListWebhookDeliveryAttempts(ctx, ids)
+	if err != nil {
+		return DeliveriesResponse{}, fmt.Errorf("failed to list webhook %s delivery attempts: %w", webhookID, err)
+	}
+
+	attemptsByDelivery := make(map[uuid.UUID][]DeliveryAttemptResponse, len(deliveries))
+	for _, a := range attempts {
+		attempt := DeliveryAttemptResponse{
+			Attempt:    a.Attempt,
+			Succeeded:  a.Succeeded,
+			DurationMs: a.DurationMs,
+			CreatedAt:  a.CreatedAt,
+		}
+		if a.ResponseStatus.Valid {
+			attempt.ResponseStatus = &a.ResponseStatus.Int32
+		}
+		if a.Error.Valid {
+			attempt.Error = &a.Error.String
+		}
+		attemptsByDelivery[a.DeliveryID] = append(attemptsByDelivery[a.DeliveryID], attempt)
+	}
+
+	res := DeliveriesResponse{Deliveries: make([]DeliveryResponse, 0, len(deliveries))}
+	for _, d := range deliveries {
+		delivery := DeliveryResponse{
+			ID:        d.ID,
+			EventID:   d.EventID,
+			EventType: d.EventType,
+			Payload:   d.Payload,
+			Status:    d.Status,
+			CreatedAt: d.CreatedAt,
+			Attempts:  attemptsByDelivery[d.ID],
+		}
+		if delivery.Attempts == nil {
+			delivery.Attempts = []DeliveryAttemptResponse{}
+		}
+		if d.LastError.Valid {
+			delivery.LastError = &d.LastError.String
+		}
+		if d.Status == "pending" {
+			delivery.NextAttemptAt = &d.NextAttemptAt
+		}
+		if d.DeliveredAt.Valid {
+			delivery.DeliveredAt = &d.DeliveredAt.Time
+		}
+		res.Deliveries = append(res.Deliveries, delivery)
+	}
+	return res, nil
+}
@@ -0,0 +1,27 @@
+package query
+
+import (
+	"beerbux/internal/webhooks/db"
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+)
+
+type ListSessionWebhooksQuery struct {
+	queries *db.Queries
+}
+
+func NewListSessionWebhooksQuery(queries *db.Queries) *ListSessionWebhooksQuery {
+	return &ListSessionWebhooksQuery{
+		queries: queries,
+	}
+}
+
+// Execute lists the webhooks of the session. The caller must check the user is an admin of the session.
+func (q *ListSessionWebhooksQuery) Execute(ctx context.Context, sessionID uuid.UUID) (WebhooksResponse, error) {
+	webhooks, err := q.queries.ListSessionWebhooks(ctx, uuid.NullUUID{UUID: sessionID, Valid: true})
+	if err != nil {
+		return WebhooksResponse{}, fmt.Errorf("failed to list session %s webhooks: %w", sessionID, err)
+	}
+	return newWebhooksResponse(webhooks), nil
+}
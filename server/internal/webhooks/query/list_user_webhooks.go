@@ -0,0 +1,26 @@
+package query
+
+import (
+	"beerbux/internal/webhooks/db"
+	"context"
+	"fmt"
+	"github.com/google/uuid"
+)
+
+type ListUserWebhooksQuery struct {
+	queries *db.Queries
+}
+
+func NewListUserWebhooksQuery(queries *db.Queries) *ListUserWebhooksQuery {
+	return &ListUserWebhooksQuery{
+		queries: queries,
+	}
+}
+
+func (q *ListUserWebhooksQuery) Execute(ctx context.Context, userID uuid.UUID) (WebhooksResponse, error) {
+	webhooks, err := q.queries.ListUserWebhooks(ctx, uuid.NullUUID{UUID: userID, Valid: true})
+	if err != nil {
+		return WebhooksResponse{}, fmt.Errorf("failed to list user %s webhooks: %w", userID, err)
+	}
+	return newWebhooksResponse(webhooks), nil
+}
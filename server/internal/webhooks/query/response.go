@@ -0,0 +1,90 @@
+package query
+
+import (
+	"beerbux/internal/webhooks/db"
+	"encoding/json"
+	"github.com/google/uuid"
+	"time"
+)
+
+// WebhookResponse is a webhook without its secret, which is only returned when it is created.
+// Exactly one of SessionID and UserID is set, depending on who the webhook belongs to.
+type WebhookResponse struct {
+	ID                  uuid.UUID  `json:"id"`
+	SessionID           *uuid.UUID `json:"sessionId,omitempty"`
+	UserID              *uuid.UUID `json:"userId,omitempty"`
+	URL                 string     `json:"url"`
+	EventTypes          []string   `json:"eventTypes"`
+	Enabled             bool       `json:"enabled"`
+	ConsecutiveFailures int32      `json:"consecutiveFailures"`
+	DisabledAt          *time.Time `json:"disabledAt,omitempty"`
+	DisabledReason      *string    `json:"disabledReason,omitempty"`
+	CreatedAt           time.Time  `json:"createdAt"`
+}
+
+func NewWebhookResponse(w db.Webhook) WebhookResponse {
+	res := WebhookResponse{
+		ID:                  w.ID,
+		URL:                 w.Url,
+		EventTypes:          w.EventTypes,
+		Enabled:             w.Enabled,
+		ConsecutiveFailures: w.ConsecutiveFailures,
+		CreatedAt:           w.CreatedAt,
+	}
+	if res.EventTypes == nil {
+		res.EventTypes = []string{}
+	}
+	if w.SessionID.Valid {
+		res.SessionID = &w.SessionID.UUID
+	}
+	if w.UserID.Valid {
+		res.UserID = &w.UserID.UUID
+	}
+	if w.DisabledAt.Valid {
+		res.DisabledAt = &w.DisabledAt.Time
+	}
+	if w.DisabledReason.Valid {
+		res.DisabledReason = &w.DisabledReason.String
+	}
+	return res
+}
+
+type WebhooksResponse struct {
+	Webhooks []WebhookResponse `json:"webhooks"`
+}
+
+func newWebhooksResponse(webhooks []db.Webhook) WebhooksResponse {
+	res := WebhooksResponse{Webhooks: make([]WebhookResponse, 0, len(webhooks))}
+	for _, w := range webhooks {
+		res.Webhooks = append(res.Webhooks, NewWebhookResponse(w))
+	}
+	return res
+}
+
+type DeliveryAttemptResponse struct {
+	Attempt        int32     `json:"attempt"`
+	Succeeded      bool      `json:"succeeded"`
+	ResponseStatus *int32    `json:"responseStatus,omitempty"`
+	Error          *string   `json:"error,omitempty"`
+	DurationMs     int32     `json:"durationMs"`
+	CreatedAt      time.Time `json:"createdAt"`
+}
+
+// DeliveryResponse is an entry in a webhook's delivery log. NextAttemptAt is only set while
+// the delivery is waiting to be sent.
+type DeliveryResponse struct {
+	ID            uuid.UUID                 `json:"id"`
+	EventID       uuid.UUID                 `json:"eventId"`
+	EventType     string                    `json:"eventType"`
+	Payload       json.RawMessage           `json:"payload"`
+	Status        string                    `json:"status"`
+	LastError     *string                   `json:"lastError,omitempty"`
+	NextAttemptAt *time.Time                `json:"nextAttemptAt,omitempty"`
+	DeliveredAt   *time.Time                `json:"deliveredAt,omitempty"`
+	CreatedAt     time.Time                 `json:"createdAt"`
+	Attempts      []DeliveryAttemptResponse `json:"attempts"`
+}
+
+type DeliveriesResponse struct {
+	Deliveries []DeliveryResponse `json:"deliveries"`
+}
@@ -0,0 +1,118 @@
+package webhooks
+
+import (
+	"beerbux/internal/common/history"
+	"context"
+	"github.com/google/uuid"
+	"log/slog"
+	"time"
+)
+
+// SessionEventPublisher records session history events with the wrapped writer and queues
+// each recorded event for the webhooks subscribed to it.
+//
+// Failing to queue an event is logged rather than returned, as the event itself has been recorded.
+type SessionEventPublisher struct {
+	history.SessionHistoryWriter
+	outbox *Outbox
+	logger *slog.Logger
+}
+
+func NewSessionEventPublisher(historyWriter history.SessionHistoryWriter, outbox *Outbox, logger *slog.Logger) *SessionEventPublisher {
+	return &SessionEventPublisher{
+		SessionHistoryWriter: historyWriter,
+		outbox:               outbox,
+		logger:               logger,
+	}
+}
+
+func (p *SessionEventPublisher) CreateSessionOpenedEvent(ctx context.Context, sessionID, memberID uuid.UUID) error {
+	if err := p.SessionHistoryWriter.CreateSessionOpenedEvent(ctx, sessionID, memberID); err != nil {
+		return err
+	}
+	p.publish(ctx, history.EventSessionOpened, sessionID, memberID, nil, uuid.Nil)
+	return nil
+}
+
+func (p *SessionEventPublisher) CreateSessionClosedEvent(ctx context.Context, sessionID, memberID uuid.UUID) error {
+	if err := p.SessionHistoryWriter.CreateSessionClosedEvent(ctx, sessionID, memberID); err != nil {
+		return err
+	}
+	p.publish(ctx, history.EventSessionClosed, sessionID, memberID, nil, uuid.Nil)
+	return nil
+}
+
+func (p *SessionEventPublisher) CreateMemberAddedEvent(ctx context.Context, sessionID, memberID, performedByMemberId uuid.UUID) error {
+	if err := p.SessionHistoryWriter.CreateMemberAddedEvent(ctx, sessionID, memberID, performedByMemberId); err != nil {
+		return err
+	}
+	data := history.MemberAddedEventData{MemberID: memberID}
+	p.publish(ctx, history.EventMemberAdded, sessionID, performedByMemberId, data, memberID)
+	return nil
+}
+
+func (p *SessionEventPublisher) CreateMemberRemovedEvent(ctx context.Context, sessionID, memberID, performedByMemberId uuid.UUID) error {
+	if err := p.SessionHistoryWriter.CreateMemberRemovedEvent(ctx, sessionID, memberID, performedByMemberId); err != nil {
+		return err
+	}
+	data := history.MemberRemovedEventData{MemberID: memberID}
+	p.publish(ctx, history.EventMemberRemoved, sessionID, performedByMemberId, data, memberID)
+	return nil
+}
+
+func (p *SessionEventPublisher) CreateMemberLeftEvent(ctx context.Context, sessionID, memberID uuid.UUID) error {
+	if err := p.SessionHistoryWriter.CreateMemberLeftEvent(ctx, sessionID, memberID); err != nil {
+		return err
+	}
+	p.publish(ctx, history.EventMemberLeft, sessionID, memberID, nil, memberID)
+	return nil
+}
+
+func (p *SessionEventPublisher) CreateMemberPromotedToAdminEvent(ctx context.Context, sessionID, memberID, performedByMemberId uuid.UUID) error {
+	if err := p.SessionHistoryWriter.CreateMemberPromotedToAdminEvent(ctx, sessionID, memberID, performedByMemberId); err != nil {
+		return err
+	}
+	data := history.MemberPromotedToAdminEventData{MemberID: memberID}
+	p.publish(ctx, history.EventMemberPromotedToAdmin, sessionID, performedByMemberId, data, memberID)
+	return nil
+}
+
+func (p *SessionEventPublisher) CreateMemberDemotedFromAdminEvent(ctx context.Context, sessionID, memberID, performedByMemberId uuid.UUID) error {
+	if err := p.SessionHistoryWriter.CreateMemberDemotedFromAdminEvent(ctx, sessionID, memberID, performedByMemberId); err != nil {
+		return err
+	}
+	data := history.MemberDemotedFromAdminEventData{MemberID: memberID}
+	p.publish(ctx, history.EventMemberDemotedFromAdmin, sessionID, performedByMemberId, data, memberID)
+	return nil
+}
+
+func (p *SessionEventPublisher) CreateTransactionCreatedEvent(
+	ctx context.Context,
+	sessionID,
+	performedByMemberId uuid.UUID,
+	transactionLines history.TransactionHistory,
+) error {
+	if err := p.SessionHistoryWriter.CreateTransactionCreatedEvent(ctx, sessionID, performedByMemberId, transactionLines); err != nil {
+		return err
+	}
+	p.publish(ctx, history.EventTransactionCreated, sessionID, performedByMemberId, transactionLines, uuid.Nil)
+	return nil
+}
+
+// publish queues the event. subjectID is the member the event is about, whose own webhooks
+// receive it even when they are no longer a member of the session; uuid.Nil if there is none.
+func (p *SessionEventPublisher) publish(ctx context.Context, eventType string, sessionID, memberID uuid.UUID, data interface{}, subjectID uuid.UUID) {
+	event := Event{
+		ID:        uuid.New(),
+		EventType: eventType,
+		SessionID: sessionID,
+		MemberID:  memberID,
+		EventData: data,
+		CreatedAt: time.Now().UTC(),
+	}
+
+	subject := uuid.NullUUID{UUID: subjectID, Valid: subjectID != uuid.Nil}
+	if _, err := p.outbox.Enqueue(ctx, event, subject); err != nil {
+		p.logger.Error("failed to queue webhook event", "session", sessionID, "event", eventType, "error", err)
+	}
+}
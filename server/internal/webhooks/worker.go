@@ -0,0 +1,173 @@
+package webhooks
+
+import (
+	"beerbux/internal/common/outbox"
+	"beerbux/internal/webhooks/db"
+	"beerbux/pkg/webhook"
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+const (
+	// MaxAttempts is the number of delivery attempts before a delivery is marked as failed.
+	MaxAttempts = 6
+	// MaxConsecutiveFailures is the number of failed attempts in a row, across deliveries,
+	// after which a webhook is disabled until its owner enables it again.
+	MaxConsecutiveFailures = 15
+	// DeliveryRetentionDays is how long finished deliveries are kept in the delivery log.
+	DeliveryRetentionDays = 30
+	// pollInterval is how often the queue is checked for due deliveries when the worker is not woken.
+	pollInterval = 15 * time.Second
+	// purgeInterval is how often deliveries older than DeliveryRetentionDays are deleted.
+	purgeInterval = time.Hour
+	// batchSize is the number of deliveries claimed at a time.
+	batchSize = 10
+	// leaseDuration is how long a claimed delivery is reserved for the worker that claimed it.
+	// Deliveries left sending when a worker stops are retried once the lease expires.
+	leaseDuration = 5 * time.Minute
+)
+
+var retryBackoff = outbox.Backoff{Base: 30 * time.Second, Max: time.Hour}
+
+// Worker sends queued webhook deliveries, retrying failures with exponential backoff,
+// recording every attempt in the delivery log and disabling webhooks that keep failing.
+type Worker struct {
+	queries *db.Queries
+	client  *webhook.Client
+	queued  <-chan struct{}
+	logger  *slog.Logger
+}
+
+func NewWorker(queries *db.Queries, client *webhook.Client, queued <-chan struct{}, logger *slog.Logger) *Worker {
+	return &Worker{
+		queries: queries,
+		client:  client,
+		queued:  queued,
+		logger:  logger,
+	}
+}
+
+// Run sends deliveries until ctx is cancelled.
+func (w *Worker) Run(ctx context.Context) {
+	outbox.Poller{
+		Interval:      pollInterval,
+		Wake:          w.queued,
+		BatchSize:     batchSize,
+		DeliverBatch:  w.deliverBatch,
+		PurgeInterval: purgeInterval,
+		Purge:         w.purge,
+	}.Run(ctx)
+}
+
+func (w *Worker) deliverBatch(ctx context.Context) int {
+	deliveries, err := w.queries.ClaimDueWebhookDeliveries(ctx, db.ClaimDueWebhookDeliveriesParams{
+		LeaseSeconds: int32(leaseDuration / time.Second),
+		BatchSize:    batchSize,
+	})
+	if err != nil {
+		w.logger.Error("failed to claim webhook deliveries", "error", err)
+		return 0
+	}
+
+	for _, d := range deliveries {
+		w.deliver(ctx, d)
+	}
+	return len(deliveries)
+}
+
+func (w *Worker) deliver(ctx context.Context, d db.ClaimDueWebhookDeliveriesRow) {
+	start := time.Now()
+	status, sendErr := w.client.Send(ctx, webhook.Request{
+		URL:        d.Url,
+		Secret:     d.Secret,
+		DeliveryID: d.ID.String(),
+		Event:      d.EventType,
+		Body:       d.Payload,
+	})
+
+	err := w.queries.CreateWebhookDeliveryAttempt(ctx, db.CreateWebhookDeliveryAttemptParams{
+		DeliveryID:     d.ID,
+		Attempt:        d.Attempts,
+		Succeeded:      sendErr == nil,
+		ResponseStatus: sql.NullInt32{Int32: int32(status), Valid: status != 0},
+		Error:          outbox.ErrorString(sendErr),
+		DurationMs:     int32(time.Since(start) / time.Millisecond),
+	})
+	if err != nil {
+		w.logger.Error("failed to record webhook delivery attempt", "delivery", d.ID, "error", err)
+	}
+
+	if sendErr == nil {
+		if err := w.queries.MarkWebhookDeliverySucceeded(ctx, d.ID); err != nil {
+			w.logger.Error("failed to update webhook delivery", "delivery", d.ID, "error", err)
+		}
+		if err := w.queries.ResetWebhookFailures(ctx, d.WebhookID); err != nil {
+			w.logger.Error("failed to reset webhook failures", "webhook", d.WebhookID, "error", err)
+		}
+		return
+	}
+
+	failures, err := w.queries.IncrementWebhookFailures(ctx, d.WebhookID)
+	if err != nil {
+		w.logger.Error("failed to record webhook failure", "webhook", d.WebhookID, "error", err)
+	}
+	if failures >= MaxConsecutiveFailures {
+		w.disable(ctx, d, sendErr)
+		return
+	}
+
+	if d.Attempts >= MaxAttempts {
+		w.logger.Warn("giving up sending webhook delivery", "delivery", d.ID, "attempts", d.Attempts, "error", sendErr)
+		err = w.queries.MarkWebhookDeliveryFailed(ctx, db.MarkWebhookDeliveryFailedParams{
+			ID:        d.ID,
+			LastError: outbox.ErrorString(sendErr),
+		})
+	} else {
+		delay := retryBackoff.Delay(int(d.Attempts))
+		w.logger.Debug("failed to send webhook delivery, retrying", "delivery", d.ID, "attempt", d.Attempts, "retryIn", delay, "error", sendErr)
+		err = w.queries.MarkWebhookDeliveryForRetry(ctx, db.MarkWebhookDeliveryForRetryParams{
+			ID:            d.ID,
+			LastError:     outbox.ErrorString(sendErr),
+			NextAttemptAt: time.Now().Add(delay),
+		})
+	}
+	if err != nil {
+		w.logger.Error("failed to update webhook delivery", "delivery", d.ID, "error", err)
+	}
+}
+
+// disable turns off a webhook that keeps failing and fails its queued deliveries,
+// including the delivery that has just failed.
+func (w *Worker) disable(ctx context.Context, d db.ClaimDueWebhookDeliveriesRow, sendErr error) {
+	w.logger.Warn("disabling failing webhook", "webhook", d.WebhookID, "failures", MaxConsecutiveFailures, "error", sendErr)
+
+	reason := fmt.Sprintf("Disabled after %d consecutive failed deliveries: %s", MaxConsecutiveFailures, sendErr)
+	err := w.queries.DisableWebhook(ctx, db.DisableWebhookParams{
+		ID:             d.WebhookID,
+		DisabledReason: sql.NullString{String: reason, Valid: true},
+	})
+	if err != nil {
+		w.logger.Error("failed to disable webhook", "webhook", d.WebhookID, "error", err)
+		return
+	}
+
+	_, err = w.queries.FailPendingWebhookDeliveries(ctx, db.FailPendingWebhookDeliveriesParams{
+		WebhookID: d.WebhookID,
+		LastError: outbox.ErrorString(sendErr),
+	})
+	if err != nil {
+		w.logger.Error("failed to fail queued webhook deliveries", "webhook", d.WebhookID, "error", err)
+	}
+}
+
+func (w *Worker) purge(ctx context.Context) {
+	deleted, err := w.queries.PurgeWebhookDeliveries(ctx, DeliveryRetentionDays)
+	if err != nil {
+		w.logger.Error("failed to purge webhook deliveries", "error", err)
+		return
+	}
+	w.logger.Debug("purged webhook deliveries", "deleted", deleted)
+}
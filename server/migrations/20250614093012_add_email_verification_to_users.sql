@@ -0,0 +1,18 @@
+-- +goose Up
+-- +goose StatementBegin
+alter table users
+    add column email_verified bool not null default false,
+    add column email_verification_otp text,
+    add column email_verification_requested_at timestamp with time zone;
+
+-- Accounts created before email verification was introduced are treated as verified.
+update users set email_verified = true;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+alter table users
+    drop column if exists email_verified,
+    drop column if exists email_verification_otp,
+    drop column if exists email_verification_requested_at;
+-- +goose StatementEnd
@@ -0,0 +1,34 @@
+-- +goose Up
+-- +goose StatementBegin
+alter table users
+    add column password_update_otp_attempts integer not null default 0,
+    add column email_update_otp_attempts integer not null default 0,
+    add column email_verification_otp_attempts integer not null default 0,
+    -- Each flow is locked separately, so that failed attempts in one flow, such as an
+    -- unauthenticated password reset, cannot lock the user out of the others.
+    add column password_otp_locked_until timestamp with time zone,
+    add column email_update_otp_locked_until timestamp with time zone,
+    add column email_verification_otp_locked_until timestamp with time zone;
+
+-- One-time passwords are now stored hashed; any outstanding plain text codes can no longer be used.
+update users
+set password_update_otp = null,
+    password_update_requested_at = null,
+    update_hashed_password = null,
+    email_update_otp = null,
+    email_update_requested_at = null,
+    update_email = null,
+    email_verification_otp = null,
+    email_verification_requested_at = null;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+alter table users
+    drop column if exists password_update_otp_attempts,
+    drop column if exists email_update_otp_attempts,
+    drop column if exists email_verification_otp_attempts,
+    drop column if exists password_otp_locked_until,
+    drop column if exists email_update_otp_locked_until,
+    drop column if exists email_verification_otp_locked_until;
+-- +goose StatementEnd
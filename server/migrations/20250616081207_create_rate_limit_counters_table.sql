@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+create unlogged table if not exists rate_limit_counters (
+    key text primary key,
+    hits integer not null default 0,
+    expires_at timestamp with time zone not null
+);
+
+create index idx_rate_limit_counters_expires_at on rate_limit_counters (expires_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+drop table if exists rate_limit_counters;
+-- +goose StatementEnd
@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+create table if not exists user_two_factor (
+    user_id uuid primary key references users(id) on delete cascade,
+    secret text not null,
+    enabled bool not null default false,
+    last_used_step bigint,
+    enabled_at timestamp with time zone,
+    created_at timestamp with time zone not null default now(),
+    updated_at timestamp with time zone not null default now()
+);
+
+create trigger user_two_factor_update_updated_at
+    before update on user_two_factor
+    for each row
+execute function fn_update_updated_at_timestamp();
+
+create table if not exists user_recovery_codes (
+    id uuid primary key default uuid_generate_v4(),
+    user_id uuid not null references users(id) on delete cascade,
+    hashed_code text not null,
+    used_at timestamp with time zone,
+    created_at timestamp with time zone not null default now()
+);
+
+create index idx_user_recovery_codes_user_id on user_recovery_codes (user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+drop table if exists user_recovery_codes;
+drop table if exists user_two_factor;
+-- +goose StatementEnd
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+alter table refresh_tokens
+    add column user_agent text,
+    add column ip_address text,
+    add column last_used_at timestamp with time zone not null default now();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+alter table refresh_tokens
+    drop column if exists user_agent,
+    drop column if exists ip_address,
+    drop column if exists last_used_at;
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Existing tokens are bcrypt hashes that cannot be looked up by selector, so everyone has to log in again.
+delete from refresh_tokens;
+
+alter table refresh_tokens
+    drop column if exists hashed_token,
+    add column selector text not null unique,
+    add column hashed_verifier text not null,
+    add column family_id uuid not null,
+    add column replaced_at timestamp with time zone;
+
+create index idx_refresh_tokens_family_id on refresh_tokens (family_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+delete from refresh_tokens;
+
+drop index if exists idx_refresh_tokens_family_id;
+
+alter table refresh_tokens
+    drop column if exists selector,
+    drop column if exists hashed_verifier,
+    drop column if exists family_id,
+    drop column if exists replaced_at,
+    add column hashed_token text not null unique;
+-- +goose StatementEnd
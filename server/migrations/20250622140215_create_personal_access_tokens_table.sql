@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+create table if not exists personal_access_tokens (
+    id uuid primary key default uuid_generate_v4(),
+    user_id uuid not null references users(id) on delete cascade,
+    name text not null,
+    selector text not null unique,
+    hashed_verifier text not null,
+    scopes text[] not null,
+    expires_at timestamp with time zone not null,
+    last_used_at timestamp with time zone,
+    revoked_at timestamp with time zone,
+    created_at timestamp with time zone not null default now(),
+    updated_at timestamp with time zone not null default now()
+);
+
+create index idx_personal_access_tokens_user_id on personal_access_tokens (user_id);
+
+create trigger personal_access_tokens_update_updated_at
+    before update on personal_access_tokens
+    for each row
+execute function fn_update_updated_at_timestamp();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+drop table if exists personal_access_tokens;
+-- +goose StatementEnd
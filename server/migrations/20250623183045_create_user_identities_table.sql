@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+create table if not exists user_identities (
+    id uuid primary key default uuid_generate_v4(),
+    user_id uuid not null references users(id) on delete cascade,
+    provider text not null,
+    subject text not null,
+    email text,
+    last_login_at timestamp with time zone,
+    created_at timestamp with time zone not null default now(),
+    updated_at timestamp with time zone not null default now(),
+    unique (provider, subject),
+    unique (user_id, provider)
+);
+
+create trigger user_identities_update_updated_at
+    before update on user_identities
+    for each row
+execute function fn_update_updated_at_timestamp();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+drop table if exists user_identities;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+alter table users
+    add column login_otp text,
+    add column login_otp_requested_at timestamp with time zone,
+    add column login_otp_attempts integer not null default 0,
+    add column login_otp_locked_until timestamp with time zone;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+alter table users
+    drop column if exists login_otp,
+    drop column if exists login_otp_requested_at,
+    drop column if exists login_otp_attempts,
+    drop column if exists login_otp_locked_until;
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+create table if not exists user_audit_events (
+    id uuid primary key default uuid_generate_v4(),
+    user_id uuid not null references users(id) on delete cascade,
+    event text not null,
+    detail text,
+    user_agent text,
+    ip_address text,
+    created_at timestamp with time zone not null default now()
+);
+
+create index idx_user_audit_events_user_id_created_at on user_audit_events (user_id, created_at desc);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+drop table if exists user_audit_events;
+-- +goose StatementEnd
@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+create table if not exists email_change_reverts (
+    id uuid primary key default uuid_generate_v4(),
+    user_id uuid not null references users(id) on delete cascade,
+    previous_email text not null,
+    new_email text not null,
+    selector text not null unique,
+    hashed_verifier text not null,
+    expires_at timestamp with time zone not null,
+    used_at timestamp with time zone,
+    created_at timestamp with time zone not null default now()
+);
+
+create index idx_email_change_reverts_user_id on email_change_reverts (user_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+drop table if exists email_change_reverts;
+-- +goose StatementEnd
@@ -0,0 +1,42 @@
+-- +goose Up
+-- +goose StatementBegin
+create table if not exists email_outbox (
+    id uuid primary key default uuid_generate_v4(),
+    recipient text not null,
+    subject text not null,
+    html text not null,
+    status text not null default 'pending' check (status in ('pending', 'sending', 'sent', 'failed')),
+    attempts integer not null default 0,
+    next_attempt_at timestamp with time zone not null default now(),
+    last_error text,
+    provider_message_id text,
+    sent_at timestamp with time zone,
+    created_at timestamp with time zone not null default now(),
+    updated_at timestamp with time zone not null default now()
+);
+
+create index idx_email_outbox_due on email_outbox (next_attempt_at) where status in ('pending', 'sending');
+
+create trigger email_outbox_update_updated_at
+    before update on email_outbox
+    for each row
+execute function fn_update_updated_at_timestamp();
+
+create table if not exists email_delivery_attempts (
+    id uuid primary key default uuid_generate_v4(),
+    email_id uuid not null references email_outbox(id) on delete cascade,
+    attempt integer not null,
+    succeeded boolean not null,
+    provider_message_id text,
+    error text,
+    created_at timestamp with time zone not null default now()
+);
+
+create index idx_email_delivery_attempts_email_id on email_delivery_attempts (email_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+drop table if exists email_delivery_attempts;
+drop table if exists email_outbox;
+-- +goose StatementEnd
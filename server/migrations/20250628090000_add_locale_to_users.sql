@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+alter table users
+    add column locale text;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+alter table users
+    drop column if exists locale;
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+create table if not exists user_notification_preferences (
+    user_id uuid primary key references users(id) on delete cascade,
+    weekly_digest bool not null default false,
+    settle_up_reminders bool not null default false,
+    -- unsubscribe_token is included in notification emails so that users can opt out without logging in.
+    unsubscribe_token uuid not null unique default uuid_generate_v4(),
+    next_digest_at timestamp with time zone not null default now() + interval '7 days',
+    last_reminder_sent_at timestamp with time zone,
+    created_at timestamp with time zone not null default now(),
+    updated_at timestamp with time zone not null default now()
+);
+
+create index idx_user_notification_preferences_next_digest_at on user_notification_preferences (next_digest_at) where weekly_digest;
+
+create trigger user_notification_preferences_update_updated_at
+    before update on user_notification_preferences
+    for each row
+execute function fn_update_updated_at_timestamp();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+drop table if exists user_notification_preferences;
+-- +goose StatementEnd
@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+create table if not exists notifications (
+    id uuid primary key default uuid_generate_v4(),
+    user_id uuid not null references users(id) on delete cascade,
+    type text not null,
+    session_id uuid not null references sessions(id) on delete cascade,
+    -- actor_id is the member whose action caused the notification.
+    actor_id uuid not null references users(id) on delete cascade,
+    transaction_id uuid references session_transactions(id) on delete cascade,
+    read_at timestamp with time zone,
+    created_at timestamp with time zone not null default now()
+);
+
+create index idx_notifications_user_id_created_at on notifications (user_id, created_at desc);
+create index idx_notifications_user_id_unread on notifications (user_id) where read_at is null;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+drop table if exists notifications;
+-- +goose StatementEnd
@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+create table if not exists push_subscriptions (
+    id uuid primary key default uuid_generate_v4(),
+    user_id uuid not null references users(id) on delete cascade,
+    endpoint text not null unique,
+    p256dh text not null,
+    auth text not null,
+    user_agent text,
+    -- expires_at is the expiration time of the subscription given by the browser, if any.
+    expires_at timestamp with time zone,
+    last_used_at timestamp with time zone,
+    created_at timestamp with time zone not null default now(),
+    updated_at timestamp with time zone not null default now()
+);
+
+create index idx_push_subscriptions_user_id on push_subscriptions (user_id);
+
+create trigger push_subscriptions_update_updated_at
+    before update on push_subscriptions
+    for each row
+execute function fn_update_updated_at_timestamp();
+
+alter table user_notification_preferences
+    add column push_invitations bool not null default true,
+    add column push_rounds bool not null default true,
+    add column push_session_updates bool not null default true;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+alter table user_notification_preferences
+    drop column if exists push_invitations,
+    drop column if exists push_rounds,
+    drop column if exists push_session_updates;
+
+drop table if exists push_subscriptions;
+-- +goose StatementEnd
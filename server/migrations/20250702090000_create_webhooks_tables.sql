@@ -0,0 +1,73 @@
+-- +goose Up
+-- +goose StatementBegin
+create table if not exists webhooks (
+    id uuid primary key default uuid_generate_v4(),
+    -- A webhook belongs either to a session, managed by its admins, or to a user,
+    -- receiving the events of every session the user is a member of.
+    session_id uuid references sessions(id) on delete cascade,
+    user_id uuid references users(id) on delete cascade,
+    created_by uuid not null references users(id) on delete cascade,
+    url text not null,
+    secret text not null,
+    -- event_types are the session history event types sent to the webhook; empty sends every type.
+    event_types text[] not null default '{}',
+    enabled bool not null default true,
+    consecutive_failures integer not null default 0,
+    disabled_at timestamp with time zone,
+    disabled_reason text,
+    created_at timestamp with time zone not null default now(),
+    updated_at timestamp with time zone not null default now(),
+    check ((session_id is null) <> (user_id is null))
+);
+
+create index idx_webhooks_session_id on webhooks (session_id) where session_id is not null;
+create index idx_webhooks_user_id on webhooks (user_id) where user_id is not null;
+
+create trigger webhooks_update_updated_at
+    before update on webhooks
+    for each row
+execute function fn_update_updated_at_timestamp();
+
+create table if not exists webhook_deliveries (
+    id uuid primary key default uuid_generate_v4(),
+    webhook_id uuid not null references webhooks(id) on delete cascade,
+    event_id uuid not null,
+    event_type text not null,
+    payload jsonb not null,
+    status text not null default 'pending' check (status in ('pending', 'sending', 'succeeded', 'failed')),
+    attempts integer not null default 0,
+    next_attempt_at timestamp with time zone not null default now(),
+    last_error text,
+    delivered_at timestamp with time zone,
+    created_at timestamp with time zone not null default now(),
+    updated_at timestamp with time zone not null default now()
+);
+
+create index idx_webhook_deliveries_due on webhook_deliveries (next_attempt_at) where status in ('pending', 'sending');
+create index idx_webhook_deliveries_webhook_id_created_at on webhook_deliveries (webhook_id, created_at desc);
+
+create trigger webhook_deliveries_update_updated_at
+    before update on webhook_deliveries
+    for each row
+execute function fn_update_updated_at_timestamp();
+
+create table if not exists webhook_delivery_attempts (
+    id uuid primary key default uuid_generate_v4(),
+    delivery_id uuid not null references webhook_deliveries(id) on delete cascade,
+    attempt integer not null,
+    succeeded boolean not null,
+    response_status integer,
+    error text,
+    duration_ms integer not null,
+    created_at timestamp with time zone not null default now()
+);
+
+create index idx_webhook_delivery_attempts_delivery_id on webhook_delivery_attempts (delivery_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+drop table if exists webhook_delivery_attempts;
+drop table if exists webhook_deliveries;
+drop table if exists webhooks;
+-- +goose StatementEnd
@@ -0,0 +1,55 @@
+-- +goose Up
+-- +goose StatementBegin
+-- chat_accounts links a user of a chat workspace, such as Slack, to a Beerbux user.
+create table if not exists chat_accounts (
+    id uuid primary key default uuid_generate_v4(),
+    team_id text not null,
+    chat_user_id text not null,
+    chat_username text not null,
+    user_id uuid not null references users(id) on delete cascade,
+    created_at timestamp with time zone not null default now(),
+    updated_at timestamp with time zone not null default now(),
+    unique (team_id, chat_user_id)
+);
+
+create index idx_chat_accounts_user_id on chat_accounts (user_id);
+
+create trigger chat_accounts_update_updated_at
+    before update on chat_accounts
+    for each row
+execute function fn_update_updated_at_timestamp();
+
+-- chat_link_codes are the one-time codes sent to chat users to link their account.
+-- Only a hash of the code is stored.
+create table if not exists chat_link_codes (
+    code_hash text primary key,
+    team_id text not null,
+    chat_user_id text not null,
+    chat_username text not null,
+    expires_at timestamp with time zone not null,
+    created_at timestamp with time zone not null default now()
+);
+
+-- chat_channels are the sessions that chat commands in a channel apply to.
+create table if not exists chat_channels (
+    team_id text not null,
+    channel_id text not null,
+    session_id uuid not null references sessions(id) on delete cascade,
+    linked_by uuid not null references users(id) on delete cascade,
+    created_at timestamp with time zone not null default now(),
+    updated_at timestamp with time zone not null default now(),
+    primary key (team_id, channel_id)
+);
+
+create trigger chat_channels_update_updated_at
+    before update on chat_channels
+    for each row
+execute function fn_update_updated_at_timestamp();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+drop table if exists chat_channels;
+drop table if exists chat_link_codes;
+drop table if exists chat_accounts;
+-- +goose StatementEnd
@@ -0,0 +1,13 @@
+-- +goose Up
+-- +goose StatementBegin
+alter table user_two_factor
+    add column failed_attempts integer not null default 0,
+    add column locked_until timestamp with time zone;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+alter table user_two_factor
+    drop column locked_until,
+    drop column failed_attempts;
+-- +goose StatementEnd
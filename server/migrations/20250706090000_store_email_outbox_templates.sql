@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Emails generated from a template are stored as the template and its data, so that they can be
+-- generated again when resent. The body is cleared once the email has been sent or has failed.
+alter table email_outbox
+    alter column html drop not null,
+    add column template text,
+    add column locale text,
+    add column template_data jsonb;
+
+update email_outbox
+set html = null
+where status in ('sent', 'failed');
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+update email_outbox
+set html = ''
+where html is null;
+
+alter table email_outbox
+    drop column template_data,
+    drop column locale,
+    drop column template,
+    alter column html set not null;
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Extra headers of the email, such as List-Unsubscribe, added by senders that support them.
+alter table email_outbox
+    add column headers jsonb;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+alter table email_outbox
+    drop column headers;
+-- +goose StatementEnd
@@ -0,0 +1,30 @@
+package clientip
+
+import (
+	"net"
+	"net/http"
+	"strings"
+)
+
+// FromRequest returns the IP address of the client making the request.
+//
+// When trustProxy is true, the right-most address in the X-Forwarded-For header is used.
+// This is the address appended by the reverse proxy in front of the API and, unlike the
+// left-most address, cannot be spoofed by the client. Only enable trustProxy when the
+// API is exclusively reachable through such a proxy.
+func FromRequest(r *http.Request, trustProxy bool) string {
+	if trustProxy {
+		if forwardedFor := r.Header.Get("X-Forwarded-For"); forwardedFor != "" {
+			addresses := strings.Split(forwardedFor, ",")
+			if ip := strings.TrimSpace(addresses[len(addresses)-1]); ip != "" {
+				return ip
+			}
+		}
+	}
+
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
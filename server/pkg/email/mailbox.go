@@ -0,0 +1,138 @@
+package email
+
+import (
+	"github.com/google/uuid"
+	"golang.org/x/net/html"
+	"log/slog"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MailboxCapacity is the number of emails kept by the Mailbox before the oldest are dropped.
+const MailboxCapacity = 100
+
+// CapturedEmail is an email held by the Mailbox instead of being delivered.
+type CapturedEmail struct {
+	ID      string            `json:"id"`
+	To      string            `json:"to"`
+	Subject string            `json:"subject"`
+	HTML    string            `json:"html"`
+	Text    string            `json:"text"`
+	OTP     string            `json:"otp,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+	SentAt  time.Time         `json:"sentAt"`
+}
+
+// Mailbox is a development Sender that keeps emails in memory so they can be read back,
+// letting OTP flows be completed without a real email provider.
+type Mailbox struct {
+	mu       sync.RWMutex
+	messages []CapturedEmail
+	logger   *slog.Logger
+}
+
+func NewMailbox(logger *slog.Logger) *Mailbox {
+	return &Mailbox{
+		logger: logger,
+	}
+}
+
+func (m *Mailbox) Send(to, subject, html string) (string, error) {
+	return m.SendWithHeaders(to, subject, html, nil)
+}
+
+func (m *Mailbox) SendWithHeaders(to, subject, html string, headers map[string]string) (string, error) {
+	msg := CapturedEmail{
+		ID:      uuid.NewString(),
+		To:      to,
+		Subject: subject,
+		HTML:    html,
+		Text:    PlainText(html),
+		OTP:     ExtractOTP(html),
+		Headers: headers,
+		SentAt:  time.Now(),
+	}
+
+	m.mu.Lock()
+	m.messages = append(m.messages, msg)
+	if len(m.messages) > MailboxCapacity {
+		m.messages = m.messages[len(m.messages)-MailboxCapacity:]
+	}
+	m.mu.Unlock()
+
+	m.logger.Info("email captured", "id", msg.ID, "to", to, "subject", subject, "otp", msg.OTP)
+	return msg.ID, nil
+}
+
+// List returns the captured emails, newest first. If to is not empty, only emails sent
+// to that address are returned.
+func (m *Mailbox) List(to string) []CapturedEmail {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	messages := make([]CapturedEmail, 0, len(m.messages))
+	for i := len(m.messages) - 1; i >= 0; i-- {
+		if to == "" || strings.EqualFold(m.messages[i].To, to) {
+			messages = append(messages, m.messages[i])
+		}
+	}
+	return messages
+}
+
+func (m *Mailbox) Get(id string) (CapturedEmail, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, msg := range m.messages {
+		if msg.ID == id {
+			return msg, true
+		}
+	}
+	return CapturedEmail{}, false
+}
+
+func (m *Mailbox) Clear() {
+	m.mu.Lock()
+	m.messages = nil
+	m.mu.Unlock()
+}
+
+var otpRegexp = regexp.MustCompile(`^[a-zA-Z0-9]{4,12}$`)
+
+// ExtractOTP finds the one-time password in an email. An otp query parameter in a link is
+// preferred, falling back to the code the templates display in a heading.
+func ExtractOTP(body string) string {
+	doc, err := html.Parse(strings.NewReader(body))
+	if err != nil {
+		return ""
+	}
+
+	var fromLink, fromHeading string
+	var walk func(n *html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.ElementNode {
+			switch n.Data {
+			case "a":
+				if u, err := url.Parse(attr(n, "href")); err == nil && fromLink == "" {
+					fromLink = u.Query().Get("otp")
+				}
+			case "h1", "h2", "h3":
+				if text := textContent(n); fromHeading == "" && otpRegexp.MatchString(text) {
+					fromHeading = text
+				}
+			}
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(doc)
+
+	if fromLink != "" {
+		return fromLink
+	}
+	return fromHeading
+}
@@ -0,0 +1,90 @@
+package email
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"maps"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"slices"
+	"strings"
+	"time"
+)
+
+var ErrInvalidHeader = errors.New("email header contains a line break")
+
+// buildMessage builds an RFC 5322 message with multipart/alternative plain-text and HTML bodies
+// and any extra headers. The generated Message-ID is returned alongside the message.
+func buildMessage(from *mail.Address, to, subject, html string, headers map[string]string) ([]byte, string, error) {
+	if strings.ContainsAny(to+subject, "\r\n") {
+		return nil, "", ErrInvalidHeader
+	}
+	for name, value := range headers {
+		if strings.ContainsAny(name, "\r\n: ") || strings.ContainsAny(value, "\r\n") {
+			return nil, "", ErrInvalidHeader
+		}
+	}
+
+	messageID := newMessageID(from.Address)
+
+	var buf bytes.Buffer
+	body := multipart.NewWriter(&buf)
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", from.String())
+	fmt.Fprintf(&msg, "To: %s\r\n", to)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	fmt.Fprintf(&msg, "Message-ID: %s\r\n", messageID)
+	for _, name := range slices.Sorted(maps.Keys(headers)) {
+		fmt.Fprintf(&msg, "%s: %s\r\n", textproto.CanonicalMIMEHeaderKey(name), headers[name])
+	}
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&msg, "Content-Type: multipart/alternative; boundary=%s\r\n\r\n", body.Boundary())
+
+	if err := writePart(body, "text/plain; charset=utf-8", PlainText(html)); err != nil {
+		return nil, "", err
+	}
+	if err := writePart(body, "text/html; charset=utf-8", html); err != nil {
+		return nil, "", err
+	}
+	if err := body.Close(); err != nil {
+		return nil, "", err
+	}
+
+	msg.Write(buf.Bytes())
+	return msg.Bytes(), messageID, nil
+}
+
+func writePart(w *multipart.Writer, contentType, content string) error {
+	part, err := w.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return err
+	}
+
+	qp := quotedprintable.NewWriter(part)
+	if _, err := qp.Write([]byte(content)); err != nil {
+		return err
+	}
+	return qp.Close()
+}
+
+func newMessageID(fromAddress string) string {
+	domain := "localhost"
+	if at := strings.LastIndex(fromAddress, "@"); at >= 0 {
+		domain = fromAddress[at+1:]
+	}
+
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return "<" + hex.EncodeToString(b) + "@" + domain + ">"
+}
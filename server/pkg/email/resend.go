@@ -7,31 +7,35 @@ import (
 
 type ResendEmailSender struct {
 	*resend.Client
+	from           string
 	devSendToEmail string
 }
 
-type Sender interface {
-	Send(to, subject, html string) (string, error)
-}
-
-func New(conf config.ResendConfig) Sender {
+func NewResendEmailSender(conf config.ResendConfig, from string) Sender {
 	return &ResendEmailSender{
 		Client:         resend.NewClient(conf.Key),
+		from:           from,
 		devSendToEmail: conf.DevelopmentSendToEmail,
 	}
 }
 
 func (r *ResendEmailSender) Send(to, subject, html string) (string, error) {
+	return r.SendWithHeaders(to, subject, html, nil)
+}
+
+func (r *ResendEmailSender) SendWithHeaders(to, subject, html string, headers map[string]string) (string, error) {
 	sendToEMail := to
 	if r.devSendToEmail != "" {
 		sendToEMail = r.devSendToEmail
 	}
 
 	params := &resend.SendEmailRequest{
-		From:    "Beerbux <onboarding@resend.dev>",
+		From:    r.from,
 		To:      []string{sendToEMail},
 		Html:    html,
+		Text:    PlainText(html),
 		Subject: subject,
+		Headers: headers,
 	}
 
 	sent, err := r.Client.Emails.Send(params)
@@ -0,0 +1,66 @@
+package email
+
+import (
+	"beerbux/internal/api/config"
+	"fmt"
+	"log/slog"
+)
+
+type Sender interface {
+	Send(to, subject, html string) (string, error)
+}
+
+// TemplateSender is implemented by senders that store emails to be delivered later, so that
+// they can keep the template an email is generated from rather than the generated HTML.
+type TemplateSender interface {
+	SendTemplate(to, subject string, tmpl Template) (string, error)
+}
+
+// HeaderSender is implemented by senders that can add extra headers to an email.
+type HeaderSender interface {
+	SendWithHeaders(to, subject, html string, headers map[string]string) (string, error)
+}
+
+// SendTemplate generates the email from the template and sends it, leaving the generation
+// to the sender if it is a TemplateSender.
+func SendTemplate(sender Sender, to, subject string, tmpl Template) (string, error) {
+	if s, ok := sender.(TemplateSender); ok {
+		return s.SendTemplate(to, subject, tmpl)
+	}
+	html, err := tmpl.Generate()
+	if err != nil {
+		return "", fmt.Errorf("failed to generate email: %w", err)
+	}
+	return SendWithHeaders(sender, to, subject, html, tmpl.Headers)
+}
+
+// SendWithHeaders sends the email with the headers if the sender is a HeaderSender, or without them otherwise.
+func SendWithHeaders(sender Sender, to, subject, html string, headers map[string]string) (string, error) {
+	if s, ok := sender.(HeaderSender); ok && len(headers) > 0 {
+		return s.SendWithHeaders(to, subject, html, headers)
+	}
+	return sender.Send(to, subject, html)
+}
+
+// ListUnsubscribeHeaders returns the headers that let mail clients unsubscribe the recipient
+// with a single click, by making a POST request to url as described in RFC 8058.
+func ListUnsubscribeHeaders(url string) map[string]string {
+	return map[string]string{
+		"List-Unsubscribe":      "<" + url + ">",
+		"List-Unsubscribe-Post": "List-Unsubscribe=One-Click",
+	}
+}
+
+// New returns the Sender for the configured email provider.
+func New(conf config.EmailConfig, logger *slog.Logger) (Sender, error) {
+	switch conf.Provider {
+	case config.EmailProviderSMTP:
+		return NewSMTPEmailSender(conf.SMTP, conf.From)
+	case config.EmailProviderTerminal:
+		return NewTerminalEmailLogger(logger), nil
+	case config.EmailProviderMailbox:
+		return NewMailbox(logger), nil
+	default:
+		return NewResendEmailSender(conf.Resend, conf.From), nil
+	}
+}
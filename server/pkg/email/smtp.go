@@ -0,0 +1,117 @@
+package email
+
+import (
+	"beerbux/internal/api/config"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"time"
+)
+
+const smtpTimeout = 30 * time.Second
+
+// SMTPEmailSender sends emails through an SMTP server, upgrading the connection with STARTTLS
+// or connecting with implicit TLS depending on the configured TLS mode.
+type SMTPEmailSender struct {
+	conf config.SMTPConfig
+	from *mail.Address
+}
+
+func NewSMTPEmailSender(conf config.SMTPConfig, from string) (Sender, error) {
+	fromAddress, err := mail.ParseAddress(from)
+	if err != nil {
+		return nil, fmt.Errorf("invalid from address %q: %w", from, err)
+	}
+	return &SMTPEmailSender{
+		conf: conf,
+		from: fromAddress,
+	}, nil
+}
+
+func (s *SMTPEmailSender) Send(to, subject, html string) (string, error) {
+	return s.SendWithHeaders(to, subject, html, nil)
+}
+
+func (s *SMTPEmailSender) SendWithHeaders(to, subject, html string, headers map[string]string) (string, error) {
+	msg, messageID, err := buildMessage(s.from, to, subject, html, headers)
+	if err != nil {
+		return "", err
+	}
+
+	client, err := s.dial()
+	if err != nil {
+		return "", err
+	}
+	defer client.Close()
+
+	if s.conf.Username != "" {
+		auth := smtp.PlainAuth("", s.conf.Username, s.conf.Password, s.conf.Host)
+		if err := client.Auth(auth); err != nil {
+			return "", fmt.Errorf("smtp auth failed: %w", err)
+		}
+	}
+
+	if err := client.Mail(s.from.Address); err != nil {
+		return "", fmt.Errorf("smtp MAIL FROM failed: %w", err)
+	}
+	if err := client.Rcpt(to); err != nil {
+		return "", fmt.Errorf("smtp RCPT TO failed: %w", err)
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return "", fmt.Errorf("smtp DATA failed: %w", err)
+	}
+	if _, err := w.Write(msg); err != nil {
+		return "", fmt.Errorf("failed to write email: %w", err)
+	}
+	if err := w.Close(); err != nil {
+		return "", fmt.Errorf("failed to send email: %w", err)
+	}
+
+	if err := client.Quit(); err != nil {
+		return "", fmt.Errorf("smtp QUIT failed: %w", err)
+	}
+	return messageID, nil
+}
+
+func (s *SMTPEmailSender) dial() (*smtp.Client, error) {
+	addr := net.JoinHostPort(s.conf.Host, strconv.Itoa(s.conf.Port))
+	tlsConfig := &tls.Config{ServerName: s.conf.Host}
+	dialer := &net.Dialer{Timeout: smtpTimeout}
+
+	var conn net.Conn
+	var err error
+	if s.conf.TLSMode == config.SMTPTLSModeImplicit {
+		conn, err = tls.DialWithDialer(dialer, "tcp", addr, tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to smtp server: %w", err)
+	}
+	_ = conn.SetDeadline(time.Now().Add(smtpTimeout))
+
+	client, err := smtp.NewClient(conn, s.conf.Host)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to start smtp session: %w", err)
+	}
+
+	if s.conf.TLSMode == config.SMTPTLSModeStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			client.Close()
+			return nil, errors.New("smtp server does not support STARTTLS")
+		}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			client.Close()
+			return nil, fmt.Errorf("smtp STARTTLS failed: %w", err)
+		}
+	}
+
+	return client, nil
+}
@@ -1,50 +1,122 @@
 package email
 
 import (
+	"beerbux/pkg/i18n"
 	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"html/template"
+	"io/fs"
 	"os"
-	"path/filepath"
+	"strings"
 )
 
-type ResetPasswordEmailData struct {
-	Username          string
-	OTP               string
-	ExpirationMinutes string
+//go:embed templates/*.html
+var embeddedTemplates embed.FS
+
+// templateFS holds the templates used to generate emails. It is the embedded templates
+// unless SetTemplateDir has been called.
+var templateFS fs.FS = mustSubFS(embeddedTemplates, "templates")
+
+// SetTemplateDir makes templates in dir take precedence over the embedded templates.
+// Templates missing from dir fall back to the embedded version, so only the templates
+// being customised need to exist there. An empty dir restores the embedded templates.
+// It must be called before any emails are generated.
+func SetTemplateDir(dir string) {
+	base := mustSubFS(embeddedTemplates, "templates")
+	if dir == "" {
+		templateFS = base
+		return
+	}
+	templateFS = overlayFS{top: os.DirFS(dir), base: base}
+}
+
+const (
+	resetPasswordEmailTemplate      = "reset_password_email.html"
+	updatePasswordEmailTemplate     = "update_password_email.html"
+	updateEmailAddressEmailTemplate = "update_email_address_email.html"
+	verifyEmailAddressEmailTemplate = "verify_email_address_email.html"
+	magicLinkEmailTemplate          = "magic_link_email.html"
+	emailChangedEmailTemplate       = "email_changed_email.html"
+	newDeviceLoginEmailTemplate     = "new_device_login_email.html"
+	weeklyDigestEmailTemplate       = "weekly_digest_email.html"
+	settleUpReminderEmailTemplate   = "settle_up_reminder_email.html"
+)
+
+// Template is an email template along with the data to generate it with. Unlike the generated
+// HTML it can be stored, and generated again later, by senders that deliver emails in the background.
+type Template struct {
+	Name   string
+	Locale string
+	Data   any
+	// Headers are added to the email by senders that support them, see HeaderSender.
+	Headers map[string]string
 }
 
-func GenerateResetPasswordEmail(data ResetPasswordEmailData) (string, error) {
-	tmpl, err := parseTemplate("reset_password_email.html")
+// Generate executes the template translated into its locale.
+func (t Template) Generate() (string, error) {
+	tmpl, err := parseTemplate(t.Name, t.Locale)
 	if err != nil {
 		return "", err
 	}
 
 	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, data); err != nil {
+	if err := tmpl.Execute(&buf, t.Data); err != nil {
 		return "", err
 	}
 
 	return buf.String(), nil
 }
 
-type UpdatePasswordEmailData struct {
+// templateData creates the data of each template that can be stored, so stored templates can
+// be decoded. Templates containing secrets such as one-time passwords and revert links are left
+// out, so that the secrets are never kept once the email has been delivered or resent after
+// they have expired.
+var templateData = map[string]func() any{
+	newDeviceLoginEmailTemplate:   func() any { return new(NewDeviceLoginEmailData) },
+	weeklyDigestEmailTemplate:     func() any { return new(WeeklyDigestEmailData) },
+	settleUpReminderEmailTemplate: func() any { return new(SettleUpReminderEmailData) },
+}
+
+// Storable reports whether the template and its data may be stored to be generated later.
+func (t Template) Storable() bool {
+	_, ok := templateData[t.Name]
+	return ok
+}
+
+// DecodeTemplate returns the named template with its JSON encoded data, as stored by a sender.
+func DecodeTemplate(name, locale string, data []byte) (Template, error) {
+	newData, ok := templateData[name]
+	if !ok {
+		return Template{}, fmt.Errorf("unknown email template %q", name)
+	}
+	d := newData()
+	if err := json.Unmarshal(data, d); err != nil {
+		return Template{}, fmt.Errorf("failed to decode email template data: %w", err)
+	}
+	return Template{Name: name, Locale: locale, Data: d}, nil
+}
+
+type ResetPasswordEmailData struct {
 	Username          string
 	OTP               string
 	ExpirationMinutes string
 }
 
-func GenerateUpdatePasswordEmail(data UpdatePasswordEmailData) (string, error) {
-	tmpl, err := parseTemplate("update_password_email.html")
-	if err != nil {
-		return "", err
-	}
+func ResetPasswordEmail(locale string, data ResetPasswordEmailData) Template {
+	return Template{Name: resetPasswordEmailTemplate, Locale: locale, Data: data}
+}
 
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, data); err != nil {
-		return "", err
-	}
+type UpdatePasswordEmailData struct {
+	Username          string
+	OTP               string
+	ExpirationMinutes string
+}
 
-	return buf.String(), nil
+func UpdatePasswordEmail(locale string, data UpdatePasswordEmailData) Template {
+	return Template{Name: updatePasswordEmailTemplate, Locale: locale, Data: data}
 }
 
 type UpdateEmailAddressData struct {
@@ -54,26 +126,129 @@ type UpdateEmailAddressData struct {
 	ExpirationMinutes string
 }
 
-func GenerateUpdateEmailAddressEmail(data UpdateEmailAddressData) (string, error) {
-	tmpl, err := parseTemplate("update_email_address_email.html")
-	if err != nil {
-		return "", err
-	}
+func UpdateEmailAddressEmail(locale string, data UpdateEmailAddressData) Template {
+	return Template{Name: updateEmailAddressEmailTemplate, Locale: locale, Data: data}
+}
 
-	var buf bytes.Buffer
-	if err := tmpl.Execute(&buf, data); err != nil {
-		return "", err
+type VerifyEmailAddressEmailData struct {
+	Username        string
+	OTP             string
+	VerificationURL string
+	ExpirationHours string
+}
+
+func VerifyEmailAddressEmail(locale string, data VerifyEmailAddressEmailData) Template {
+	return Template{Name: verifyEmailAddressEmailTemplate, Locale: locale, Data: data}
+}
+
+type MagicLinkEmailData struct {
+	Username          string
+	OTP               string
+	LoginURL          string
+	ExpirationMinutes string
+}
+
+func MagicLinkEmail(locale string, data MagicLinkEmailData) Template {
+	return Template{Name: magicLinkEmailTemplate, Locale: locale, Data: data}
+}
+
+type EmailChangedEmailData struct {
+	Username        string
+	NewEmail        string
+	RevertURL       string
+	ExpirationHours string
+}
+
+func EmailChangedEmail(locale string, data EmailChangedEmailData) Template {
+	return Template{Name: emailChangedEmailTemplate, Locale: locale, Data: data}
+}
+
+type NewDeviceLoginEmailData struct {
+	Username    string
+	UserAgent   string
+	IPAddress   string
+	Time        string
+	SettingsURL string
+}
+
+func NewDeviceLoginEmail(locale string, data NewDeviceLoginEmailData) Template {
+	return Template{Name: newDeviceLoginEmailTemplate, Locale: locale, Data: data}
+}
+
+// FriendBalance is the number of beers owed between the recipient and one of their friends.
+type FriendBalance struct {
+	Name  string
+	Beers string
+	// YouOwe is set when the recipient owes the friend, rather than the friend owing them.
+	YouOwe bool
+}
+
+type DigestSession struct {
+	Name   string
+	Rounds int64
+}
+
+type WeeklyDigestEmailData struct {
+	Username       string
+	PeriodStart    string
+	PeriodEnd      string
+	RoundsBought   int64
+	BeersBought    string
+	RoundsReceived int64
+	BeersReceived  string
+	Sessions       []DigestSession
+	Balances       []FriendBalance
+	PreferencesURL string
+	UnsubscribeURL string
+}
+
+func WeeklyDigestEmail(locale string, data WeeklyDigestEmailData) Template {
+	return Template{Name: weeklyDigestEmailTemplate, Locale: locale, Data: data}
+}
+
+type SettleUpReminderEmailData struct {
+	Username       string
+	NetDebt        string
+	Threshold      string
+	Balances       []FriendBalance
+	PreferencesURL string
+	UnsubscribeURL string
+}
+
+func SettleUpReminderEmail(locale string, data SettleUpReminderEmailData) Template {
+	return Template{Name: settleUpReminderEmailTemplate, Locale: locale, Data: data}
+}
+
+// parseTemplate parses the template translated into locale, named like
+// reset_password_email.es.html, falling back to the English template when there is no translation.
+func parseTemplate(path, locale string) (*template.Template, error) {
+	if locale != "" && locale != i18n.DefaultLocale {
+		localized := strings.TrimSuffix(path, ".html") + "." + locale + ".html"
+		if _, err := fs.Stat(templateFS, localized); err == nil {
+			return template.ParseFS(templateFS, localized)
+		}
 	}
+	return template.ParseFS(templateFS, path)
+}
 
-	return buf.String(), nil
+// overlayFS opens files from top, falling back to base for files that do not exist in top.
+type overlayFS struct {
+	top  fs.FS
+	base fs.FS
 }
 
-func parseTemplate(path string) (*template.Template, error) {
-	wd, err := os.Getwd()
-	if err != nil {
-		return nil, err
+func (o overlayFS) Open(name string) (fs.File, error) {
+	f, err := o.top.Open(name)
+	if err == nil || !errors.Is(err, fs.ErrNotExist) {
+		return f, err
 	}
+	return o.base.Open(name)
+}
 
-	tmplPath := filepath.Join(wd, "templates", path)
-	return template.ParseFiles(tmplPath)
+func mustSubFS(fsys fs.FS, dir string) fs.FS {
+	sub, err := fs.Sub(fsys, dir)
+	if err != nil {
+		panic(err)
+	}
+	return sub
 }
@@ -0,0 +1,84 @@
+package email
+
+import (
+	"golang.org/x/net/html"
+	"strings"
+)
+
+// PlainText converts an HTML email body into a plain-text alternative for mail clients that
+// do not render HTML. Links are written out with their URL so they can still be followed.
+func PlainText(body string) string {
+	doc, err := html.Parse(strings.NewReader(body))
+	if err != nil {
+		return body
+	}
+
+	var sb strings.Builder
+	writePlainText(&sb, doc)
+
+	lines := strings.Split(sb.String(), "\n")
+	out := make([]string, 0, len(lines))
+	blank := true
+	for _, line := range lines {
+		line = strings.Join(strings.Fields(line), " ")
+		if line == "" {
+			// Collapse runs of blank lines into one.
+			if !blank {
+				out = append(out, "")
+			}
+			blank = true
+			continue
+		}
+		out = append(out, line)
+		blank = false
+	}
+
+	return strings.TrimSpace(strings.Join(out, "\n")) + "\n"
+}
+
+func writePlainText(sb *strings.Builder, n *html.Node) {
+	switch n.Type {
+	case html.TextNode:
+		sb.WriteString(strings.ReplaceAll(n.Data, "\n", " "))
+		return
+	case html.ElementNode:
+		switch n.Data {
+		case "head", "script", "style":
+			return
+		case "br":
+			sb.WriteString("\n")
+			return
+		case "li":
+			sb.WriteString("\n- ")
+		case "p", "div", "h1", "h2", "h3", "h4", "h5", "h6", "ul", "ol", "table", "tr":
+			sb.WriteString("\n\n")
+		}
+	}
+
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		writePlainText(sb, c)
+	}
+
+	if n.Type == html.ElementNode && n.Data == "a" {
+		if href := attr(n, "href"); href != "" && href != textContent(n) {
+			sb.WriteString(" (" + href + ")")
+		}
+	}
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func textContent(n *html.Node) string {
+	var sb strings.Builder
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		writePlainText(&sb, c)
+	}
+	return strings.TrimSpace(sb.String())
+}
@@ -0,0 +1,52 @@
+package i18n
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+// catalogs maps a locale to its translations, keyed by the English message.
+var catalogs = mustLoadCatalogs()
+
+// Translate returns message in the given locale. Messages without a translation are
+// returned unchanged, so English is used for anything that has not been translated yet.
+func Translate(locale, message string) string {
+	if translated, ok := catalogs[locale][message]; ok {
+		return translated
+	}
+	return message
+}
+
+// Sprintf translates format into the given locale before formatting it with args.
+func Sprintf(locale, format string, args ...any) string {
+	return fmt.Sprintf(Translate(locale, format), args...)
+}
+
+func mustLoadCatalogs() map[string]map[string]string {
+	files, err := fs.Glob(localeFiles, "locales/*.json")
+	if err != nil {
+		panic(err)
+	}
+
+	result := make(map[string]map[string]string, len(files))
+	for _, file := range files {
+		content, err := localeFiles.ReadFile(file)
+		if err != nil {
+			panic(err)
+		}
+
+		var catalog map[string]string
+		if err := json.Unmarshal(content, &catalog); err != nil {
+			panic(fmt.Sprintf("invalid translations in %s: %v", file, err))
+		}
+		result[strings.TrimSuffix(path.Base(file), ".json")] = catalog
+	}
+	return result
+}
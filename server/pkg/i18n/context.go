@@ -0,0 +1,66 @@
+package i18n
+
+import (
+	"context"
+	"sync"
+)
+
+type contextKey struct{}
+
+// RequestLocale resolves the locale of a request the first time it is needed, so that
+// looking up the user's saved preference only happens for requests that send a message.
+type RequestLocale struct {
+	mu             sync.Mutex
+	acceptLanguage string
+	preference     func() string
+	resolved       bool
+	locale         string
+}
+
+// NewRequestLocale creates a RequestLocale for a request with the given Accept-Language header.
+func NewRequestLocale(acceptLanguage string) *RequestLocale {
+	return &RequestLocale{acceptLanguage: acceptLanguage}
+}
+
+// SetPreference sets the function used to look up the user's saved locale, which takes
+// precedence over the Accept-Language header. It has no effect once the locale has been resolved.
+func (l *RequestLocale) SetPreference(preference func() string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.preference = preference
+}
+
+// Locale returns the resolved locale of the request.
+func (l *RequestLocale) Locale() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if !l.resolved {
+		var preference string
+		if l.preference != nil {
+			preference = l.preference()
+		}
+		l.locale = Resolve(preference, l.acceptLanguage)
+		l.resolved = true
+	}
+	return l.locale
+}
+
+// NewContext returns a copy of ctx carrying the request locale.
+func NewContext(ctx context.Context, locale *RequestLocale) context.Context {
+	return context.WithValue(ctx, contextKey{}, locale)
+}
+
+// RequestLocaleFromContext returns the request locale stored in ctx, if any.
+func RequestLocaleFromContext(ctx context.Context) (*RequestLocale, bool) {
+	locale, ok := ctx.Value(contextKey{}).(*RequestLocale)
+	return locale, ok
+}
+
+// FromContext returns the locale of the request ctx belongs to, or DefaultLocale when
+// the request has no locale.
+func FromContext(ctx context.Context) string {
+	if locale, ok := RequestLocaleFromContext(ctx); ok {
+		return locale.Locale()
+	}
+	return DefaultLocale
+}
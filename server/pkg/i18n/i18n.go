@@ -0,0 +1,97 @@
+package i18n
+
+import (
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultLocale is the locale messages and templates are written in. It is used when
+// neither the user nor their browser prefers a supported locale.
+const DefaultLocale = "en"
+
+// SupportedLocales lists the locales that messages and email templates can be sent in.
+func SupportedLocales() []string {
+	locales := []string{DefaultLocale}
+	for locale := range catalogs {
+		locales = append(locales, locale)
+	}
+	slices.Sort(locales[1:])
+	return locales
+}
+
+// Supported reports whether messages can be translated into locale.
+func Supported(locale string) bool {
+	if locale == DefaultLocale {
+		return true
+	}
+	_, ok := catalogs[locale]
+	return ok
+}
+
+// Normalize returns the supported locale for a language tag such as "es-MX", or an empty
+// string when the language is not supported.
+func Normalize(tag string) string {
+	base, _, _ := strings.Cut(strings.TrimSpace(tag), "-")
+	base, _, _ = strings.Cut(base, "_")
+	base = strings.ToLower(base)
+	if !Supported(base) {
+		return ""
+	}
+	return base
+}
+
+// MatchAcceptLanguage returns the supported locale the client prefers most according to
+// an Accept-Language header, falling back to DefaultLocale.
+func MatchAcceptLanguage(header string) string {
+	type preference struct {
+		tag     string
+		quality float64
+	}
+
+	var preferences []preference
+	for _, part := range strings.Split(header, ",") {
+		tag, params, _ := strings.Cut(part, ";")
+		tag = strings.TrimSpace(tag)
+		if tag == "" || tag == "*" {
+			continue
+		}
+
+		quality := 1.0
+		for _, param := range strings.Split(params, ";") {
+			key, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if !ok || key != "q" {
+				continue
+			}
+			q, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				q = 0
+			}
+			quality = q
+		}
+		if quality <= 0 {
+			continue
+		}
+		preferences = append(preferences, preference{tag: tag, quality: quality})
+	}
+
+	sort.SliceStable(preferences, func(i, j int) bool {
+		return preferences[i].quality > preferences[j].quality
+	})
+	for _, p := range preferences {
+		if locale := Normalize(p.tag); locale != "" {
+			return locale
+		}
+	}
+	return DefaultLocale
+}
+
+// Resolve picks the locale for a user, preferring their saved preference over the
+// Accept-Language header of their request.
+func Resolve(preference, acceptLanguage string) string {
+	if locale := Normalize(preference); locale != "" {
+		return locale
+	}
+	return MatchAcceptLanguage(acceptLanguage)
+}
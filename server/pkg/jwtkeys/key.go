@@ -0,0 +1,170 @@
+// Package jwtkeys manages the keys used to sign and verify JWTs, supporting EdDSA and
+// RS256 keys identified by a kid header so that keys can be rotated, and publishing
+// the public keys as a JSON Web Key Set.
+package jwtkeys
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+)
+
+const (
+	AlgorithmEdDSA = "EdDSA"
+	AlgorithmRS256 = "RS256"
+	AlgorithmHS256 = "HS256"
+)
+
+// minRSAKeyBits is the smallest RSA key accepted.
+const minRSAKeyBits = 2048
+
+var ErrUnsupportedKey = errors.New("unsupported key type")
+
+// Key is a key used to verify, and optionally sign, JWTs.
+type Key struct {
+	// ID is the kid of the key, the RFC 7638 thumbprint of the public key.
+	ID        string
+	Algorithm string
+	// signer is nil for keys that may only be used for verification.
+	signer crypto.Signer
+	public crypto.PublicKey
+}
+
+// CanSign reports whether the private key is available.
+func (k *Key) CanSign() bool {
+	return k.signer != nil
+}
+
+// LoadKeyFile reads a PEM encoded Ed25519 or RSA key. A private key can be used for
+// signing, while a public key can only be used to verify tokens signed by a retired key.
+func LoadKeyFile(path string) (*Key, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key file %s: %w", path, err)
+	}
+
+	key, err := ParseKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse key file %s: %w", path, err)
+	}
+	return key, nil
+}
+
+// ParseKey parses a PEM encoded Ed25519 or RSA private or public key.
+func ParseKey(data []byte) (*Key, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM data found")
+	}
+
+	var parsed any
+	var err error
+	switch block.Type {
+	case "PRIVATE KEY":
+		parsed, err = x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		parsed, err = x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		parsed, err = x509.ParsePKCS1PublicKey(block.Bytes)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedKey, block.Type)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return newKey(parsed)
+}
+
+func newKey(parsed any) (*Key, error) {
+	key := &Key{}
+	if signer, ok := parsed.(crypto.Signer); ok {
+		key.signer = signer
+		parsed = signer.Public()
+	}
+
+	switch pub := parsed.(type) {
+	case ed25519.PublicKey:
+		key.Algorithm = AlgorithmEdDSA
+		key.public = pub
+	case *rsa.PublicKey:
+		if pub.N.BitLen() < minRSAKeyBits {
+			return nil, fmt.Errorf("RSA key must be at least %d bits", minRSAKeyBits)
+		}
+		key.Algorithm = AlgorithmRS256
+		key.public = pub
+	default:
+		return nil, fmt.Errorf("%w: %T", ErrUnsupportedKey, pub)
+	}
+
+	key.ID = key.JWK().thumbprint()
+	return key, nil
+}
+
+// JWK is a public key in the JSON Web Key format described in RFC 7517.
+type JWK struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid,omitempty"`
+	Use       string `json:"use,omitempty"`
+	Algorithm string `json:"alg,omitempty"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+}
+
+// JWK returns the public key of the key.
+func (k *Key) JWK() JWK {
+	jwk := JWK{
+		KeyID:     k.ID,
+		Use:       "sig",
+		Algorithm: k.Algorithm,
+	}
+
+	switch pub := k.public.(type) {
+	case ed25519.PublicKey:
+		jwk.KeyType = "OKP"
+		jwk.Curve = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(pub)
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(pub.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(pub.E)).Bytes())
+	}
+	return jwk
+}
+
+// thumbprint computes the RFC 7638 thumbprint of the key, which hashes only the
+// required members in lexicographic order.
+func (j JWK) thumbprint() string {
+	var required any
+	switch j.KeyType {
+	case "OKP":
+		required = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{j.Curve, j.KeyType, j.X}
+	default:
+		required = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{j.E, j.KeyType, j.N}
+	}
+
+	data, _ := json.Marshal(required)
+	sum := sha256.Sum256(data)
+	return base64.RawURLEncoding.EncodeToString(sum[:])
+}
@@ -0,0 +1,161 @@
+package jwtkeys
+
+import (
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v4"
+	"slices"
+)
+
+var ErrUnknownKey = errors.New("unknown signing key")
+
+// KeySet signs JWTs with a single key and verifies them with any of its keys,
+// allowing tokens signed by a retired key to be accepted until they expire.
+//
+// A KeySet created with only an HMAC secret signs and verifies HS256 tokens without a kid.
+type KeySet struct {
+	signing    *Key
+	keys       map[string]*Key
+	secret     []byte
+	algorithms []string
+}
+
+// Options configures a KeySet.
+type Options struct {
+	// SigningKey signs new tokens. When nil, tokens are signed with HS256 using the Secret.
+	SigningKey *Key
+	// VerificationKeys are retired keys that are still accepted when verifying tokens.
+	VerificationKeys []*Key
+	// Secret is the HMAC secret used for HS256.
+	Secret string
+	// Algorithms is the allow-list of algorithms accepted when verifying tokens.
+	// It defaults to the algorithms of the configured keys, and to HS256 when no keys are configured.
+	// Including HS256 alongside asymmetric keys accepts tokens signed with the Secret,
+	// which is useful while migrating away from HS256.
+	Algorithms []string
+}
+
+func NewKeySet(opts Options) (*KeySet, error) {
+	ks := &KeySet{
+		signing: opts.SigningKey,
+		keys:    make(map[string]*Key),
+		secret:  []byte(opts.Secret),
+	}
+
+	if ks.signing != nil {
+		if !ks.signing.CanSign() {
+			return nil, errors.New("signing key must be a private key")
+		}
+		ks.keys[ks.signing.ID] = ks.signing
+	}
+	for _, k := range opts.VerificationKeys {
+		ks.keys[k.ID] = k
+	}
+
+	ks.algorithms = opts.Algorithms
+	if len(ks.algorithms) == 0 {
+		ks.algorithms = ks.keyAlgorithms()
+	}
+	for _, alg := range ks.algorithms {
+		switch alg {
+		case AlgorithmEdDSA, AlgorithmRS256:
+		case AlgorithmHS256:
+			if len(ks.secret) == 0 {
+				return nil, errors.New("HS256 requires a secret")
+			}
+		default:
+			return nil, fmt.Errorf("unsupported algorithm: %s", alg)
+		}
+	}
+
+	signingAlgorithm := AlgorithmHS256
+	if ks.signing != nil {
+		signingAlgorithm = ks.signing.Algorithm
+	}
+	if !slices.Contains(ks.algorithms, signingAlgorithm) {
+		return nil, fmt.Errorf("signing algorithm %s is not in the allowed algorithms", signingAlgorithm)
+	}
+
+	return ks, nil
+}
+
+func (ks *KeySet) keyAlgorithms() []string {
+	if len(ks.keys) == 0 {
+		return []string{AlgorithmHS256}
+	}
+	var algorithms []string
+	for _, k := range ks.keys {
+		if !slices.Contains(algorithms, k.Algorithm) {
+			algorithms = append(algorithms, k.Algorithm)
+		}
+	}
+	slices.Sort(algorithms)
+	return algorithms
+}
+
+// Algorithms returns the allow-list of algorithms accepted when verifying tokens.
+func (ks *KeySet) Algorithms() []string {
+	return slices.Clone(ks.algorithms)
+}
+
+// Sign signs the claims with the signing key, setting the kid header.
+func (ks *KeySet) Sign(claims jwt.Claims) (string, error) {
+	if ks.signing == nil {
+		return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(ks.secret)
+	}
+
+	token := jwt.NewWithClaims(jwt.GetSigningMethod(ks.signing.Algorithm), claims)
+	token.Header["kid"] = ks.signing.ID
+	return token.SignedString(ks.signing.signer)
+}
+
+// Parse parses and validates the token. Only algorithms in the allow-list are accepted,
+// and asymmetric tokens must name a known key in the kid header.
+func (ks *KeySet) Parse(tokenString string, claims jwt.Claims) (*jwt.Token, error) {
+	parser := jwt.NewParser(jwt.WithValidMethods(ks.algorithms))
+	return parser.ParseWithClaims(tokenString, claims, ks.keyFunc)
+}
+
+func (ks *KeySet) keyFunc(token *jwt.Token) (interface{}, error) {
+	alg := token.Method.Alg()
+	if alg == AlgorithmHS256 {
+		return ks.secret, nil
+	}
+
+	kid, _ := token.Header["kid"].(string)
+	key, ok := ks.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownKey, kid)
+	}
+	if key.Algorithm != alg {
+		return nil, fmt.Errorf("key %q cannot be used with %s", kid, alg)
+	}
+	return key.public, nil
+}
+
+// JWKS is a JSON Web Key Set as described in RFC 7517.
+type JWKS struct {
+	Keys []JWK `json:"keys"`
+}
+
+// JWKS returns the public keys of the set, with the signing key first. HMAC secrets are never included.
+func (ks *KeySet) JWKS() JWKS {
+	jwks := JWKS{Keys: make([]JWK, 0, len(ks.keys))}
+	if ks.signing != nil {
+		jwks.Keys = append(jwks.Keys, ks.signing.JWK())
+	}
+
+	ids := make([]string, 0, len(ks.keys))
+	for id := range ks.keys {
+		if ks.signing == nil || id != ks.signing.ID {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	for _, id := range ids {
+		if slices.Contains(ks.algorithms, ks.keys[id].Algorithm) {
+			jwks.Keys = append(jwks.Keys, ks.keys[id].JWK())
+		}
+	}
+	return jwks
+}
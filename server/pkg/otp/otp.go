@@ -2,6 +2,9 @@ package otp
 
 import (
 	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
 	"math/big"
 )
 
@@ -18,3 +21,15 @@ func Generate(length int) (string, error) {
 	}
 	return string(value), nil
 }
+
+// Hash returns the hex encoded SHA-256 digest of the given one-time password so that
+// codes are not stored in the database in plain text.
+func Hash(otp string) string {
+	sum := sha256.Sum256([]byte(otp))
+	return hex.EncodeToString(sum[:])
+}
+
+// Compare reports whether the given one-time password matches the hash in constant time.
+func Compare(hash, otp string) bool {
+	return subtle.ConstantTimeCompare([]byte(hash), []byte(Hash(otp))) == 1
+}
@@ -0,0 +1,142 @@
+package password
+
+import (
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// maxBreachedLineLength bounds the bytes read for a single line, which is a 40 character
+// digest followed by a count.
+const maxBreachedLineLength = 128
+
+var errMalformedBreachedList = errors.New("breached password list is not a sorted list of SHA-1 digests")
+
+// breachedList binary searches a file of sorted SHA-1 digests. Reads use ReadAt, so it is safe
+// for concurrent use.
+type breachedList struct {
+	file *os.File
+	size int64
+}
+
+func openBreachedList(path string) (*breachedList, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open breached password list: %w", err)
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("failed to stat breached password list: %w", err)
+	}
+
+	list := &breachedList{file: f, size: info.Size()}
+
+	// Check the first entry so that a list in the wrong format fails at startup rather
+	// than on the first password change.
+	if list.size > 0 {
+		line, err := list.readLine(0)
+		if err == nil {
+			_, err = parseBreachedLine(line)
+		}
+		if err != nil {
+			f.Close()
+			return nil, fmt.Errorf("failed to read breached password list: %w", err)
+		}
+	}
+
+	return list, nil
+}
+
+// contains reports whether the upper case hex digest is in the list.
+func (l *breachedList) contains(digest string) (bool, error) {
+	// lo is always the start of a line and every line before it is less than the digest.
+	lo, hi := int64(0), l.size
+	for lo < hi {
+		mid := lo + (hi-lo)/2
+
+		start, err := l.nextLineStart(mid)
+		if err != nil {
+			return false, err
+		}
+		if start >= hi {
+			hi = mid
+			continue
+		}
+
+		line, err := l.readLine(start)
+		if err != nil {
+			return false, err
+		}
+		entry, err := parseBreachedLine(line)
+		if err != nil {
+			return false, err
+		}
+
+		switch strings.Compare(entry, digest) {
+		case 0:
+			return true, nil
+		case -1:
+			lo = start + int64(len(line)) + 1
+		default:
+			hi = mid
+		}
+	}
+	return false, nil
+}
+
+// nextLineStart returns the offset of the first line starting at or after off.
+func (l *breachedList) nextLineStart(off int64) (int64, error) {
+	if off == 0 {
+		return 0, nil
+	}
+
+	// Read from the byte before off so that a line starting exactly at off is found.
+	buf := make([]byte, maxBreachedLineLength)
+	n, err := l.file.ReadAt(buf, off-1)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return 0, err
+	}
+	i := bytes.IndexByte(buf[:n], '\n')
+	if i < 0 {
+		if errors.Is(err, io.EOF) {
+			return l.size, nil
+		}
+		return 0, errMalformedBreachedList
+	}
+	return off + int64(i), nil
+}
+
+// readLine returns the line starting at off without the line ending.
+func (l *breachedList) readLine(off int64) ([]byte, error) {
+	buf := make([]byte, maxBreachedLineLength)
+	n, err := l.file.ReadAt(buf, off)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	line := buf[:n]
+	if i := bytes.IndexByte(line, '\n'); i >= 0 {
+		line = line[:i]
+	} else if !errors.Is(err, io.EOF) {
+		return nil, errMalformedBreachedList
+	}
+	return line, nil
+}
+
+// parseBreachedLine returns the upper case digest from a "digest:count" line.
+func parseBreachedLine(line []byte) (string, error) {
+	digest, _, _ := bytes.Cut(bytes.TrimRight(line, "\r"), []byte(":"))
+	if len(digest) != sha1.Size*2 {
+		return "", errMalformedBreachedList
+	}
+	if _, err := hex.Decode(make([]byte, sha1.Size), digest); err != nil {
+		return "", errMalformedBreachedList
+	}
+	return strings.ToUpper(string(digest)), nil
+}
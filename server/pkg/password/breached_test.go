@@ -0,0 +1,121 @@
+package password
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// writeBreachedList writes the digests, sorted, in the format of the Have I Been Pwned download.
+func writeBreachedList(t *testing.T, digests []string, lineEnding string, trailingNewline bool) string {
+	t.Helper()
+	slices.Sort(digests)
+
+	var b strings.Builder
+	for i, digest := range digests {
+		fmt.Fprintf(&b, "%s:%d", digest, i+1)
+		if i < len(digests)-1 || trailingNewline {
+			b.WriteString(lineEnding)
+		}
+	}
+
+	path := filepath.Join(t.TempDir(), "breached.txt")
+	if err := os.WriteFile(path, []byte(b.String()), 0o600); err != nil {
+		t.Fatalf("failed to write breached list: %v", err)
+	}
+	return path
+}
+
+func testDigests(n int) []string {
+	digests := make([]string, n)
+	for i := range digests {
+		digests[i] = sha1Hex(fmt.Sprintf("password%d", i))
+	}
+	return digests
+}
+
+func TestBreachedListContains(t *testing.T) {
+	for _, n := range []int{1, 2, 3, 10, 257} {
+		for _, format := range []struct {
+			name            string
+			lineEnding      string
+			trailingNewline bool
+		}{
+			{name: "LF", lineEnding: "\n", trailingNewline: true},
+			{name: "CRLF", lineEnding: "\r\n", trailingNewline: true},
+			{name: "no trailing newline", lineEnding: "\n", trailingNewline: false},
+		} {
+			digests := testDigests(n)
+			list, err := openBreachedList(writeBreachedList(t, digests, format.lineEnding, format.trailingNewline))
+			if err != nil {
+				t.Fatalf("%d entries, %s: openBreachedList: %v", n, format.name, err)
+			}
+
+			for _, digest := range digests {
+				if ok, err := list.contains(digest); err != nil || !ok {
+					t.Errorf("%d entries, %s: contains(%s) = %t, %v; want true", n, format.name, digest, ok, err)
+				}
+			}
+
+			for _, digest := range []string{
+				strings.Repeat("0", 40),
+				strings.Repeat("F", 40),
+				sha1Hex("not in the list"),
+			} {
+				if ok, err := list.contains(digest); err != nil || ok {
+					t.Errorf("%d entries, %s: contains(%s) = %t, %v; want false", n, format.name, digest, ok, err)
+				}
+			}
+
+			list.file.Close()
+		}
+	}
+}
+
+func TestBreachedListEmpty(t *testing.T) {
+	list, err := openBreachedList(writeBreachedList(t, nil, "\n", false))
+	if err != nil {
+		t.Fatalf("openBreachedList: %v", err)
+	}
+	defer list.file.Close()
+
+	if ok, err := list.contains(sha1Hex("password")); err != nil || ok {
+		t.Errorf("contains = %t, %v; want false", ok, err)
+	}
+}
+
+func TestOpenBreachedListRejectsOtherFormats(t *testing.T) {
+	for _, content := range []string{
+		"password\n",
+		"5BAA61E4C9B93F3F0682250B6CF8331B7EE68FD\n",
+		"5BAA61E4C9B93F3F0682250B6CF8331B7EE68FDZ:1\n",
+	} {
+		path := filepath.Join(t.TempDir(), "breached.txt")
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatalf("failed to write breached list: %v", err)
+		}
+		if _, err := openBreachedList(path); !errors.Is(err, errMalformedBreachedList) {
+			t.Errorf("openBreachedList(%q) err = %v, want %v", content, err, errMalformedBreachedList)
+		}
+	}
+}
+
+func TestPolicyRejectsBreachedPasswords(t *testing.T) {
+	path := writeBreachedList(t, []string{sha1Hex("password1"), sha1Hex("qwertyuiop")}, "\n", true)
+	policy, err := NewPolicy(8, path)
+	if err != nil {
+		t.Fatalf("NewPolicy: %v", err)
+	}
+
+	var violation *PolicyViolation
+	if err := policy.Validate("qwertyuiop"); !errors.As(err, &violation) {
+		t.Errorf("Validate(breached) err = %v, want a policy violation", err)
+	}
+	if err := policy.Validate("correct horse battery staple"); err != nil {
+		t.Errorf("Validate(not breached) err = %v, want nil", err)
+	}
+}
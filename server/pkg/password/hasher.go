@@ -0,0 +1,101 @@
+// Package password hashes and verifies passwords and enforces the password policy.
+package password
+
+import (
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/bcrypt"
+	"strings"
+)
+
+const (
+	AlgorithmArgon2id = "argon2id"
+	AlgorithmBcrypt   = "bcrypt"
+)
+
+var ErrUnknownHashFormat = errors.New("unknown password hash format")
+
+// Hasher hashes passwords with the configured algorithm and verifies hashes created with
+// any supported algorithm, detecting the algorithm from the hash format, so that existing
+// bcrypt hashes keep working after switching to argon2id.
+type Hasher struct {
+	algorithm  string
+	argon2id   Argon2idParams
+	bcryptCost int
+}
+
+type Options struct {
+	// Algorithm is used to hash new passwords, either argon2id or bcrypt.
+	Algorithm  string
+	Argon2id   Argon2idParams
+	BcryptCost int
+}
+
+func NewHasher(opts Options) (*Hasher, error) {
+	switch opts.Algorithm {
+	case AlgorithmArgon2id:
+		p := opts.Argon2id
+		if p.Memory == 0 || p.Iterations == 0 || p.Parallelism == 0 || p.SaltLength < 8 || p.KeyLength < 16 {
+			return nil, errors.New("invalid argon2id parameters")
+		}
+	case AlgorithmBcrypt:
+		if opts.BcryptCost < bcrypt.MinCost || opts.BcryptCost > bcrypt.MaxCost {
+			return nil, fmt.Errorf("bcrypt cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported password hash algorithm %q", opts.Algorithm)
+	}
+
+	return &Hasher{
+		algorithm:  opts.Algorithm,
+		argon2id:   opts.Argon2id,
+		bcryptCost: opts.BcryptCost,
+	}, nil
+}
+
+// Hash hashes the password with the configured algorithm.
+func (h *Hasher) Hash(password string) (string, error) {
+	if h.algorithm == AlgorithmBcrypt {
+		hashed, err := bcrypt.GenerateFromPassword([]byte(password), h.bcryptCost)
+		if err != nil {
+			return "", fmt.Errorf("failed to hash password: %w", err)
+		}
+		return string(hashed), nil
+	}
+	return hashArgon2id(password, h.argon2id)
+}
+
+// Verify reports whether the password matches the hash. When it matches, needsRehash
+// reports whether the hash was created with a different algorithm or parameters than
+// are configured, in which case the password should be hashed again and stored.
+func (h *Hasher) Verify(hash, password string) (match bool, needsRehash bool, err error) {
+	switch {
+	case strings.HasPrefix(hash, argon2idPrefix):
+		match, params, err := verifyArgon2id(hash, password)
+		if err != nil || !match {
+			return false, false, err
+		}
+		return true, h.algorithm != AlgorithmArgon2id || params != h.argon2id, nil
+
+	case isBcryptHash(hash):
+		err := bcrypt.CompareHashAndPassword([]byte(hash), []byte(password))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return false, false, nil
+		}
+		if err != nil {
+			return false, false, err
+		}
+		cost, err := bcrypt.Cost([]byte(hash))
+		if err != nil {
+			return false, false, err
+		}
+		return true, h.algorithm != AlgorithmBcrypt || cost != h.bcryptCost, nil
+
+	default:
+		return false, false, ErrUnknownHashFormat
+	}
+}
+
+func isBcryptHash(hash string) bool {
+	return strings.HasPrefix(hash, "$2a$") || strings.HasPrefix(hash, "$2b$") || strings.HasPrefix(hash, "$2y$")
+}
@@ -0,0 +1,121 @@
+package password
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// testArgon2idParams keep the tests fast; they are far below what should be used in production.
+var testArgon2idParams = Argon2idParams{
+	Memory:      64,
+	Iterations:  1,
+	Parallelism: 1,
+	SaltLength:  16,
+	KeyLength:   32,
+}
+
+func newTestHasher(t *testing.T, opts Options) *Hasher {
+	t.Helper()
+	h, err := NewHasher(opts)
+	if err != nil {
+		t.Fatalf("NewHasher: %v", err)
+	}
+	return h
+}
+
+func TestHasherArgon2id(t *testing.T) {
+	h := newTestHasher(t, Options{Algorithm: AlgorithmArgon2id, Argon2id: testArgon2idParams})
+
+	hash, err := h.Hash("correct horse")
+	if err != nil {
+		t.Fatalf("Hash: %v", err)
+	}
+	if !strings.HasPrefix(hash, "$argon2id$v=19$m=64,t=1,p=1$") {
+		t.Errorf("hash %q is not in the PHC string format", hash)
+	}
+
+	match, needsRehash, err := h.Verify(hash, "correct horse")
+	if err != nil || !match || needsRehash {
+		t.Errorf("Verify(correct) = %t, %t, %v; want true, false, nil", match, needsRehash, err)
+	}
+
+	match, needsRehash, err = h.Verify(hash, "battery staple")
+	if err != nil || match || needsRehash {
+		t.Errorf("Verify(incorrect) = %t, %t, %v; want false, false, nil", match, needsRehash, err)
+	}
+}
+
+func TestHasherRehash(t *testing.T) {
+	stronger := testArgon2idParams
+	stronger.Iterations = 2
+
+	bcryptHasher := newTestHasher(t, Options{Algorithm: AlgorithmBcrypt, BcryptCost: 4})
+	argon2idHasher := newTestHasher(t, Options{Algorithm: AlgorithmArgon2id, Argon2id: testArgon2idParams})
+
+	for _, tt := range []struct {
+		name        string
+		hashedWith  *Hasher
+		verifier    *Hasher
+		needsRehash bool
+	}{
+		{name: "bcrypt to argon2id", hashedWith: bcryptHasher, verifier: argon2idHasher, needsRehash: true},
+		{name: "argon2id to bcrypt", hashedWith: argon2idHasher, verifier: bcryptHasher, needsRehash: true},
+		{name: "argon2id parameters changed", hashedWith: argon2idHasher, verifier: newTestHasher(t, Options{Algorithm: AlgorithmArgon2id, Argon2id: stronger}), needsRehash: true},
+		{name: "bcrypt cost changed", hashedWith: bcryptHasher, verifier: newTestHasher(t, Options{Algorithm: AlgorithmBcrypt, BcryptCost: 5}), needsRehash: true},
+		{name: "bcrypt unchanged", hashedWith: bcryptHasher, verifier: bcryptHasher, needsRehash: false},
+		{name: "argon2id unchanged", hashedWith: argon2idHasher, verifier: argon2idHasher, needsRehash: false},
+	} {
+		hash, err := tt.hashedWith.Hash("correct horse")
+		if err != nil {
+			t.Fatalf("%s: Hash: %v", tt.name, err)
+		}
+
+		match, needsRehash, err := tt.verifier.Verify(hash, "correct horse")
+		if err != nil || !match {
+			t.Errorf("%s: Verify = %t, %v; want a match", tt.name, match, err)
+			continue
+		}
+		if needsRehash != tt.needsRehash {
+			t.Errorf("%s: needsRehash = %t, want %t", tt.name, needsRehash, tt.needsRehash)
+		}
+
+		// A mismatch never asks for a rehash, which would store a hash of the wrong password.
+		if _, needsRehash, _ := tt.verifier.Verify(hash, "battery staple"); needsRehash {
+			t.Errorf("%s: needsRehash for an incorrect password", tt.name)
+		}
+	}
+}
+
+func TestHasherVerifyInvalidHash(t *testing.T) {
+	h := newTestHasher(t, Options{Algorithm: AlgorithmArgon2id, Argon2id: testArgon2idParams})
+
+	if _, _, err := h.Verify("plain text", "plain text"); !errors.Is(err, ErrUnknownHashFormat) {
+		t.Errorf("Verify(plain text) err = %v, want %v", err, ErrUnknownHashFormat)
+	}
+	for _, hash := range []string{
+		"$argon2id$v=19$m=64,t=1,p=1$c2FsdA",
+		"$argon2id$v=16$m=64,t=1,p=1$c2FsdHNhbHQ$a2V5a2V5",
+		"$argon2id$v=19$m=x,t=1,p=1$c2FsdHNhbHQ$a2V5a2V5",
+		"$argon2id$v=19$m=64,t=1,p=1$!$a2V5a2V5",
+	} {
+		if match, _, err := h.Verify(hash, "password"); err == nil || match {
+			t.Errorf("Verify(%q) = %t, %v; want an error", hash, match, err)
+		}
+	}
+}
+
+func TestNewHasherRejectsInvalidOptions(t *testing.T) {
+	weak := testArgon2idParams
+	weak.SaltLength = 4
+
+	for _, opts := range []Options{
+		{Algorithm: "md5"},
+		{Algorithm: AlgorithmArgon2id, Argon2id: weak},
+		{Algorithm: AlgorithmBcrypt, BcryptCost: 3},
+	} {
+		if _, err := NewHasher(opts); err == nil {
+			t.Errorf("NewHasher(%+v) returned no error", opts)
+		}
+	}
+}
@@ -0,0 +1,99 @@
+package password
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxLength limits the work done hashing a password.
+const MaxLength = 128
+
+var ErrPolicyViolation = errors.New("password does not meet the password policy")
+
+// PolicyViolation describes why a password was rejected in a form that can be shown to the user.
+type PolicyViolation struct {
+	Reason string
+	// Format and Args produce Reason, so that it can be translated before it is formatted.
+	Format string
+	Args   []any
+}
+
+func newPolicyViolation(format string, args ...any) *PolicyViolation {
+	return &PolicyViolation{
+		Reason: fmt.Sprintf(format, args...),
+		Format: format,
+		Args:   args,
+	}
+}
+
+func (v *PolicyViolation) Error() string {
+	return v.Reason
+}
+
+func (v *PolicyViolation) Is(target error) bool {
+	return target == ErrPolicyViolation
+}
+
+// Policy is the password policy enforced when a password is set.
+type Policy struct {
+	minLength int
+	breached  *breachedList
+}
+
+// NewPolicy creates a policy requiring at least minLength characters. When breachedListPath
+// is set, passwords found in the file are rejected.
+//
+// The file is the Have I Been Pwned SHA-1 download ordered by hash: one hex SHA-1 digest per
+// line, optionally followed by ":count", sorted in ascending order. The file is searched on disk
+// rather than loaded into memory, so the full list of over a billion entries can be used.
+func NewPolicy(minLength int, breachedListPath string) (*Policy, error) {
+	if minLength < 1 || minLength > MaxLength {
+		return nil, fmt.Errorf("minimum password length must be between 1 and %d", MaxLength)
+	}
+
+	policy := &Policy{minLength: minLength}
+	if breachedListPath == "" {
+		return policy, nil
+	}
+
+	breached, err := openBreachedList(breachedListPath)
+	if err != nil {
+		return nil, err
+	}
+	policy.breached = breached
+
+	return policy, nil
+}
+
+// Validate returns a *PolicyViolation if the password does not meet the policy, or another
+// error if the breached password list could not be read.
+func (p *Policy) Validate(password string) error {
+	length := utf8.RuneCountInString(password)
+	if length < p.minLength {
+		return newPolicyViolation("Password must be at least %d characters", p.minLength)
+	}
+	if length > MaxLength {
+		return newPolicyViolation("Password must be at most %d characters", MaxLength)
+	}
+	if p.breached == nil {
+		return nil
+	}
+
+	breached, err := p.breached.contains(sha1Hex(password))
+	if err != nil {
+		return fmt.Errorf("failed to search breached password list: %w", err)
+	}
+	if breached {
+		return newPolicyViolation("This password has appeared in a data breach, please choose a different password")
+	}
+	return nil
+}
+
+func sha1Hex(s string) string {
+	sum := sha1.Sum([]byte(s))
+	return strings.ToUpper(hex.EncodeToString(sum[:]))
+}
@@ -0,0 +1,65 @@
+// Package safehttp creates HTTP clients for sending requests to URLs provided by users, such
+// as webhook receivers and push service endpoints, without letting them reach internal services.
+package safehttp
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"syscall"
+	"time"
+)
+
+var ErrPrivateAddress = errors.New("address is not public")
+
+// NewClient returns a client that neither uses a proxy nor follows redirects, as the URL should
+// be configured with its final location. Unless private networks are allowed, it refuses to
+// connect to loopback, private and link-local addresses.
+func NewClient(timeout time.Duration, allowPrivateNetworks bool) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout}
+	if !allowPrivateNetworks {
+		dialer.Control = rejectPrivateAddresses
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+}
+
+// IsPublicHost reports whether the host of a URL looks like a public domain name. IP literals,
+// localhost and single label names are rejected. This only catches obviously internal URLs
+// up front, the client still checks the address it connects to after name resolution.
+func IsPublicHost(host string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if host == "" || !strings.Contains(host, ".") {
+		return false
+	}
+	if _, err := netip.ParseAddr(strings.Trim(host, "[]")); err == nil {
+		return false
+	}
+	return host != "localhost" && !strings.HasSuffix(host, ".localhost")
+}
+
+// rejectPrivateAddresses is a net.Dialer Control function refusing connections to addresses
+// that are not publicly routable. It runs after name resolution, so DNS cannot be used to get around it.
+func rejectPrivateAddresses(_, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return err
+	}
+	addr := addrPort.Addr().Unmap()
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() || addr.IsLoopback() || addr.IsLinkLocalUnicast() {
+		return ErrPrivateAddress
+	}
+	return nil
+}
@@ -1,6 +1,7 @@
 package send
 
 import (
+	"beerbux/pkg/i18n"
 	"encoding/json"
 	"errors"
 	"net/http"
@@ -20,15 +21,21 @@ func JSON(w http.ResponseWriter, v interface{}, status int) {
 }
 
 // Error sends a simple error JSON payload with the given status code.
+//
+// The error is translated into the locale of the request when the response writer knows it.
 func Error(w http.ResponseWriter, err string, code int) {
 	w.WriteHeader(code)
-	_ = json.NewEncoder(w).Encode(map[string]string{"error": err})
+	_ = json.NewEncoder(w).Encode(map[string]string{"error": translate(w, err)})
 }
 
 func BadRequest(w http.ResponseWriter, err string) {
 	Error(w, err, http.StatusBadRequest)
 }
 
+func Forbidden(w http.ResponseWriter, err string) {
+	Error(w, err, http.StatusForbidden)
+}
+
 func NotFound(w http.ResponseWriter, err string) {
 	Error(w, err, http.StatusNotFound)
 }
@@ -41,6 +48,10 @@ func Unauthorized(w http.ResponseWriter, err string) {
 	Error(w, err, http.StatusUnauthorized)
 }
 
+func TooManyRequests(w http.ResponseWriter, err string) {
+	Error(w, err, http.StatusTooManyRequests)
+}
+
 // ValidationError sends a JSON payload detailing ozzo-validation validation errors.
 // The JSON payload contains an errors map detailing the fields and their errors.
 //
@@ -61,14 +72,40 @@ func ValidationError(w http.ResponseWriter, err error) {
 	if errors.As(err, &validationErrors) {
 		fieldErrs := result["errors"].(map[string]string)
 		for field, e := range validationErrors {
-			fieldErrs[field] = e.Error()
+			var ozErr oz.Error
+			if errors.As(e, &ozErr) {
+				// Translate the message template so that its parameters are still filled in
+				fieldErrs[field] = ozErr.SetMessage(translate(w, ozErr.Message())).Error()
+				continue
+			}
+			fieldErrs[field] = translate(w, e.Error())
 		}
 	} else {
 		// Handle any unexpected errors
-		result["error"] = err.Error()
+		result["error"] = translate(w, err.Error())
 	}
 
 	if err := json.NewEncoder(w).Encode(result); err != nil {
 		Error(w, "Failed to encode validation error", http.StatusInternalServerError)
 	}
 }
+
+// localizer is implemented by response writers that know the locale of the request they respond to.
+type localizer interface {
+	Locale() string
+}
+
+// translate translates message into the locale of the request, looking through any
+// response writers wrapping the localizer. Messages are left in English otherwise.
+func translate(w http.ResponseWriter, message string) string {
+	for {
+		if l, ok := w.(localizer); ok {
+			return i18n.Translate(l.Locale(), message)
+		}
+		u, ok := w.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			return message
+		}
+		w = u.Unwrap()
+	}
+}
@@ -0,0 +1,70 @@
+package slack
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// HeaderTimestamp is the Unix time in seconds at which Slack sent the request.
+	HeaderTimestamp = "X-Slack-Request-Timestamp"
+	// HeaderSignature is "v0=" followed by the hex encoded HMAC-SHA256 of "v0:", the
+	// timestamp, a colon and the body, keyed with the app's signing secret.
+	HeaderSignature = "X-Slack-Signature"
+
+	signatureVersion = "v0"
+)
+
+var (
+	ErrMissingSignature = errors.New("slack signature headers are missing")
+	ErrInvalidSignature = errors.New("slack signature does not match")
+	ErrTimestampExpired = errors.New("slack timestamp is outside the tolerance")
+)
+
+// Sign returns the value of the signature header for the body sent at the given time.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	return signatureVersion + "=" + hex.EncodeToString(mac(secret, strconv.FormatInt(timestamp.Unix(), 10), body))
+}
+
+// Verify checks the signature headers of a request from Slack against the body. Requests
+// signed more than tolerance ago, or that far in the future, are rejected to prevent replays.
+func Verify(secret string, header http.Header, body []byte, tolerance time.Duration) error {
+	timestamp, signature := header.Get(HeaderTimestamp), header.Get(HeaderSignature)
+	if timestamp == "" || signature == "" {
+		return ErrMissingSignature
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrMissingSignature
+	}
+	if age := time.Since(time.Unix(unix, 0)); age > tolerance || age < -tolerance {
+		return ErrTimestampExpired
+	}
+
+	hexSignature, ok := strings.CutPrefix(signature, signatureVersion+"=")
+	if !ok {
+		return ErrInvalidSignature
+	}
+	got, err := hex.DecodeString(hexSignature)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if !hmac.Equal(got, mac(secret, timestamp, body)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func mac(secret, timestamp string, body []byte) []byte {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(signatureVersion + ":" + timestamp + ":"))
+	h.Write(body)
+	return h.Sum(nil)
+}
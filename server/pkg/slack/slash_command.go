@@ -0,0 +1,66 @@
+// Package slack verifies and parses Slack slash command requests and builds their responses.
+// Other chat services sending Slack-compatible slash commands are supported the same way.
+package slack
+
+import (
+	"errors"
+	"net/url"
+)
+
+var ErrInvalidSlashCommand = errors.New("invalid slash command")
+
+const (
+	// ResponseEphemeral responses are only shown to the user who ran the command.
+	ResponseEphemeral = "ephemeral"
+	// ResponseInChannel responses are posted to the channel the command was run in.
+	ResponseInChannel = "in_channel"
+)
+
+// SlashCommand is the form Slack posts when a user runs a slash command.
+type SlashCommand struct {
+	TeamID      string
+	ChannelID   string
+	UserID      string
+	UserName    string
+	Command     string
+	Text        string
+	ResponseURL string
+}
+
+// ParseSlashCommand parses the form encoded body of a slash command request.
+func ParseSlashCommand(body []byte) (SlashCommand, error) {
+	form, err := url.ParseQuery(string(body))
+	if err != nil {
+		return SlashCommand{}, ErrInvalidSlashCommand
+	}
+
+	cmd := SlashCommand{
+		TeamID:      form.Get("team_id"),
+		ChannelID:   form.Get("channel_id"),
+		UserID:      form.Get("user_id"),
+		UserName:    form.Get("user_name"),
+		Command:     form.Get("command"),
+		Text:        form.Get("text"),
+		ResponseURL: form.Get("response_url"),
+	}
+	if cmd.TeamID == "" || cmd.ChannelID == "" || cmd.UserID == "" || cmd.Command == "" {
+		return SlashCommand{}, ErrInvalidSlashCommand
+	}
+	return cmd, nil
+}
+
+// Response is the reply to a slash command. Text uses Slack's mrkdwn formatting.
+type Response struct {
+	ResponseType string `json:"response_type"`
+	Text         string `json:"text"`
+}
+
+// Ephemeral returns a response only shown to the user who ran the command.
+func Ephemeral(text string) Response {
+	return Response{ResponseType: ResponseEphemeral, Text: text}
+}
+
+// InChannel returns a response posted to the channel the command was run in.
+func InChannel(text string) Response {
+	return Response{ResponseType: ResponseInChannel, Text: text}
+}
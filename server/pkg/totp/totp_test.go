@@ -0,0 +1,93 @@
+package totp
+
+import (
+	"encoding/base32"
+	"errors"
+	"testing"
+	"time"
+)
+
+// rfc6238Secret is the SHA-1 shared secret from RFC 6238 Appendix B.
+var rfc6238Secret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+// rfc6238Vectors are the SHA-1 test vectors from RFC 6238 Appendix B. The RFC uses 8 digit
+// codes, the expected codes here are the last Digits digits of those values.
+var rfc6238Vectors = []struct {
+	unix int64
+	code string
+}{
+	{unix: 59, code: "287082"},
+	{unix: 1111111109, code: "081804"},
+	{unix: 1111111111, code: "050471"},
+	{unix: 1234567890, code: "005924"},
+	{unix: 2000000000, code: "279037"},
+	{unix: 20000000000, code: "353130"},
+}
+
+func TestCode(t *testing.T) {
+	for _, tt := range rfc6238Vectors {
+		got, err := Code(rfc6238Secret, Step(time.Unix(tt.unix, 0)))
+		if err != nil {
+			t.Fatalf("Code at %d: %v", tt.unix, err)
+		}
+		if got != tt.code {
+			t.Errorf("Code at %d = %q, want %q", tt.unix, got, tt.code)
+		}
+	}
+}
+
+func TestValidate(t *testing.T) {
+	for _, tt := range rfc6238Vectors {
+		now := time.Unix(tt.unix, 0)
+		step, ok := Validate(rfc6238Secret, tt.code, now)
+		if !ok {
+			t.Errorf("Validate(%q) at %d rejected the code", tt.code, tt.unix)
+			continue
+		}
+		if step != Step(now) {
+			t.Errorf("Validate(%q) at %d = step %d, want %d", tt.code, tt.unix, step, Step(now))
+		}
+	}
+}
+
+func TestValidateSkew(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	for _, tt := range []struct {
+		name  string
+		steps int64
+		ok    bool
+	}{
+		{name: "previous step", steps: -Skew, ok: true},
+		{name: "next step", steps: Skew, ok: true},
+		{name: "outside skew behind", steps: -Skew - 1, ok: false},
+		{name: "outside skew ahead", steps: Skew + 1, ok: false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			code, err := Code(rfc6238Secret, Step(now)+tt.steps)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, ok := Validate(rfc6238Secret, code, now); ok != tt.ok {
+				t.Errorf("Validate = %v, want %v", ok, tt.ok)
+			}
+		})
+	}
+}
+
+func TestValidateRejectsMalformedCodes(t *testing.T) {
+	now := time.Unix(59, 0)
+	for _, code := range []string{"", "28708", "2870822", "abcdef"} {
+		if _, ok := Validate(rfc6238Secret, code, now); ok {
+			t.Errorf("Validate(%q) accepted a malformed code", code)
+		}
+	}
+	if _, ok := Validate(rfc6238Secret, "287 082", now); !ok {
+		t.Error("Validate rejected a code containing a space")
+	}
+}
+
+func TestCodeInvalidSecret(t *testing.T) {
+	if _, err := Code("not base32!", 1); !errors.Is(err, ErrInvalidSecret) {
+		t.Errorf("Code error = %v, want ErrInvalidSecret", err)
+	}
+}
@@ -0,0 +1,82 @@
+// Package webhook sends signed webhook requests and verifies their signatures.
+package webhook
+
+import (
+	"beerbux/pkg/safehttp"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const (
+	requestTimeout = 10 * time.Second
+	// maxErrorBodySize is how much of the body of a failed response is kept for the delivery log.
+	maxErrorBodySize = 512
+)
+
+// StatusError is returned when the receiver responds with a status other than 2xx.
+type StatusError struct {
+	StatusCode int
+	Body       string
+}
+
+func (e *StatusError) Error() string {
+	if e.Body == "" {
+		return fmt.Sprintf("webhook responded with status %d", e.StatusCode)
+	}
+	return fmt.Sprintf("webhook responded with status %d: %s", e.StatusCode, e.Body)
+}
+
+// Request is a webhook request to send.
+type Request struct {
+	URL        string
+	Secret     string
+	DeliveryID string
+	Event      string
+	Body       []byte
+}
+
+// Client sends webhook requests. Unless private networks are allowed, it refuses to connect to
+// loopback, private and link-local addresses, so webhooks cannot be used to reach internal services.
+// Redirects are not followed, as the receiver should be configured with its final URL.
+type Client struct {
+	httpClient *http.Client
+}
+
+func NewClient(allowPrivateNetworks bool) *Client {
+	return &Client{
+		httpClient: safehttp.NewClient(requestTimeout, allowPrivateNetworks),
+	}
+}
+
+// Send signs and posts the request, returning the response status code. A response other
+// than 2xx is returned as a *StatusError along with its status code.
+func (c *Client) Send(ctx context.Context, r Request) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.URL, bytes.NewReader(r.Body))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Beerbux-Webhooks/1.0")
+	req.Header.Set(HeaderDeliveryID, r.DeliveryID)
+	req.Header.Set(HeaderEvent, r.Event)
+	now := time.Now()
+	req.Header.Set(HeaderTimestamp, fmt.Sprint(now.Unix()))
+	req.Header.Set(HeaderSignature, Sign(r.Secret, now, r.Body))
+
+	res, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to send webhook: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, maxErrorBodySize))
+		return res.StatusCode, &StatusError{StatusCode: res.StatusCode, Body: string(bytes.TrimSpace(body))}
+	}
+	_, _ = io.Copy(io.Discard, io.LimitReader(res.Body, maxErrorBodySize))
+	return res.StatusCode, nil
+}
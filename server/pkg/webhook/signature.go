@@ -0,0 +1,87 @@
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// HeaderDeliveryID is the ID of the delivery, which is kept when a delivery is retried.
+	HeaderDeliveryID = "Beerbux-Webhook-Id"
+	// HeaderEvent is the type of the event in the body.
+	HeaderEvent = "Beerbux-Webhook-Event"
+	// HeaderTimestamp is the Unix time in seconds at which the request was signed.
+	HeaderTimestamp = "Beerbux-Webhook-Timestamp"
+	// HeaderSignature is "sha256=" followed by the hex encoded HMAC-SHA256 of the timestamp,
+	// a full stop and the body, keyed with the webhook secret.
+	HeaderSignature = "Beerbux-Webhook-Signature"
+
+	signaturePrefix = "sha256="
+	secretPrefix    = "whsec_"
+)
+
+var (
+	ErrMissingSignature = errors.New("webhook signature headers are missing")
+	ErrInvalidSignature = errors.New("webhook signature does not match")
+	ErrTimestampExpired = errors.New("webhook timestamp is outside the tolerance")
+)
+
+// GenerateSecret returns a new random secret for signing webhook requests.
+func GenerateSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return secretPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Sign returns the value of the signature header for the body sent at the given time.
+func Sign(secret string, timestamp time.Time, body []byte) string {
+	return signaturePrefix + hex.EncodeToString(mac(secret, strconv.FormatInt(timestamp.Unix(), 10), body))
+}
+
+// Verify checks the signature headers of a webhook request against the body. Requests signed
+// more than tolerance ago, or that far in the future, are rejected to prevent replays.
+func Verify(secret string, header http.Header, body []byte, tolerance time.Duration) error {
+	timestamp, signature := header.Get(HeaderTimestamp), header.Get(HeaderSignature)
+	if timestamp == "" || signature == "" {
+		return ErrMissingSignature
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return ErrMissingSignature
+	}
+	if age := time.Since(time.Unix(unix, 0)); age > tolerance || age < -tolerance {
+		return ErrTimestampExpired
+	}
+
+	hexSignature, ok := strings.CutPrefix(signature, signaturePrefix)
+	if !ok {
+		return ErrInvalidSignature
+	}
+	got, err := hex.DecodeString(hexSignature)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if !hmac.Equal(got, mac(secret, timestamp, body)) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
+
+func mac(secret, timestamp string, body []byte) []byte {
+	h := hmac.New(sha256.New, []byte(secret))
+	h.Write([]byte(timestamp))
+	h.Write([]byte("."))
+	h.Write(body)
+	return h.Sum(nil)
+}
@@ -0,0 +1,189 @@
+package webpush
+
+import (
+	"crypto/ecdh"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DevServiceCapacity is the number of messages kept by the DevService before the oldest are dropped.
+const DevServiceCapacity = 100
+
+var (
+	ErrUnknownSubscription = errors.New("unknown push subscription")
+	ErrInvalidPushRequest  = errors.New("invalid push request")
+)
+
+// ReceivedMessage is a message delivered to the DevService, decrypted as the browser would.
+type ReceivedMessage struct {
+	ID             string          `json:"id"`
+	SubscriptionID string          `json:"subscriptionId"`
+	TTL            int             `json:"ttl"`
+	Urgency        string          `json:"urgency,omitempty"`
+	Topic          string          `json:"topic,omitempty"`
+	Payload        json.RawMessage `json:"payload"`
+	ReceivedAt     time.Time       `json:"receivedAt"`
+}
+
+// DevSubscription is a subscription created by the DevService, serialised like the
+// browser's PushSubscription so it can be registered with the API as is.
+type DevSubscription struct {
+	ID             string            `json:"id"`
+	Endpoint       string            `json:"endpoint"`
+	ExpirationTime *int64            `json:"expirationTime"`
+	Keys           map[string]string `json:"keys"`
+}
+
+type devDevice struct {
+	private *ecdh.PrivateKey
+	auth    []byte
+	expired bool
+}
+
+// DevService is a development stand-in for a browser and its push service. It creates
+// subscriptions whose endpoints point back at it, checks the VAPID authorization of the
+// messages sent to them and decrypts them so that they can be read back.
+type DevService struct {
+	mu       sync.RWMutex
+	devices  map[string]*devDevice
+	messages []ReceivedMessage
+	logger   *slog.Logger
+}
+
+func NewDevService(logger *slog.Logger) *DevService {
+	return &DevService{
+		devices: make(map[string]*devDevice),
+		logger:  logger,
+	}
+}
+
+// Subscribe creates a subscription whose endpoint is endpointBaseURL followed by its ID.
+func (s *DevService) Subscribe(endpointBaseURL string) (DevSubscription, error) {
+	private, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return DevSubscription{}, fmt.Errorf("failed to generate subscription key: %w", err)
+	}
+	auth := make([]byte, authSize)
+	if _, err := rand.Read(auth); err != nil {
+		return DevSubscription{}, fmt.Errorf("failed to generate auth secret: %w", err)
+	}
+
+	id := uuid.NewString()
+	s.mu.Lock()
+	s.devices[id] = &devDevice{private: private, auth: auth}
+	s.mu.Unlock()
+
+	return DevSubscription{
+		ID:       id,
+		Endpoint: strings.TrimRight(endpointBaseURL, "/") + "/" + id,
+		Keys: map[string]string{
+			"p256dh": base64.RawURLEncoding.EncodeToString(private.PublicKey().Bytes()),
+			"auth":   base64.RawURLEncoding.EncodeToString(auth),
+		},
+	}, nil
+}
+
+// Expire makes the push endpoint of the subscription respond with 410 Gone, as push services
+// do once the user unsubscribes, so that the cleanup of expired subscriptions can be tested.
+func (s *DevService) Expire(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	device, ok := s.devices[id]
+	if ok {
+		device.expired = true
+	}
+	return ok
+}
+
+// Receive handles a message sent to the push endpoint of the subscription with the given ID.
+// The audience is the origin the push endpoint was requested on.
+func (s *DevService) Receive(id, audience string, r *http.Request) (ReceivedMessage, error) {
+	s.mu.RLock()
+	device, ok := s.devices[id]
+	s.mu.RUnlock()
+	if !ok {
+		return ReceivedMessage{}, ErrUnknownSubscription
+	}
+	if device.expired {
+		return ReceivedMessage{}, ErrSubscriptionGone
+	}
+
+	if _, err := VerifyAuthorization(r.Header.Get("Authorization"), audience); err != nil {
+		return ReceivedMessage{}, err
+	}
+	if r.Header.Get("Content-Encoding") != "aes128gcm" {
+		return ReceivedMessage{}, fmt.Errorf("%w: unsupported content encoding", ErrInvalidPushRequest)
+	}
+	ttl, err := strconv.Atoi(r.Header.Get("TTL"))
+	if err != nil || ttl < 0 {
+		return ReceivedMessage{}, fmt.Errorf("%w: missing or invalid TTL", ErrInvalidPushRequest)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(r.Body, 4097))
+	if err != nil {
+		return ReceivedMessage{}, fmt.Errorf("failed to read push message: %w", err)
+	}
+	if len(body) > 4096 {
+		return ReceivedMessage{}, ErrPayloadTooLarge
+	}
+	plaintext, err := Decrypt(body, device.private, device.auth)
+	if err != nil {
+		return ReceivedMessage{}, err
+	}
+
+	payload := json.RawMessage(plaintext)
+	if !json.Valid(plaintext) {
+		payload, _ = json.Marshal(string(plaintext))
+	}
+	msg := ReceivedMessage{
+		ID:             uuid.NewString(),
+		SubscriptionID: id,
+		TTL:            ttl,
+		Urgency:        r.Header.Get("Urgency"),
+		Topic:          r.Header.Get("Topic"),
+		Payload:        payload,
+		ReceivedAt:     time.Now(),
+	}
+
+	s.mu.Lock()
+	s.messages = append(s.messages, msg)
+	if len(s.messages) > DevServiceCapacity {
+		s.messages = s.messages[len(s.messages)-DevServiceCapacity:]
+	}
+	s.mu.Unlock()
+
+	s.logger.Info("push message received", "id", msg.ID, "subscription", id)
+	return msg, nil
+}
+
+// List returns the received messages, newest first. If subscriptionID is not empty, only
+// messages sent to that subscription are returned.
+func (s *DevService) List(subscriptionID string) []ReceivedMessage {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	messages := make([]ReceivedMessage, 0, len(s.messages))
+	for i := len(s.messages) - 1; i >= 0; i-- {
+		if subscriptionID == "" || s.messages[i].SubscriptionID == subscriptionID {
+			messages = append(messages, s.messages[i])
+		}
+	}
+	return messages
+}
+
+func (s *DevService) Clear() {
+	s.mu.Lock()
+	s.messages = nil
+	s.mu.Unlock()
+}
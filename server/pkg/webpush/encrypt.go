@@ -0,0 +1,160 @@
+package webpush
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+const (
+	// recordSize is the record size declared in the aes128gcm header. Payloads are always
+	// encrypted into a single record.
+	recordSize = 4096
+	saltSize   = 16
+	keySize    = 65
+	authSize   = 16
+	tagSize    = 16
+	// headerSize is the size of the aes128gcm header: the salt, the record size and the
+	// length of the key ID followed by the application server's public key as the key ID.
+	headerSize = saltSize + 4 + 1 + keySize
+	// MaxPayloadSize is the largest payload that can be sent, so that the encrypted message
+	// fits in the 4096 bytes every push service must accept.
+	MaxPayloadSize = 4096 - headerSize - tagSize - 1
+)
+
+var (
+	ErrPayloadTooLarge = errors.New("payload too large")
+	ErrInvalidMessage  = errors.New("invalid encrypted message")
+)
+
+// Encrypt encrypts plaintext for a subscription using the aes128gcm content encoding, as
+// described by RFC 8291, given the subscription's public key and authentication secret.
+func Encrypt(plaintext, uaPublic, authSecret []byte) ([]byte, error) {
+	asPrivate, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate key: %w", err)
+	}
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+	return encrypt(plaintext, uaPublic, authSecret, asPrivate, salt)
+}
+
+func encrypt(plaintext, uaPublic, authSecret []byte, asPrivate *ecdh.PrivateKey, salt []byte) ([]byte, error) {
+	if len(plaintext) > MaxPayloadSize {
+		return nil, ErrPayloadTooLarge
+	}
+	uaKey, err := ecdh.P256().NewPublicKey(uaPublic)
+	if err != nil {
+		return nil, fmt.Errorf("invalid subscription key: %w", err)
+	}
+	if len(authSecret) != authSize {
+		return nil, errors.New("invalid subscription auth secret")
+	}
+
+	sharedSecret, err := asPrivate.ECDH(uaKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive shared secret: %w", err)
+	}
+	asPublic := asPrivate.PublicKey().Bytes()
+
+	gcm, nonce, err := contentCipher(sharedSecret, authSecret, uaPublic, asPublic, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	header := make([]byte, 0, headerSize)
+	header = append(header, salt...)
+	header = binary.BigEndian.AppendUint32(header, recordSize)
+	header = append(header, byte(len(asPublic)))
+	header = append(header, asPublic...)
+
+	// The payload is the last record, so it is followed by the 0x02 delimiter and no padding.
+	record := append(bytes.Clone(plaintext), 0x02)
+	return gcm.Seal(header, nonce, record, nil), nil
+}
+
+// Decrypt decrypts a message encrypted with Encrypt, given the private key and authentication
+// secret of the subscription it was sent to. Push services never do this; it is what the
+// browser does when the message is delivered.
+func Decrypt(body []byte, uaPrivate *ecdh.PrivateKey, authSecret []byte) ([]byte, error) {
+	if len(body) < headerSize+tagSize {
+		return nil, ErrInvalidMessage
+	}
+	salt := body[:saltSize]
+	rs := binary.BigEndian.Uint32(body[saltSize : saltSize+4])
+	idLen := int(body[saltSize+4])
+	if idLen != keySize || len(body)-headerSize > int(rs) {
+		return nil, ErrInvalidMessage
+	}
+	asPublic := body[saltSize+5 : headerSize]
+
+	asKey, err := ecdh.P256().NewPublicKey(asPublic)
+	if err != nil {
+		return nil, ErrInvalidMessage
+	}
+	sharedSecret, err := uaPrivate.ECDH(asKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive shared secret: %w", err)
+	}
+
+	gcm, nonce, err := contentCipher(sharedSecret, authSecret, uaPrivate.PublicKey().Bytes(), asPublic, salt)
+	if err != nil {
+		return nil, err
+	}
+	record, err := gcm.Open(nil, nonce, body[headerSize:], nil)
+	if err != nil {
+		return nil, ErrInvalidMessage
+	}
+
+	// Remove the padding and the delimiter, which must mark the last record.
+	end := bytes.LastIndexFunc(record, func(r rune) bool { return r != 0 })
+	if end < 0 || record[end] != 0x02 {
+		return nil, ErrInvalidMessage
+	}
+	return record[:end], nil
+}
+
+// contentCipher derives the content encryption key and nonce from the shared ECDH secret.
+func contentCipher(sharedSecret, authSecret, uaPublic, asPublic, salt []byte) (cipher.AEAD, []byte, error) {
+	prkKey, err := hkdf.Extract(sha256.New, sharedSecret, authSecret)
+	if err != nil {
+		return nil, nil, err
+	}
+	keyInfo := "WebPush: info\x00" + string(uaPublic) + string(asPublic)
+	ikm, err := hkdf.Expand(sha256.New, prkKey, keyInfo, 32)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	prk, err := hkdf.Extract(sha256.New, ikm, salt)
+	if err != nil {
+		return nil, nil, err
+	}
+	cek, err := hkdf.Expand(sha256.New, prk, "Content-Encoding: aes128gcm\x00", 16)
+	if err != nil {
+		return nil, nil, err
+	}
+	nonce, err := hkdf.Expand(sha256.New, prk, "Content-Encoding: nonce\x00", 12)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, nil, err
+	}
+	return gcm, nonce, nil
+}
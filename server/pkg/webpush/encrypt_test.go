@@ -0,0 +1,95 @@
+package webpush
+
+import (
+	"bytes"
+	"crypto/ecdh"
+	"encoding/base64"
+	"errors"
+	"testing"
+)
+
+// The example from RFC 8291 Appendix A.
+const (
+	rfc8291Plaintext  = "V2hlbiBJIGdyb3cgdXAsIEkgd2FudCB0byBiZSBhIHdhdGVybWVsb24"
+	rfc8291ASPublic   = "BP4z9KsN6nGRTbVYI_c7VJSPQTBtkgcy27mlmlMoZIIgDll6e3vCYLocInmYWAmS6TlzAC8wEqKK6PBru3jl7A8"
+	rfc8291ASPrivate  = "yfWPiYE-n46HLnH0KqZOF1fJJU3MYrct3AELtAQ-oRw"
+	rfc8291UAPublic   = "BCVxsr7N_eNgVRqvHtD0zTZsEc6-VV-JvLexhqUzORcxaOzi6-AYWXvTBHm4bjyPjs7Vd8pZGH6SRpkNtoIAiw4"
+	rfc8291UAPrivate  = "q1dXpw3UpT5VOmu_cf_v6ih07Aems3njxI-JWgLcM94"
+	rfc8291Salt       = "DGv6ra1nlYgDCS1FRnbzlw"
+	rfc8291AuthSecret = "BTBZMqHH6r4Tts7J_aSIgg"
+	rfc8291Message    = "DGv6ra1nlYgDCS1FRnbzlwAAEABBBP4z9KsN6nGRTbVYI_c7VJSPQTBtkgcy27mlmlMoZIIgDll6e3vCYLocInmYWAmS6TlzAC8wEqKK6PBru3jl7A_yl95bQpu6cVPTpK4Mqgkf1CXztLVBSt2Ks3oZwbuwXPXLWyouBWLVWGNWQexSgSxsj_Qulcy4a-fN"
+)
+
+func decode(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		t.Fatalf("failed to decode %q: %v", s, err)
+	}
+	return b
+}
+
+func privateKey(t *testing.T, s string) *ecdh.PrivateKey {
+	t.Helper()
+	key, err := ecdh.P256().NewPrivateKey(decode(t, s))
+	if err != nil {
+		t.Fatalf("invalid private key: %v", err)
+	}
+	return key
+}
+
+func TestEncryptRFC8291(t *testing.T) {
+	asPrivate := privateKey(t, rfc8291ASPrivate)
+	if !bytes.Equal(asPrivate.PublicKey().Bytes(), decode(t, rfc8291ASPublic)) {
+		t.Fatal("application server private key does not match its public key")
+	}
+
+	got, err := encrypt(
+		decode(t, rfc8291Plaintext),
+		decode(t, rfc8291UAPublic),
+		decode(t, rfc8291AuthSecret),
+		asPrivate,
+		decode(t, rfc8291Salt),
+	)
+	if err != nil {
+		t.Fatalf("encrypt: %v", err)
+	}
+	if want := decode(t, rfc8291Message); !bytes.Equal(got, want) {
+		t.Errorf("encrypt = %s, want %s", base64.RawURLEncoding.EncodeToString(got), rfc8291Message)
+	}
+}
+
+func TestDecryptRFC8291(t *testing.T) {
+	got, err := Decrypt(decode(t, rfc8291Message), privateKey(t, rfc8291UAPrivate), decode(t, rfc8291AuthSecret))
+	if err != nil {
+		t.Fatalf("Decrypt: %v", err)
+	}
+	if want := decode(t, rfc8291Plaintext); !bytes.Equal(got, want) {
+		t.Errorf("Decrypt = %q, want %q", got, want)
+	}
+}
+
+func TestEncryptRoundTrip(t *testing.T) {
+	uaPrivate := privateKey(t, rfc8291UAPrivate)
+	authSecret := decode(t, rfc8291AuthSecret)
+	plaintext := bytes.Repeat([]byte("a"), MaxPayloadSize)
+
+	body, err := Encrypt(plaintext, uaPrivate.PublicKey().Bytes(), authSecret)
+	if err != nil {
+		t.Fatalf("Encrypt: %v", err)
+	}
+	if len(body) > recordSize {
+		t.Errorf("encrypted message is %d bytes, want at most %d", len(body), recordSize)
+	}
+	got, err := Decrypt(body, uaPrivate, authSecret)
+	if err != nil {
+		t.Fatalf("Decrypt: %v", err)
+	}
+	if !bytes.Equal(got, plaintext) {
+		t.Error("Decrypt did not return the original plaintext")
+	}
+
+	if _, err := Encrypt(append(plaintext, 'a'), uaPrivate.PublicKey().Bytes(), authSecret); !errors.Is(err, ErrPayloadTooLarge) {
+		t.Errorf("Encrypt error = %v, want ErrPayloadTooLarge", err)
+	}
+}
@@ -0,0 +1,155 @@
+package webpush
+
+import (
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v4"
+	"math/big"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// vapidTokenTTL is how long the VAPID token sent with a message is valid; push services
+// reject tokens that expire more than 24 hours in the future.
+const vapidTokenTTL = 12 * time.Hour
+
+var ErrInvalidVAPIDAuthorization = errors.New("invalid VAPID authorization")
+
+// VAPIDKeys is the key pair identifying the application server to push services, as
+// described by RFC 8292. Browsers only accept messages signed with the key that the
+// subscription was created with, so the keys must not change once subscriptions exist.
+type VAPIDKeys struct {
+	private *ecdsa.PrivateKey
+	// PublicKey is the uncompressed public key encoded as unpadded base64url, which the
+	// browser needs as the applicationServerKey when subscribing.
+	PublicKey string
+}
+
+// GenerateVAPIDKeys generates a new key pair.
+func GenerateVAPIDKeys() (*VAPIDKeys, error) {
+	key, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate VAPID keys: %w", err)
+	}
+	return newVAPIDKeys(key)
+}
+
+// ParseVAPIDPrivateKey parses a P-256 private key encoded as base64url, the format used by
+// most web push libraries.
+func ParseVAPIDPrivateKey(encoded string) (*VAPIDKeys, error) {
+	data, err := decodeBase64URL(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("invalid VAPID private key encoding: %w", err)
+	}
+	key, err := ecdh.P256().NewPrivateKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid VAPID private key: %w", err)
+	}
+	return newVAPIDKeys(key)
+}
+
+func newVAPIDKeys(key *ecdh.PrivateKey) (*VAPIDKeys, error) {
+	public := key.PublicKey().Bytes()
+	return &VAPIDKeys{
+		private: &ecdsa.PrivateKey{
+			PublicKey: ecdsaPublicKey(public),
+			D:         new(big.Int).SetBytes(key.Bytes()),
+		},
+		PublicKey: base64.RawURLEncoding.EncodeToString(public),
+	}, nil
+}
+
+// PrivateKey returns the private key encoded as base64url, as accepted by ParseVAPIDPrivateKey.
+func (k *VAPIDKeys) PrivateKey() string {
+	return base64.RawURLEncoding.EncodeToString(k.private.D.FillBytes(make([]byte, 32)))
+}
+
+// authorization returns the Authorization header for a message sent to endpoint. The
+// subject is a mailto: or https: URL the push service can use to contact the sender.
+func (k *VAPIDKeys) authorization(endpoint, subject string) (string, error) {
+	audience, err := origin(endpoint)
+	if err != nil {
+		return "", err
+	}
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.RegisteredClaims{
+		Audience:  jwt.ClaimStrings{audience},
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(vapidTokenTTL)),
+		Subject:   subject,
+	}).SignedString(k.private)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign VAPID token: %w", err)
+	}
+	return "vapid t=" + token + ", k=" + k.PublicKey, nil
+}
+
+// VerifyAuthorization checks the VAPID Authorization header of a message, as a push service
+// does, and returns the public key of the application server that sent it. The audience is
+// the origin of the push service.
+func VerifyAuthorization(header, audience string) (string, error) {
+	scheme, params, ok := strings.Cut(header, " ")
+	if !ok || !strings.EqualFold(scheme, "vapid") {
+		return "", ErrInvalidVAPIDAuthorization
+	}
+
+	var token, publicKey string
+	for _, param := range strings.Split(params, ",") {
+		key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+		switch key {
+		case "t":
+			token = value
+		case "k":
+			publicKey = value
+		}
+	}
+
+	keyBytes, err := decodeBase64URL(publicKey)
+	if err != nil {
+		return "", ErrInvalidVAPIDAuthorization
+	}
+	if _, err := ecdh.P256().NewPublicKey(keyBytes); err != nil {
+		return "", ErrInvalidVAPIDAuthorization
+	}
+	verificationKey := ecdsaPublicKey(keyBytes)
+
+	var claims jwt.RegisteredClaims
+	_, err = jwt.ParseWithClaims(token, &claims, func(t *jwt.Token) (any, error) {
+		return &verificationKey, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodES256.Alg()}))
+	if err != nil || claims.ExpiresAt == nil || !claims.VerifyAudience(audience, true) {
+		return "", ErrInvalidVAPIDAuthorization
+	}
+	if claims.ExpiresAt.After(time.Now().Add(24 * time.Hour)) {
+		return "", ErrInvalidVAPIDAuthorization
+	}
+	return publicKey, nil
+}
+
+// ecdsaPublicKey converts an uncompressed P-256 public key that has already been validated.
+func ecdsaPublicKey(uncompressed []byte) ecdsa.PublicKey {
+	return ecdsa.PublicKey{
+		Curve: elliptic.P256(),
+		X:     new(big.Int).SetBytes(uncompressed[1:33]),
+		Y:     new(big.Int).SetBytes(uncompressed[33:]),
+	}
+}
+
+// origin returns the scheme and host of a URL, which is the audience of the VAPID token.
+func origin(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return "", fmt.Errorf("invalid push endpoint: %s", rawURL)
+	}
+	return u.Scheme + "://" + u.Host, nil
+}
+
+// decodeBase64URL decodes base64url with or without padding, as browsers and libraries differ.
+func decodeBase64URL(s string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+}
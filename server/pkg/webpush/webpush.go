@@ -0,0 +1,142 @@
+// Package webpush sends notifications to browsers using the Web Push protocol: messages are
+// encrypted for the subscription as described by RFC 8291 and sent to its push service with
+// a VAPID authorization as described by RFC 8292.
+package webpush
+
+import (
+	"beerbux/pkg/safehttp"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Urgency tells the push service how soon a message should be delivered, letting devices
+// on battery save power on messages that can wait (RFC 8030 section 5.3).
+const (
+	UrgencyVeryLow = "very-low"
+	UrgencyLow     = "low"
+	UrgencyNormal  = "normal"
+	UrgencyHigh    = "high"
+)
+
+// ErrSubscriptionGone is returned when the push service no longer knows the subscription,
+// usually because the user unsubscribed or the subscription expired. It should be deleted.
+var ErrSubscriptionGone = errors.New("push subscription no longer exists")
+
+// Subscription is a push subscription as created by the browser's PushManager.
+type Subscription struct {
+	Endpoint string
+	// P256DH is the subscription's P-256 public key, encoded as base64url.
+	P256DH string
+	// Auth is the subscription's authentication secret, encoded as base64url.
+	Auth string
+}
+
+// Validate checks that the subscription's keys can be used to encrypt messages.
+func (s Subscription) Validate() error {
+	if _, err := origin(s.Endpoint); err != nil {
+		return err
+	}
+	_, _, err := s.keys()
+	return err
+}
+
+func (s Subscription) keys() ([]byte, []byte, error) {
+	public, err := decodeBase64URL(s.P256DH)
+	if err != nil || len(public) != keySize || public[0] != 0x04 {
+		return nil, nil, errors.New("invalid subscription key")
+	}
+	auth, err := decodeBase64URL(s.Auth)
+	if err != nil || len(auth) != authSize {
+		return nil, nil, errors.New("invalid subscription auth secret")
+	}
+	return public, auth, nil
+}
+
+// Message is a notification to send to a subscription.
+type Message struct {
+	Payload []byte
+	// TTL is how long the push service keeps the message while the device is offline.
+	TTL     time.Duration
+	Urgency string
+	// Topic optionally replaces an earlier undelivered message with the same topic.
+	Topic string
+}
+
+// Client sends messages to push services.
+type Client struct {
+	keys       *VAPIDKeys
+	subject    string
+	httpClient *http.Client
+}
+
+// NewClient creates a Client signing messages with keys. The subject is a mailto: or https:
+// URL that the operators of a push service can use to contact the sender.
+//
+// Endpoints are provided by the browser, so unless private networks are allowed, the client
+// refuses to connect to loopback, private and link-local addresses and does not follow redirects.
+func NewClient(keys *VAPIDKeys, subject string, allowPrivateNetworks bool) *Client {
+	return &Client{
+		keys:       keys,
+		subject:    subject,
+		httpClient: safehttp.NewClient(10*time.Second, allowPrivateNetworks),
+	}
+}
+
+// PublicKey returns the VAPID public key browsers need to subscribe.
+func (c *Client) PublicKey() string {
+	return c.keys.PublicKey
+}
+
+// Send encrypts the message for the subscription and sends it to its push service.
+// ErrSubscriptionGone is returned when the subscription should be deleted.
+func (c *Client) Send(ctx context.Context, sub Subscription, msg Message) error {
+	uaPublic, authSecret, err := sub.keys()
+	if err != nil {
+		return err
+	}
+	body, err := Encrypt(msg.Payload, uaPublic, authSecret)
+	if err != nil {
+		return err
+	}
+	authorization, err := c.keys.authorization(sub.Endpoint, c.subject)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.Endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create push request: %w", err)
+	}
+	req.Header.Set("Authorization", authorization)
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("Content-Encoding", "aes128gcm")
+	req.Header.Set("TTL", strconv.Itoa(int(msg.TTL.Seconds())))
+	if msg.Urgency != "" {
+		req.Header.Set("Urgency", msg.Urgency)
+	}
+	if msg.Topic != "" {
+		req.Header.Set("Topic", msg.Topic)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send push message: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return ErrSubscriptionGone
+	default:
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("push service responded with %d: %s", resp.StatusCode, bytes.TrimSpace(detail))
+	}
+}
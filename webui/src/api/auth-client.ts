@@ -1,14 +1,35 @@
 import { apiFetch } from "@/api/api-fetch.ts";
-import type { UserAuthDetails } from "./types/user.ts";
+import type { LoginMFARequired, UserAuthDetails } from "./types/user.ts";
 
 function useAuthClient() {
-	const login = async (username: string, password: string): Promise<UserAuthDetails> => {
-		return apiFetch<UserAuthDetails>("/auth/login", {
+	const login = async (username: string, password: string): Promise<UserAuthDetails | LoginMFARequired> => {
+		return apiFetch<UserAuthDetails | LoginMFARequired>("/auth/login", {
 			method: "POST",
 			body: JSON.stringify({ username, password }),
 		});
 	};
 
+	const loginTwoFactor = async (mfaToken: string, code: string): Promise<UserAuthDetails> => {
+		return apiFetch<UserAuthDetails>("/auth/login/2fa", {
+			method: "POST",
+			body: JSON.stringify({ mfaToken, code }),
+		});
+	};
+
+	const requestMagicLink = async (email: string): Promise<void> => {
+		return apiFetch<void>("/auth/magic-link", {
+			method: "POST",
+			body: JSON.stringify({ email }),
+		});
+	};
+
+	const loginMagicLink = async (email: string, otp: string): Promise<UserAuthDetails | LoginMFARequired> => {
+		return apiFetch<UserAuthDetails | LoginMFARequired>("/auth/magic-link/login", {
+			method: "POST",
+			body: JSON.stringify({ email, otp }),
+		});
+	};
+
 	const signup = async (
 		name: string,
 		username: string,
@@ -22,6 +43,13 @@ function useAuthClient() {
 		});
 	};
 
+	const verifyEmail = async (email: string, otp: string): Promise<void> => {
+		return apiFetch<void>("/auth/email/verify", {
+			method: "POST",
+			body: JSON.stringify({ email, otp }),
+		});
+	};
+
 	const getCurrentUser = async (): Promise<UserAuthDetails> => {
 		return apiFetch<UserAuthDetails>("/user");
 	};
@@ -54,14 +82,26 @@ function useAuthClient() {
 		});
 	};
 
+	const revertEmail = async (token: string): Promise<void> => {
+		return apiFetch<void>("/auth/email/revert", {
+			method: "POST",
+			body: JSON.stringify({ token }),
+		});
+	};
+
 	return {
 		login,
+		loginTwoFactor,
+		requestMagicLink,
+		loginMagicLink,
 		signup,
+		verifyEmail,
 		getCurrentUser,
 		initializePasswordReset,
 		resetPassword,
 		initializeEmailUpdate,
 		updateEmail,
+		revertEmail,
 	};
 }
 
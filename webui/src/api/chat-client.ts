@@ -0,0 +1,15 @@
+import { apiFetch } from "@/api/api-fetch.ts";
+import type { ChatAccount } from "@/api/types/chat.ts";
+
+function useChatClient() {
+	const linkAccount = async (code: string) => {
+		return apiFetch<ChatAccount>("/chat/accounts/link", {
+			method: "POST",
+			body: JSON.stringify({ code }),
+		});
+	};
+
+	return { linkAccount };
+}
+
+export default useChatClient;
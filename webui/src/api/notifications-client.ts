@@ -0,0 +1,26 @@
+import { apiFetch } from "@/api/api-fetch.ts";
+import type { NotificationList, NotificationPreferences } from "@/api/types/notifications.ts";
+
+function useNotificationsClient() {
+	const getPreferences = async () => {
+		return apiFetch<NotificationPreferences>("/notifications/preferences");
+	};
+
+	const updatePreferences = async (preferences: NotificationPreferences) => {
+		return apiFetch<NotificationPreferences>("/notifications/preferences", {
+			method: "PUT",
+			body: JSON.stringify(preferences),
+		});
+	};
+
+	const unsubscribe = async (token: string, list: NotificationList) => {
+		return apiFetch<void>("/notifications/unsubscribe", {
+			method: "POST",
+			body: JSON.stringify({ token, list }),
+		});
+	};
+
+	return { getPreferences, updatePreferences, unsubscribe };
+}
+
+export default useNotificationsClient;
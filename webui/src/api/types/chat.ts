@@ -0,0 +1,7 @@
+export type ChatAccount = {
+	id: string;
+	teamId: string;
+	chatUserId: string;
+	chatUsername: string;
+	createdAt: string;
+};
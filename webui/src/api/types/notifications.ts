@@ -0,0 +1,16 @@
+export type NotificationPreferences = {
+	weeklyDigest: boolean;
+	settleUpReminders: boolean;
+	push: PushPreferences;
+};
+
+export type PushPreferences = {
+	invitations: boolean;
+	rounds: boolean;
+	sessionUpdates: boolean;
+};
+
+/*
+ * NotificationList is a kind of notification email that can be unsubscribed from, or "all" for every kind.
+ */
+export type NotificationList = "weekly_digest" | "settle_up_reminders" | "all";
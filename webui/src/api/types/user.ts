@@ -4,6 +4,15 @@ export type UserAuthDetails = {
 	username: string;
 };
 
+/*
+ * LoginMFARequired is returned by a login when the user has two-factor authentication enabled.
+ * The MFA token is sent along with the code to complete the login.
+ */
+export type LoginMFARequired = {
+	mfaRequired: true;
+	mfaToken: string;
+};
+
 export type UserBalance = {
 	credit: number;
 	debit: number;
@@ -2,9 +2,12 @@ import { ValidationError } from "@/api/api-fetch.ts";
 import useAuthClient from "@/api/auth-client.ts";
 import { Card, CardContent, CardDescription, CardFooter, CardHeader, CardTitle } from "@/components/ui/card";
 import { LoginForm, type LoginFormValues } from "@/features/auth/login/login-form.tsx";
+import { MagicLinkForm, type MagicLinkFormValues } from "@/features/auth/login/magic-link-form.tsx";
+import { TwoFactorForm, type TwoFactorFormValues } from "@/features/auth/login/two-factor-form.tsx";
+import { useLinkParams } from "@/hooks/use-link-params.ts";
 import { tryCatch } from "@/lib/try-catch.ts";
 import { useUserStore } from "@/stores/user-store.tsx";
-import { useEffect, useRef } from "react";
+import { useEffect, useRef, useState } from "react";
 import { Link, useNavigate, useSearchParams } from "react-router";
 import { toast } from "sonner";
 import { PageHeading } from "@/components/page-heading.tsx";
@@ -12,10 +15,15 @@ import { PageHeading } from "@/components/page-heading.tsx";
 function LoginPage() {
 	const [searchParams] = useSearchParams();
 	const navigatedAfterSignup = searchParams.get("signup") === "true";
+	// Logins that finish elsewhere, such as with an OpenID Connect provider, link here with the
+	// MFA token when the user has two-factor authentication enabled.
+	const linkParams = useLinkParams();
+	const [mfaToken, setMfaToken] = useState(linkParams.get("mfaToken"));
+	const [showMagicLink, setShowMagicLink] = useState(false);
 	const hasShownToast = useRef(false);
 	const setUser = useUserStore((state) => state.setUser);
 	const navigate = useNavigate();
-	const { login } = useAuthClient();
+	const { login, loginTwoFactor, requestMagicLink } = useAuthClient();
 
 	useEffect(() => {
 		if (navigatedAfterSignup && !hasShownToast.current) {
@@ -25,7 +33,24 @@ function LoginPage() {
 	}, [navigatedAfterSignup]);
 
 	async function handleLogin({ username, password }: LoginFormValues) {
-		const { data: user, err } = await tryCatch(login(username, password));
+		const { data, err } = await tryCatch(login(username, password));
+		if (err) {
+			handleLoginError(err);
+			return;
+		}
+
+		if ("mfaRequired" in data) {
+			setMfaToken(data.mfaToken);
+			return;
+		}
+
+		setUser(data);
+		navigate("/");
+	}
+
+	async function handleTwoFactor({ code }: TwoFactorFormValues) {
+		if (!mfaToken) return;
+		const { data: user, err } = await tryCatch(loginTwoFactor(mfaToken, code));
 		if (err) {
 			handleLoginError(err);
 			return;
@@ -35,6 +60,16 @@ function LoginPage() {
 		navigate("/");
 	}
 
+	async function handleRequestMagicLink({ email }: MagicLinkFormValues) {
+		const { err } = await tryCatch(requestMagicLink(email));
+		if (err) {
+			handleLoginError(err);
+			return;
+		}
+
+		toast.success("If there is an account for that email address, a login link has been sent to it.");
+	}
+
 	function handleLoginError(err: unknown) {
 		if (err instanceof ValidationError) {
 			toast.error("There was an issue with the data you provided", {
@@ -58,15 +93,30 @@ function LoginPage() {
 			<Card>
 				<CardHeader>
 					<CardTitle>Login</CardTitle>
-					<CardDescription>Login to the app</CardDescription>
+					<CardDescription>
+						{mfaToken
+							? "Enter the code from your authenticator app or one of your recovery codes"
+							: "Login to the app"}
+					</CardDescription>
 				</CardHeader>
 				<CardContent>
-					<LoginForm onSubmit={handleLogin} />
+					{mfaToken ? (
+						<TwoFactorForm onSubmit={handleTwoFactor} onCancel={() => setMfaToken(null)} />
+					) : showMagicLink ? (
+						<MagicLinkForm onSubmit={handleRequestMagicLink} />
+					) : (
+						<LoginForm onSubmit={handleLogin} />
+					)}
 				</CardContent>
-				<CardFooter>
+				<CardFooter className="flex justify-between">
 					<Link to="/signup" className="text-sm">
 						Don't have an account?
 					</Link>
+					{!mfaToken && (
+						<button type="button" className="text-sm" onClick={() => setShowMagicLink(!showMagicLink)}>
+							{showMagicLink ? "Login with a password" : "Login with an email link"}
+						</button>
+					)}
 				</CardFooter>
 			</Card>
 		</>
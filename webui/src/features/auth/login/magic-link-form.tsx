@@ -0,0 +1,52 @@
+import { Button } from "@/components/ui/button.tsx";
+import { Form, FormControl, FormField, FormItem, FormLabel } from "@/components/ui/form.tsx";
+import { Input } from "@/components/ui/input.tsx";
+import { zodResolver } from "@hookform/resolvers/zod";
+import { useForm } from "react-hook-form";
+import { z } from "zod";
+
+const formSchema = z.object({
+	email: z.string().email("Invalid email address").nonempty("Email is required"),
+});
+
+export type MagicLinkFormValues = z.infer<typeof formSchema>;
+
+interface MagicLinkFormProps {
+	onSubmit: (values: MagicLinkFormValues) => void;
+}
+
+export function MagicLinkForm({ onSubmit }: MagicLinkFormProps) {
+	const form = useForm<MagicLinkFormValues>({
+		resolver: zodResolver(formSchema),
+		defaultValues: {
+			email: "",
+		},
+	});
+
+	function handleSubmit(values: MagicLinkFormValues) {
+		onSubmit(values);
+	}
+
+	return (
+		<Form {...form}>
+			<form onSubmit={form.handleSubmit(handleSubmit)} className="space-y-4">
+				<FormField
+					name="email"
+					control={form.control}
+					render={({ field }) => (
+						<FormItem>
+							<FormLabel htmlFor={field.name}>Email</FormLabel>
+							<FormControl>
+								<Input type="email" {...field} />
+							</FormControl>
+						</FormItem>
+					)}
+				/>
+
+				<Button type="submit" disabled={form.formState.isSubmitting}>
+					Email me a login link
+				</Button>
+			</form>
+		</Form>
+	);
+}
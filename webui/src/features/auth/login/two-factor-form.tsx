@@ -0,0 +1,61 @@
+import { Button } from "@/components/ui/button.tsx";
+import { Form, FormControl, FormField, FormItem, FormLabel } from "@/components/ui/form.tsx";
+import { Input } from "@/components/ui/input.tsx";
+import { zodResolver } from "@hookform/resolvers/zod";
+import { useForm } from "react-hook-form";
+import { z } from "zod";
+
+const formSchema = z.object({
+	code: z.string().trim().nonempty("Code is required"),
+});
+
+export type TwoFactorFormValues = z.infer<typeof formSchema>;
+
+interface TwoFactorFormProps {
+	onSubmit: (values: TwoFactorFormValues) => void;
+	onCancel: () => void;
+}
+
+/*
+ * TwoFactorForm takes the code from the user's authenticator app, or one of their recovery codes.
+ */
+export function TwoFactorForm({ onSubmit, onCancel }: TwoFactorFormProps) {
+	const form = useForm<TwoFactorFormValues>({
+		resolver: zodResolver(formSchema),
+		defaultValues: {
+			code: "",
+		},
+	});
+
+	function handleSubmit(values: TwoFactorFormValues) {
+		onSubmit(values);
+	}
+
+	return (
+		<Form {...form}>
+			<form onSubmit={form.handleSubmit(handleSubmit)} className="space-y-4">
+				<FormField
+					name="code"
+					control={form.control}
+					render={({ field }) => (
+						<FormItem>
+							<FormLabel htmlFor={field.name}>Authentication code</FormLabel>
+							<FormControl>
+								<Input autoComplete="one-time-code" autoFocus {...field} />
+							</FormControl>
+						</FormItem>
+					)}
+				/>
+
+				<div className="flex gap-2">
+					<Button type="submit" disabled={form.formState.isSubmitting}>
+						Verify
+					</Button>
+					<Button type="button" variant="secondary" onClick={onCancel}>
+						Cancel
+					</Button>
+				</div>
+			</form>
+		</Form>
+	);
+}
@@ -0,0 +1,80 @@
+import useAuthClient from "@/api/auth-client.ts";
+import { PageHeading } from "@/components/page-heading.tsx";
+import { Button } from "@/components/ui/button.tsx";
+import { Card, CardContent, CardDescription, CardFooter, CardHeader, CardTitle } from "@/components/ui/card";
+import { useLinkParams } from "@/hooks/use-link-params.ts";
+import { tryCatch } from "@/lib/try-catch.ts";
+import { useUserStore } from "@/stores/user-store.tsx";
+import { useEffect, useRef, useState } from "react";
+import { Link, useNavigate } from "react-router";
+
+type LoginState = { status: "logging-in" } | { status: "failed"; message: string };
+
+/*
+ * MagicLinkPage is the landing page for the link in the magic link login email.
+ * The link carries the email address and the OTP, which are sent to the API as soon as the page loads.
+ */
+function MagicLinkPage() {
+	const linkParams = useLinkParams();
+	const email = linkParams.get("email");
+	const otp = linkParams.get("otp");
+	const [state, setState] = useState<LoginState>({ status: "logging-in" });
+	const hasSubmitted = useRef(false);
+	const setUser = useUserStore((state) => state.setUser);
+	const navigate = useNavigate();
+	const { loginMagicLink } = useAuthClient();
+
+	useEffect(() => {
+		// The OTP can only be used once, so make sure it is not sent twice.
+		if (hasSubmitted.current) return;
+		hasSubmitted.current = true;
+
+		if (!email || !otp) {
+			setState({
+				status: "failed",
+				message: "The login link is incomplete, please use the link from the email.",
+			});
+			return;
+		}
+
+		tryCatch(loginMagicLink(email, otp)).then(({ data, err }) => {
+			if (err) {
+				setState({ status: "failed", message: err instanceof Error ? err.message : "Unknown error" });
+				return;
+			}
+
+			if ("mfaRequired" in data) {
+				navigate(`/login#${new URLSearchParams({ mfaToken: data.mfaToken })}`, { replace: true });
+				return;
+			}
+
+			setUser(data);
+			navigate("/", { replace: true });
+		});
+	}, [email, otp, loginMagicLink, setUser, navigate]);
+
+	return (
+		<>
+			<PageHeading title="Login" />
+			<Card>
+				<CardHeader>
+					<CardTitle>Login with a link</CardTitle>
+					<CardDescription>{email}</CardDescription>
+				</CardHeader>
+				<CardContent>
+					{state.status === "logging-in" && <p>Logging you in...</p>}
+					{state.status === "failed" && <p className="text-destructive">{state.message}</p>}
+				</CardContent>
+				{state.status === "failed" && (
+					<CardFooter>
+						<Button asChild>
+							<Link to="/login">Back to login</Link>
+						</Button>
+					</CardFooter>
+				)}
+			</Card>
+		</>
+	);
+}
+
+export default MagicLinkPage;
@@ -0,0 +1,86 @@
+import useAuthClient from "@/api/auth-client.ts";
+import { PageHeading } from "@/components/page-heading.tsx";
+import { Button } from "@/components/ui/button.tsx";
+import { Card, CardContent, CardDescription, CardFooter, CardHeader, CardTitle } from "@/components/ui/card";
+import { useLinkParams } from "@/hooks/use-link-params.ts";
+import { tryCatch } from "@/lib/try-catch.ts";
+import { useUserStore } from "@/stores/user-store.tsx";
+import { useState } from "react";
+import { Link } from "react-router";
+
+type RevertState =
+	| { status: "confirming" }
+	| { status: "reverting" }
+	| { status: "reverted" }
+	| { status: "failed"; message: string };
+
+/*
+ * RevertEmailPage is the landing page for the link sent to the previous email address when it is changed.
+ * Reverting the change logs the user out of every device, so the user confirms it before the token is sent.
+ */
+function RevertEmailPage() {
+	const linkParams = useLinkParams();
+	const token = linkParams.get("token");
+	const [state, setState] = useState<RevertState>(
+		token
+			? { status: "confirming" }
+			: { status: "failed", message: "The link is incomplete, please use the link from the email." },
+	);
+	const logout = useUserStore((state) => state.logout);
+	const { revertEmail } = useAuthClient();
+
+	async function handleRevert() {
+		if (!token) return;
+		setState({ status: "reverting" });
+
+		const { err } = await tryCatch(revertEmail(token));
+		if (err) {
+			setState({ status: "failed", message: err instanceof Error ? err.message : "Unknown error" });
+			return;
+		}
+
+		logout();
+		setState({ status: "reverted" });
+	}
+
+	return (
+		<>
+			<PageHeading title="Restore email" />
+			<Card>
+				<CardHeader>
+					<CardTitle>Restore your email address</CardTitle>
+					<CardDescription>
+						If you did not change the email address on your account, restore your previous email address and
+						log out of every device.
+					</CardDescription>
+				</CardHeader>
+				<CardContent>
+					{state.status === "confirming" && <p>Your email address will be changed back to this address.</p>}
+					{state.status === "reverting" && <p>Restoring your email address...</p>}
+					{state.status === "reverted" && (
+						<p>
+							Your email address has been restored and you have been logged out of every device. Log in
+							and change your password to secure your account.
+						</p>
+					)}
+					{state.status === "failed" && <p className="text-destructive">{state.message}</p>}
+				</CardContent>
+				<CardFooter>
+					{state.status === "confirming" || state.status === "reverting" ? (
+						<Button onClick={handleRevert} disabled={state.status === "reverting"}>
+							Restore email address
+						</Button>
+					) : (
+						<Button asChild>
+							<Link to={state.status === "reverted" ? "/login" : "/"}>
+								{state.status === "reverted" ? "Login" : "Back home"}
+							</Link>
+						</Button>
+					)}
+				</CardFooter>
+			</Card>
+		</>
+	);
+}
+
+export default RevertEmailPage;
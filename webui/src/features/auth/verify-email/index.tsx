@@ -0,0 +1,75 @@
+import useAuthClient from "@/api/auth-client.ts";
+import { PageHeading } from "@/components/page-heading.tsx";
+import { Button } from "@/components/ui/button.tsx";
+import { Card, CardContent, CardDescription, CardFooter, CardHeader, CardTitle } from "@/components/ui/card";
+import { tryCatch } from "@/lib/try-catch.ts";
+import { useEffect, useRef, useState } from "react";
+import { Link, useSearchParams } from "react-router";
+
+type VerificationState =
+	| { status: "verifying" }
+	| { status: "verified" }
+	| { status: "failed"; message: string };
+
+/*
+ * VerifyEmailPage is the landing page for the link in the email verification email.
+ * The link carries the email address and the OTP, which are sent to the API as soon as the page loads.
+ */
+function VerifyEmailPage() {
+	const [searchParams] = useSearchParams();
+	const email = searchParams.get("email");
+	const otp = searchParams.get("otp");
+	const [state, setState] = useState<VerificationState>({ status: "verifying" });
+	const hasSubmitted = useRef(false);
+	const { verifyEmail } = useAuthClient();
+
+	useEffect(() => {
+		// The OTP can only be used once, so make sure it is not sent twice.
+		if (hasSubmitted.current) return;
+		hasSubmitted.current = true;
+
+		if (!email || !otp) {
+			setState({
+				status: "failed",
+				message: "The verification link is incomplete, please use the link from the email.",
+			});
+			return;
+		}
+
+		tryCatch(verifyEmail(email, otp)).then(({ err }) => {
+			setState(
+				err
+					? { status: "failed", message: err instanceof Error ? err.message : "Unknown error" }
+					: { status: "verified" },
+			);
+		});
+	}, [email, otp, verifyEmail]);
+
+	return (
+		<>
+			<PageHeading title="Verify email" />
+			<Card>
+				<CardHeader>
+					<CardTitle>Verify your email address</CardTitle>
+					<CardDescription>{email}</CardDescription>
+				</CardHeader>
+				<CardContent>
+					{state.status === "verifying" && <p>Verifying your email address...</p>}
+					{state.status === "verified" && <p>Your email address has been verified. You can now login.</p>}
+					{state.status === "failed" && <p className="text-destructive">{state.message}</p>}
+				</CardContent>
+				{state.status !== "verifying" && (
+					<CardFooter>
+						<Button asChild>
+							<Link to={state.status === "verified" ? "/login" : "/"}>
+								{state.status === "verified" ? "Login" : "Back home"}
+							</Link>
+						</Button>
+					</CardFooter>
+				)}
+			</Card>
+		</>
+	);
+}
+
+export default VerifyEmailPage;
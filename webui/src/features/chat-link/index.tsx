@@ -0,0 +1,95 @@
+import useChatClient from "@/api/chat-client.ts";
+import type { ChatAccount } from "@/api/types/chat.ts";
+import { PageHeading } from "@/components/page-heading.tsx";
+import { Button } from "@/components/ui/button.tsx";
+import { Card, CardContent, CardDescription, CardFooter, CardHeader, CardTitle } from "@/components/ui/card";
+import { useLinkParams } from "@/hooks/use-link-params.ts";
+import { tryCatch } from "@/lib/try-catch.ts";
+import { useUserStore } from "@/stores/user-store.tsx";
+import { useState } from "react";
+import { Link } from "react-router";
+
+type LinkState =
+	| { status: "confirming" }
+	| { status: "linking" }
+	| { status: "linked"; account: ChatAccount }
+	| { status: "failed"; message: string };
+
+/*
+ * ChatLinkPage is the landing page for the link the chat bot sends to users whose chat account is not linked.
+ * Linking lets the chat user act as the logged-in user, so the user confirms it rather than it happening on load,
+ * which would let anyone who sends the user their own link act as them.
+ */
+function ChatLinkPage() {
+	const linkParams = useLinkParams();
+	const code = linkParams.get("code");
+	const user = useUserStore((state) => state.user);
+	const [state, setState] = useState<LinkState>(
+		code
+			? { status: "confirming" }
+			: { status: "failed", message: "The link is incomplete, please use the link sent to you in chat." },
+	);
+	const { linkAccount } = useChatClient();
+
+	async function handleLink() {
+		if (!code) return;
+		setState({ status: "linking" });
+
+		const { data: account, err } = await tryCatch(linkAccount(code));
+		if (err) {
+			setState({ status: "failed", message: err instanceof Error ? err.message : "Unknown error" });
+			return;
+		}
+
+		setState({ status: "linked", account });
+	}
+
+	return (
+		<>
+			<PageHeading title="Link chat account" />
+			<Card>
+				<CardHeader>
+					<CardTitle>Link your chat account</CardTitle>
+					<CardDescription>
+						Only link a chat account if you opened this link from a Beerbux command you used in chat yourself.
+					</CardDescription>
+				</CardHeader>
+				<CardContent>
+					{!user && state.status === "confirming" && (
+						<p>Log in to Beerbux, then open the link from chat again to link your chat account.</p>
+					)}
+					{user && state.status === "confirming" && (
+						<p>
+							Commands you use in chat will act as <strong>{user.username}</strong>.
+						</p>
+					)}
+					{state.status === "linking" && <p>Linking your chat account...</p>}
+					{state.status === "linked" && (
+						<p>
+							Your chat account <strong>{state.account.chatUsername}</strong> is now linked. You can go back
+							to chat and use Beerbux commands.
+						</p>
+					)}
+					{state.status === "failed" && <p className="text-destructive">{state.message}</p>}
+				</CardContent>
+				<CardFooter>
+					{!user && state.status === "confirming" ? (
+						<Button asChild>
+							<Link to="/login">Login</Link>
+						</Button>
+					) : state.status === "confirming" || state.status === "linking" ? (
+						<Button onClick={handleLink} disabled={state.status === "linking"}>
+							Link chat account
+						</Button>
+					) : (
+						<Button asChild>
+							<Link to="/">Back home</Link>
+						</Button>
+					)}
+				</CardFooter>
+			</Card>
+		</>
+	);
+}
+
+export default ChatLinkPage;
@@ -1,6 +1,7 @@
 import useAuthClient from "@/api/auth-client.ts";
 import useUserClient from "@/api/user-client.ts";
 import { PageHeading } from "@/components/page-heading.tsx";
+import { Button } from "@/components/ui/button.tsx";
 import { Card, CardContent, CardDescription, CardHeader, CardTitle } from "@/components/ui/card";
 import { OTPForm } from "@/features/settings/otp-form.tsx";
 import { UpdateEmailForm } from "@/features/settings/update-email-form.tsx";
@@ -10,6 +11,7 @@ import type { UserSettingsFormValues } from "@/features/settings/user-settings-f
 import { tryCatch } from "@/lib/try-catch.ts";
 import { useUserStore } from "@/stores/user-store.tsx";
 import { useState } from "react";
+import { Link } from "react-router";
 import { toast } from "sonner";
 
 export default function SettingsPage() {
@@ -144,6 +146,17 @@ export default function SettingsPage() {
 						)}
 					</CardContent>
 				</Card>
+				<Card>
+					<CardHeader>
+						<CardTitle>Notifications</CardTitle>
+						<CardDescription>Choose the emails and push notifications you receive.</CardDescription>
+					</CardHeader>
+					<CardContent>
+						<Button variant="secondary" asChild>
+							<Link to="/settings/notifications">Notification settings</Link>
+						</Button>
+					</CardContent>
+				</Card>
 			</section>
 		</>
 	);
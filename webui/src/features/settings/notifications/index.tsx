@@ -0,0 +1,145 @@
+import useNotificationsClient from "@/api/notifications-client.ts";
+import type { NotificationPreferences } from "@/api/types/notifications.ts";
+import { PageError } from "@/components/page-error.tsx";
+import { PageHeading } from "@/components/page-heading.tsx";
+import { Card, CardContent, CardDescription, CardHeader, CardTitle } from "@/components/ui/card";
+import { Label } from "@/components/ui/label.tsx";
+import { Skeleton } from "@/components/ui/skeleton.tsx";
+import { useBackNavigation } from "@/hooks/use-back-navigation.ts";
+import { useMutation, useQuery, useQueryClient } from "@tanstack/react-query";
+import { toast } from "sonner";
+
+/*
+ * NotificationSettingsPage lets the user choose the notification emails and push notifications they receive.
+ * Notification emails link here to manage preferences.
+ */
+export default function NotificationSettingsPage() {
+	useBackNavigation("/settings");
+	const queryClient = useQueryClient();
+	const { getPreferences, updatePreferences } = useNotificationsClient();
+
+	const preferencesQuery = useQuery({
+		queryKey: ["notification-preferences"],
+		queryFn: () => getPreferences(),
+	});
+
+	const updatePreferencesMutation = useMutation({
+		mutationFn: (preferences: NotificationPreferences) => updatePreferences(preferences),
+		onSuccess: (preferences) => {
+			queryClient.setQueryData(["notification-preferences"], preferences);
+			toast.success("Notification preferences updated");
+		},
+		onError: (err) => {
+			toast.error("Failed to update your notification preferences", {
+				description: err.message,
+			});
+		},
+	});
+
+	if (preferencesQuery.isError) {
+		return <PageError message="There has been an issue fetching your notification preferences." />;
+	}
+
+	const preferences = preferencesQuery.data;
+	const disabled = !preferences || updatePreferencesMutation.isPending;
+
+	function update(change: Partial<NotificationPreferences>) {
+		if (!preferences) return;
+		updatePreferencesMutation.mutate({ ...preferences, ...change });
+	}
+
+	return (
+		<>
+			<PageHeading title="Notifications" />
+			<section className="space-y-4">
+				<Card>
+					<CardHeader>
+						<CardTitle>Emails</CardTitle>
+						<CardDescription>Choose the emails you would like to receive.</CardDescription>
+					</CardHeader>
+					<CardContent className="space-y-4">
+						{preferences ? (
+							<>
+								<PreferenceCheckbox
+									id="weekly-digest"
+									label="Weekly digest of the rounds you bought and received"
+									checked={preferences.weeklyDigest}
+									disabled={disabled}
+									onChange={(weeklyDigest) => update({ weeklyDigest })}
+								/>
+								<PreferenceCheckbox
+									id="settle-up-reminders"
+									label="Reminders to settle up when you owe too many beers"
+									checked={preferences.settleUpReminders}
+									disabled={disabled}
+									onChange={(settleUpReminders) => update({ settleUpReminders })}
+								/>
+							</>
+						) : (
+							<Skeleton className="h-12 w-full" />
+						)}
+					</CardContent>
+				</Card>
+				<Card>
+					<CardHeader>
+						<CardTitle>Push notifications</CardTitle>
+						<CardDescription>Choose the notifications sent to your devices.</CardDescription>
+					</CardHeader>
+					<CardContent className="space-y-4">
+						{preferences ? (
+							<>
+								<PreferenceCheckbox
+									id="push-invitations"
+									label="When you are added to a session"
+									checked={preferences.push.invitations}
+									disabled={disabled}
+									onChange={(invitations) => update({ push: { ...preferences.push, invitations } })}
+								/>
+								<PreferenceCheckbox
+									id="push-rounds"
+									label="When someone buys you a round"
+									checked={preferences.push.rounds}
+									disabled={disabled}
+									onChange={(rounds) => update({ push: { ...preferences.push, rounds } })}
+								/>
+								<PreferenceCheckbox
+									id="push-session-updates"
+									label="When a session you are in changes"
+									checked={preferences.push.sessionUpdates}
+									disabled={disabled}
+									onChange={(sessionUpdates) => update({ push: { ...preferences.push, sessionUpdates } })}
+								/>
+							</>
+						) : (
+							<Skeleton className="h-18 w-full" />
+						)}
+					</CardContent>
+				</Card>
+			</section>
+		</>
+	);
+}
+
+type PreferenceCheckboxProps = {
+	id: string;
+	label: string;
+	checked: boolean;
+	disabled: boolean;
+	onChange: (checked: boolean) => void;
+};
+
+function PreferenceCheckbox({ id, label, checked, disabled, onChange }: PreferenceCheckboxProps) {
+	return (
+		<div className="flex items-center gap-2">
+			<input
+				id={id}
+				type="checkbox"
+				className="peer size-4 accent-primary"
+				checked={checked}
+				disabled={disabled}
+				onChange={(e) => onChange(e.target.checked)}
+			/>
+			<Label htmlFor={id}>{label}</Label>
+		</div>
+	);
+}
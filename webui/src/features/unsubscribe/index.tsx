@@ -0,0 +1,89 @@
+import useNotificationsClient from "@/api/notifications-client.ts";
+import type { NotificationList } from "@/api/types/notifications.ts";
+import { PageHeading } from "@/components/page-heading.tsx";
+import { Button } from "@/components/ui/button.tsx";
+import { Card, CardContent, CardDescription, CardFooter, CardHeader, CardTitle } from "@/components/ui/card";
+import { tryCatch } from "@/lib/try-catch.ts";
+import { useEffect, useRef, useState } from "react";
+import { Link, useSearchParams } from "react-router";
+
+type UnsubscribeState =
+	| { status: "unsubscribing" }
+	| { status: "unsubscribed" }
+	| { status: "failed"; message: string };
+
+const listDescriptions: Record<NotificationList, string> = {
+	weekly_digest: "the weekly digest",
+	settle_up_reminders: "settle up reminders",
+	all: "all notification emails",
+};
+
+function isNotificationList(list: string): list is NotificationList {
+	return list in listDescriptions;
+}
+
+/*
+ * UnsubscribePage is the landing page for the unsubscribe link in notification emails.
+ * The link carries the user's unsubscribe token, so the user does not have to log in to unsubscribe.
+ */
+function UnsubscribePage() {
+	const [searchParams] = useSearchParams();
+	const token = searchParams.get("token");
+	const listParam = searchParams.get("list") ?? "all";
+	const list = isNotificationList(listParam) ? listParam : "all";
+	const [state, setState] = useState<UnsubscribeState>({ status: "unsubscribing" });
+	const hasSubmitted = useRef(false);
+	const { unsubscribe } = useNotificationsClient();
+
+	useEffect(() => {
+		if (hasSubmitted.current) return;
+		hasSubmitted.current = true;
+
+		if (!token) {
+			setState({
+				status: "failed",
+				message: "The unsubscribe link is incomplete, please use the link from the email.",
+			});
+			return;
+		}
+
+		tryCatch(unsubscribe(token, list)).then(({ err }) => {
+			setState(
+				err
+					? { status: "failed", message: err instanceof Error ? err.message : "Unknown error" }
+					: { status: "unsubscribed" },
+			);
+		});
+	}, [token, list, unsubscribe]);
+
+	return (
+		<>
+			<PageHeading title="Unsubscribe" />
+			<Card>
+				<CardHeader>
+					<CardTitle>Unsubscribe</CardTitle>
+					<CardDescription>Stop receiving {listDescriptions[list]} from Beerbux.</CardDescription>
+				</CardHeader>
+				<CardContent>
+					{state.status === "unsubscribing" && <p>Unsubscribing you...</p>}
+					{state.status === "unsubscribed" && (
+						<p>You have been unsubscribed from {listDescriptions[list]}. You can opt back in from your settings.</p>
+					)}
+					{state.status === "failed" && <p className="text-destructive">{state.message}</p>}
+				</CardContent>
+				{state.status !== "unsubscribing" && (
+					<CardFooter className="flex gap-2">
+						<Button asChild>
+							<Link to="/settings/notifications">Notification settings</Link>
+						</Button>
+						<Button variant="secondary" asChild>
+							<Link to="/">Back home</Link>
+						</Button>
+					</CardFooter>
+				)}
+			</Card>
+		</>
+	);
+}
+
+export default UnsubscribePage;
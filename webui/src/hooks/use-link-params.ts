@@ -0,0 +1,20 @@
+import { useEffect, useState } from "react";
+import { useLocation, useNavigate } from "react-router";
+
+/*
+ * useLinkParams returns the parameters in the fragment of a link from an email or a redirect, such as
+ * a one-time password, and removes them from the URL so that they are not kept in the browser history.
+ * Unlike the query string, the fragment is never sent to a server, so it stays out of logs and Referer headers.
+ */
+export function useLinkParams(): URLSearchParams {
+	const location = useLocation();
+	const navigate = useNavigate();
+	const [params] = useState(() => new URLSearchParams(location.hash.slice(1)));
+
+	useEffect(() => {
+		if (!location.hash) return;
+		navigate({ pathname: location.pathname, search: location.search }, { replace: true });
+	}, [location.hash, location.pathname, location.search, navigate]);
+
+	return params;
+}
@@ -1,12 +1,18 @@
 import NotFoundPage from "@/features/NotFound.tsx";
 import LoginPage from "@/features/auth/login";
+import MagicLinkPage from "@/features/auth/magic-link";
+import RevertEmailPage from "@/features/auth/revert-email";
 import SignupPage from "@/features/auth/signup";
+import VerifyEmailPage from "@/features/auth/verify-email";
+import ChatLinkPage from "@/features/chat-link";
 import DashboardPage from "@/features/dashboard";
 import FriendDetailPage from "@/features/firend";
 import HomePage from "@/features/home";
 import SessionDetailPage from "@/features/session/detail";
 import SessionListingPage from "@/features/session/listing";
 import SettingsPage from "@/features/settings";
+import NotificationSettingsPage from "@/features/settings/notifications";
+import UnsubscribePage from "@/features/unsubscribe";
 import RootLayout from "@/layouts/root-layout";
 import { useUserStore } from "@/stores/user-store.tsx";
 import type { JSX } from "react";
@@ -23,11 +29,17 @@ function AppRoutes() {
 			<Route element={<RootLayout />}>
 				<Route index element={<AuthGuard page={<DashboardPage />} alt={<HomePage />} />} />
 				<Route path="/login" element={<LoginPage />} />
+				<Route path="/login/magic-link" element={<MagicLinkPage />} />
 				<Route path="/signup" element={<SignupPage />} />
+				<Route path="/verify-email" element={<VerifyEmailPage />} />
+				<Route path="/revert-email" element={<RevertEmailPage />} />
 				<Route path="/sessions" element={<AuthGuard page={<SessionListingPage />} />} />
 				<Route path="/sessions" element={<AuthGuard page={<SessionListingPage />} />} />
 				<Route path="/session/:sessionId" element={<AuthGuard page={<SessionDetailPage />} />} />
 				<Route path="/settings" element={<AuthGuard page={<SettingsPage />} />} />
+				<Route path="/settings/notifications" element={<AuthGuard page={<NotificationSettingsPage />} />} />
+				<Route path="/unsubscribe" element={<UnsubscribePage />} />
+				<Route path="/chat/link" element={<ChatLinkPage />} />
 				<Route path="/friend/:friendId" element={<FriendDetailPage />} />
 				<Route path="/friend/:friendId" element={<AuthGuard page={<FriendDetailPage />} />} />
 				<Route path="*" element={<NotFoundPage />} />